@@ -6,10 +6,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	_ "paku-commerce/docs" // ⬅️ CRÍTICO: debe estar presente
+	carthttp "paku-commerce/internal/commerce/cart/http"
+	checkouthttp "paku-commerce/internal/commerce/checkout/http"
+	"paku-commerce/internal/commerce/runtime"
 	"paku-commerce/pkg/server"
 )
 
@@ -39,6 +43,19 @@ func main() {
 		port = "8080"
 	}
 
+	closeStorage, err := runtime.InitStorage(context.Background(), runtime.StorageConfigFromEnv())
+	if err != nil {
+		log.Fatalf("storage error: %v", err)
+	}
+	defer closeStorage()
+
+	if err := runtime.InitPets(runtime.PetsConfigFromEnv()); err != nil {
+		log.Fatalf("pets client error: %v", err)
+	}
+	if err := runtime.InitPayments(runtime.PaymentsConfigFromEnv()); err != nil {
+		log.Fatalf("payments client error: %v", err)
+	}
+
 	srv := &http.Server{
 		Addr:              ":" + port,
 		Handler:           server.NewRouter(),
@@ -48,6 +65,47 @@ func main() {
 		IdleTimeout:       60 * time.Second,
 	}
 
+	// Tareas de fondo: se detienen antes de cerrar el storage
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workersCfg := runtime.WorkersConfigFromEnv()
+	if interval := workersCfg.CartExpiryInterval; interval > 0 {
+		expiryWorker := carthttp.WireCartExpiryWorker(interval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			expiryWorker.Run(workersCtx)
+		}()
+		log.Printf("cart expiry worker every %s", interval)
+	}
+	if interval := workersCfg.OrderExpiryInterval; interval > 0 {
+		orderExpiryWorker := checkouthttp.WireOrderExpiryWorker(interval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			orderExpiryWorker.Run(workersCtx)
+		}()
+		log.Printf("order expiry worker every %s", interval)
+	}
+	if interval := workersCfg.HoldRetryInterval; interval > 0 {
+		holdRetryWorker := checkouthttp.WireHoldRetryWorker(interval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			holdRetryWorker.Run(workersCtx)
+		}()
+		log.Printf("hold retry worker every %s", interval)
+	}
+	if interval := workersCfg.PaymentIntentPollInterval; interval > 0 && runtime.QRPaymentsSingleton != nil {
+		paymentIntentWorker := checkouthttp.WirePaymentIntentWorker(interval)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			paymentIntentWorker.Run(workersCtx)
+		}()
+		log.Printf("payment intent poll worker every %s", interval)
+	}
+
 	go func() {
 		log.Printf("listening on :%s", port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -64,8 +122,11 @@ func main() {
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("server forced to shutdown: %v", err)
+		log.Printf("server forced to shutdown: %v", err)
 	}
 
+	stopWorkers()
+	workers.Wait()
+
 	log.Println("server stopped")
 }
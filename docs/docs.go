@@ -25,7 +25,7 @@ const docTemplate = `{
     "paths": {
         "/api/v1/commerce/cart/expire": {
             "post": {
-                "description": "Expirar carritos vencidos (dev/admin)",
+                "description": "Expirar carritos vencidos a demanda (admin). El barrido normal lo hace el worker de fondo;\nrequiere X-Admin-Token igual a ADMIN_TOKEN (sin ADMIN_TOKEN el endpoint está deshabilitado).",
                 "consumes": [
                     "application/json"
                 ],
@@ -37,6 +37,13 @@ const docTemplate = `{
                 ],
                 "summary": "Expire carts",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Optional timestamp",
                         "name": "body",
@@ -59,6 +66,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -75,7 +94,7 @@ const docTemplate = `{
                         "UserID": []
                     }
                 ],
-                "description": "Obtener el carrito del usuario",
+                "description": "Obtener el carrito del usuario. Con include=quote agrega la cotización vigente\n(precio por línea, descuentos, total) y marca los items que ya no son válidos.",
                 "produces": [
                     "application/json"
                 ],
@@ -90,6 +109,12 @@ const docTemplate = `{
                         "name": "X-User-ID",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "quote",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -97,6 +122,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/internal_commerce_cart_http.CartResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revisión del carrito (usar en If-Match)"
+                            }
                         }
                     },
                     "404": {
@@ -119,7 +150,7 @@ const docTemplate = `{
                         "UserID": []
                     }
                 ],
-                "description": "Crear o actualizar el carrito del usuario",
+                "description": "Crear o actualizar el carrito del usuario. Con pets se agregan mascotas adicionales\n(sus items llevan pet_id). Si cambia el perfil de una mascota, sus items que\ndejan de aplicar se quitan y se informan en removed_items. Con pet_id (sin pet_profile)\nel perfil se resuelve en el servicio de mascotas.",
                 "consumes": [
                     "application/json"
                 ],
@@ -138,6 +169,12 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag del carrito (GET /cart/me)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
                     {
                         "description": "Cart data",
                         "name": "body",
@@ -153,6 +190,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/internal_commerce_cart_http.CartResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revisión del carrito"
+                            }
                         }
                     },
                     "400": {
@@ -161,11 +204,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
                     }
                 }
             },
@@ -187,12 +248,24 @@ const docTemplate = `{
                         "name": "X-User-ID",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag del carrito (GET /cart/me)",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "204": {
                         "description": "No Content"
                     },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -202,9 +275,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/commerce/checkout/orders": {
-            "post": {
-                "description": "Crear una orden pending_payment",
+        "/api/v1/commerce/cart/me/coupon": {
+            "put": {
+                "security": [
+                    {
+                        "UserID": []
+                    }
+                ],
+                "description": "Validar un cupón contra el carrito y guardarlo (se usa en /checkout/start)",
                 "consumes": [
                     "application/json"
                 ],
@@ -212,110 +290,155 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "checkout"
+                    "cart"
                 ],
-                "summary": "Create order",
+                "summary": "Apply cart coupon",
                 "parameters": [
                     {
-                        "description": "Order request",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag del carrito (GET /cart/me)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Cupón",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.QuoteRequestDTO"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ApplyCouponRequestDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.CreateOrderResponseDTO"
+                            "$ref": "#/definitions/internal_commerce_cart_http.CartResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revisión del carrito"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/commerce/checkout/orders/{id}/confirm-payment": {
-            "post": {
-                "description": "Confirmar el pago de una orden",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UserID": []
+                    }
                 ],
+                "description": "Quitar el cupón del carrito",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "checkout"
+                    "cart"
                 ],
-                "summary": "Confirm payment",
+                "summary": "Remove cart coupon",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Payment confirmation",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ConfirmPaymentRequestDTO"
-                        }
+                        "type": "string",
+                        "description": "ETag del carrito (GET /cart/me)",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ConfirmPaymentResponseDTO"
+                            "$ref": "#/definitions/internal_commerce_cart_http.CartResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revisión del carrito"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/commerce/checkout/quote": {
+        "/api/v1/commerce/cart/me/items": {
             "post": {
-                "description": "Cotizar un checkout sin crear orden",
+                "security": [
+                    {
+                        "UserID": []
+                    }
+                ],
+                "description": "Agregar un item al carrito (si ya existe, suma la cantidad)",
                 "consumes": [
                     "application/json"
                 ],
@@ -323,17 +446,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "checkout"
+                    "cart"
                 ],
-                "summary": "Quote checkout",
+                "summary": "Add cart item",
                 "parameters": [
                     {
-                        "description": "Quote request",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag del carrito (GET /cart/me)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Item",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.QuoteRequestDTO"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ItemDTO"
                         }
                     }
                 ],
@@ -341,48 +477,63 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.QuoteResponseDTO"
+                            "$ref": "#/definitions/internal_commerce_cart_http.CartResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revisión del carrito"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/commerce/checkout/start": {
-            "post": {
+        "/api/v1/commerce/cart/me/items/{type}/{id}": {
+            "delete": {
                 "security": [
                     {
                         "UserID": []
                     }
                 ],
-                "description": "Iniciar checkout (crear hold + order + actualizar cart)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Quitar un item del carrito",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "checkout"
+                    "cart"
                 ],
-                "summary": "Start checkout",
+                "summary": "Remove cart item",
                 "parameters": [
                     {
                         "type": "string",
@@ -392,76 +543,1645 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Start checkout request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.StartCheckoutRequestDTO"
-                        }
+                        "type": "string",
+                        "description": "ETag del carrito (GET /cart/me)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item type (service|product)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mascota del item (vacío = pet_profile principal)",
+                        "name": "pet_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.StartCheckoutResponseDTO"
+                            "$ref": "#/definitions/internal_commerce_cart_http.CartResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revisión del carrito"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "internal_commerce_cart_http.CartDTO": {
-            "type": "object",
-            "properties": {
-                "booking_hold_id": {
-                    "type": "string"
-                },
-                "expires_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/internal_commerce_cart_http.ItemDTO"
+            },
+            "patch": {
+                "security": [
+                    {
+                        "UserID": []
                     }
-                },
-                "order_id": {
-                    "type": "string"
-                },
-                "pet_profile": {
-                    "$ref": "#/definitions/internal_commerce_cart_http.PetProfileDTO"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
+                ],
+                "description": "Cambiar la cantidad de un item del carrito",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Update cart item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag del carrito (GET /cart/me)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item type (service|product)",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mascota del item (vacío = pet_profile principal)",
+                        "name": "pet_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Nueva cantidad",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.UpdateCartItemRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.CartResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revisión del carrito"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/incidents": {
+            "get": {
+                "description": "Cola de revisión manual: side effects de booking/pagos que checkout no pudo completar, del más antiguo al más reciente (requiere X-Admin-Token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "List incidents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "open | resolved (default: todos)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por orden",
+                        "name": "order_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ListIncidentsResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/incidents/{id}/resolve": {
+            "post": {
+                "description": "Cerrar a mano un incidente con una nota de lo que se hizo (requiere X-Admin-Token)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Resolve incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Resolution",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ResolveIncidentRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.IncidentResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/incidents/{id}/retry": {
+            "post": {
+                "description": "Reintentar la operación fallida del incidente; si funciona queda resuelto, si no responde 502 y sigue abierto con el nuevo error (requiere X-Admin-Token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Retry incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.IncidentResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders": {
+            "get": {
+                "security": [
+                    {
+                        "UserID": []
+                    }
+                ],
+                "description": "Historial de órdenes del usuario, de la más nueva a la más antigua, paginado por cursor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "List user orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por estado",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Creadas desde (RFC3339, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Creadas hasta (RFC3339, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor de la página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tamaño de página (default 20, máx 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ListOrdersResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Crear una orden pending_payment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Create order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (dueño de la orden)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Order request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.QuoteRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.CreateOrderResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "UserID": []
+                    }
+                ],
+                "description": "Obtener una orden del usuario (con X-Admin-Token se puede consultar cualquier orden)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Get order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.OrderResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "UserID": []
+                    }
+                ],
+                "description": "Cancelar una orden aplicando la política de cancelación: gratis hasta N horas antes de la cita, penalidad porcentual después y sin cancelación una vez iniciada (con X-Admin-Token se puede cancelar cualquier orden)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Cancel order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.CancelOrderResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders/{id}/confirm-payment": {
+            "post": {
+                "description": "Confirmar el pago de una orden",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Confirm payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment confirmation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ConfirmPaymentRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ConfirmPaymentResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders/{id}/events": {
+            "get": {
+                "description": "Historial de la orden (creación, pago, hold, cancelación, ...) con actor y request ID (requiere X-Admin-Token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "List order events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ListOrderEventsResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders/{id}/fulfill": {
+            "post": {
+                "description": "Marcar una orden pagada como fulfilled después de la cita (requiere X-Admin-Token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Fulfill order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.OrderResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders/{id}/no-show": {
+            "post": {
+                "description": "Marcar una orden pagada como no_show si la mascota no se presentó (requiere X-Admin-Token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Mark order as no-show",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.OrderResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders/{id}/payment": {
+            "get": {
+                "security": [
+                    {
+                        "UserID": []
+                    }
+                ],
+                "description": "Estado del último QR de pago de la orden. Mientras está pending se consulta al proveedor, así el frontend puede hacer polling hasta que order_status sea paid",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Get QR payment status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.PaymentIntentResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "UserID": []
+                    }
+                ],
+                "description": "Generar un QR de Yape o Plin para pagar una orden pending_payment o failed. Si ya hay un QR vigente con el mismo método se retorna (200); el pago se confirma cuando la transferencia llega al proveedor (ver GET)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Create QR payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Método de pago",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.CreatePaymentIntentRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.PaymentIntentResponseDTO"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.PaymentIntentResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/orders/{id}/refunds": {
+            "get": {
+                "description": "Reembolsos emitidos sobre el pago de una orden y su neto pagado (requiere X-Admin-Token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "List order refunds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ListOrderRefundsResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Reembolsar el pago de una orden: total (sin lines) o por líneas, prorrateadas por el descuento. Idempotente por refund_key (requiere X-Admin-Token)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Refund order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.RefundOrderRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.RefundOrderResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/quote": {
+            "post": {
+                "description": "Cotizar un checkout sin crear orden",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Quote checkout",
+                "parameters": [
+                    {
+                        "description": "Quote request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.QuoteRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.QuoteResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/checkout/start": {
+            "post": {
+                "security": [
+                    {
+                        "UserID": []
+                    }
+                ],
+                "description": "Iniciar checkout (crear hold + order + actualizar cart)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Start checkout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "X-User-ID",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Start checkout request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.StartCheckoutRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.StartCheckoutResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/commerce/payments/webhooks/{provider}": {
+            "post": {
+                "description": "Recibir eventos del proveedor de pagos (payment.succeeded, payment.failed, payment.refunded) firmados en X-Payment-Signature. Cada evento se aplica una sola vez por ID; 503 pide al proveedor que reintente la entrega",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Payment provider webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Proveedor de pagos",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "t=\u003cunix\u003e,v1=\u003chmac-sha256 hex\u003e",
+                        "name": "X-Payment-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.PaymentWebhookResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "internal_commerce_cart_http.ApplyCouponRequestDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_cart_http.CartDTO": {
+            "type": "object",
+            "properties": {
+                "booking_hold_id": {
+                    "type": "string"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_cart_http.ItemDTO"
+                    }
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "pet_id": {
+                    "type": "string"
+                },
+                "pet_profile": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.PetProfileDTO"
+                },
+                "pets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_cart_http.PetDTO"
+                    }
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
+        "internal_commerce_cart_http.CartQuoteDTO": {
+            "type": "object",
+            "properties": {
+                "coupon_error": {
+                    "description": "el cupón guardado ya no aplica",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_commerce_cart_http.ErrorDTO"
+                        }
+                    ]
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_cart_http.DiscountLineDTO"
+                    }
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_cart_http.ItemErrorDTO"
+                    }
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_cart_http.CartQuoteLineDTO"
+                    }
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.MoneyDTO"
+                },
+                "total": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.MoneyDTO"
+                },
+                "total_discount": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.MoneyDTO"
+                }
+            }
+        },
+        "internal_commerce_cart_http.CartQuoteLineDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "line_total": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.MoneyDTO"
+                },
+                "pet_id": {
+                    "type": "string"
+                },
+                "qty": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.MoneyDTO"
+                }
+            }
+        },
         "internal_commerce_cart_http.CartResponseDTO": {
             "type": "object",
             "properties": {
                 "cart": {
                     "$ref": "#/definitions/internal_commerce_cart_http.CartDTO"
+                },
+                "quote": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.CartQuoteDTO"
+                },
+                "removed_items": {
+                    "description": "RemovedItems lista los items quitados al cambiar el pet_profile (PUT /cart/me).",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_cart_http.ItemErrorDTO"
+                    }
+                }
+            }
+        },
+        "internal_commerce_cart_http.DiscountLineDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.MoneyDTO"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "\"coupon\" | \"promotion\"",
+                    "type": "string"
                 }
             }
         },
@@ -471,6 +2191,12 @@ const docTemplate = `{
                 "code": {
                     "type": "string"
                 },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_cart_http.ItemErrorDTO"
+                    }
+                },
                 "message": {
                     "type": "string"
                 }
@@ -496,8 +2222,18 @@ const docTemplate = `{
         "internal_commerce_cart_http.ExpireResponseDTO": {
             "type": "object",
             "properties": {
+                "cancelled_holds": {
+                    "type": "integer"
+                },
+                "cancelled_orders": {
+                    "type": "integer"
+                },
                 "expired_count": {
                     "type": "integer"
+                },
+                "skipped": {
+                    "description": "otra réplica estaba expirando carritos",
+                    "type": "boolean"
                 }
             }
         },
@@ -507,6 +2243,10 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "pet_id": {
+                    "description": "vacío = pet_profile principal",
+                    "type": "string"
+                },
                 "qty": {
                     "type": "integer"
                 },
@@ -515,6 +2255,51 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_commerce_cart_http.ItemErrorDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "pet_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_cart_http.MoneyDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_cart_http.PetDTO": {
+            "type": "object",
+            "properties": {
+                "pet_id": {
+                    "type": "string"
+                },
+                "pet_profile": {
+                    "$ref": "#/definitions/internal_commerce_cart_http.PetProfileDTO"
+                }
+            }
+        },
         "internal_commerce_cart_http.PetProfileDTO": {
             "type": "object",
             "properties": {
@@ -529,6 +2314,14 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_commerce_cart_http.UpdateCartItemRequestDTO": {
+            "type": "object",
+            "properties": {
+                "qty": {
+                    "type": "integer"
+                }
+            }
+        },
         "internal_commerce_cart_http.UpsertCartRequestDTO": {
             "type": "object",
             "properties": {
@@ -544,8 +2337,41 @@ const docTemplate = `{
                 "order_id": {
                     "type": "string"
                 },
+                "pet_id": {
+                    "type": "string"
+                },
                 "pet_profile": {
                     "$ref": "#/definitions/internal_commerce_cart_http.PetProfileDTO"
+                },
+                "pets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_cart_http.PetDTO"
+                    }
+                }
+            }
+        },
+        "internal_commerce_checkout_http.CancelOrderResponseDTO": {
+            "type": "object",
+            "properties": {
+                "fee": {
+                    "description": "penalidad retenida según la política",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                        }
+                    ]
+                },
+                "order": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.OrderDTO"
+                },
+                "refund": {
+                    "description": "monto a devolver (0 si la orden no estaba pagada)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                        }
+                    ]
                 }
             }
         },
@@ -558,80 +2384,197 @@ const docTemplate = `{
                 "expires_at": {
                     "type": "string"
                 },
-                "id": {
+                "id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.ConfirmPaymentRequestDTO": {
+            "type": "object",
+            "properties": {
+                "paid_at": {
+                    "description": "ISO8601",
+                    "type": "string"
+                },
+                "payment_ref": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.ConfirmPaymentResponseDTO": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.OrderDTO"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.CreateOrderResponseDTO": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.OrderDTO"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.CreatePaymentIntentRequestDTO": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "description": "yape | plin",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.DiscountLineDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "\"coupon\" | \"promotion\"",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.IncidentDTO": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "reintentos manuales fallidos",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "último error de la operación",
+                    "type": "string"
+                },
+                "hold_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "operation": {
+                    "description": "cancel_hold | confirm_hold | refund | cancellation_refund | confirm_payment",
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "resolution": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
                     "type": "string"
                 },
-                "order_id": {
+                "status": {
+                    "description": "open | resolved",
                     "type": "string"
                 },
-                "user_id": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "internal_commerce_checkout_http.ConfirmPaymentRequestDTO": {
+        "internal_commerce_checkout_http.IncidentResponseDTO": {
             "type": "object",
             "properties": {
-                "paid_at": {
-                    "description": "ISO8601",
-                    "type": "string"
-                },
-                "payment_ref": {
-                    "type": "string"
+                "incident": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.IncidentDTO"
                 }
             }
         },
-        "internal_commerce_checkout_http.ConfirmPaymentResponseDTO": {
+        "internal_commerce_checkout_http.ItemDTO": {
             "type": "object",
             "properties": {
-                "order": {
-                    "$ref": "#/definitions/internal_commerce_checkout_http.OrderDTO"
+                "id": {
+                    "type": "string"
+                },
+                "pet_id": {
+                    "description": "vacío = pet_profile principal",
+                    "type": "string"
+                },
+                "qty": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "\"service\" | \"product\"",
+                    "type": "string"
                 }
             }
         },
-        "internal_commerce_checkout_http.CreateOrderResponseDTO": {
+        "internal_commerce_checkout_http.ListIncidentsResponseDTO": {
             "type": "object",
             "properties": {
-                "order": {
-                    "$ref": "#/definitions/internal_commerce_checkout_http.OrderDTO"
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_checkout_http.IncidentDTO"
+                    }
                 }
             }
         },
-        "internal_commerce_checkout_http.DiscountLineDTO": {
+        "internal_commerce_checkout_http.ListOrderEventsResponseDTO": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "source": {
-                    "description": "\"coupon\" | \"promotion\"",
-                    "type": "string"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_checkout_http.OrderEventDTO"
+                    }
                 }
             }
         },
-        "internal_commerce_checkout_http.ErrorResponse": {
+        "internal_commerce_checkout_http.ListOrderRefundsResponseDTO": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "net_paid": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                },
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_checkout_http.RefundDTO"
+                    }
                 }
             }
         },
-        "internal_commerce_checkout_http.ItemDTO": {
+        "internal_commerce_checkout_http.ListOrdersResponseDTO": {
             "type": "object",
             "properties": {
-                "id": {
+                "next_cursor": {
+                    "description": "ausente en la última página",
                     "type": "string"
                 },
-                "qty": {
-                    "type": "integer"
-                },
-                "type": {
-                    "description": "\"service\" | \"product\"",
-                    "type": "string"
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_checkout_http.OrderDTO"
+                    }
                 }
             }
         },
@@ -649,15 +2592,44 @@ const docTemplate = `{
         "internal_commerce_checkout_http.OrderDTO": {
             "type": "object",
             "properties": {
+                "appointment_at": {
+                    "description": "inicio de la cita (ventana de cancelación)",
+                    "type": "string"
+                },
                 "booking_hold_id": {
                     "type": "string"
                 },
+                "cancel_reason": {
+                    "description": "requested | expired | cart_expired",
+                    "type": "string"
+                },
+                "cancellation_fee": {
+                    "description": "penalidad retenida al cancelar una orden pagada",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                        }
+                    ]
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
                 "coupon_code": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "expires_at": {
+                    "description": "plazo de pago de pending_payment",
+                    "type": "string"
+                },
+                "failed_at": {
+                    "type": "string"
+                },
+                "fulfilled_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -667,12 +2639,50 @@ const docTemplate = `{
                         "$ref": "#/definitions/internal_commerce_checkout_http.OrderItemDTO"
                     }
                 },
+                "net_paid": {
+                    "description": "total cobrado menos reembolsos (solo órdenes pagadas)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                        }
+                    ]
+                },
+                "no_show_at": {
+                    "type": "string"
+                },
                 "paid_at": {
                     "type": "string"
                 },
                 "payment_ref": {
                     "type": "string"
                 },
+                "pet_id": {
+                    "description": "ID del pet principal, si se resolvió por ID",
+                    "type": "string"
+                },
+                "pets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_checkout_http.OrderPetDTO"
+                    }
+                },
+                "processing_at": {
+                    "type": "string"
+                },
+                "refunded_amount": {
+                    "description": "suma de los reembolsos emitidos",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                        }
+                    ]
+                },
+                "refunded_at": {
+                    "type": "string"
+                },
+                "slot_id": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -684,6 +2694,41 @@ const docTemplate = `{
                 },
                 "total_discount": {
                     "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.OrderEventDTO": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "from_status": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
@@ -696,9 +2741,16 @@ const docTemplate = `{
                 "line_total": {
                     "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
                 },
+                "pet_id": {
+                    "type": "string"
+                },
                 "qty": {
                     "type": "integer"
                 },
+                "refunded": {
+                    "description": "la línea ya se reembolsó",
+                    "type": "boolean"
+                },
                 "type": {
                     "type": "string"
                 },
@@ -707,6 +2759,112 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_commerce_checkout_http.OrderPetDTO": {
+            "type": "object",
+            "properties": {
+                "pet_id": {
+                    "description": "vacío = pet_profile principal",
+                    "type": "string"
+                },
+                "pet_profile": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.PetProfileDTO"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.OrderResponseDTO": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.OrderDTO"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.PaymentIntentDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "method": {
+                    "description": "yape | plin",
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "provider_ref": {
+                    "type": "string"
+                },
+                "qr_data": {
+                    "description": "contenido a renderizar como QR",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending | succeeded | failed | expired",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.PaymentIntentResponseDTO": {
+            "type": "object",
+            "properties": {
+                "order_status": {
+                    "description": "paid cuando la transferencia ya se aplicó",
+                    "type": "string"
+                },
+                "payment": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.PaymentIntentDTO"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.PaymentWebhookResponseDTO": {
+            "type": "object",
+            "properties": {
+                "duplicate": {
+                    "type": "boolean"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "por qué se ignoró",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "processed | ignored",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.PetDTO": {
+            "type": "object",
+            "properties": {
+                "pet_id": {
+                    "type": "string"
+                },
+                "pet_profile": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.PetProfileDTO"
+                }
+            }
+        },
         "internal_commerce_checkout_http.PetProfileDTO": {
             "type": "object",
             "properties": {
@@ -756,8 +2914,17 @@ const docTemplate = `{
                         "$ref": "#/definitions/internal_commerce_checkout_http.ItemDTO"
                     }
                 },
+                "pet_id": {
+                    "type": "string"
+                },
                 "pet_profile": {
                     "$ref": "#/definitions/internal_commerce_checkout_http.PetProfileDTO"
+                },
+                "pets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_commerce_checkout_http.PetDTO"
+                    }
                 }
             }
         },
@@ -769,6 +2936,68 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_commerce_checkout_http.RefundDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.MoneyDTO"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "payment_ref": {
+                    "type": "string"
+                },
+                "provider_ref": {
+                    "type": "string"
+                },
+                "refund_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.RefundOrderRequestDTO": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "description": "posiciones de items; vacío = reembolso total",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "refund_key": {
+                    "description": "clave de idempotencia",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.RefundOrderResponseDTO": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.OrderDTO"
+                },
+                "refund": {
+                    "$ref": "#/definitions/internal_commerce_checkout_http.RefundDTO"
+                }
+            }
+        },
+        "internal_commerce_checkout_http.ResolveIncidentRequestDTO": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "description": "qué se hizo para resolverlo",
+                    "type": "string"
+                }
+            }
+        },
         "internal_commerce_checkout_http.StartCheckoutRequestDTO": {
             "type": "object",
             "properties": {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"paku-commerce/internal/commerce/cart/domain"
+	"paku-commerce/internal/platform/transaction"
 )
 
 // CartRepository implementa domain.CartRepository en memoria.
@@ -21,11 +22,21 @@ func NewCartRepository() *CartRepository {
 	}
 }
 
-// Upsert crea o actualiza un carrito.
+// Upsert crea o actualiza un carrito si su revisión coincide con la almacenada.
 func (r *CartRepository) Upsert(ctx context.Context, cart domain.Cart) (domain.Cart, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	stored, exists := r.carts[cart.UserID]
+	if !exists && cart.Revision != 0 {
+		return domain.Cart{}, domain.ErrCartNotFound
+	}
+	if exists && stored.Revision != cart.Revision {
+		return domain.Cart{}, domain.ErrCartRevisionConflict
+	}
+
+	cart.Revision++
+	r.recordUndo(ctx, cart.UserID, &cart)
 	r.carts[cart.UserID] = cart
 	return cart, nil
 }
@@ -47,6 +58,22 @@ func (r *CartRepository) DeleteByUserID(ctx context.Context, userID string) erro
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.recordUndo(ctx, userID, nil)
+	delete(r.carts, userID)
+	return nil
+}
+
+// DeleteIfRevision elimina el carrito solo si su revisión coincide.
+func (r *CartRepository) DeleteIfRevision(ctx context.Context, userID string, revision int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.carts[userID]
+	if !exists || stored.Revision != revision {
+		return domain.ErrCartRevisionConflict
+	}
+
+	r.recordUndo(ctx, userID, nil)
 	delete(r.carts, userID)
 	return nil
 }
@@ -64,3 +91,24 @@ func (r *CartRepository) ListExpired(ctx context.Context, now time.Time) ([]doma
 	}
 	return expired, nil
 }
+
+// recordUndo registra cómo restaurar el carrito de userID si la transacción se revierte.
+// written es lo que escribe la transacción (nil = lo elimina): solo se restaura si el
+// carrito sigue así, para no pisar lo que otro request escribió después.
+// Debe llamarse con el lock tomado, antes de escribir.
+func (r *CartRepository) recordUndo(ctx context.Context, userID string, written *domain.Cart) {
+	previous, existed := r.carts[userID]
+	transaction.RecordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		current, exists := r.carts[userID]
+		if exists != (written != nil) || (exists && current.Revision != written.Revision) {
+			return
+		}
+		if existed {
+			r.carts[userID] = previous
+		} else {
+			delete(r.carts, userID)
+		}
+	})
+}
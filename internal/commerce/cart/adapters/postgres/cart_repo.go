@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"paku-commerce/internal/commerce/cart/domain"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	dbpostgres "paku-commerce/internal/db/postgres"
+)
+
+// CartRepository implementa domain.CartRepository sobre PostgreSQL.
+type CartRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCartRepository crea un repositorio de carritos en PostgreSQL.
+func NewCartRepository(pool *pgxpool.Pool) *CartRepository {
+	return &CartRepository{pool: pool}
+}
+
+const selectCartSQL = `
+	SELECT id, user_id,
+	       pet_species, pet_weight_kg, pet_coat_type,
+	       booking_hold_id, order_id, coupon_code, updated_at, expires_at,
+	       revision, pet_id
+	FROM carts`
+
+// uniqueViolation es el SQLSTATE de PostgreSQL para claves duplicadas.
+const uniqueViolation = "23505"
+
+// Upsert crea o reemplaza el carrito del usuario (un carrito por user_id),
+// solo si cart.Revision coincide con la revisión almacenada.
+func (r *CartRepository) Upsert(ctx context.Context, cart domain.Cart) (domain.Cart, error) {
+	err := pgx.BeginFunc(ctx, dbpostgres.Conn(ctx, r.pool), func(tx pgx.Tx) error {
+		var stored int
+		err := tx.QueryRow(ctx, `SELECT revision FROM carts WHERE user_id = $1 FOR UPDATE`, cart.UserID).Scan(&stored)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			if cart.Revision != 0 {
+				return domain.ErrCartNotFound
+			}
+		case err != nil:
+			return fmt.Errorf("failed to lock cart: %w", err)
+		case stored != cart.Revision:
+			return domain.ErrCartRevisionConflict
+		}
+		cart.Revision++
+
+		// Un user_id tiene un solo carrito: si cambió el ID (carrito nuevo), el anterior se reemplaza.
+		if _, err := tx.Exec(ctx, `DELETE FROM carts WHERE user_id = $1 AND id <> $2`, cart.UserID, cart.ID); err != nil {
+			return fmt.Errorf("failed to replace cart: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO carts (
+				id, user_id,
+				pet_species, pet_weight_kg, pet_coat_type,
+				booking_hold_id, order_id, coupon_code, updated_at, expires_at,
+				revision, pet_id
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (id) DO UPDATE SET
+				user_id = EXCLUDED.user_id,
+				pet_species = EXCLUDED.pet_species,
+				pet_weight_kg = EXCLUDED.pet_weight_kg,
+				pet_coat_type = EXCLUDED.pet_coat_type,
+				booking_hold_id = EXCLUDED.booking_hold_id,
+				order_id = EXCLUDED.order_id,
+				coupon_code = EXCLUDED.coupon_code,
+				updated_at = EXCLUDED.updated_at,
+				expires_at = EXCLUDED.expires_at,
+				revision = EXCLUDED.revision,
+				pet_id = EXCLUDED.pet_id`,
+			cart.ID, cart.UserID,
+			cart.PetProfile.Species, cart.PetProfile.WeightKg, cart.PetProfile.CoatType,
+			cart.BookingHoldID, cart.OrderID, cart.CouponCode, cart.UpdatedAt, cart.ExpiresAt,
+			cart.Revision, cart.PetID,
+		)
+		if err != nil {
+			// Dos creaciones concurrentes para el mismo usuario: gana la primera.
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+				return domain.ErrCartRevisionConflict
+			}
+			return fmt.Errorf("failed to upsert cart: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM cart_items WHERE cart_id = $1`, cart.ID); err != nil {
+			return fmt.Errorf("failed to delete cart items: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM cart_pets WHERE cart_id = $1`, cart.ID); err != nil {
+			return fmt.Errorf("failed to delete cart pets: %w", err)
+		}
+
+		for i, pet := range cart.Pets {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO cart_pets (cart_id, position, pet_id, pet_species, pet_weight_kg, pet_coat_type)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+				cart.ID, i, pet.ID, pet.PetProfile.Species, pet.PetProfile.WeightKg, pet.PetProfile.CoatType,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert cart pet: %w", err)
+			}
+		}
+
+		for i, item := range cart.Items {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO cart_items (cart_id, position, item_type, item_id, qty, pet_id)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+				cart.ID, i, string(item.ItemType), item.ItemID, item.Qty, item.PetID,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert cart item: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	return cart, nil
+}
+
+// GetByUserID obtiene un carrito por user_id.
+func (r *CartRepository) GetByUserID(ctx context.Context, userID string) (domain.Cart, error) {
+	row := dbpostgres.Conn(ctx, r.pool).QueryRow(ctx, selectCartSQL+` WHERE user_id = $1`, userID)
+
+	cart, err := scanCart(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Cart{}, domain.ErrCartNotFound
+	}
+	if err != nil {
+		return domain.Cart{}, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	petsByCart, err := r.listPets(ctx, []string{cart.ID})
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	cart.Pets = petsByCart[cart.ID]
+
+	itemsByCart, err := r.listItems(ctx, []string{cart.ID})
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	cart.Items = itemsByCart[cart.ID]
+
+	return cart, nil
+}
+
+// DeleteByUserID elimina un carrito (los items se borran en cascada).
+func (r *CartRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	if _, err := dbpostgres.Conn(ctx, r.pool).Exec(ctx, `DELETE FROM carts WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	return nil
+}
+
+// DeleteIfRevision elimina el carrito solo si su revisión coincide.
+func (r *CartRepository) DeleteIfRevision(ctx context.Context, userID string, revision int) error {
+	tag, err := dbpostgres.Conn(ctx, r.pool).Exec(ctx, `DELETE FROM carts WHERE user_id = $1 AND revision = $2`, userID, revision)
+	if err != nil {
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrCartRevisionConflict
+	}
+	return nil
+}
+
+// ListExpired retorna carritos vencidos.
+func (r *CartRepository) ListExpired(ctx context.Context, now time.Time) ([]domain.Cart, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, selectCartSQL+` WHERE expires_at < $1 ORDER BY expires_at`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired carts: %w", err)
+	}
+
+	carts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Cart, error) {
+		return scanCart(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired carts: %w", err)
+	}
+	if len(carts) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(carts))
+	for _, cart := range carts {
+		ids = append(ids, cart.ID)
+	}
+
+	petsByCart, err := r.listPets(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	itemsByCart, err := r.listItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range carts {
+		carts[i].Pets = petsByCart[carts[i].ID]
+		carts[i].Items = itemsByCart[carts[i].ID]
+	}
+
+	return carts, nil
+}
+
+func (r *CartRepository) listItems(ctx context.Context, cartIDs []string) (map[string][]checkoutdomain.PurchaseItem, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT cart_id, item_type, item_id, qty, pet_id
+		FROM cart_items
+		WHERE cart_id = ANY($1)
+		ORDER BY cart_id, position`, cartIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cart items: %w", err)
+	}
+	defer rows.Close()
+
+	itemsByCart := make(map[string][]checkoutdomain.PurchaseItem, len(cartIDs))
+	for rows.Next() {
+		var (
+			cartID, itemType string
+			item             checkoutdomain.PurchaseItem
+		)
+		if err := rows.Scan(&cartID, &itemType, &item.ItemID, &item.Qty, &item.PetID); err != nil {
+			return nil, fmt.Errorf("failed to scan cart item: %w", err)
+		}
+		item.ItemType = checkoutdomain.ItemType(itemType)
+		itemsByCart[cartID] = append(itemsByCart[cartID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list cart items: %w", err)
+	}
+
+	return itemsByCart, nil
+}
+
+func (r *CartRepository) listPets(ctx context.Context, cartIDs []string) (map[string][]checkoutdomain.Pet, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT cart_id, pet_id, pet_species, pet_weight_kg, pet_coat_type
+		FROM cart_pets
+		WHERE cart_id = ANY($1)
+		ORDER BY cart_id, position`, cartIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cart pets: %w", err)
+	}
+	defer rows.Close()
+
+	petsByCart := make(map[string][]checkoutdomain.Pet, len(cartIDs))
+	for rows.Next() {
+		var (
+			cartID string
+			pet    checkoutdomain.Pet
+		)
+		if err := rows.Scan(&cartID, &pet.ID, &pet.PetProfile.Species, &pet.PetProfile.WeightKg, &pet.PetProfile.CoatType); err != nil {
+			return nil, fmt.Errorf("failed to scan cart pet: %w", err)
+		}
+		petsByCart[cartID] = append(petsByCart[cartID], pet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list cart pets: %w", err)
+	}
+
+	return petsByCart, nil
+}
+
+func scanCart(row pgx.Row) (domain.Cart, error) {
+	var cart domain.Cart
+	err := row.Scan(
+		&cart.ID, &cart.UserID,
+		&cart.PetProfile.Species, &cart.PetProfile.WeightKg, &cart.PetProfile.CoatType,
+		&cart.BookingHoldID, &cart.OrderID, &cart.CouponCode, &cart.UpdatedAt, &cart.ExpiresAt,
+		&cart.Revision, &cart.PetID,
+	)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return cart, nil
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"paku-commerce/internal/commerce/cart/domain"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	"paku-commerce/internal/db/postgres/pgtest"
+	"paku-commerce/internal/platform/id"
+)
+
+func TestCartRepository_RoundTripAndExpire(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewCartRepository(pool)
+	ctx := context.Background()
+
+	userID := "user_" + id.NewRequestID()
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	holdID := "hold_pg_1"
+
+	cart := domain.NewCart(userID, servicedomain.PetProfile{
+		Species:  servicedomain.SpeciesDog,
+		WeightKg: 15,
+		CoatType: servicedomain.CoatTypeDouble,
+	}, []checkoutdomain.PurchaseItem{
+		{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+		{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1},
+		{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1, PetID: "luna"},
+	}, now)
+	cart.PetID = "pet_max"
+	cart.Pets = []checkoutdomain.Pet{
+		{ID: "luna", PetProfile: servicedomain.PetProfile{Species: servicedomain.SpeciesCat, WeightKg: 4, CoatType: servicedomain.CoatTypeShort}},
+	}
+	cart.BookingHoldID = &holdID
+	coupon := "BANO10"
+	cart.CouponCode = &coupon
+
+	cart, err := repo.Upsert(ctx, cart)
+	if err != nil {
+		t.Fatalf("unexpected error on upsert: %v", err)
+	}
+	if cart.Revision != 1 {
+		t.Errorf("expected revision 1 after create, got: %d", cart.Revision)
+	}
+
+	got, err := repo.GetByUserID(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error on get: %v", err)
+	}
+	got.UpdatedAt = got.UpdatedAt.UTC()
+	got.ExpiresAt = got.ExpiresAt.UTC()
+	if !reflect.DeepEqual(got, cart) {
+		t.Errorf("cart did not round-trip:\n got: %+v\nwant: %+v", got, cart)
+	}
+
+	// Una revisión desactualizada no sobreescribe
+	stale := domain.NewCart(userID, cart.PetProfile, cart.Items[:1], now)
+	if _, err := repo.Upsert(ctx, stale); err != domain.ErrCartRevisionConflict {
+		t.Fatalf("expected ErrCartRevisionConflict, got: %v", err)
+	}
+
+	// Un carrito nuevo para el mismo usuario reemplaza al anterior
+	replacement := domain.NewCart(userID, cart.PetProfile, cart.Items[:1], now)
+	replacement.Revision = got.Revision
+	if _, err := repo.Upsert(ctx, replacement); err != nil {
+		t.Fatalf("unexpected error on replace: %v", err)
+	}
+	got, err = repo.GetByUserID(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error on get: %v", err)
+	}
+	if got.ID != replacement.ID || len(got.Items) != 1 {
+		t.Errorf("expected replacement cart with 1 item, got: %s with %d items", got.ID, len(got.Items))
+	}
+
+	// ListExpired incluye el carrito vencido con sus items
+	expired, err := repo.ListExpired(ctx, now.Add(domain.CartTTL+time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error on list expired: %v", err)
+	}
+	found := false
+	for _, c := range expired {
+		if c.ID == replacement.ID {
+			found = true
+			if len(c.Items) != 1 {
+				t.Errorf("expected expired cart to carry its items")
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected cart in expired list")
+	}
+
+	if err := repo.DeleteIfRevision(ctx, userID, got.Revision-1); err != domain.ErrCartRevisionConflict {
+		t.Fatalf("expected ErrCartRevisionConflict on stale delete, got: %v", err)
+	}
+	if err := repo.DeleteIfRevision(ctx, userID, got.Revision); err != nil {
+		t.Fatalf("unexpected error on delete: %v", err)
+	}
+	if _, err := repo.GetByUserID(ctx, userID); err != domain.ErrCartNotFound {
+		t.Errorf("expected ErrCartNotFound after delete, got: %v", err)
+	}
+}
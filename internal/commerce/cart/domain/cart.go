@@ -15,12 +15,16 @@ const CartTTL = 90 * time.Minute
 type Cart struct {
 	ID            string
 	UserID        string
-	PetProfile    servicedomain.PetProfile
+	PetID         string                   // ID del pet principal en el servicio de mascotas ("" = perfil inline)
+	PetProfile    servicedomain.PetProfile // pet principal (items sin PetID)
+	Pets          []checkoutdomain.Pet     // mascotas adicionales (items con PetID)
 	Items         []checkoutdomain.PurchaseItem
 	BookingHoldID *string
 	OrderID       *string
+	CouponCode    *string // normalizado (ver promotions.NormalizeCode)
 	UpdatedAt     time.Time
 	ExpiresAt     time.Time
+	Revision      int // se incrementa en cada escritura (lo gestiona el repositorio)
 }
 
 // NewCart crea un nuevo carrito para un usuario.
@@ -43,6 +47,104 @@ func (c *Cart) UpdateCart(petProfile servicedomain.PetProfile, items []checkoutd
 	c.ExpiresAt = now.Add(CartTTL)
 }
 
+// AddItem agrega un item al carrito; si ya existe (misma mascota, tipo e ID) suma la cantidad.
+// Renueva TTL.
+func (c *Cart) AddItem(item checkoutdomain.PurchaseItem, now time.Time) error {
+	if err := validateItem(item.ItemType, item.ItemID, item.Qty); err != nil {
+		return err
+	}
+
+	items := append([]checkoutdomain.PurchaseItem(nil), c.Items...)
+	if i := c.indexOf(item.PetID, item.ItemType, item.ItemID); i >= 0 {
+		items[i].Qty += item.Qty
+	} else {
+		items = append(items, item)
+	}
+
+	c.UpdateCart(c.PetProfile, items, now)
+	return nil
+}
+
+// SetItemQty cambia la cantidad de un item existente de la mascota petID ("" = pet principal).
+// Renueva TTL.
+func (c *Cart) SetItemQty(petID string, itemType checkoutdomain.ItemType, itemID string, qty int, now time.Time) error {
+	if err := validateItem(itemType, itemID, qty); err != nil {
+		return err
+	}
+
+	i := c.indexOf(petID, itemType, itemID)
+	if i < 0 {
+		return ErrCartItemNotFound
+	}
+
+	items := append([]checkoutdomain.PurchaseItem(nil), c.Items...)
+	items[i].Qty = qty
+
+	c.UpdateCart(c.PetProfile, items, now)
+	return nil
+}
+
+// RemoveItem quita un item de la mascota petID ("" = pet principal). Renueva TTL.
+// El carrito puede quedar vacío (el checkout lo rechaza).
+func (c *Cart) RemoveItem(petID string, itemType checkoutdomain.ItemType, itemID string, now time.Time) error {
+	i := c.indexOf(petID, itemType, itemID)
+	if i < 0 {
+		return ErrCartItemNotFound
+	}
+
+	items := make([]checkoutdomain.PurchaseItem, 0, len(c.Items)-1)
+	items = append(items, c.Items[:i]...)
+	items = append(items, c.Items[i+1:]...)
+
+	c.UpdateCart(c.PetProfile, items, now)
+	return nil
+}
+
+func (c Cart) indexOf(petID string, itemType checkoutdomain.ItemType, itemID string) int {
+	for i, item := range c.Items {
+		if item.PetID == petID && item.ItemType == itemType && item.ItemID == itemID {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateItem(itemType checkoutdomain.ItemType, itemID string, qty int) error {
+	if itemType != checkoutdomain.ItemTypeService && itemType != checkoutdomain.ItemTypeProduct {
+		return ErrInvalidItem
+	}
+	if itemID == "" {
+		return ErrInvalidItem
+	}
+	if qty <= 0 {
+		return ErrInvalidQty
+	}
+	return nil
+}
+
+// ApplyCoupon guarda el código de cupón (ya validado y normalizado). Renueva TTL.
+func (c *Cart) ApplyCoupon(code string, now time.Time) {
+	c.CouponCode = &code
+	c.UpdateCart(c.PetProfile, c.Items, now)
+}
+
+// RemoveCoupon quita el cupón del carrito. Renueva TTL.
+func (c *Cart) RemoveCoupon(now time.Time) {
+	c.CouponCode = nil
+	c.UpdateCart(c.PetProfile, c.Items, now)
+}
+
+// PurchaseIntent arma la intención de compra con las mascotas, items y cupón del carrito.
+func (c Cart) PurchaseIntent() checkoutdomain.PurchaseIntent {
+	return checkoutdomain.PurchaseIntent{
+		PetID:      c.PetID,
+		PetProfile: c.PetProfile,
+		Pets:       c.Pets,
+		Items:      c.Items,
+		CouponCode: c.CouponCode,
+	}
+}
+
 // IsExpired verifica si el carrito está vencido.
 func (c Cart) IsExpired(now time.Time) bool {
 	return now.After(c.ExpiresAt)
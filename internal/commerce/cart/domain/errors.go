@@ -1,9 +1,36 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
 
 var (
 	ErrCartNotFound  = errors.New("cart not found")
 	ErrInvalidUserID = errors.New("user_id is required")
 	ErrEmptyItems    = errors.New("items cannot be empty")
+
+	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrInvalidItem      = errors.New("item type must be service or product and id is required")
+	ErrInvalidQty       = errors.New("qty must be greater than zero")
+
+	// ErrInvalidCartItems indica que uno o más items no cumplen las reglas del catálogo.
+	ErrInvalidCartItems = errors.New("cart contains invalid items")
+
+	// ErrCartRevisionConflict indica que la revisión esperada no coincide con la almacenada.
+	ErrCartRevisionConflict = errors.New("cart revision does not match")
 )
+
+// InvalidItemsError detalla las violaciones por item. errors.Is(err, ErrInvalidCartItems) es true.
+type InvalidItemsError struct {
+	Violations []checkoutdomain.ItemViolation
+}
+
+func (e *InvalidItemsError) Error() string {
+	return ErrInvalidCartItems.Error()
+}
+
+func (e *InvalidItemsError) Unwrap() error {
+	return ErrInvalidCartItems
+}
@@ -7,8 +7,13 @@ import (
 
 // CartRepository define el acceso a carritos.
 type CartRepository interface {
+	// Upsert es un compare-and-swap por revisión: cart.Revision debe ser la almacenada
+	// (0 si el carrito no existe). Retorna el carrito con la revisión incrementada
+	// o ErrCartRevisionConflict.
 	Upsert(ctx context.Context, cart Cart) (Cart, error)
 	GetByUserID(ctx context.Context, userID string) (Cart, error)
 	DeleteByUserID(ctx context.Context, userID string) error
+	// DeleteIfRevision elimina el carrito solo si su revisión coincide.
+	DeleteIfRevision(ctx context.Context, userID string, revision int) error
 	ListExpired(ctx context.Context, now time.Time) ([]Cart, error)
 }
@@ -1,11 +1,15 @@
 package http
 
 import (
+	"errors"
 	"time"
 
 	cartdomain "paku-commerce/internal/commerce/cart/domain"
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
 	servicedomain "paku-commerce/internal/commerce/service/domain"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+	pricingusecases "paku-commerce/internal/pricing/usecases"
 )
 
 // PetProfileDTO representa el perfil de mascota.
@@ -15,36 +19,99 @@ type PetProfileDTO struct {
 	CoatType string `json:"coat_type"`
 }
 
+// PetDTO representa una mascota adicional del carrito.
+// Sin pet_profile, el perfil se resuelve por pet_id en el servicio de mascotas.
+type PetDTO struct {
+	PetID      string        `json:"pet_id"`
+	PetProfile PetProfileDTO `json:"pet_profile"`
+}
+
 // ItemDTO representa un item del carrito.
 type ItemDTO struct {
-	Type string `json:"type"`
-	ID   string `json:"id"`
-	Qty  int    `json:"qty"`
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Qty   int    `json:"qty"`
+	PetID string `json:"pet_id,omitempty"` // vacío = pet_profile principal
 }
 
 // UpsertCartRequestDTO es el request para PUT /cart/me.
+// Se envía pet_profile inline o solo pet_id (el perfil se resuelve server-side).
 type UpsertCartRequestDTO struct {
+	PetID         string        `json:"pet_id,omitempty"`
 	PetProfile    PetProfileDTO `json:"pet_profile"`
+	Pets          []PetDTO      `json:"pets,omitempty"`
 	Items         []ItemDTO     `json:"items"`
 	BookingHoldID *string       `json:"booking_hold_id,omitempty"`
 	OrderID       *string       `json:"order_id,omitempty"`
 }
 
+// UpdateCartItemRequestDTO es el request para PATCH /cart/me/items/{type}/{id}.
+type UpdateCartItemRequestDTO struct {
+	Qty int `json:"qty"`
+}
+
+// ApplyCouponRequestDTO es el request para PUT /cart/me/coupon.
+type ApplyCouponRequestDTO struct {
+	Code string `json:"code"`
+}
+
 // CartDTO representa un carrito.
 type CartDTO struct {
 	ID            string        `json:"id"`
 	UserID        string        `json:"user_id"`
+	PetID         string        `json:"pet_id,omitempty"`
 	PetProfile    PetProfileDTO `json:"pet_profile"`
+	Pets          []PetDTO      `json:"pets,omitempty"`
 	Items         []ItemDTO     `json:"items"`
 	BookingHoldID *string       `json:"booking_hold_id,omitempty"`
 	OrderID       *string       `json:"order_id,omitempty"`
+	CouponCode    *string       `json:"coupon_code,omitempty"`
 	UpdatedAt     string        `json:"updated_at"`
 	ExpiresAt     string        `json:"expires_at"`
+	Revision      int           `json:"revision"`
+}
+
+// MoneyDTO representa dinero en HTTP.
+type MoneyDTO struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// DiscountLineDTO representa una línea de descuento.
+type DiscountLineDTO struct {
+	Source string   `json:"source"` // "coupon" | "promotion"
+	Name   string   `json:"name"`
+	Amount MoneyDTO `json:"amount"`
+}
+
+// CartQuoteLineDTO representa un item cotizado del carrito.
+type CartQuoteLineDTO struct {
+	Type      string   `json:"type"`
+	ID        string   `json:"id"`
+	Qty       int      `json:"qty"`
+	UnitPrice MoneyDTO `json:"unit_price"`
+	LineTotal MoneyDTO `json:"line_total"`
+	PetID     string   `json:"pet_id,omitempty"`
+}
+
+// CartQuoteDTO es la cotización vigente del carrito (GET /cart/me?include=quote).
+// Issues lista los items que ya no son válidos; no se incluyen en Lines ni en los totales.
+type CartQuoteDTO struct {
+	Lines         []CartQuoteLineDTO `json:"lines"`
+	Subtotal      MoneyDTO           `json:"subtotal"`
+	Discounts     []DiscountLineDTO  `json:"discounts"`
+	TotalDiscount MoneyDTO           `json:"total_discount"`
+	Total         MoneyDTO           `json:"total"`
+	Issues        []ItemErrorDTO     `json:"issues"`
+	CouponError   *ErrorDTO          `json:"coupon_error,omitempty"` // el cupón guardado ya no aplica
 }
 
 // CartResponseDTO es el response para cart operations.
 type CartResponseDTO struct {
-	Cart CartDTO `json:"cart"`
+	Cart  CartDTO       `json:"cart"`
+	Quote *CartQuoteDTO `json:"quote,omitempty"`
+	// RemovedItems lista los items quitados al cambiar el pet_profile (PUT /cart/me).
+	RemovedItems []ItemErrorDTO `json:"removed_items,omitempty"`
 }
 
 // ExpireRequestDTO es el request opcional para POST /cart/expire.
@@ -54,11 +121,25 @@ type ExpireRequestDTO struct {
 
 // ExpireResponseDTO es el response para POST /cart/expire.
 type ExpireResponseDTO struct {
-	ExpiredCount int `json:"expired_count"`
+	ExpiredCount    int  `json:"expired_count"`
+	CancelledHolds  int  `json:"cancelled_holds"`
+	CancelledOrders int  `json:"cancelled_orders"`
+	Skipped         bool `json:"skipped,omitempty"` // otra réplica estaba expirando carritos
 }
 
 // ErrorDTO representa los detalles de un error.
 type ErrorDTO struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Details []ItemErrorDTO `json:"details,omitempty"`
+}
+
+// ItemErrorDTO identifica un item inválido del carrito para resaltarlo en el cliente.
+type ItemErrorDTO struct {
+	Index   int    `json:"index"`
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	PetID   string `json:"pet_id,omitempty"`
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
@@ -76,6 +157,25 @@ func (dto PetProfileDTO) toPetProfile() servicedomain.PetProfile {
 	}
 }
 
+func toPetProfileDTO(pet servicedomain.PetProfile) PetProfileDTO {
+	return PetProfileDTO{
+		Species:  pet.Species,
+		WeightKg: pet.WeightKg,
+		CoatType: pet.CoatType,
+	}
+}
+
+func toPets(dtos []PetDTO) []checkoutdomain.Pet {
+	if len(dtos) == 0 {
+		return nil
+	}
+	pets := make([]checkoutdomain.Pet, 0, len(dtos))
+	for _, dto := range dtos {
+		pets = append(pets, checkoutdomain.Pet{ID: dto.PetID, PetProfile: dto.PetProfile.toPetProfile()})
+	}
+	return pets
+}
+
 func toPurchaseItems(dtos []ItemDTO) []checkoutdomain.PurchaseItem {
 	items := make([]checkoutdomain.PurchaseItem, 0, len(dtos))
 	for _, dto := range dtos {
@@ -83,33 +183,122 @@ func toPurchaseItems(dtos []ItemDTO) []checkoutdomain.PurchaseItem {
 			ItemType: checkoutdomain.ItemType(dto.Type),
 			ItemID:   dto.ID,
 			Qty:      dto.Qty,
+			PetID:    dto.PetID,
 		})
 	}
 	return items
 }
 
+func toMoneyDTO(m pricingdomain.Money) MoneyDTO {
+	return MoneyDTO{
+		Amount:   m.Amount,
+		Currency: string(m.Currency),
+	}
+}
+
+func toCartQuoteDTO(quote checkoutusecases.CheckoutQuote, issues []checkoutdomain.ItemViolation, couponErr error) CartQuoteDTO {
+	lines := make([]CartQuoteLineDTO, 0, len(quote.Quote.Items))
+	for _, item := range quote.Quote.Items {
+		lines = append(lines, CartQuoteLineDTO{
+			Type:      string(item.ItemType),
+			ID:        item.ItemID,
+			Qty:       item.Qty,
+			UnitPrice: toMoneyDTO(item.UnitPrice),
+			LineTotal: toMoneyDTO(item.LineTotal),
+			PetID:     item.PetID,
+		})
+	}
+
+	discounts := make([]DiscountLineDTO, 0, len(quote.Discounts))
+	for _, d := range quote.Discounts {
+		discounts = append(discounts, DiscountLineDTO{
+			Source: d.Source,
+			Name:   d.Name,
+			Amount: toMoneyDTO(d.Amount),
+		})
+	}
+
+	dto := CartQuoteDTO{
+		Lines:         lines,
+		Subtotal:      toMoneyDTO(quote.OriginalSubtotal),
+		Discounts:     discounts,
+		TotalDiscount: toMoneyDTO(quote.TotalDiscount),
+		Total:         toMoneyDTO(quote.Total),
+		Issues:        toItemErrorDTOs(issues),
+	}
+	if couponErr != nil {
+		code, msg := mapErrorToCodeAndMessage(couponErr)
+		dto.CouponError = &ErrorDTO{Code: code, Message: msg}
+	}
+	return dto
+}
+
+func toItemErrorDTOs(violations []checkoutdomain.ItemViolation) []ItemErrorDTO {
+	dtos := make([]ItemErrorDTO, 0, len(violations))
+	for _, v := range violations {
+		dtos = append(dtos, ItemErrorDTO{
+			Index:   v.Index,
+			Type:    string(v.Item.ItemType),
+			ID:      v.Item.ItemID,
+			PetID:   v.Item.PetID,
+			Code:    itemViolationCode(v.Reason),
+			Message: v.Reason.Error(),
+		})
+	}
+	return dtos
+}
+
+func itemViolationCode(reason error) string {
+	switch {
+	case errors.Is(reason, checkoutusecases.ErrUnknownService):
+		return "unknown_service"
+	case errors.Is(reason, checkoutusecases.ErrServiceNotEligible):
+		return "service_not_eligible"
+	case errors.Is(reason, checkoutusecases.ErrMissingParentService):
+		return "missing_parent_service"
+	case errors.Is(reason, checkoutusecases.ErrInvalidQuantity):
+		return "invalid_quantity"
+	case errors.Is(reason, checkoutusecases.ErrUnknownProduct):
+		return "unknown_product"
+	case errors.Is(reason, checkoutusecases.ErrUnknownItemType):
+		return "unknown_item_type"
+	case errors.Is(reason, pricingusecases.ErrNoPriceRule):
+		return "unpriced"
+	case errors.Is(reason, checkoutusecases.ErrUnknownPet):
+		return "unknown_pet"
+	default:
+		return "invalid_item"
+	}
+}
+
 func toCartDTO(cart cartdomain.Cart) CartDTO {
 	items := make([]ItemDTO, 0, len(cart.Items))
 	for _, item := range cart.Items {
 		items = append(items, ItemDTO{
-			Type: string(item.ItemType),
-			ID:   item.ItemID,
-			Qty:  item.Qty,
+			Type:  string(item.ItemType),
+			ID:    item.ItemID,
+			Qty:   item.Qty,
+			PetID: item.PetID,
 		})
 	}
 
+	var pets []PetDTO
+	for _, pet := range cart.Pets {
+		pets = append(pets, PetDTO{PetID: pet.ID, PetProfile: toPetProfileDTO(pet.PetProfile)})
+	}
+
 	return CartDTO{
-		ID:     cart.ID,
-		UserID: cart.UserID,
-		PetProfile: PetProfileDTO{
-			Species:  cart.PetProfile.Species,
-			WeightKg: cart.PetProfile.WeightKg,
-			CoatType: cart.PetProfile.CoatType,
-		},
+		ID:            cart.ID,
+		UserID:        cart.UserID,
+		PetID:         cart.PetID,
+		PetProfile:    toPetProfileDTO(cart.PetProfile),
+		Pets:          pets,
 		Items:         items,
 		BookingHoldID: cart.BookingHoldID,
 		OrderID:       cart.OrderID,
+		CouponCode:    cart.CouponCode,
 		UpdatedAt:     cart.UpdatedAt.Format(time.RFC3339),
 		ExpiresAt:     cart.ExpiresAt.Format(time.RFC3339),
+		Revision:      cart.Revision,
 	}
 }
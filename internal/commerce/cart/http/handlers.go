@@ -1,34 +1,60 @@
 package http
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+
 	cartdomain "paku-commerce/internal/commerce/cart/domain"
 	cartusecases "paku-commerce/internal/commerce/cart/usecases"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
+	"paku-commerce/internal/platform/audit"
+	promotionsdomain "paku-commerce/internal/promotions/domain"
+	promotionsusecases "paku-commerce/internal/promotions/usecases"
 )
 
 // CartHandlers contiene los handlers de cart.
 type CartHandlers struct {
-	UpsertCartUC  *cartusecases.UpsertCart
-	GetCartUC     *cartusecases.GetCart
-	DeleteCartUC  *cartusecases.DeleteCart
-	ExpireCartsUC *cartusecases.ExpireCarts
+	UpsertCartUC       *cartusecases.UpsertCart
+	GetCartUC          *cartusecases.GetCart
+	QuoteCartUC        *cartusecases.QuoteCart
+	DeleteCartUC       *cartusecases.DeleteCart
+	AddCartItemUC      *cartusecases.AddCartItem
+	UpdateCartItemUC   *cartusecases.UpdateCartItem
+	RemoveCartItemUC   *cartusecases.RemoveCartItem
+	ApplyCartCouponUC  *cartusecases.ApplyCartCoupon
+	RemoveCartCouponUC *cartusecases.RemoveCartCoupon
+	ExpireCartsUC      *cartusecases.ExpireCarts
+	AdminToken         string // habilita POST /cart/expire ("" = deshabilitado)
 }
 
 // HandleUpsertCart maneja PUT /cart/me.
 // @Summary      Upsert cart
-// @Description  Crear o actualizar el carrito del usuario
+// @Description  Crear o actualizar el carrito del usuario. Con pets se agregan mascotas adicionales
+// @Description  (sus items llevan pet_id). Si cambia el perfil de una mascota, sus items que
+// @Description  dejan de aplicar se quitan y se informan en removed_items. Con pet_id (sin pet_profile)
+// @Description  el perfil se resuelve en el servicio de mascotas.
 // @Tags         cart
 // @Accept       json
 // @Produce      json
 // @Param        X-User-ID  header    string              true  "User ID"
+// @Param        If-Match   header    string              false "ETag del carrito (GET /cart/me)"
 // @Param        body       body      UpsertCartRequestDTO  true  "Cart data"
 // @Success      200        {object}  CartResponseDTO
+// @Header       200        {string}  ETag  "Revisión del carrito"
 // @Failure      400        {object}  ErrorResponse
+// @Failure      412        {object}  ErrorResponse
+// @Failure      422        {object}  ErrorResponse
 // @Failure      500        {object}  ErrorResponse
+// @Failure      503        {object}  ErrorResponse
 // @Router       /api/v1/commerce/cart/me [put]
 // @Security     UserID
 func (h *CartHandlers) HandleUpsertCart(w http.ResponseWriter, r *http.Request) {
@@ -38,6 +64,12 @@ func (h *CartHandlers) HandleUpsertCart(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	expectedRevision, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
 	var req UpsertCartRequestDTO
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "bad_request", "invalid JSON")
@@ -51,31 +83,40 @@ func (h *CartHandlers) HandleUpsertCart(w http.ResponseWriter, r *http.Request)
 	}
 
 	input := cartusecases.UpsertCartInput{
-		UserID:        userID,
-		PetProfile:    req.PetProfile.toPetProfile(),
-		Items:         toPurchaseItems(req.Items),
-		BookingHoldID: req.BookingHoldID,
-		OrderID:       req.OrderID,
+		UserID:           userID,
+		PetID:            req.PetID,
+		PetProfile:       req.PetProfile.toPetProfile(),
+		Pets:             toPets(req.Pets),
+		Items:            toPurchaseItems(req.Items),
+		BookingHoldID:    req.BookingHoldID,
+		OrderID:          req.OrderID,
+		ExpectedRevision: expectedRevision,
 	}
 
 	output, err := h.UpsertCartUC.Execute(r.Context(), input)
 	if err != nil {
-		code, msg := mapErrorToCodeAndMessage(err)
-		respondError(w, mapErrorToHTTPStatus(err), code, msg)
+		respondUsecaseError(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", cartETag(output.Cart))
 	resp := CartResponseDTO{Cart: toCartDTO(output.Cart)}
+	if len(output.Removed) > 0 {
+		resp.RemovedItems = toItemErrorDTOs(output.Removed)
+	}
 	respondJSON(w, http.StatusOK, resp)
 }
 
 // HandleGetCart maneja GET /cart/me.
 // @Summary      Get cart
-// @Description  Obtener el carrito del usuario
+// @Description  Obtener el carrito del usuario. Con include=quote agrega la cotización vigente
+// @Description  (precio por línea, descuentos, total) y marca los items que ya no son válidos.
 // @Tags         cart
 // @Produce      json
-// @Param        X-User-ID  header    string  true  "User ID"
+// @Param        X-User-ID  header    string  true   "User ID"
+// @Param        include    query     string  false  "quote"
 // @Success      200        {object}  CartResponseDTO
+// @Header       200        {string}  ETag  "Revisión del carrito (usar en If-Match)"
 // @Failure      404        {object}  ErrorResponse
 // @Failure      500        {object}  ErrorResponse
 // @Router       /api/v1/commerce/cart/me [get]
@@ -87,14 +128,29 @@ func (h *CartHandlers) HandleGetCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Get("include") == "quote" {
+		input := cartusecases.QuoteCartInput{UserID: userID}
+		output, err := h.QuoteCartUC.Execute(r.Context(), input)
+		if err != nil {
+			respondUsecaseError(w, err)
+			return
+		}
+
+		quote := toCartQuoteDTO(output.Quote, output.Issues, output.CouponError)
+		w.Header().Set("ETag", cartETag(output.Cart))
+		resp := CartResponseDTO{Cart: toCartDTO(output.Cart), Quote: &quote}
+		respondJSON(w, http.StatusOK, resp)
+		return
+	}
+
 	input := cartusecases.GetCartInput{UserID: userID}
 	output, err := h.GetCartUC.Execute(r.Context(), input)
 	if err != nil {
-		code, msg := mapErrorToCodeAndMessage(err)
-		respondError(w, mapErrorToHTTPStatus(err), code, msg)
+		respondUsecaseError(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", cartETag(output.Cart))
 	resp := CartResponseDTO{Cart: toCartDTO(output.Cart)}
 	respondJSON(w, http.StatusOK, resp)
 }
@@ -104,7 +160,9 @@ func (h *CartHandlers) HandleGetCart(w http.ResponseWriter, r *http.Request) {
 // @Description  Eliminar el carrito del usuario
 // @Tags         cart
 // @Param        X-User-ID  header  string  true  "User ID"
+// @Param        If-Match   header  string  false "ETag del carrito (GET /cart/me)"
 // @Success      204        "No Content"
+// @Failure      412        {object}  ErrorResponse
 // @Failure      500        {object}  ErrorResponse
 // @Router       /api/v1/commerce/cart/me [delete]
 // @Security     UserID
@@ -115,29 +173,314 @@ func (h *CartHandlers) HandleDeleteCart(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	input := cartusecases.DeleteCartInput{UserID: userID}
-	_, err := h.DeleteCartUC.Execute(r.Context(), input)
+	expectedRevision, err := parseIfMatch(r)
 	if err != nil {
-		code, msg := mapErrorToCodeAndMessage(err)
-		respondError(w, mapErrorToHTTPStatus(err), code, msg)
+		respondError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	input := cartusecases.DeleteCartInput{UserID: userID, ExpectedRevision: expectedRevision}
+	_, err = h.DeleteCartUC.Execute(r.Context(), input)
+	if err != nil {
+		respondUsecaseError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// HandleAddCartItem maneja POST /cart/me/items.
+// @Summary      Add cart item
+// @Description  Agregar un item al carrito (si ya existe, suma la cantidad)
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    string   true   "User ID"
+// @Param        If-Match   header    string   false  "ETag del carrito (GET /cart/me)"
+// @Param        body       body      ItemDTO  true   "Item"
+// @Success      200        {object}  CartResponseDTO
+// @Header       200        {string}  ETag  "Revisión del carrito"
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      412        {object}  ErrorResponse
+// @Failure      422        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /api/v1/commerce/cart/me/items [post]
+// @Security     UserID
+func (h *CartHandlers) HandleAddCartItem(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "bad_request", "X-User-ID header is required")
+		return
+	}
+
+	expectedRevision, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	var req ItemDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", "invalid JSON")
+		return
+	}
+
+	input := cartusecases.AddCartItemInput{
+		UserID:           userID,
+		Item:             toPurchaseItems([]ItemDTO{req})[0],
+		ExpectedRevision: expectedRevision,
+	}
+
+	output, err := h.AddCartItemUC.Execute(r.Context(), input)
+	if err != nil {
+		respondUsecaseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(output.Cart))
+	resp := CartResponseDTO{Cart: toCartDTO(output.Cart)}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// HandleUpdateCartItem maneja PATCH /cart/me/items/{type}/{id}.
+// @Summary      Update cart item
+// @Description  Cambiar la cantidad de un item del carrito
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    string                    true   "User ID"
+// @Param        If-Match   header    string                    false  "ETag del carrito (GET /cart/me)"
+// @Param        type       path      string                    true   "Item type (service|product)"
+// @Param        id         path      string                    true   "Item ID"
+// @Param        pet_id     query     string                    false  "Mascota del item (vacío = pet_profile principal)"
+// @Param        body       body      UpdateCartItemRequestDTO  true   "Nueva cantidad"
+// @Success      200        {object}  CartResponseDTO
+// @Header       200        {string}  ETag  "Revisión del carrito"
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      412        {object}  ErrorResponse
+// @Failure      422        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /api/v1/commerce/cart/me/items/{type}/{id} [patch]
+// @Security     UserID
+func (h *CartHandlers) HandleUpdateCartItem(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "bad_request", "X-User-ID header is required")
+		return
+	}
+
+	expectedRevision, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	var req UpdateCartItemRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", "invalid JSON")
+		return
+	}
+
+	input := cartusecases.UpdateCartItemInput{
+		UserID:           userID,
+		PetID:            r.URL.Query().Get("pet_id"),
+		ItemType:         checkoutdomain.ItemType(chi.URLParam(r, "type")),
+		ItemID:           chi.URLParam(r, "id"),
+		Qty:              req.Qty,
+		ExpectedRevision: expectedRevision,
+	}
+
+	output, err := h.UpdateCartItemUC.Execute(r.Context(), input)
+	if err != nil {
+		respondUsecaseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(output.Cart))
+	resp := CartResponseDTO{Cart: toCartDTO(output.Cart)}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// HandleRemoveCartItem maneja DELETE /cart/me/items/{type}/{id}.
+// @Summary      Remove cart item
+// @Description  Quitar un item del carrito
+// @Tags         cart
+// @Produce      json
+// @Param        X-User-ID  header    string  true   "User ID"
+// @Param        If-Match   header    string  false  "ETag del carrito (GET /cart/me)"
+// @Param        type       path      string  true   "Item type (service|product)"
+// @Param        id         path      string  true   "Item ID"
+// @Param        pet_id     query     string  false  "Mascota del item (vacío = pet_profile principal)"
+// @Success      200        {object}  CartResponseDTO
+// @Header       200        {string}  ETag  "Revisión del carrito"
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      412        {object}  ErrorResponse
+// @Failure      422        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /api/v1/commerce/cart/me/items/{type}/{id} [delete]
+// @Security     UserID
+func (h *CartHandlers) HandleRemoveCartItem(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "bad_request", "X-User-ID header is required")
+		return
+	}
+
+	expectedRevision, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	input := cartusecases.RemoveCartItemInput{
+		UserID:           userID,
+		PetID:            r.URL.Query().Get("pet_id"),
+		ItemType:         checkoutdomain.ItemType(chi.URLParam(r, "type")),
+		ItemID:           chi.URLParam(r, "id"),
+		ExpectedRevision: expectedRevision,
+	}
+
+	output, err := h.RemoveCartItemUC.Execute(r.Context(), input)
+	if err != nil {
+		respondUsecaseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(output.Cart))
+	resp := CartResponseDTO{Cart: toCartDTO(output.Cart)}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// HandleApplyCartCoupon maneja PUT /cart/me/coupon.
+// @Summary      Apply cart coupon
+// @Description  Validar un cupón contra el carrito y guardarlo (se usa en /checkout/start)
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Param        X-User-ID  header    string                 true   "User ID"
+// @Param        If-Match   header    string                 false  "ETag del carrito (GET /cart/me)"
+// @Param        body       body      ApplyCouponRequestDTO  true   "Cupón"
+// @Success      200        {object}  CartResponseDTO
+// @Header       200        {string}  ETag  "Revisión del carrito"
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      412        {object}  ErrorResponse
+// @Failure      422        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /api/v1/commerce/cart/me/coupon [put]
+// @Security     UserID
+func (h *CartHandlers) HandleApplyCartCoupon(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "bad_request", "X-User-ID header is required")
+		return
+	}
+
+	expectedRevision, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	var req ApplyCouponRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", "invalid JSON")
+		return
+	}
+	if strings.TrimSpace(req.Code) == "" {
+		respondError(w, http.StatusBadRequest, "bad_request", "code is required")
+		return
+	}
+
+	input := cartusecases.ApplyCartCouponInput{
+		UserID:           userID,
+		Code:             req.Code,
+		ExpectedRevision: expectedRevision,
+	}
+
+	output, err := h.ApplyCartCouponUC.Execute(r.Context(), input)
+	if err != nil {
+		respondUsecaseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(output.Cart))
+	resp := CartResponseDTO{Cart: toCartDTO(output.Cart)}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// HandleRemoveCartCoupon maneja DELETE /cart/me/coupon.
+// @Summary      Remove cart coupon
+// @Description  Quitar el cupón del carrito
+// @Tags         cart
+// @Produce      json
+// @Param        X-User-ID  header    string  true   "User ID"
+// @Param        If-Match   header    string  false  "ETag del carrito (GET /cart/me)"
+// @Success      200        {object}  CartResponseDTO
+// @Header       200        {string}  ETag  "Revisión del carrito"
+// @Failure      400        {object}  ErrorResponse
+// @Failure      404        {object}  ErrorResponse
+// @Failure      412        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /api/v1/commerce/cart/me/coupon [delete]
+// @Security     UserID
+func (h *CartHandlers) HandleRemoveCartCoupon(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "bad_request", "X-User-ID header is required")
+		return
+	}
+
+	expectedRevision, err := parseIfMatch(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	input := cartusecases.RemoveCartCouponInput{
+		UserID:           userID,
+		ExpectedRevision: expectedRevision,
+	}
+
+	output, err := h.RemoveCartCouponUC.Execute(r.Context(), input)
+	if err != nil {
+		respondUsecaseError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", cartETag(output.Cart))
+	resp := CartResponseDTO{Cart: toCartDTO(output.Cart)}
+	respondJSON(w, http.StatusOK, resp)
+}
+
 // HandleExpireCarts maneja POST /cart/expire.
 // @Summary      Expire carts
-// @Description  Expirar carritos vencidos (dev/admin)
+// @Description  Expirar carritos vencidos a demanda (admin). El barrido normal lo hace el worker de fondo;
+// @Description  requiere X-Admin-Token igual a ADMIN_TOKEN (sin ADMIN_TOKEN el endpoint está deshabilitado).
 // @Tags         cart
 // @Accept       json
 // @Produce      json
-// @Param        body  body      ExpireRequestDTO  false  "Optional timestamp"
-// @Success      200   {object}  ExpireResponseDTO
-// @Failure      400   {object}  ErrorResponse
-// @Failure      500   {object}  ErrorResponse
+// @Param        X-Admin-Token  header    string            true   "Admin token"
+// @Param        body           body      ExpireRequestDTO  false  "Optional timestamp"
+// @Success      200            {object}  ExpireResponseDTO
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
 // @Router       /api/v1/commerce/cart/expire [post]
 func (h *CartHandlers) HandleExpireCarts(w http.ResponseWriter, r *http.Request) {
+	if h.AdminToken == "" {
+		respondError(w, http.StatusForbidden, "forbidden", "manual cart expiry is disabled")
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(h.AdminToken)) != 1 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "invalid admin token")
+		return
+	}
+
 	var req ExpireRequestDTO
 
 	// Body opcional: si no hay body o está vacío, no fallar
@@ -161,37 +504,113 @@ func (h *CartHandlers) HandleExpireCarts(w http.ResponseWriter, r *http.Request)
 
 	// Ejecutar usecase
 	input := cartusecases.ExpireCartsInput{Now: now}
-	output, err := h.ExpireCartsUC.Execute(r.Context(), input)
+	output, err := h.ExpireCartsUC.Execute(audit.WithActor(r.Context(), audit.ActorAdmin), input)
 	if err != nil {
-		code, msg := mapErrorToCodeAndMessage(err)
-		respondError(w, mapErrorToHTTPStatus(err), code, msg)
+		respondUsecaseError(w, err)
 		return
 	}
 
-	resp := ExpireResponseDTO{ExpiredCount: output.ExpiredCount}
+	resp := ExpireResponseDTO{
+		ExpiredCount:    output.ExpiredCount,
+		CancelledHolds:  output.CancelledHolds,
+		CancelledOrders: output.CancelledOrders,
+		Skipped:         output.Skipped,
+	}
 	respondJSON(w, http.StatusOK, resp)
 }
 
+// cartETag construye el ETag (fuerte) a partir de la revisión del carrito.
+func cartETag(cart cartdomain.Cart) string {
+	return `"` + strconv.Itoa(cart.Revision) + `"`
+}
+
+// parseIfMatch lee If-Match y retorna la revisión esperada (nil si no hay precondición).
+// Un ETag débil (W/) nunca coincide en comparación fuerte: se trata como revisión inválida.
+func parseIfMatch(r *http.Request) (*int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(value, "W/") {
+		revision := -1
+		return &revision, nil
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return nil, errors.New("invalid If-Match header")
+	}
+	revision, err := strconv.Atoi(unquoted)
+	if err != nil {
+		revision = -1 // ETag que no emitimos: no puede coincidir
+	}
+	return &revision, nil
+}
+
 func mapErrorToHTTPStatus(err error) int {
-	if errors.Is(err, cartdomain.ErrCartNotFound) {
+	if errors.Is(err, cartdomain.ErrCartRevisionConflict) {
+		return http.StatusPreconditionFailed
+	}
+	if errors.Is(err, cartdomain.ErrInvalidCartItems) ||
+		errors.Is(err, promotionsusecases.ErrInvalidCoupon) || errors.Is(err, promotionsdomain.ErrCouponNotFound) ||
+		errors.Is(err, platformpets.ErrPetNotFound) || errors.Is(err, checkoutusecases.ErrPetProfileMismatch) {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, platformpets.ErrPetsUnavailable) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, cartdomain.ErrCartNotFound) || errors.Is(err, cartdomain.ErrCartItemNotFound) {
 		return http.StatusNotFound
 	}
-	if errors.Is(err, cartdomain.ErrInvalidUserID) || errors.Is(err, cartdomain.ErrEmptyItems) {
+	if errors.Is(err, cartdomain.ErrInvalidUserID) || errors.Is(err, cartdomain.ErrEmptyItems) ||
+		errors.Is(err, cartdomain.ErrInvalidItem) || errors.Is(err, cartdomain.ErrInvalidQty) ||
+		errors.Is(err, checkoutdomain.ErrInvalidPets) {
 		return http.StatusBadRequest
 	}
 	return http.StatusInternalServerError
 }
 
 func mapErrorToCodeAndMessage(err error) (string, string) {
-	if errors.Is(err, cartdomain.ErrCartNotFound) {
+	if errors.Is(err, cartdomain.ErrCartRevisionConflict) {
+		return "precondition_failed", err.Error()
+	}
+	if errors.Is(err, cartdomain.ErrInvalidCartItems) {
+		return "invalid_items", err.Error()
+	}
+	if errors.Is(err, promotionsusecases.ErrInvalidCoupon) || errors.Is(err, promotionsdomain.ErrCouponNotFound) {
+		return "invalid_coupon", err.Error()
+	}
+	if errors.Is(err, platformpets.ErrPetNotFound) {
+		return "pet_not_found", err.Error()
+	}
+	if errors.Is(err, platformpets.ErrPetsUnavailable) {
+		return "pets_unavailable", err.Error()
+	}
+	if errors.Is(err, cartdomain.ErrCartNotFound) || errors.Is(err, cartdomain.ErrCartItemNotFound) {
 		return "not_found", err.Error()
 	}
-	if errors.Is(err, cartdomain.ErrInvalidUserID) || errors.Is(err, cartdomain.ErrEmptyItems) {
+	if errors.Is(err, cartdomain.ErrInvalidUserID) || errors.Is(err, cartdomain.ErrEmptyItems) ||
+		errors.Is(err, cartdomain.ErrInvalidItem) || errors.Is(err, cartdomain.ErrInvalidQty) ||
+		errors.Is(err, checkoutdomain.ErrInvalidPets) {
 		return "bad_request", err.Error()
 	}
 	return "internal", "internal server error"
 }
 
+// respondUsecaseError traduce un error de usecase; los items inválidos se detallan por línea.
+func respondUsecaseError(w http.ResponseWriter, err error) {
+	code, msg := mapErrorToCodeAndMessage(err)
+	resp := ErrorResponse{Error: ErrorDTO{Code: code, Message: msg}}
+
+	var invalidItems *cartdomain.InvalidItemsError
+	if errors.As(err, &invalidItems) {
+		resp.Error.Details = toItemErrorDTOs(invalidItems.Violations)
+	}
+
+	respondJSON(w, mapErrorToHTTPStatus(err), resp)
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -9,13 +10,21 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/runtime"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
 	//checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
 )
 
+const testAdminToken = "test-admin-token"
+
 func setupTestCartRouter() http.Handler {
 	//orderRepo := checkoutmemory.NewOrderRepository()
 	//handlers := WireCartHandlers(orderRepo)
 	handlers := WireCartHandlers()
+	handlers.AdminToken = testAdminToken
 
 	r := chi.NewRouter()
 	RegisterRoutes(r, handlers)
@@ -161,6 +170,432 @@ func TestHTTP_DeleteCart(t *testing.T) {
 	}
 }
 
+func TestHTTP_Cart_IfMatch(t *testing.T) {
+	router := setupTestCartRouter()
+
+	put := func(ifMatch string, qty int) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 10, "coat_type": "short"},
+			"items":       []map[string]interface{}{{"type": "service", "id": "bath", "qty": qty}},
+		})
+		req := httptest.NewRequest("PUT", "/cart/me", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "user_etag")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// If-Match sobre un carrito inexistente falla
+	if rec := put(`"1"`, 1); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412 for missing cart, got: %d", rec.Code)
+	}
+
+	if rec := put("", 1); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d", rec.Code)
+	}
+
+	getReq := httptest.NewRequest("GET", "/cart/me", nil)
+	getReq.Header.Set("X-User-ID", "user_etag")
+	getRec := httptest.NewRecorder()
+	router.ServeHTTP(getRec, getReq)
+	etag := getRec.Header().Get("ETag")
+	if etag != `"1"` {
+		t.Fatalf("expected ETag \"1\", got: %q", etag)
+	}
+
+	rec := put(etag, 2)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with matching If-Match, got: %d", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got != `"2"` {
+		t.Errorf("expected new ETag \"2\", got: %q", got)
+	}
+
+	// El ETag anterior ya no coincide
+	rec = put(etag, 3)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412 with stale If-Match, got: %d", rec.Code)
+	}
+	var resp ErrorResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Error.Code != "precondition_failed" {
+		t.Errorf("expected error code precondition_failed, got: %s", resp.Error.Code)
+	}
+
+	delReq := httptest.NewRequest("DELETE", "/cart/me", nil)
+	delReq.Header.Set("X-User-ID", "user_etag")
+	delReq.Header.Set("If-Match", etag)
+	delRec := httptest.NewRecorder()
+	router.ServeHTTP(delRec, delReq)
+	if delRec.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected status 412 on stale delete, got: %d", delRec.Code)
+	}
+
+	delReq = httptest.NewRequest("DELETE", "/cart/me", nil)
+	delReq.Header.Set("X-User-ID", "user_etag")
+	delReq.Header.Set("If-Match", `"2"`)
+	delRec = httptest.NewRecorder()
+	router.ServeHTTP(delRec, delReq)
+	if delRec.Code != http.StatusNoContent {
+		t.Errorf("expected status 204 on matching delete, got: %d", delRec.Code)
+	}
+}
+
+func TestHTTP_CartItems(t *testing.T) {
+	router := setupTestCartRouter()
+
+	createBody := map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 10, "coat_type": "double"},
+		"items":       []map[string]interface{}{{"type": "service", "id": "bath", "qty": 1}},
+	}
+	body, _ := json.Marshal(createBody)
+	createReq := httptest.NewRequest("PUT", "/cart/me", bytes.NewReader(body))
+	createReq.Header.Set("Content-Type", "application/json")
+	createReq.Header.Set("X-User-ID", "user_items")
+	router.ServeHTTP(httptest.NewRecorder(), createReq)
+
+	send := func(method, path string, payload interface{}) (*httptest.ResponseRecorder, CartResponseDTO) {
+		var raw []byte
+		if payload != nil {
+			raw, _ = json.Marshal(payload)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "user_items")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var resp CartResponseDTO
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp
+	}
+
+	rec, resp := send("POST", "/cart/me/items", map[string]interface{}{"type": "service", "id": "deshedding", "qty": 1})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on add, got: %d", rec.Code)
+	}
+	if len(resp.Cart.Items) != 2 {
+		t.Errorf("expected 2 items, got: %d", len(resp.Cart.Items))
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Errorf("expected ETag header on add")
+	}
+
+	rec, resp = send("PATCH", "/cart/me/items/service/deshedding", map[string]interface{}{"qty": 2})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on patch, got: %d", rec.Code)
+	}
+	if resp.Cart.Items[1].Qty != 2 {
+		t.Errorf("expected deshedding qty 2, got: %d", resp.Cart.Items[1].Qty)
+	}
+
+	// Quitar bath dejaría a deshedding sin su servicio base
+	rec, _ = send("DELETE", "/cart/me/items/service/bath", nil)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 when removing parent service, got: %d", rec.Code)
+	}
+
+	rec, _ = send("PATCH", "/cart/me/items/service/deshedding", map[string]interface{}{"qty": -1})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 on invalid qty, got: %d", rec.Code)
+	}
+
+	rec, resp = send("DELETE", "/cart/me/items/service/deshedding", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on delete, got: %d", rec.Code)
+	}
+	if len(resp.Cart.Items) != 1 {
+		t.Errorf("expected 1 item after delete, got: %d", len(resp.Cart.Items))
+	}
+
+	rec, _ = send("DELETE", "/cart/me/items/service/deshedding", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for missing item, got: %d", rec.Code)
+	}
+}
+
+func TestHTTP_UpsertCart_InvalidItems(t *testing.T) {
+	router := setupTestCartRouter()
+
+	reqBody := map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 15, "coat_type": "double"},
+		"items": []map[string]interface{}{
+			{"type": "service", "id": "bath", "qty": 1},
+			{"type": "service", "id": "foo", "qty": 1},
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("PUT", "/cart/me", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "user_invalid_items")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got: %d", rec.Code)
+	}
+
+	var resp ErrorResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Error.Code != "invalid_items" {
+		t.Errorf("expected error code invalid_items, got: %s", resp.Error.Code)
+	}
+	if len(resp.Error.Details) != 1 {
+		t.Fatalf("expected 1 item error, got: %+v", resp.Error.Details)
+	}
+	if d := resp.Error.Details[0]; d.Index != 1 || d.ID != "foo" || d.Code != "unknown_service" {
+		t.Errorf("unexpected item error: %+v", d)
+	}
+}
+
+func TestHTTP_GetCart_IncludeQuote(t *testing.T) {
+	router := setupTestCartRouter()
+
+	// Carrito guardado antes de que el pet pasara a hairless: dematting ya no es elegible
+	now := time.Now()
+	cart := cartdomain.NewCart("user_quote", servicedomain.PetProfile{
+		Species:  servicedomain.SpeciesDog,
+		WeightKg: 15,
+		CoatType: servicedomain.CoatTypeHairless,
+	}, []checkoutdomain.PurchaseItem{
+		{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+		{ItemType: checkoutdomain.ItemTypeService, ItemID: "dematting", Qty: 1},
+	}, now)
+	if _, err := runtime.CartRepoSingleton.Upsert(context.Background(), cart); err != nil {
+		t.Fatalf("failed to seed cart: %v", err)
+	}
+
+	getReq := httptest.NewRequest("GET", "/cart/me?include=quote", nil)
+	getReq.Header.Set("X-User-ID", "user_quote")
+	getRec := httptest.NewRecorder()
+	router.ServeHTTP(getRec, getReq)
+
+	if getRec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d", getRec.Code)
+	}
+
+	var resp CartResponseDTO
+	json.NewDecoder(getRec.Body).Decode(&resp)
+	if resp.Quote == nil {
+		t.Fatalf("expected quote in response")
+	}
+	if len(resp.Quote.Lines) != 1 || resp.Quote.Lines[0].ID != "bath" || resp.Quote.Lines[0].UnitPrice.Amount != 4500 {
+		t.Errorf("expected bath line at 4500, got: %+v", resp.Quote.Lines)
+	}
+	if resp.Quote.Subtotal.Amount != 4500 {
+		t.Errorf("expected subtotal 4500, got: %d", resp.Quote.Subtotal.Amount)
+	}
+	if resp.Quote.Total.Amount != resp.Quote.Subtotal.Amount-resp.Quote.TotalDiscount.Amount {
+		t.Errorf("expected total = subtotal - discounts, got: %+v", resp.Quote)
+	}
+	if len(resp.Quote.Issues) != 1 || resp.Quote.Issues[0].ID != "dematting" || resp.Quote.Issues[0].Code != "service_not_eligible" {
+		t.Errorf("expected dematting flagged as not eligible, got: %+v", resp.Quote.Issues)
+	}
+
+	// Sin include no se cotiza
+	plainReq := httptest.NewRequest("GET", "/cart/me", nil)
+	plainReq.Header.Set("X-User-ID", "user_quote")
+	plainRec := httptest.NewRecorder()
+	router.ServeHTTP(plainRec, plainReq)
+
+	var plain CartResponseDTO
+	json.NewDecoder(plainRec.Body).Decode(&plain)
+	if plain.Quote != nil {
+		t.Errorf("expected no quote without include=quote")
+	}
+}
+
+func TestHTTP_CartCoupon(t *testing.T) {
+	router := setupTestCartRouter()
+
+	createBody := map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 10, "coat_type": "short"},
+		"items":       []map[string]interface{}{{"type": "service", "id": "bath", "qty": 1}},
+	}
+	body, _ := json.Marshal(createBody)
+	createReq := httptest.NewRequest("PUT", "/cart/me", bytes.NewReader(body))
+	createReq.Header.Set("Content-Type", "application/json")
+	createReq.Header.Set("X-User-ID", "user_coupon")
+	router.ServeHTTP(httptest.NewRecorder(), createReq)
+
+	applyCoupon := func(code string) *httptest.ResponseRecorder {
+		b, _ := json.Marshal(map[string]string{"code": code})
+		req := httptest.NewRequest("PUT", "/cart/me/coupon", bytes.NewReader(b))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "user_coupon")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := applyCoupon("NOEXISTE")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422 for unknown coupon, got: %d", rec.Code)
+	}
+
+	rec = applyCoupon(" bano10 ")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d", rec.Code)
+	}
+	var resp CartResponseDTO
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Cart.CouponCode == nil || *resp.Cart.CouponCode != "BANO10" {
+		t.Fatalf("expected normalized coupon BANO10, got: %v", resp.Cart.CouponCode)
+	}
+
+	// La cotización del carrito incluye el descuento del cupón
+	getReq := httptest.NewRequest("GET", "/cart/me?include=quote", nil)
+	getReq.Header.Set("X-User-ID", "user_coupon")
+	getRec := httptest.NewRecorder()
+	router.ServeHTTP(getRec, getReq)
+	var quoted CartResponseDTO
+	json.NewDecoder(getRec.Body).Decode(&quoted)
+	hasCoupon := false
+	for _, d := range quoted.Quote.Discounts {
+		if d.Source == "coupon" && d.Name == "BANO10" {
+			hasCoupon = true
+		}
+	}
+	if !hasCoupon {
+		t.Errorf("expected coupon discount in cart quote, got: %+v", quoted.Quote.Discounts)
+	}
+
+	delReq := httptest.NewRequest("DELETE", "/cart/me/coupon", nil)
+	delReq.Header.Set("X-User-ID", "user_coupon")
+	delRec := httptest.NewRecorder()
+	router.ServeHTTP(delRec, delReq)
+	if delRec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on coupon removal, got: %d", delRec.Code)
+	}
+	var removed CartResponseDTO
+	json.NewDecoder(delRec.Body).Decode(&removed)
+	if removed.Cart.CouponCode != nil {
+		t.Errorf("expected coupon removed, got: %v", *removed.Cart.CouponCode)
+	}
+}
+
+func TestHTTP_UpsertCart_PetProfileChange_RemovesIneligibleItems(t *testing.T) {
+	router := setupTestCartRouter()
+
+	put := func(pet map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"pet_profile": pet,
+			"items": []map[string]interface{}{
+				{"type": "service", "id": "bath", "qty": 1},
+				{"type": "service", "id": "deshedding", "qty": 1},
+			},
+		})
+		req := httptest.NewRequest("PUT", "/cart/me", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "user_migrate")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := put(map[string]interface{}{"species": "dog", "weight_kg": 15, "coat_type": "double"}); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d", rec.Code)
+	}
+
+	// Con pelo corto, deshedding deja de ser elegible: se quita y se informa
+	rec := put(map[string]interface{}{"species": "dog", "weight_kg": 15, "coat_type": "short"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 after pet change, got: %d", rec.Code)
+	}
+	var resp CartResponseDTO
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if len(resp.Cart.Items) != 1 || resp.Cart.Items[0].ID != "bath" {
+		t.Errorf("expected only bath to remain, got: %+v", resp.Cart.Items)
+	}
+	if len(resp.RemovedItems) != 1 || resp.RemovedItems[0].ID != "deshedding" || resp.RemovedItems[0].Code != "service_not_eligible" {
+		t.Errorf("expected deshedding reported as removed, got: %+v", resp.RemovedItems)
+	}
+
+	// Sin precios para 55kg se quitarían todos los items: el carrito no puede quedar vacío
+	rec = put(map[string]interface{}{"species": "dog", "weight_kg": 55, "coat_type": "double"})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422 when every item is removed, got: %d", rec.Code)
+	}
+	var errResp ErrorResponse
+	json.NewDecoder(rec.Body).Decode(&errResp)
+	codes := map[string]string{}
+	for _, d := range errResp.Error.Details {
+		codes[d.ID] = d.Code
+	}
+	if codes["bath"] != "unpriced" || codes["deshedding"] == "" {
+		t.Errorf("expected bath and deshedding reported, got: %+v", errResp.Error.Details)
+	}
+}
+
+func TestHTTP_CartMultiPet(t *testing.T) {
+	router := setupTestCartRouter()
+
+	do := func(method, path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "user_multi_pet")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	pets := []map[string]interface{}{
+		{"pet_id": "luna", "pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 8, "coat_type": "short"}},
+	}
+
+	// Un item de una mascota que no está en pets se rechaza
+	rec := do("PUT", "/cart/me", map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 15, "coat_type": "double"},
+		"pets":        pets,
+		"items":       []map[string]interface{}{{"type": "service", "id": "bath", "qty": 1, "pet_id": "rocky"}},
+	})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422 for unknown pet, got: %d", rec.Code)
+	}
+	var errResp ErrorResponse
+	json.NewDecoder(rec.Body).Decode(&errResp)
+	if len(errResp.Error.Details) != 1 || errResp.Error.Details[0].Code != "unknown_pet" {
+		t.Errorf("expected unknown_pet detail, got: %+v", errResp.Error.Details)
+	}
+
+	rec = do("PUT", "/cart/me", map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 15, "coat_type": "double"},
+		"pets":        pets,
+		"items": []map[string]interface{}{
+			{"type": "service", "id": "bath", "qty": 1},
+			{"type": "service", "id": "bath", "qty": 1, "pet_id": "luna"},
+		},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+
+	// El mismo servicio se distingue por mascota
+	rec = do("PATCH", "/cart/me/items/service/bath?pet_id=luna", map[string]interface{}{"qty": 2})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on patch, got: %d", rec.Code)
+	}
+	var resp CartResponseDTO
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if len(resp.Cart.Pets) != 1 || resp.Cart.Pets[0].PetID != "luna" {
+		t.Errorf("expected luna in cart pets, got: %+v", resp.Cart.Pets)
+	}
+	if resp.Cart.Items[0].Qty != 1 || resp.Cart.Items[1].PetID != "luna" || resp.Cart.Items[1].Qty != 2 {
+		t.Errorf("expected only luna's bath updated, got: %+v", resp.Cart.Items)
+	}
+
+	// deshedding no aplica al pelo corto de luna
+	rec = do("POST", "/cart/me/items", map[string]interface{}{"type": "service", "id": "deshedding", "qty": 1, "pet_id": "luna"})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 for luna's deshedding, got: %d", rec.Code)
+	}
+}
+
 func TestHTTP_ExpireCarts(t *testing.T) {
 	router := setupTestCartRouter()
 
@@ -185,6 +620,7 @@ func TestHTTP_ExpireCarts(t *testing.T) {
 	expireBodyBytes, _ := json.Marshal(expireBody)
 	expireReq := httptest.NewRequest("POST", "/cart/expire", bytes.NewReader(expireBodyBytes))
 	expireReq.Header.Set("Content-Type", "application/json")
+	expireReq.Header.Set("X-Admin-Token", testAdminToken)
 
 	expireRec := httptest.NewRecorder()
 	router.ServeHTTP(expireRec, expireReq)
@@ -215,6 +651,7 @@ func TestHTTP_ExpireCarts_EmptyBody(t *testing.T) {
 
 	// Sin body
 	expireReq := httptest.NewRequest("POST", "/cart/expire", nil)
+	expireReq.Header.Set("X-Admin-Token", testAdminToken)
 	expireRec := httptest.NewRecorder()
 	router.ServeHTTP(expireRec, expireReq)
 
@@ -229,3 +666,34 @@ func TestHTTP_ExpireCarts_EmptyBody(t *testing.T) {
 		t.Errorf("invalid expired count: %d", expireResp.ExpiredCount)
 	}
 }
+
+func TestHTTP_ExpireCarts_RequiresAdminToken(t *testing.T) {
+	router := setupTestCartRouter()
+
+	for _, token := range []string{"", "wrong"} {
+		req := httptest.NewRequest("POST", "/cart/expire", nil)
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: expected status 401, got: %d", token, rec.Code)
+		}
+	}
+
+	// Sin ADMIN_TOKEN configurado el endpoint queda deshabilitado
+	handlers := WireCartHandlers()
+	handlers.AdminToken = ""
+	r := chi.NewRouter()
+	RegisterRoutes(r, handlers)
+
+	req := httptest.NewRequest("POST", "/cart/expire", nil)
+	req.Header.Set("X-Admin-Token", "anything")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 when disabled, got: %d", rec.Code)
+	}
+}
@@ -10,6 +10,11 @@ func RegisterRoutes(r chi.Router, handlers *CartHandlers) {
 		r.Put("/me", handlers.HandleUpsertCart)
 		r.Get("/me", handlers.HandleGetCart)
 		r.Delete("/me", handlers.HandleDeleteCart)
+		r.Post("/me/items", handlers.HandleAddCartItem)
+		r.Patch("/me/items/{type}/{id}", handlers.HandleUpdateCartItem)
+		r.Delete("/me/items/{type}/{id}", handlers.HandleRemoveCartItem)
+		r.Put("/me/coupon", handlers.HandleApplyCartCoupon)
+		r.Delete("/me/coupon", handlers.HandleRemoveCartCoupon)
 		r.Post("/expire", handlers.HandleExpireCarts)
 	})
 }
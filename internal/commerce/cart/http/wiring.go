@@ -2,25 +2,114 @@ package http
 
 import (
 	"context"
+	"time"
 
+	catalogports "paku-commerce/internal/commerce/cart/ports/catalog"
 	checkoutports "paku-commerce/internal/commerce/cart/ports/checkout"
+	promotionsports "paku-commerce/internal/commerce/cart/ports/promotions"
 	cartusecases "paku-commerce/internal/commerce/cart/usecases"
+	cartworker "paku-commerce/internal/commerce/cart/worker"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
 	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
 	platformbooking "paku-commerce/internal/commerce/platform/booking"
 	"paku-commerce/internal/commerce/runtime"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+	pricingusecases "paku-commerce/internal/pricing/usecases"
+	promotionsusecases "paku-commerce/internal/promotions/usecases"
 )
 
-// InProcessCheckoutClient implementa checkoutports.CheckoutClient usando checkout domain directamente.
+// InProcessCheckoutClient implementa checkoutports.CheckoutClient e IncidentReporter
+// usando checkout domain directamente.
 type InProcessCheckoutClient struct {
-	CancelOrderUC *checkoutusecases.CancelOrder
+	CancelOrderUC    *checkoutusecases.CancelOrder
+	RecordIncidentUC *checkoutusecases.RecordIncident
 }
 
-func (c *InProcessCheckoutClient) CancelOrder(ctx context.Context, orderID string) error {
-	input := checkoutusecases.CancelOrderInput{OrderID: orderID}
+func (c *InProcessCheckoutClient) CancelOrder(ctx context.Context, orderID string, reason checkoutdomain.CancelReason) error {
+	input := checkoutusecases.CancelOrderInput{OrderID: orderID, Reason: reason}
 	_, err := c.CancelOrderUC.Execute(ctx, input)
 	return err
 }
 
+func (c *InProcessCheckoutClient) ReportHoldReleaseFailure(ctx context.Context, orderID, holdID string, cause error) error {
+	_, err := c.RecordIncidentUC.Execute(ctx, checkoutusecases.RecordIncidentInput{
+		OrderID:   orderID,
+		HoldID:    holdID,
+		Operation: checkoutdomain.IncidentOperationCancelHold,
+		Err:       cause,
+	})
+	return err
+}
+
+// InProcessCatalogValidator implementa catalogports.ItemValidator usando las reglas del checkout.
+type InProcessCatalogValidator struct {
+	ValidateItemsUC *checkoutusecases.ValidateItems
+}
+
+func (v *InProcessCatalogValidator) ValidateItems(ctx context.Context, intent checkoutdomain.PurchaseIntent) ([]checkoutdomain.ItemViolation, error) {
+	input := checkoutusecases.ValidateItemsInput{PetProfile: intent.PetProfile, Pets: intent.Pets, Items: intent.Items}
+	output, err := v.ValidateItemsUC.Execute(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return output.Violations, nil
+}
+
+// InProcessQuoter implementa checkoutports.Quoter usando QuoteCheckout directamente.
+type InProcessQuoter struct {
+	QuoteCheckoutUC *checkoutusecases.QuoteCheckout
+}
+
+func (q *InProcessQuoter) Quote(ctx context.Context, intent checkoutdomain.PurchaseIntent) (checkoutusecases.CheckoutQuote, error) {
+	input := checkoutusecases.QuoteCheckoutInput{Intent: intent}
+	output, err := q.QuoteCheckoutUC.Execute(ctx, input)
+	if err != nil {
+		return checkoutusecases.CheckoutQuote{}, err
+	}
+	return output.Quote, nil
+}
+
+// InProcessCouponValidator implementa promotionsports.CouponValidator cotizando los items
+// y validando el cupón con promotions.ValidateCoupon.
+type InProcessCouponValidator struct {
+	QuoteItemsUC     *pricingusecases.QuoteItems
+	ValidateCouponUC *promotionsusecases.ValidateCoupon
+}
+
+func (v *InProcessCouponValidator) ValidateCoupon(ctx context.Context, code string, intent checkoutdomain.PurchaseIntent) (string, error) {
+	quoteInput := pricingusecases.QuoteItemsInput{
+		PetProfile: intent.PetProfile,
+		Pets:       make(map[string]servicedomain.PetProfile, len(intent.Pets)),
+		Items:      make([]pricingusecases.QuoteRequestItem, 0, len(intent.Items)),
+	}
+	for _, pet := range intent.Pets {
+		quoteInput.Pets[pet.ID] = pet.PetProfile
+	}
+	for _, item := range intent.Items {
+		quoteInput.Items = append(quoteInput.Items, pricingusecases.QuoteRequestItem{
+			ItemType: pricingdomain.ItemType(item.ItemType),
+			ItemID:   item.ItemID,
+			Qty:      item.Qty,
+			PetID:    item.PetID,
+		})
+	}
+
+	quoteOutput, err := v.QuoteItemsUC.Execute(ctx, quoteInput)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := v.ValidateCouponUC.Execute(ctx, promotionsusecases.ValidateCouponInput{
+		Code:  code,
+		Quote: quoteOutput.Quote,
+	})
+	if err != nil {
+		return "", err
+	}
+	return output.Coupon.Code, nil
+}
+
 // InProcessBookingClient implementa platform booking.Client (stub no-op).
 type InProcessBookingClient struct{}
 
@@ -49,16 +138,39 @@ func WireCartHandlers() *CartHandlers {
 	var bookingClient platformbooking.Client = &InProcessBookingClient{}
 
 	// Port: checkout (in-process)
-	cancelOrderUC := &checkoutusecases.CancelOrder{
-		Repo:    orderRepo,
-		Booking: &platformbooking.StubClient{},
+	checkoutClient := newInProcessCheckoutClient(orderRepo)
+
+	// Port: catálogo (in-process, mismas reglas que el checkout)
+	var validator catalogports.ItemValidator = &InProcessCatalogValidator{
+		ValidateItemsUC: &checkoutusecases.ValidateItems{
+			ServiceRepo:   runtime.ServiceRepoSingleton,
+			PriceRuleRepo: runtime.PriceRuleRepoSingleton,
+		},
+	}
+
+	// Port: cotización (in-process)
+	var quoter checkoutports.Quoter = &InProcessQuoter{
+		QuoteCheckoutUC: &checkoutusecases.QuoteCheckout{
+			ServiceRepo:   runtime.ServiceRepoSingleton,
+			PriceRuleRepo: runtime.PriceRuleRepoSingleton,
+			PriceQuoteUC:  &pricingusecases.QuoteItems{RuleRepo: runtime.PriceRuleRepoSingleton},
+			PromotionsUC:  &promotionsusecases.ApplyDiscounts{Repo: runtime.PromotionsRepoSingleton},
+			Pets:          runtime.PetsClientSingleton,
+		},
+	}
+
+	// Port: cupones (in-process)
+	var couponValidator promotionsports.CouponValidator = &InProcessCouponValidator{
+		QuoteItemsUC:     &pricingusecases.QuoteItems{RuleRepo: runtime.PriceRuleRepoSingleton},
+		ValidateCouponUC: &promotionsusecases.ValidateCoupon{Repo: runtime.PromotionsRepoSingleton},
 	}
-	var checkoutClient checkoutports.CheckoutClient = &InProcessCheckoutClient{CancelOrderUC: cancelOrderUC}
 
 	// Usecases
 	upsertCartUC := &cartusecases.UpsertCart{
-		Repo: cartRepo,
-		Now:  nil,
+		Repo:      cartRepo,
+		Validator: validator,
+		Pets:      runtime.PetsClientSingleton,
+		Now:       nil,
 	}
 
 	getCartUC := &cartusecases.GetCart{
@@ -66,20 +178,95 @@ func WireCartHandlers() *CartHandlers {
 		Now:  nil,
 	}
 
+	quoteCartUC := &cartusecases.QuoteCart{
+		Repo:      cartRepo,
+		Validator: validator,
+		Quoter:    quoter,
+		Now:       nil,
+	}
+
 	deleteCartUC := &cartusecases.DeleteCart{
 		Repo: cartRepo,
 	}
 
-	expireCartsUC := &cartusecases.ExpireCarts{
-		Repo:     cartRepo,
-		Booking:  bookingClient,
-		Checkout: checkoutClient,
+	addCartItemUC := &cartusecases.AddCartItem{
+		Repo:      cartRepo,
+		Validator: validator,
+	}
+
+	updateCartItemUC := &cartusecases.UpdateCartItem{
+		Repo:      cartRepo,
+		Validator: validator,
+	}
+
+	removeCartItemUC := &cartusecases.RemoveCartItem{
+		Repo:      cartRepo,
+		Validator: validator,
+	}
+
+	applyCartCouponUC := &cartusecases.ApplyCartCoupon{
+		Repo:    cartRepo,
+		Coupons: couponValidator,
+	}
+
+	removeCartCouponUC := &cartusecases.RemoveCartCoupon{
+		Repo: cartRepo,
 	}
 
+	expireCartsUC := wireExpireCarts(bookingClient, checkoutClient)
+
 	return &CartHandlers{
-		UpsertCartUC:  upsertCartUC,
-		GetCartUC:     getCartUC,
-		DeleteCartUC:  deleteCartUC,
-		ExpireCartsUC: expireCartsUC,
+		UpsertCartUC:       upsertCartUC,
+		GetCartUC:          getCartUC,
+		QuoteCartUC:        quoteCartUC,
+		DeleteCartUC:       deleteCartUC,
+		AddCartItemUC:      addCartItemUC,
+		UpdateCartItemUC:   updateCartItemUC,
+		RemoveCartItemUC:   removeCartItemUC,
+		ApplyCartCouponUC:  applyCartCouponUC,
+		RemoveCartCouponUC: removeCartCouponUC,
+		ExpireCartsUC:      expireCartsUC,
+		AdminToken:         runtime.WorkersConfigFromEnv().AdminToken,
+	}
+}
+
+// WireCartExpiryWorker construye el barrido periódico de carritos vencidos.
+func WireCartExpiryWorker(interval time.Duration) *cartworker.ExpiryWorker {
+	return &cartworker.ExpiryWorker{
+		ExpireCartsUC: wireExpireCarts(&InProcessBookingClient{}, newInProcessCheckoutClient(runtime.OrderRepoSingleton)),
+		Interval:      interval,
+	}
+}
+
+// newInProcessCheckoutClient construye el port de checkout con su cola de revisión.
+func newInProcessCheckoutClient(orderRepo checkoutdomain.OrderRepository) *InProcessCheckoutClient {
+	recordIncidentUC := &checkoutusecases.RecordIncident{Repo: runtime.IncidentRepoSingleton}
+	cancelOrderUC := &checkoutusecases.CancelOrder{
+		Repo:             orderRepo,
+		Booking:          &platformbooking.StubClient{},
+		RecordIncidentUC: recordIncidentUC,
+	}
+	// Con pagos QR, la orden cancelada también anula su QR pendiente
+	if qrPayments := runtime.QRPaymentsSingleton; qrPayments != nil {
+		cancelOrderUC.CancelPaymentIntentUC = &checkoutusecases.CancelPendingPaymentIntent{
+			Intents:          runtime.PaymentIntentRepoSingleton,
+			QR:               qrPayments,
+			RecordIncidentUC: recordIncidentUC,
+		}
+	}
+	return &InProcessCheckoutClient{
+		CancelOrderUC:    cancelOrderUC,
+		RecordIncidentUC: recordIncidentUC,
+	}
+}
+
+// wireExpireCarts comparte la configuración de ExpireCarts entre el endpoint manual y el worker.
+func wireExpireCarts(bookingClient platformbooking.Client, checkoutClient *InProcessCheckoutClient) *cartusecases.ExpireCarts {
+	return &cartusecases.ExpireCarts{
+		Repo:      runtime.CartRepoSingleton,
+		Booking:   bookingClient,
+		Checkout:  checkoutClient,
+		Incidents: checkoutClient,
+		Locker:    runtime.LockerSingleton,
 	}
 }
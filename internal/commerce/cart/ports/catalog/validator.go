@@ -0,0 +1,14 @@
+package catalog
+
+import (
+	"context"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// ItemValidator valida items del carrito contra el catálogo (mismas reglas que el checkout).
+type ItemValidator interface {
+	// ValidateItems retorna una violación por item inválido de la intención (cada item se evalúa
+	// con el perfil de su mascota); el error es solo de infraestructura.
+	ValidateItems(ctx context.Context, intent checkoutdomain.PurchaseIntent) ([]checkoutdomain.ItemViolation, error)
+}
@@ -1,9 +1,24 @@
 package checkout
 
-import "context"
+import (
+	"context"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+)
 
 // CheckoutClient define operaciones de checkout para cart.
 type CheckoutClient interface {
-	// CancelOrder cancela una orden.
-	CancelOrder(ctx context.Context, orderID string) error
+	// CancelOrder cancela una orden indicando el motivo (queda en su historial).
+	CancelOrder(ctx context.Context, orderID string, reason checkoutdomain.CancelReason) error
+}
+
+// IncidentReporter registra en la cola de revisión de checkout los holds que cart no pudo liberar.
+type IncidentReporter interface {
+	ReportHoldReleaseFailure(ctx context.Context, orderID, holdID string, cause error) error
+}
+
+// Quoter cotiza items con las reglas del checkout (precios + promociones).
+type Quoter interface {
+	Quote(ctx context.Context, intent checkoutdomain.PurchaseIntent) (checkoutusecases.CheckoutQuote, error)
 }
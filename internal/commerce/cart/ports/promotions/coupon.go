@@ -0,0 +1,13 @@
+package promotions
+
+import (
+	"context"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// CouponValidator valida un cupón contra los items del carrito.
+type CouponValidator interface {
+	// ValidateCoupon retorna el código normalizado si el cupón existe y aplica a los items de la intención.
+	ValidateCoupon(ctx context.Context, code string, intent checkoutdomain.PurchaseIntent) (string, error)
+}
@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	catalogports "paku-commerce/internal/commerce/cart/ports/catalog"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// AddCartItemInput contiene el item a agregar.
+type AddCartItemInput struct {
+	UserID           string
+	Item             checkoutdomain.PurchaseItem
+	ExpectedRevision *int // If-Match opcional
+}
+
+// AddCartItemOutput contiene el carrito actualizado.
+type AddCartItemOutput struct {
+	Cart cartdomain.Cart
+}
+
+// AddCartItem agrega un item al carrito existente del usuario.
+type AddCartItem struct {
+	Repo      cartdomain.CartRepository
+	Validator catalogports.ItemValidator // opcional
+	Now       func() time.Time
+}
+
+// Execute agrega el item (sumando cantidad si ya existe) y renueva TTL.
+func (uc AddCartItem) Execute(ctx context.Context, input AddCartItemInput) (AddCartItemOutput, error) {
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	cart, err := mutateCart(ctx, uc.Repo, uc.Validator, input.UserID, input.ExpectedRevision, now, func(cart *cartdomain.Cart) error {
+		return cart.AddItem(input.Item, now)
+	})
+	if err != nil {
+		return AddCartItemOutput{}, err
+	}
+
+	return AddCartItemOutput{Cart: cart}, nil
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	promotionsports "paku-commerce/internal/commerce/cart/ports/promotions"
+)
+
+// ApplyCartCouponInput contiene el código a aplicar.
+type ApplyCartCouponInput struct {
+	UserID           string
+	Code             string
+	ExpectedRevision *int // If-Match opcional
+}
+
+// ApplyCartCouponOutput contiene el carrito actualizado.
+type ApplyCartCouponOutput struct {
+	Cart cartdomain.Cart
+}
+
+// ApplyCartCoupon valida un cupón contra el carrito y lo guarda.
+type ApplyCartCoupon struct {
+	Repo    cartdomain.CartRepository
+	Coupons promotionsports.CouponValidator
+	Now     func() time.Time
+}
+
+// Execute valida el cupón con los items actuales y guarda el código normalizado.
+func (uc ApplyCartCoupon) Execute(ctx context.Context, input ApplyCartCouponInput) (ApplyCartCouponOutput, error) {
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	cart, err := mutateCart(ctx, uc.Repo, nil, input.UserID, input.ExpectedRevision, now, func(cart *cartdomain.Cart) error {
+		code, err := uc.Coupons.ValidateCoupon(ctx, input.Code, cart.PurchaseIntent())
+		if err != nil {
+			return err
+		}
+		cart.ApplyCoupon(code, now)
+		return nil
+	})
+	if err != nil {
+		return ApplyCartCouponOutput{}, err
+	}
+
+	return ApplyCartCouponOutput{Cart: cart}, nil
+}
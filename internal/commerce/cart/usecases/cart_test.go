@@ -2,13 +2,18 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	cartmemory "paku-commerce/internal/commerce/cart/adapters/memory"
 	cartdomain "paku-commerce/internal/commerce/cart/domain"
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
 	servicedomain "paku-commerce/internal/commerce/service/domain"
+	"paku-commerce/internal/platform/lock"
+	pricingusecases "paku-commerce/internal/pricing/usecases"
 )
 
 func TestUpsertCart_CreatesNew(t *testing.T) {
@@ -40,6 +45,238 @@ func TestUpsertCart_CreatesNew(t *testing.T) {
 	}
 }
 
+func TestUpsertCart_PetID_StoresResolvedProfile(t *testing.T) {
+	profile := servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 15, CoatType: servicedomain.CoatTypeDouble}
+	uc := &UpsertCart{
+		Repo: cartmemory.NewCartRepository(),
+		Pets: &platformpets.StubClient{Profiles: map[string]servicedomain.PetProfile{"max": profile}},
+	}
+
+	output, err := uc.Execute(context.Background(), UpsertCartInput{
+		UserID: "user_1",
+		PetID:  "max",
+		Items:  []checkoutdomain.PurchaseItem{{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.Cart.PetID != "max" || output.Cart.PetProfile != profile {
+		t.Errorf("expected cart with resolved profile of max, got: %s %+v", output.Cart.PetID, output.Cart.PetProfile)
+	}
+
+	_, err = uc.Execute(context.Background(), UpsertCartInput{
+		UserID: "user_1",
+		PetID:  "ghost",
+		Items:  []checkoutdomain.PurchaseItem{{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1}},
+	})
+	if !errors.Is(err, platformpets.ErrPetNotFound) {
+		t.Errorf("expected ErrPetNotFound, got: %v", err)
+	}
+}
+
+func TestUpsertCart_ExpectedRevisionMismatch_ReturnsConflict(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	uc := &UpsertCart{Repo: repo, Now: func() time.Time { return time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC) }}
+
+	input := UpsertCartInput{
+		UserID: "user_1",
+		Items: []checkoutdomain.PurchaseItem{
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+		},
+	}
+
+	created, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Cart.Revision != 1 {
+		t.Fatalf("expected revision 1, got: %d", created.Cart.Revision)
+	}
+
+	// Otra pestaña actualiza el carrito (revisión 1 -> 2)
+	rev := created.Cart.Revision
+	input.ExpectedRevision = &rev
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// La primera pestaña todavía tiene la revisión 1
+	input.Items = append(input.Items, checkoutdomain.PurchaseItem{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1})
+	if _, err := uc.Execute(context.Background(), input); err != cartdomain.ErrCartRevisionConflict {
+		t.Fatalf("expected ErrCartRevisionConflict, got: %v", err)
+	}
+
+	cart, _ := repo.GetByUserID(context.Background(), "user_1")
+	if len(cart.Items) != 1 || cart.Revision != 2 {
+		t.Errorf("expected cart untouched at revision 2, got %d items at revision %d", len(cart.Items), cart.Revision)
+	}
+
+	// Tampoco se elimina con una revisión desactualizada
+	del := &DeleteCart{Repo: repo}
+	if _, err := del.Execute(context.Background(), DeleteCartInput{UserID: "user_1", ExpectedRevision: &rev}); err != cartdomain.ErrCartRevisionConflict {
+		t.Errorf("expected ErrCartRevisionConflict on delete, got: %v", err)
+	}
+}
+
+func TestUpsertCart_PetProfileChange_RemovesItemsAndOrphanedAddons(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	uc := &UpsertCart{
+		Repo:      repo,
+		Validator: &stubItemValidator{},
+		Now:       func() time.Time { return time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC) },
+	}
+
+	input := UpsertCartInput{
+		UserID: "user_1",
+		PetProfile: servicedomain.PetProfile{
+			Species:  servicedomain.SpeciesDog,
+			WeightKg: 15,
+			CoatType: servicedomain.CoatTypeDouble,
+		},
+		Items: []checkoutdomain.PurchaseItem{
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1},
+			{ItemType: checkoutdomain.ItemTypeProduct, ItemID: "shampoo_basic", Qty: 1},
+		},
+	}
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Con 55kg bath no tiene precio; deshedding queda sin servicio base
+	input.PetProfile.WeightKg = 55
+	output, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(output.Cart.Items) != 1 || output.Cart.Items[0].ItemID != "shampoo_basic" {
+		t.Errorf("expected only shampoo_basic to remain, got: %+v", output.Cart.Items)
+	}
+	expected := map[int]error{
+		0: pricingusecases.ErrNoPriceRule,
+		1: checkoutusecases.ErrMissingParentService,
+	}
+	if len(output.Removed) != len(expected) {
+		t.Fatalf("expected %d removed items, got: %+v", len(expected), output.Removed)
+	}
+	for _, r := range output.Removed {
+		if expected[r.Index] != r.Reason {
+			t.Errorf("item %d: expected %v, got: %v", r.Index, expected[r.Index], r.Reason)
+		}
+	}
+}
+
+func TestUpsertCart_SamePetProfile_DoesNotMigrate(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	uc := &UpsertCart{
+		Repo:      repo,
+		Validator: &stubItemValidator{},
+		Now:       func() time.Time { return time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC) },
+	}
+
+	input := UpsertCartInput{
+		UserID: "user_1",
+		PetProfile: servicedomain.PetProfile{
+			Species:  servicedomain.SpeciesDog,
+			WeightKg: 15,
+			CoatType: servicedomain.CoatTypeDouble,
+		},
+		Items: []checkoutdomain.PurchaseItem{
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+		},
+	}
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Un addon enviado sin base sigue siendo un error del cliente, no una migración
+	input.Items = []checkoutdomain.PurchaseItem{
+		{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1},
+	}
+	if _, err := uc.Execute(context.Background(), input); !errors.Is(err, cartdomain.ErrInvalidCartItems) {
+		t.Fatalf("expected ErrInvalidCartItems, got: %v", err)
+	}
+}
+
+func TestCartItems_AddMergesUpdateAndRemove(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	start := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time { return now }
+
+	cart := cartdomain.NewCart("user_1", servicedomain.PetProfile{}, []checkoutdomain.PurchaseItem{{ItemType: "service", ItemID: "bath", Qty: 1}}, start)
+	repo.Upsert(context.Background(), cart)
+
+	add := &AddCartItem{Repo: repo, Now: clock}
+	update := &UpdateCartItem{Repo: repo, Now: clock}
+	remove := &RemoveCartItem{Repo: repo, Now: clock}
+
+	// Agregar un item existente suma la cantidad y renueva TTL
+	now = start.Add(30 * time.Minute)
+	out, err := add.Execute(context.Background(), AddCartItemInput{
+		UserID: "user_1",
+		Item:   checkoutdomain.PurchaseItem{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.Cart.Items) != 1 || out.Cart.Items[0].Qty != 3 {
+		t.Errorf("expected merged bath x3, got: %+v", out.Cart.Items)
+	}
+	if !out.Cart.ExpiresAt.Equal(now.Add(cartdomain.CartTTL)) {
+		t.Errorf("expected TTL renewed, got expires_at %v", out.Cart.ExpiresAt)
+	}
+
+	if _, err := add.Execute(context.Background(), AddCartItemInput{
+		UserID: "user_1",
+		Item:   checkoutdomain.PurchaseItem{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Cantidad inválida
+	_, err = update.Execute(context.Background(), UpdateCartItemInput{UserID: "user_1", ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 0})
+	if err != cartdomain.ErrInvalidQty {
+		t.Errorf("expected ErrInvalidQty, got: %v", err)
+	}
+
+	upd, err := update.Execute(context.Background(), UpdateCartItemInput{UserID: "user_1", ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if upd.Cart.Items[0].Qty != 1 {
+		t.Errorf("expected bath qty 1, got: %d", upd.Cart.Items[0].Qty)
+	}
+
+	rem, err := remove.Execute(context.Background(), RemoveCartItemInput{UserID: "user_1", ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rem.Cart.Items) != 1 || rem.Cart.Items[0].ItemID != "bath" {
+		t.Errorf("expected only bath left, got: %+v", rem.Cart.Items)
+	}
+
+	_, err = remove.Execute(context.Background(), RemoveCartItemInput{UserID: "user_1", ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding"})
+	if err != cartdomain.ErrCartItemNotFound {
+		t.Errorf("expected ErrCartItemNotFound, got: %v", err)
+	}
+}
+
+func TestAddCartItem_NoCart_ReturnsNotFound(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	uc := &AddCartItem{Repo: repo}
+
+	_, err := uc.Execute(context.Background(), AddCartItemInput{
+		UserID: "user_1",
+		Item:   checkoutdomain.PurchaseItem{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+	})
+	if err != cartdomain.ErrCartNotFound {
+		t.Errorf("expected ErrCartNotFound, got: %v", err)
+	}
+}
+
 func TestGetCart_Expired_ReturnsNotFound(t *testing.T) {
 	repo := cartmemory.NewCartRepository()
 	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
@@ -86,8 +323,152 @@ func TestExpireCarts_DeletesExpired(t *testing.T) {
 	}
 }
 
+func TestExpireCarts_ReportsCancelledHoldsAndOrders(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	items := []checkoutdomain.PurchaseItem{{ItemType: "service", ItemID: "bath", Qty: 1}}
+
+	holdID, orderID := "hold_1", "order_1"
+	withRefs := cartdomain.NewCart("user_1", servicedomain.PetProfile{}, items, now)
+	withRefs.BookingHoldID = &holdID
+	withRefs.OrderID = &orderID
+	repo.Upsert(context.Background(), withRefs)
+	repo.Upsert(context.Background(), cartdomain.NewCart("user_2", servicedomain.PetProfile{}, items, now))
+	holdOnlyID := "hold_2"
+	holdOnly := cartdomain.NewCart("user_3", servicedomain.PetProfile{}, items, now)
+	holdOnly.BookingHoldID = &holdOnlyID
+	repo.Upsert(context.Background(), holdOnly)
+
+	checkoutStub := &stubCheckoutClient{}
+	bookingStub := &stubBookingClient{}
+	uc := &ExpireCarts{Repo: repo, Booking: bookingStub, Checkout: checkoutStub, Locker: lock.NewMemoryLocker()}
+	output, err := uc.Execute(context.Background(), ExpireCartsInput{Now: now.Add(100 * time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// El hold de la orden lo libera CancelOrder; el carrito solo libera el hold sin orden
+	expected := ExpireCartsOutput{ExpiredCount: 3, CancelledHolds: 1, CancelledOrders: 1}
+	if output != expected {
+		t.Errorf("expected %+v, got %+v", expected, output)
+	}
+	if len(bookingStub.cancelled) != 1 || bookingStub.cancelled[0] != holdOnlyID {
+		t.Errorf("expected only the orphan hold released by cart, got: %v", bookingStub.cancelled)
+	}
+	if len(checkoutStub.reasons) != 1 || checkoutStub.reasons[0] != checkoutdomain.CancelReasonCartExpired {
+		t.Errorf("expected order cancelled with cart_expired, got: %v", checkoutStub.reasons)
+	}
+}
+
+func TestExpireCarts_HoldReleaseFails_ReportsIncident(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	holdID := "hold_1"
+	cart := cartdomain.NewCart("user_1", servicedomain.PetProfile{}, []checkoutdomain.PurchaseItem{{ItemType: "service", ItemID: "bath", Qty: 1}}, now)
+	cart.BookingHoldID = &holdID
+	repo.Upsert(context.Background(), cart)
+
+	checkoutStub := &stubCheckoutClient{}
+	uc := &ExpireCarts{Repo: repo, Booking: &failingCancelBookingClient{}, Checkout: checkoutStub, Incidents: checkoutStub}
+	output, err := uc.Execute(context.Background(), ExpireCartsInput{Now: now.Add(100 * time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// El carrito se limpia igual y el hold queda en la cola de revisión
+	if output.ExpiredCount != 1 || output.CancelledHolds != 0 {
+		t.Errorf("expected cart expired without released hold, got %+v", output)
+	}
+	if len(checkoutStub.incidents) != 1 || checkoutStub.incidents[0] != "/hold_1" {
+		t.Errorf("expected hold release incident, got: %v", checkoutStub.incidents)
+	}
+}
+
+func TestExpireCarts_PaidOrder_KeepsHold(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	holdID, orderID := "hold_1", "order_paid"
+	cart := cartdomain.NewCart("user_1", servicedomain.PetProfile{}, []checkoutdomain.PurchaseItem{{ItemType: "service", ItemID: "bath", Qty: 1}}, now)
+	cart.BookingHoldID = &holdID
+	cart.OrderID = &orderID
+	repo.Upsert(context.Background(), cart)
+
+	// CancelOrder rechaza la orden pagada: su hold confirmado no se libera
+	checkoutStub := &stubCheckoutClient{err: &checkoutdomain.TransitionError{From: checkoutdomain.OrderStatusPaid, To: checkoutdomain.OrderStatusCancelled}}
+	bookingStub := &stubBookingClient{}
+	uc := &ExpireCarts{Repo: repo, Booking: bookingStub, Checkout: checkoutStub}
+	output, err := uc.Execute(context.Background(), ExpireCartsInput{Now: now.Add(100 * time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.ExpiredCount != 1 || output.CancelledOrders != 0 || output.CancelledHolds != 0 {
+		t.Errorf("expected cart cleaned without cancelling anything, got %+v", output)
+	}
+	if len(bookingStub.cancelled) != 0 {
+		t.Errorf("expected paid order hold untouched, got: %v", bookingStub.cancelled)
+	}
+}
+
+func TestExpireCarts_CartUpdatedConcurrently_IsKept(t *testing.T) {
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	repo := &racingCartRepository{CartRepository: cartmemory.NewCartRepository()}
+	repo.Upsert(context.Background(), cartdomain.NewCart("user_1", servicedomain.PetProfile{}, []checkoutdomain.PurchaseItem{{ItemType: "service", ItemID: "bath", Qty: 1}}, now))
+
+	uc := &ExpireCarts{Repo: repo, Booking: &stubBookingClient{}, Checkout: &stubCheckoutClient{}}
+	output, err := uc.Execute(context.Background(), ExpireCartsInput{Now: now.Add(100 * time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.ExpiredCount != 0 {
+		t.Errorf("expected no expired carts, got %+v", output)
+	}
+	if _, err := repo.GetByUserID(context.Background(), "user_1"); err != nil {
+		t.Errorf("expected updated cart to remain, got: %v", err)
+	}
+}
+
+func TestExpireCarts_LockHeldElsewhere_Skips(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	repo.Upsert(context.Background(), cartdomain.NewCart("user_1", servicedomain.PetProfile{}, []checkoutdomain.PurchaseItem{{ItemType: "service", ItemID: "bath", Qty: 1}}, now))
+
+	locker := lock.NewMemoryLocker()
+	unlock, _, _ := locker.TryLock(context.Background(), ExpireCartsLockKey) // otra réplica
+	defer unlock()
+
+	uc := &ExpireCarts{Repo: repo, Booking: &stubBookingClient{}, Checkout: &stubCheckoutClient{}, Locker: locker}
+	output, err := uc.Execute(context.Background(), ExpireCartsInput{Now: now.Add(100 * time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !output.Skipped || output.ExpiredCount != 0 {
+		t.Errorf("expected skipped run, got %+v", output)
+	}
+	if _, err := repo.GetByUserID(context.Background(), "user_1"); err != nil {
+		t.Errorf("expected cart to remain, got: %v", err)
+	}
+}
+
+// racingCartRepository simula que el usuario actualiza su carrito justo después de ListExpired.
+type racingCartRepository struct {
+	*cartmemory.CartRepository
+}
+
+func (r *racingCartRepository) ListExpired(ctx context.Context, now time.Time) ([]cartdomain.Cart, error) {
+	carts, err := r.CartRepository.ListExpired(ctx, now)
+	for _, cart := range carts {
+		cart.UpdateCart(cart.PetProfile, cart.Items, now)
+		_, _ = r.CartRepository.Upsert(ctx, cart)
+	}
+	return carts, err
+}
+
 // Stubs para tests
-type stubBookingClient struct{}
+type stubBookingClient struct {
+	cancelled []string
+}
 
 func (s *stubBookingClient) CreateHold(ctx context.Context, slotID string) (string, error) {
 	return "", nil
@@ -102,11 +483,67 @@ func (s *stubBookingClient) ConfirmHold(ctx context.Context, holdID string) erro
 }
 
 func (s *stubBookingClient) CancelHold(ctx context.Context, holdID string) error {
+	s.cancelled = append(s.cancelled, holdID)
 	return nil
 }
 
-type stubCheckoutClient struct{}
+// failingCancelBookingClient simula booking caído al liberar holds.
+type failingCancelBookingClient struct {
+	stubBookingClient
+}
+
+func (s *failingCancelBookingClient) CancelHold(ctx context.Context, holdID string) error {
+	return errors.New("booking unavailable")
+}
+
+// stubItemValidator imita las reglas del catálogo: bath tiene precio hasta 40kg,
+// deshedding no aplica a pelo corto y requiere bath.
+type stubItemValidator struct{}
+
+func (s *stubItemValidator) ValidateItems(ctx context.Context, intent checkoutdomain.PurchaseIntent) ([]checkoutdomain.ItemViolation, error) {
+	hasBath := make(map[string]bool)
+	for _, item := range intent.Items {
+		if item.ItemID == "bath" {
+			hasBath[item.PetID] = true
+		}
+	}
+
+	var violations []checkoutdomain.ItemViolation
+	for i, item := range intent.Items {
+		pet, ok := intent.PetProfileFor(item.PetID)
+		var reason error
+		switch {
+		case !ok:
+			reason = checkoutusecases.ErrUnknownPet
+		case item.ItemID == "bath" && pet.WeightKg > 40:
+			reason = pricingusecases.ErrNoPriceRule
+		case item.ItemID == "deshedding" && pet.CoatType == servicedomain.CoatTypeShort:
+			reason = checkoutusecases.ErrServiceNotEligible
+		case item.ItemID == "deshedding" && !hasBath[item.PetID]:
+			reason = checkoutusecases.ErrMissingParentService
+		}
+		if reason != nil {
+			violations = append(violations, checkoutdomain.ItemViolation{Index: i, Item: item, Reason: reason})
+		}
+	}
+	return violations, nil
+}
+
+type stubCheckoutClient struct {
+	reasons   []checkoutdomain.CancelReason
+	incidents []string // "orderID/holdID" reportados
+	err       error    // error que retorna CancelOrder
+}
+
+func (s *stubCheckoutClient) CancelOrder(ctx context.Context, orderID string, reason checkoutdomain.CancelReason) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.reasons = append(s.reasons, reason)
+	return nil
+}
 
-func (s *stubCheckoutClient) CancelOrder(ctx context.Context, orderID string) error {
+func (s *stubCheckoutClient) ReportHoldReleaseFailure(ctx context.Context, orderID, holdID string, cause error) error {
+	s.incidents = append(s.incidents, orderID+"/"+holdID)
 	return nil
 }
@@ -9,6 +9,9 @@ import (
 // DeleteCartInput contiene el user_id.
 type DeleteCartInput struct {
 	UserID string
+
+	// ExpectedRevision (If-Match) exige que el carrito exista con esa revisión. nil = sin precondición.
+	ExpectedRevision *int
 }
 
 // DeleteCartOutput es vacío.
@@ -25,6 +28,13 @@ func (uc DeleteCart) Execute(ctx context.Context, input DeleteCartInput) (Delete
 		return DeleteCartOutput{}, cartdomain.ErrInvalidUserID
 	}
 
+	if input.ExpectedRevision != nil {
+		if err := uc.Repo.DeleteIfRevision(ctx, input.UserID, *input.ExpectedRevision); err != nil {
+			return DeleteCartOutput{}, err
+		}
+		return DeleteCartOutput{}, nil
+	}
+
 	err := uc.Repo.DeleteByUserID(ctx, input.UserID)
 	if err != nil && err != cartdomain.ErrCartNotFound {
 		return DeleteCartOutput{}, err
@@ -2,13 +2,19 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	cartdomain "paku-commerce/internal/commerce/cart/domain"
 	checkoutports "paku-commerce/internal/commerce/cart/ports/checkout"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
 	platformbooking "paku-commerce/internal/commerce/platform/booking"
+	"paku-commerce/internal/platform/lock"
 )
 
+// ExpireCartsLockKey es la clave del lock que serializa la expiración entre réplicas.
+const ExpireCartsLockKey = "cart-expiry"
+
 // ExpireCartsInput contiene el timestamp de referencia.
 type ExpireCartsInput struct {
 	Now time.Time
@@ -16,45 +22,74 @@ type ExpireCartsInput struct {
 
 // ExpireCartsOutput contiene estadísticas de expiración.
 type ExpireCartsOutput struct {
-	ExpiredCount int
+	ExpiredCount    int
+	CancelledHolds  int
+	CancelledOrders int
+	// Skipped indica que otra réplica tenía el lock y no se procesó nada.
+	Skipped bool
 }
 
 // ExpireCarts limpia carritos vencidos y ejecuta side-effects.
 type ExpireCarts struct {
-	Repo     cartdomain.CartRepository
-	Booking  platformbooking.Client
-	Checkout checkoutports.CheckoutClient
+	Repo      cartdomain.CartRepository
+	Booking   platformbooking.Client
+	Checkout  checkoutports.CheckoutClient
+	Incidents checkoutports.IncidentReporter // opcional: registra los holds que no se liberaron
+	Locker    lock.Locker                    // opcional: evita que dos réplicas procesen los mismos carritos
 }
 
 // Execute expira carritos vencidos.
 func (uc ExpireCarts) Execute(ctx context.Context, input ExpireCartsInput) (ExpireCartsOutput, error) {
+	if uc.Locker != nil {
+		unlock, acquired, err := uc.Locker.TryLock(ctx, ExpireCartsLockKey)
+		if err != nil {
+			return ExpireCartsOutput{}, err
+		}
+		if !acquired {
+			return ExpireCartsOutput{Skipped: true}, nil
+		}
+		defer unlock()
+	}
+
 	expiredCarts, err := uc.Repo.ListExpired(ctx, input.Now)
 	if err != nil {
 		return ExpireCartsOutput{}, err
 	}
 
-	count := 0
+	var output ExpireCartsOutput
 	for _, cart := range expiredCarts {
-		// Cancelar hold si existe
-		if cart.BookingHoldID != nil && *cart.BookingHoldID != "" {
-			// Best-effort: ignorar errores (ej. hold ya cancelado/no existe)
-			_ = uc.Booking.CancelHold(ctx, *cart.BookingHoldID)
-		}
+		hasHold := cart.BookingHoldID != nil && *cart.BookingHoldID != ""
 
-		// Cancelar orden si existe
 		if cart.OrderID != nil && *cart.OrderID != "" {
-			// Best-effort: ignorar errores (ej. orden ya cancelada/pagada)
-			_ = uc.Checkout.CancelOrder(ctx, *cart.OrderID)
+			// La orden es dueña del hold: CancelOrder lo libera solo si la orden no se pagó
+			err := uc.Checkout.CancelOrder(ctx, *cart.OrderID, checkoutdomain.CancelReasonCartExpired)
+			switch {
+			case err == nil:
+				output.CancelledOrders++
+			case errors.Is(err, checkoutdomain.ErrInvalidOrderState), errors.Is(err, checkoutdomain.ErrOrderNotFound):
+				// Orden pagada (o inexistente): su hold no se toca y el carrito se limpia igual
+			default:
+				// Falla transitoria: conservar el carrito para reintentar en la próxima pasada
+				continue
+			}
+		} else if hasHold {
+			// Hold sin orden: el carrito es su único dueño.
+			// Best-effort: el carrito se limpia igual y la falla queda en la cola de revisión
+			if err := uc.Booking.CancelHold(ctx, *cart.BookingHoldID); err == nil {
+				output.CancelledHolds++
+			} else if uc.Incidents != nil {
+				_ = uc.Incidents.ReportHoldReleaseFailure(ctx, "", *cart.BookingHoldID, err)
+			}
 		}
 
-		// Eliminar carrito
-		if err := uc.Repo.DeleteByUserID(ctx, cart.UserID); err != nil {
+		// Eliminar carrito solo si nadie lo modificó desde ListExpired
+		if err := uc.Repo.DeleteIfRevision(ctx, cart.UserID, cart.Revision); err != nil {
 			// Log pero no fallar el proceso completo
 			continue
 		}
 
-		count++
+		output.ExpiredCount++
 	}
 
-	return ExpireCartsOutput{ExpiredCount: count}, nil
+	return output, nil
 }
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	catalogports "paku-commerce/internal/commerce/cart/ports/catalog"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// maxCartMutationAttempts limita los reintentos ante escrituras concurrentes sin If-Match.
+const maxCartMutationAttempts = 3
+
+// mutateCart carga el carrito vigente, aplica mutate, valida contra el catálogo y lo persiste
+// (compare-and-swap por revisión).
+// Sin expectedRevision reintenta ante ErrCartRevisionConflict: las operaciones por item
+// se re-aplican sobre el carrito actualizado sin pisar cambios ajenos.
+func mutateCart(
+	ctx context.Context,
+	repo cartdomain.CartRepository,
+	validator catalogports.ItemValidator,
+	userID string,
+	expectedRevision *int,
+	now time.Time,
+	mutate func(cart *cartdomain.Cart) error,
+) (cartdomain.Cart, error) {
+	if userID == "" {
+		return cartdomain.Cart{}, cartdomain.ErrInvalidUserID
+	}
+
+	var err error
+	for attempt := 0; attempt < maxCartMutationAttempts; attempt++ {
+		var cart cartdomain.Cart
+		cart, err = repo.GetByUserID(ctx, userID)
+		if err != nil {
+			return cartdomain.Cart{}, err
+		}
+		if cart.IsExpired(now) {
+			return cartdomain.Cart{}, cartdomain.ErrCartNotFound
+		}
+		if expectedRevision != nil && *expectedRevision != cart.Revision {
+			return cartdomain.Cart{}, cartdomain.ErrCartRevisionConflict
+		}
+
+		if err := mutate(&cart); err != nil {
+			return cartdomain.Cart{}, err
+		}
+		if err := validateCartItems(ctx, validator, cart.PurchaseIntent()); err != nil {
+			return cartdomain.Cart{}, err
+		}
+
+		cart, err = repo.Upsert(ctx, cart)
+		if err == nil {
+			return cart, nil
+		}
+		if expectedRevision != nil || !errors.Is(err, cartdomain.ErrCartRevisionConflict) {
+			return cartdomain.Cart{}, err
+		}
+	}
+
+	return cartdomain.Cart{}, err
+}
+
+// validateCartItems retorna *cartdomain.InvalidItemsError si algún item no cumple el catálogo.
+// Sin validator no se valida (tests y wiring mínimo).
+func validateCartItems(
+	ctx context.Context,
+	validator catalogports.ItemValidator,
+	intent checkoutdomain.PurchaseIntent,
+) error {
+	if err := checkoutdomain.ValidatePets(intent.Pets); err != nil {
+		return err
+	}
+	if validator == nil {
+		return nil
+	}
+
+	violations, err := validator.ValidateItems(ctx, intent)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &cartdomain.InvalidItemsError{Violations: violations}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	catalogports "paku-commerce/internal/commerce/cart/ports/catalog"
+	checkoutports "paku-commerce/internal/commerce/cart/ports/checkout"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+	promotionsdomain "paku-commerce/internal/promotions/domain"
+	promotionsusecases "paku-commerce/internal/promotions/usecases"
+)
+
+// QuoteCartInput contiene el user_id.
+type QuoteCartInput struct {
+	UserID string
+}
+
+// QuoteCartOutput contiene el carrito con su cotización vigente.
+type QuoteCartOutput struct {
+	Cart  cartdomain.Cart
+	Quote checkoutusecases.CheckoutQuote
+	// Issues son los items que dejaron de ser válidos (inelegibles, sin precio, etc.).
+	// No se incluyen en Quote.
+	Issues []checkoutdomain.ItemViolation
+	// CouponError indica por qué el cupón guardado ya no aplica (la cotización se hace sin él).
+	CouponError error
+}
+
+// QuoteCart cotiza el carrito guardado con precios y promociones actuales.
+type QuoteCart struct {
+	Repo      cartdomain.CartRepository
+	Validator catalogports.ItemValidator
+	Quoter    checkoutports.Quoter
+	Now       func() time.Time
+}
+
+// Execute obtiene el carrito (considerando expiración) y lo cotiza excluyendo los items inválidos.
+func (uc QuoteCart) Execute(ctx context.Context, input QuoteCartInput) (QuoteCartOutput, error) {
+	getCart := GetCart{Repo: uc.Repo, Now: uc.Now}
+	cartOutput, err := getCart.Execute(ctx, GetCartInput{UserID: input.UserID})
+	if err != nil {
+		return QuoteCartOutput{}, err
+	}
+	cart := cartOutput.Cart
+
+	issues, err := uc.Validator.ValidateItems(ctx, cart.PurchaseIntent())
+	if err != nil {
+		return QuoteCartOutput{}, err
+	}
+
+	invalid := make(map[int]bool, len(issues))
+	for _, issue := range issues {
+		invalid[issue.Index] = true
+	}
+	validItems := make([]checkoutdomain.PurchaseItem, 0, len(cart.Items))
+	for i, item := range cart.Items {
+		if !invalid[i] {
+			validItems = append(validItems, item)
+		}
+	}
+
+	quote := emptyQuote()
+	var couponErr error
+	if len(validItems) > 0 {
+		intent := cart.PurchaseIntent()
+		intent.Items = validItems
+		quote, err = uc.Quoter.Quote(ctx, intent)
+		if err != nil && intent.CouponCode != nil && isCouponError(err) {
+			// El cupón dejó de aplicar (p. ej. cambió el subtotal): cotizar sin él e informarlo
+			couponErr = err
+			intent.CouponCode = nil
+			quote, err = uc.Quoter.Quote(ctx, intent)
+		}
+		if err != nil {
+			return QuoteCartOutput{}, err
+		}
+	}
+
+	return QuoteCartOutput{
+		Cart:        cart,
+		Quote:       quote,
+		Issues:      issues,
+		CouponError: couponErr,
+	}, nil
+}
+
+func isCouponError(err error) bool {
+	return errors.Is(err, promotionsusecases.ErrInvalidCoupon) || errors.Is(err, promotionsdomain.ErrCouponNotFound)
+}
+
+func emptyQuote() checkoutusecases.CheckoutQuote {
+	zero := pricingdomain.Zero(pricingdomain.CurrencyPEN)
+	return checkoutusecases.CheckoutQuote{
+		OriginalSubtotal: zero,
+		Quote:            pricingdomain.Quote{Subtotal: zero},
+		TotalDiscount:    zero,
+		Total:            zero,
+	}
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+)
+
+// RemoveCartCouponInput contiene el user_id.
+type RemoveCartCouponInput struct {
+	UserID           string
+	ExpectedRevision *int // If-Match opcional
+}
+
+// RemoveCartCouponOutput contiene el carrito actualizado.
+type RemoveCartCouponOutput struct {
+	Cart cartdomain.Cart
+}
+
+// RemoveCartCoupon quita el cupón del carrito.
+type RemoveCartCoupon struct {
+	Repo cartdomain.CartRepository
+	Now  func() time.Time
+}
+
+// Execute quita el cupón (idempotente) y renueva TTL.
+func (uc RemoveCartCoupon) Execute(ctx context.Context, input RemoveCartCouponInput) (RemoveCartCouponOutput, error) {
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	cart, err := mutateCart(ctx, uc.Repo, nil, input.UserID, input.ExpectedRevision, now, func(cart *cartdomain.Cart) error {
+		cart.RemoveCoupon(now)
+		return nil
+	})
+	if err != nil {
+		return RemoveCartCouponOutput{}, err
+	}
+
+	return RemoveCartCouponOutput{Cart: cart}, nil
+}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	catalogports "paku-commerce/internal/commerce/cart/ports/catalog"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// RemoveCartItemInput identifica el item a quitar.
+type RemoveCartItemInput struct {
+	UserID           string
+	PetID            string // "" = pet principal
+	ItemType         checkoutdomain.ItemType
+	ItemID           string
+	ExpectedRevision *int // If-Match opcional
+}
+
+// RemoveCartItemOutput contiene el carrito actualizado.
+type RemoveCartItemOutput struct {
+	Cart cartdomain.Cart
+}
+
+// RemoveCartItem quita un item del carrito.
+type RemoveCartItem struct {
+	Repo      cartdomain.CartRepository
+	Validator catalogports.ItemValidator // opcional
+	Now       func() time.Time
+}
+
+// Execute quita el item y renueva TTL.
+func (uc RemoveCartItem) Execute(ctx context.Context, input RemoveCartItemInput) (RemoveCartItemOutput, error) {
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	cart, err := mutateCart(ctx, uc.Repo, uc.Validator, input.UserID, input.ExpectedRevision, now, func(cart *cartdomain.Cart) error {
+		return cart.RemoveItem(input.PetID, input.ItemType, input.ItemID, now)
+	})
+	if err != nil {
+		return RemoveCartItemOutput{}, err
+	}
+
+	return RemoveCartItemOutput{Cart: cart}, nil
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	catalogports "paku-commerce/internal/commerce/cart/ports/catalog"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// UpdateCartItemInput contiene el item y su nueva cantidad.
+type UpdateCartItemInput struct {
+	UserID           string
+	PetID            string // "" = pet principal
+	ItemType         checkoutdomain.ItemType
+	ItemID           string
+	Qty              int
+	ExpectedRevision *int // If-Match opcional
+}
+
+// UpdateCartItemOutput contiene el carrito actualizado.
+type UpdateCartItemOutput struct {
+	Cart cartdomain.Cart
+}
+
+// UpdateCartItem cambia la cantidad de un item del carrito.
+type UpdateCartItem struct {
+	Repo      cartdomain.CartRepository
+	Validator catalogports.ItemValidator // opcional
+	Now       func() time.Time
+}
+
+// Execute reemplaza la cantidad del item y renueva TTL.
+func (uc UpdateCartItem) Execute(ctx context.Context, input UpdateCartItemInput) (UpdateCartItemOutput, error) {
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	cart, err := mutateCart(ctx, uc.Repo, uc.Validator, input.UserID, input.ExpectedRevision, now, func(cart *cartdomain.Cart) error {
+		return cart.SetItemQty(input.PetID, input.ItemType, input.ItemID, input.Qty, now)
+	})
+	if err != nil {
+		return UpdateCartItemOutput{}, err
+	}
+
+	return UpdateCartItemOutput{Cart: cart}, nil
+}
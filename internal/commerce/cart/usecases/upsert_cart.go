@@ -2,31 +2,46 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	catalogports "paku-commerce/internal/commerce/cart/ports/catalog"
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
 	servicedomain "paku-commerce/internal/commerce/service/domain"
+	pricingusecases "paku-commerce/internal/pricing/usecases"
 )
 
 // UpsertCartInput contiene los datos para crear/actualizar carrito.
 type UpsertCartInput struct {
 	UserID        string
+	PetID         string // si PetProfile viene vacío, se resuelve con el servicio de mascotas
 	PetProfile    servicedomain.PetProfile
+	Pets          []checkoutdomain.Pet // mascotas adicionales (items con PetID)
 	Items         []checkoutdomain.PurchaseItem
 	BookingHoldID *string
 	OrderID       *string
+
+	// ExpectedRevision (If-Match) exige que el carrito exista con esa revisión. nil = sin precondición.
+	ExpectedRevision *int
 }
 
 // UpsertCartOutput contiene el carrito creado/actualizado.
 type UpsertCartOutput struct {
 	Cart cartdomain.Cart
+	// Removed son los items quitados automáticamente porque dejaron de aplicar
+	// al nuevo perfil de su mascota (Index es la posición en input.Items).
+	Removed []checkoutdomain.ItemViolation
 }
 
 // UpsertCart crea o actualiza un carrito.
 type UpsertCart struct {
-	Repo cartdomain.CartRepository
-	Now  func() time.Time
+	Repo      cartdomain.CartRepository
+	Validator catalogports.ItemValidator // opcional: valida items contra el catálogo
+	Pets      platformpets.Client        // opcional: resuelve perfiles enviados solo por pet_id
+	Now       func() time.Time
 }
 
 // Execute crea o actualiza el carrito del usuario.
@@ -52,18 +67,59 @@ func (uc UpsertCart) Execute(ctx context.Context, input UpsertCartInput) (Upsert
 	// Intentar obtener carrito existente
 	existingCart, err := uc.Repo.GetByUserID(ctx, input.UserID)
 
+	intent := checkoutdomain.PurchaseIntent{PetID: input.PetID, PetProfile: input.PetProfile, Pets: input.Pets, Items: input.Items}
+	if err := checkoutdomain.ValidatePets(intent.Pets); err != nil {
+		return UpsertCartOutput{}, err
+	}
+
+	// El carrito guarda el perfil resuelto: así la migración y el checkout usan el mismo snapshot
+	resolved, resolveErr := checkoutusecases.ResolvePetProfiles{Pets: uc.Pets}.Execute(ctx, checkoutusecases.ResolvePetProfilesInput{Intent: intent})
+	if resolveErr != nil {
+		return UpsertCartOutput{}, resolveErr
+	}
+	intent = resolved.Intent
+
+	// Si cambió el perfil de alguna mascota, quitar sus items que ya no aplican antes de validar
+	var removed []checkoutdomain.ItemViolation
+	if err == nil {
+		if changed := changedPets(existingCart, intent); len(changed) > 0 {
+			var migrateErr error
+			intent.Items, removed, migrateErr = uc.migrateItems(ctx, intent, changed)
+			if migrateErr != nil {
+				return UpsertCartOutput{}, migrateErr
+			}
+			if len(intent.Items) == 0 {
+				return UpsertCartOutput{}, &cartdomain.InvalidItemsError{Violations: removed}
+			}
+		}
+	}
+	items := intent.Items
+
+	if err := validateCartItems(ctx, uc.Validator, intent); err != nil {
+		return UpsertCartOutput{}, err
+	}
+
 	var cart cartdomain.Cart
-	if err == cartdomain.ErrCartNotFound {
+	if errors.Is(err, cartdomain.ErrCartNotFound) {
+		if input.ExpectedRevision != nil {
+			return UpsertCartOutput{}, cartdomain.ErrCartRevisionConflict
+		}
 		// Crear nuevo
-		cart = cartdomain.NewCart(input.UserID, input.PetProfile, input.Items, now)
+		cart = cartdomain.NewCart(input.UserID, intent.PetProfile, items, now)
 	} else if err != nil {
 		return UpsertCartOutput{}, err
 	} else {
-		// Actualizar existente
+		if input.ExpectedRevision != nil && *input.ExpectedRevision != existingCart.Revision {
+			return UpsertCartOutput{}, cartdomain.ErrCartRevisionConflict
+		}
+		// Actualizar existente (el repo vuelve a verificar la revisión al persistir)
 		cart = existingCart
-		cart.UpdateCart(input.PetProfile, input.Items, now)
+		cart.UpdateCart(intent.PetProfile, items, now)
 	}
 
+	cart.PetID = intent.PetID
+	cart.Pets = intent.Pets
+
 	// Actualizar referencias opcionales
 	cart.BookingHoldID = input.BookingHoldID
 	cart.OrderID = input.OrderID
@@ -74,5 +130,86 @@ func (uc UpsertCart) Execute(ctx context.Context, input UpsertCartInput) (Upsert
 		return UpsertCartOutput{}, err
 	}
 
-	return UpsertCartOutput{Cart: updatedCart}, nil
+	return UpsertCartOutput{Cart: updatedCart, Removed: removed}, nil
+}
+
+// changedPets retorna los IDs de mascota ("" = pet principal) cuyo perfil cambió respecto al carrito guardado.
+// Una mascota nueva no cuenta como cambio: sus items se validan normalmente.
+func changedPets(existing cartdomain.Cart, intent checkoutdomain.PurchaseIntent) map[string]bool {
+	changed := make(map[string]bool)
+	stored := existing.PurchaseIntent()
+	if stored.PetProfile != intent.PetProfile {
+		changed[""] = true
+	}
+	for _, pet := range intent.Pets {
+		if profile, ok := stored.PetProfileFor(pet.ID); ok && profile != pet.PetProfile {
+			changed[pet.ID] = true
+		}
+	}
+	return changed
+}
+
+// migrateItems quita los items de las mascotas cuyo perfil cambió y que dejaron de aplicar:
+// servicios no elegibles o sin precio, y addons cuyo servicio base se quitó.
+// Los demás errores (servicio desconocido, addon enviado sin base) se dejan a la validación normal.
+func (uc UpsertCart) migrateItems(
+	ctx context.Context,
+	intent checkoutdomain.PurchaseIntent,
+	changed map[string]bool,
+) ([]checkoutdomain.PurchaseItem, []checkoutdomain.ItemViolation, error) {
+	items := intent.Items
+	if uc.Validator == nil {
+		return items, nil, nil
+	}
+
+	// positions mapea el índice en kept al índice original en items
+	kept := items
+	positions := make([]int, len(items))
+	for i := range positions {
+		positions[i] = i
+	}
+
+	var removed []checkoutdomain.ItemViolation
+	missingParentBefore := make(map[int]bool) // addons enviados sin base: no se migran
+	for pass := 0; ; pass++ {
+		intent.Items = kept
+		violations, err := uc.Validator.ValidateItems(ctx, intent)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		drop := make(map[int]error)
+		for _, v := range violations {
+			if !changed[v.Item.PetID] {
+				continue
+			}
+			orphaned := errors.Is(v.Reason, checkoutusecases.ErrMissingParentService)
+			if pass == 0 && orphaned {
+				missingParentBefore[positions[v.Index]] = true
+			}
+			if isPetDependent(v.Reason) || (orphaned && !missingParentBefore[positions[v.Index]]) {
+				drop[v.Index] = v.Reason
+			}
+		}
+		if len(drop) == 0 {
+			return kept, removed, nil
+		}
+
+		nextKept := make([]checkoutdomain.PurchaseItem, 0, len(kept)-len(drop))
+		nextPositions := make([]int, 0, len(kept)-len(drop))
+		for i, item := range kept {
+			if reason, ok := drop[i]; ok {
+				removed = append(removed, checkoutdomain.ItemViolation{Index: positions[i], Item: item, Reason: reason})
+				continue
+			}
+			nextKept = append(nextKept, item)
+			nextPositions = append(nextPositions, positions[i])
+		}
+		kept, positions = nextKept, nextPositions
+	}
+}
+
+// isPetDependent indica si la violación se debe al pet_profile (y no al item en sí).
+func isPetDependent(reason error) bool {
+	return errors.Is(reason, checkoutusecases.ErrServiceNotEligible) || errors.Is(reason, pricingusecases.ErrNoPriceRule)
 }
@@ -0,0 +1,73 @@
+// Package worker contiene las tareas de fondo del módulo cart.
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	cartusecases "paku-commerce/internal/commerce/cart/usecases"
+	"paku-commerce/internal/platform/audit"
+)
+
+// DefaultExpiryInterval es la frecuencia por defecto del barrido de carritos vencidos.
+const DefaultExpiryInterval = time.Minute
+
+// ExpiryWorker ejecuta ExpireCarts periódicamente dentro del proceso.
+// El lock del usecase evita que varias réplicas procesen los mismos carritos.
+type ExpiryWorker struct {
+	ExpireCartsUC *cartusecases.ExpireCarts
+	Interval      time.Duration // 0 = DefaultExpiryInterval
+	Now           func() time.Time
+	Logger        *log.Logger // opcional: log.Default()
+}
+
+// Run barre carritos vencidos en cada tick hasta que ctx se cancele.
+// Un barrido en curso termina antes de retornar (cortado por ctx si tarda).
+func (w *ExpiryWorker) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultExpiryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce ejecuta un barrido y registra cuántos carritos, holds y órdenes limpió.
+func (w *ExpiryWorker) RunOnce(ctx context.Context) (cartusecases.ExpireCartsOutput, error) {
+	now := time.Now()
+	if w.Now != nil {
+		now = w.Now()
+	}
+
+	// Las cancelaciones de órdenes quedan en su historial con el worker como actor
+	ctx = audit.WithActor(ctx, audit.ActorCartExpiry)
+	output, err := w.ExpireCartsUC.Execute(ctx, cartusecases.ExpireCartsInput{Now: now})
+	switch {
+	case err != nil:
+		w.logger().Printf("cart expiry: %v", err)
+	case output.Skipped:
+		// Otra réplica tiene el lock: nada que reportar
+	case output.ExpiredCount > 0:
+		w.logger().Printf("cart expiry: expired=%d cancelled_holds=%d cancelled_orders=%d",
+			output.ExpiredCount, output.CancelledHolds, output.CancelledOrders)
+	}
+	return output, err
+}
+
+func (w *ExpiryWorker) logger() *log.Logger {
+	if w.Logger != nil {
+		return w.Logger
+	}
+	return log.Default()
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	cartmemory "paku-commerce/internal/commerce/cart/adapters/memory"
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	cartusecases "paku-commerce/internal/commerce/cart/usecases"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformbooking "paku-commerce/internal/commerce/platform/booking"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	"paku-commerce/internal/platform/lock"
+)
+
+type noopCheckoutClient struct{}
+
+func (noopCheckoutClient) CancelOrder(ctx context.Context, orderID string, reason checkoutdomain.CancelReason) error {
+	return nil
+}
+
+func TestExpiryWorker_RunExpiresCartsAndStopsOnCancel(t *testing.T) {
+	repo := cartmemory.NewCartRepository()
+	created := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	holdID := "hold_1"
+	cart := cartdomain.NewCart("user_1", servicedomain.PetProfile{}, []checkoutdomain.PurchaseItem{{ItemType: "service", ItemID: "bath", Qty: 1}}, created)
+	cart.BookingHoldID = &holdID
+	repo.Upsert(context.Background(), cart)
+
+	var logs bytes.Buffer
+	w := &ExpiryWorker{
+		ExpireCartsUC: &cartusecases.ExpireCarts{
+			Repo:     repo,
+			Booking:  &platformbooking.StubClient{},
+			Checkout: noopCheckoutClient{},
+			Locker:   lock.NewMemoryLocker(),
+		},
+		Interval: 5 * time.Millisecond,
+		Now:      func() time.Time { return created.Add(2 * cartdomain.CartTTL) },
+		Logger:   log.New(&logs, "", 0),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		if _, err := repo.GetByUserID(context.Background(), "user_1"); err == cartdomain.ErrCartNotFound {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("expected worker to expire the cart")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected worker to stop after cancel")
+	}
+
+	if !strings.Contains(logs.String(), "expired=1 cancelled_holds=1 cancelled_orders=0") {
+		t.Errorf("expected counts in log, got: %q", logs.String())
+	}
+}
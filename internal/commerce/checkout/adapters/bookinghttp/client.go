@@ -8,6 +8,9 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	platformbooking "paku-commerce/internal/commerce/platform/booking"
+	"paku-commerce/internal/platform/audit"
 )
 
 // Config contiene la configuración del cliente booking HTTP.
@@ -184,6 +187,44 @@ func (c *Client) CancelHold(ctx context.Context, holdID string) error {
 	return c.parseError(resp)
 }
 
+// GetSlot consulta un slot para conocer la hora de inicio de la cita
+// (GET /api/v1/slots/{id} → {slot_id, starts_at}). StartCheckout tolera que falle:
+// la orden queda sin fecha de cita.
+func (c *Client) GetSlot(ctx context.Context, slotID string) (platformbooking.Slot, error) {
+	endpoint := c.cfg.BaseURL + "/api/v1/slots/" + slotID
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return platformbooking.Slot{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return platformbooking.Slot{}, &HttpError{
+			Code:       "booking_unavailable",
+			Message:    "booking service unavailable",
+			StatusCode: 0,
+			Err:        err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		var result struct {
+			SlotID   string    `json:"slot_id"`
+			StartsAt time.Time `json:"starts_at"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return platformbooking.Slot{}, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return platformbooking.Slot{ID: result.SlotID, StartsAt: result.StartsAt}, nil
+	}
+
+	return platformbooking.Slot{}, c.parseError(resp)
+}
+
 // setHeaders configura headers comunes para requests.
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
@@ -192,9 +233,10 @@ func (c *Client) setHeaders(req *http.Request) {
 		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
 	}
 
-	// TODO: obtener X-Request-ID del context si existe
-	// Necesitaría acceso a la key usada por RequestIDMiddleware
-	// Por ahora dejamos como pending
+	// Propagar el request ID del request entrante (RequestIDMiddleware)
+	if requestID := audit.RequestID(req.Context()); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
 }
 
 // parseError parsea errores HTTP del servicio booking.
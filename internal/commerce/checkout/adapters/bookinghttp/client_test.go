@@ -44,6 +44,31 @@ func TestCreateHold_OK_ReturnsHoldID(t *testing.T) {
 	}
 }
 
+func TestGetSlot_OK_ReturnsStartsAt(t *testing.T) {
+	startsAt := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/v1/slots/slot_123" {
+			t.Errorf("expected GET /api/v1/slots/slot_123, got %s %s", r.Method, r.URL.Path)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"slot_id":   "slot_123",
+			"starts_at": startsAt,
+		})
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(Config{BaseURL: server.URL})
+
+	slot, err := client.GetSlot(context.Background(), "slot_123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slot.ID != "slot_123" || !slot.StartsAt.Equal(startsAt) {
+		t.Errorf("unexpected slot: %+v", slot)
+	}
+}
+
 func TestCreateHold_422_ReturnsErrSlotUnavailable(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnprocessableEntity)
@@ -0,0 +1,276 @@
+// Package culqihttp implementa payments.PaymentsClient y payments.CardCharger sobre la
+// API v2 de Culqi (cargos y devoluciones con tarjeta tokenizada, montos en céntimos).
+package culqihttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// DefaultBaseURL es la URL de la API de Culqi.
+const DefaultBaseURL = "https://api.culqi.com"
+
+// refundReason es el motivo de devolución que Culqi exige (solicitud del comprador).
+const refundReason = "solicitud_comprador"
+
+// idempotencyKeyMetadata es la clave de metadata donde viaja RefundRequest.IdempotencyKey.
+const idempotencyKeyMetadata = "idempotency_key"
+
+// Config contiene la configuración del cliente Culqi.
+type Config struct {
+	BaseURL   string // "" = DefaultBaseURL
+	SecretKey string // sk_test_... / sk_live_...
+	Timeout   time.Duration
+}
+
+// Client implementa payments.PaymentsClient y payments.CardCharger usando la API de Culqi.
+type Client struct {
+	httpClient *http.Client
+	cfg        Config
+}
+
+// NewClient crea un nuevo cliente de Culqi.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("SecretKey is required")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &Client{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		cfg:        cfg,
+	}, nil
+}
+
+// chargeResponse es el objeto charge de Culqi.
+type chargeResponse struct {
+	Object       string `json:"object"`
+	ID           string `json:"id"`
+	Amount       int64  `json:"amount"`
+	CurrencyCode string `json:"currency_code"`
+	Capture      *bool  `json:"capture"`
+	Outcome      struct {
+		Type string `json:"type"` // "venta_exitosa" si el emisor aprobó el cargo
+	} `json:"outcome"`
+	// ActionCode "REVIEW" indica que el cargo requiere autenticación 3DS
+	ActionCode  string `json:"action_code"`
+	UserMessage string `json:"user_message"`
+}
+
+func (r chargeResponse) toCharge() payments.Charge {
+	status := payments.ChargeStatusFailed
+	if r.Outcome.Type == "venta_exitosa" {
+		status = payments.ChargeStatusSucceeded
+		if r.Capture != nil && !*r.Capture {
+			// Autorizado pero no capturado: el dinero aún no se cobró
+			status = payments.ChargeStatusPending
+		}
+	}
+	return payments.Charge{
+		PaymentRef: r.ID,
+		Amount:     pricingdomain.NewMoney(r.Amount, pricingdomain.Currency(r.CurrencyCode)),
+		Status:     status,
+	}
+}
+
+// ChargeCard crea un cargo de Amount sobre la tarjeta tokenizada.
+// Si Culqi pide 3DS retorna un *APIError con ErrAuthenticationRequired.
+func (c *Client) ChargeCard(ctx context.Context, req payments.CardChargeRequest) (payments.Charge, error) {
+	body := map[string]interface{}{
+		"amount":        req.Amount.Amount,
+		"currency_code": string(req.Amount.Currency),
+		"email":         req.Email,
+		"source_id":     req.Token,
+		"metadata":      map[string]string{"order_id": req.OrderID},
+	}
+
+	resp, err := c.do(ctx, "POST", "/v2/charges", body)
+	if err != nil {
+		return payments.Charge{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return payments.Charge{}, c.parseError(resp)
+	}
+
+	var charge chargeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&charge); err != nil {
+		return payments.Charge{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if charge.ActionCode == "REVIEW" {
+		return payments.Charge{}, &APIError{
+			StatusCode:  resp.StatusCode,
+			Type:        "3ds_required",
+			UserMessage: charge.UserMessage,
+			Err:         payments.ErrAuthenticationRequired,
+		}
+	}
+	return charge.toCharge(), nil
+}
+
+// ValidatePayment consulta el cargo paymentRef y lo verifica contra expected.
+func (c *Client) ValidatePayment(ctx context.Context, paymentRef string, expected pricingdomain.Money) error {
+	resp, err := c.do(ctx, "GET", "/v2/charges/"+url.PathEscape(paymentRef), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+
+	var charge chargeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&charge); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return charge.toCharge().Verify(expected)
+}
+
+// refundResponse es el objeto refund de Culqi.
+type refundResponse struct {
+	ID       string            `json:"id"`
+	ChargeID string            `json:"charge_id"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// Refund crea una devolución sobre el cargo PaymentRef.
+// Culqi no deduplica devoluciones: la clave de idempotencia viaja en la metadata y, antes
+// de crear, se busca una devolución del cargo con la misma clave para retornarla.
+func (c *Client) Refund(ctx context.Context, req payments.RefundRequest) (payments.RefundResult, error) {
+	if req.IdempotencyKey != "" {
+		existing, found, err := c.findRefund(ctx, req.PaymentRef, req.IdempotencyKey)
+		if err != nil {
+			return payments.RefundResult{}, err
+		}
+		if found {
+			return payments.RefundResult{ProviderRef: existing.ID}, nil
+		}
+	}
+
+	body := map[string]interface{}{
+		"amount":    req.Amount.Amount,
+		"charge_id": req.PaymentRef,
+		"reason":    refundReason,
+	}
+	if req.IdempotencyKey != "" {
+		body["metadata"] = map[string]string{idempotencyKeyMetadata: req.IdempotencyKey}
+	}
+
+	resp, err := c.do(ctx, "POST", "/v2/refunds", body)
+	if err != nil {
+		return payments.RefundResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return payments.RefundResult{}, c.parseError(resp)
+	}
+
+	var refund refundResponse
+	if err := json.NewDecoder(resp.Body).Decode(&refund); err != nil {
+		return payments.RefundResult{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return payments.RefundResult{ProviderRef: refund.ID}, nil
+}
+
+// findRefund busca entre las devoluciones del cargo la emitida con idempotencyKey.
+func (c *Client) findRefund(ctx context.Context, chargeID, idempotencyKey string) (refundResponse, bool, error) {
+	resp, err := c.do(ctx, "GET", "/v2/refunds?charge_id="+url.QueryEscape(chargeID), nil)
+	if err != nil {
+		return refundResponse{}, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return refundResponse{}, false, c.parseError(resp)
+	}
+
+	var list struct {
+		Data []refundResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return refundResponse{}, false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	for _, refund := range list.Data {
+		if refund.ChargeID == chargeID && refund.Metadata[idempotencyKeyMetadata] == idempotencyKey {
+			return refund, true, nil
+		}
+	}
+	return refundResponse{}, false, nil
+}
+
+// do envía un request autenticado a la API. Los errores de red son ErrPaymentsUnavailable.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.cfg.BaseURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.cfg.SecretKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &APIError{Err: fmt.Errorf("%w: %v", payments.ErrPaymentsUnavailable, err)}
+	}
+	return resp, nil
+}
+
+// parseError traduce una respuesta de error de Culqi a los errores de payments.
+func (c *Client) parseError(resp *http.Response) error {
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	var errorResponse struct {
+		Type            string `json:"type"`
+		Code            string `json:"code"`
+		DeclineCode     string `json:"decline_code"`
+		ChargeID        string `json:"charge_id"`
+		MerchantMessage string `json:"merchant_message"`
+		UserMessage     string `json:"user_message"`
+	}
+	_ = json.Unmarshal(bodyBytes, &errorResponse)
+
+	apiErr := &APIError{
+		StatusCode:      resp.StatusCode,
+		Type:            errorResponse.Type,
+		Code:            errorResponse.Code,
+		DeclineCode:     errorResponse.DeclineCode,
+		ChargeID:        errorResponse.ChargeID,
+		MerchantMessage: errorResponse.MerchantMessage,
+		UserMessage:     errorResponse.UserMessage,
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPaymentRequired || errorResponse.Type == "card_error":
+		apiErr.Err = payments.ErrCardDeclined
+	case resp.StatusCode == http.StatusNotFound:
+		apiErr.Err = payments.ErrPaymentNotFound
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		apiErr.Err = payments.ErrPaymentsUnavailable
+	}
+	return apiErr
+}
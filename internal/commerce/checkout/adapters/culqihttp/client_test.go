@@ -0,0 +1,161 @@
+package culqihttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+const testSecretKey = "sk_test_paku"
+
+func newTestClient(t *testing.T, baseURL, secretKey string) *Client {
+	t.Helper()
+	client, err := NewClient(Config{BaseURL: baseURL, SecretKey: secretKey, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func chargeRequest(token string) payments.CardChargeRequest {
+	return payments.CardChargeRequest{
+		Token:   token,
+		Email:   "owner@example.com",
+		Amount:  pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+		OrderID: "order_1",
+	}
+}
+
+func TestChargeCard_Success_ReturnsValidCharge(t *testing.T) {
+	server := NewFakeServer(testSecretKey)
+	defer server.Close()
+	client := newTestClient(t, server.URL, testSecretKey)
+
+	charge, err := client.ChargeCard(context.Background(), chargeRequest(FakeTokenSuccess))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if charge.PaymentRef == "" || charge.Status != payments.ChargeStatusSucceeded ||
+		charge.Amount != pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN) {
+		t.Errorf("unexpected charge: %+v", charge)
+	}
+
+	// El cargo se puede verificar contra el total de la orden
+	if err := client.ValidatePayment(context.Background(), charge.PaymentRef, charge.Amount); err != nil {
+		t.Errorf("expected valid payment, got: %v", err)
+	}
+	other := pricingdomain.NewMoney(5000, pricingdomain.CurrencyPEN)
+	if err := client.ValidatePayment(context.Background(), charge.PaymentRef, other); !errors.Is(err, payments.ErrPaymentAmountMismatch) {
+		t.Errorf("expected ErrPaymentAmountMismatch, got: %v", err)
+	}
+	if err := client.ValidatePayment(context.Background(), "chr_missing", other); !errors.Is(err, payments.ErrPaymentNotFound) {
+		t.Errorf("expected ErrPaymentNotFound, got: %v", err)
+	}
+}
+
+func TestChargeCard_Declined_ReturnsErrCardDeclined(t *testing.T) {
+	server := NewFakeServer(testSecretKey)
+	defer server.Close()
+	client := newTestClient(t, server.URL, testSecretKey)
+
+	_, err := client.ChargeCard(context.Background(), chargeRequest(FakeTokenDeclined))
+	if !errors.Is(err, payments.ErrCardDeclined) {
+		t.Fatalf("expected ErrCardDeclined, got: %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.DeclineCode != "insufficient_funds" || apiErr.UserMessage == "" {
+		t.Errorf("expected decline details, got: %+v", apiErr)
+	}
+}
+
+func TestChargeCard_3DSRequired_ReturnsErrAuthenticationRequired(t *testing.T) {
+	server := NewFakeServer(testSecretKey)
+	defer server.Close()
+	client := newTestClient(t, server.URL, testSecretKey)
+
+	if _, err := client.ChargeCard(context.Background(), chargeRequest(FakeToken3DS)); !errors.Is(err, payments.ErrAuthenticationRequired) {
+		t.Errorf("expected ErrAuthenticationRequired, got: %v", err)
+	}
+}
+
+func TestRefund_PartialThenExceeding(t *testing.T) {
+	server := NewFakeServer(testSecretKey)
+	defer server.Close()
+	client := newTestClient(t, server.URL, testSecretKey)
+
+	charge, err := client.ChargeCard(context.Background(), chargeRequest(FakeTokenSuccess))
+	if err != nil {
+		t.Fatalf("unexpected error on charge: %v", err)
+	}
+
+	result, err := client.Refund(context.Background(), payments.RefundRequest{
+		PaymentRef:     charge.PaymentRef,
+		Amount:         pricingdomain.NewMoney(4000, pricingdomain.CurrencyPEN),
+		IdempotencyKey: "order_1:rf_1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error on refund: %v", err)
+	}
+	if result.ProviderRef == "" {
+		t.Errorf("expected refund ID")
+	}
+
+	// Reintento con la misma clave: retorna la devolución ya creada sin devolver otra vez
+	again, err := client.Refund(context.Background(), payments.RefundRequest{
+		PaymentRef:     charge.PaymentRef,
+		Amount:         pricingdomain.NewMoney(4000, pricingdomain.CurrencyPEN),
+		IdempotencyKey: "order_1:rf_1",
+	})
+	if err != nil || again.ProviderRef != result.ProviderRef {
+		t.Errorf("expected the same refund %s for the same key, got: %+v %v", result.ProviderRef, again, err)
+	}
+
+	_, err = client.Refund(context.Background(), payments.RefundRequest{
+		PaymentRef: charge.PaymentRef,
+		Amount:     pricingdomain.NewMoney(1000, pricingdomain.CurrencyPEN),
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Type != "invalid_request_error" {
+		t.Errorf("expected invalid_request_error for a refund over the charge, got: %v", err)
+	}
+}
+
+func TestClient_WrongSecretKey_ReturnsAuthenticationError(t *testing.T) {
+	server := NewFakeServer(testSecretKey)
+	defer server.Close()
+	client := newTestClient(t, server.URL, "sk_test_wrong")
+
+	_, err := client.ChargeCard(context.Background(), chargeRequest(FakeTokenSuccess))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Type != "authentication_error" {
+		t.Errorf("expected authentication_error, got: %v", err)
+	}
+}
+
+func TestClient_ProviderDown_ReturnsErrPaymentsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	client := newTestClient(t, server.URL, testSecretKey)
+
+	if err := client.ValidatePayment(context.Background(), "chr_1", pricingdomain.Money{}); !errors.Is(err, payments.ErrPaymentsUnavailable) {
+		t.Errorf("expected ErrPaymentsUnavailable on 503, got: %v", err)
+	}
+
+	server.Close()
+	if _, err := client.ChargeCard(context.Background(), chargeRequest(FakeTokenSuccess)); !errors.Is(err, payments.ErrPaymentsUnavailable) {
+		t.Errorf("expected ErrPaymentsUnavailable when unreachable, got: %v", err)
+	}
+}
+
+func TestNewClient_WithoutSecretKey_ReturnsError(t *testing.T) {
+	if _, err := NewClient(Config{}); err == nil {
+		t.Error("expected error without SecretKey")
+	}
+}
@@ -0,0 +1,37 @@
+package culqihttp
+
+import "fmt"
+
+// APIError contiene el detalle de un error de la API de Culqi.
+// Err es el error de payments al que corresponde (ErrCardDeclined, ErrPaymentsUnavailable, ...).
+type APIError struct {
+	StatusCode      int
+	Type            string // card_error, invalid_request_error, authentication_error, ...
+	Code            string // card_declined, ...
+	DeclineCode     string // insufficient_funds, stolen_card, ...
+	ChargeID        string // cobro rechazado, si Culqi llegó a crearlo
+	MerchantMessage string
+	UserMessage     string // mensaje apto para mostrar al comprador
+	Err             error
+}
+
+func (e *APIError) Error() string {
+	message := e.MerchantMessage
+	if message == "" {
+		message = e.UserMessage
+	}
+	if e.DeclineCode != "" {
+		return fmt.Sprintf("culqi error (status=%d, type=%s, decline_code=%s): %s", e.StatusCode, e.Type, e.DeclineCode, message)
+	}
+	if message != "" {
+		return fmt.Sprintf("culqi error (status=%d, type=%s): %s", e.StatusCode, e.Type, message)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("culqi error (status=%d): %v", e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("culqi error (status=%d)", e.StatusCode)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,159 @@
+package culqihttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Tokens de tarjeta que reconoce NewFakeServer (imitan las tarjetas de prueba de Culqi).
+const (
+	FakeTokenSuccess  = "tkn_test_success"  // cargo aprobado
+	FakeTokenDeclined = "tkn_test_declined" // 402 card_declined (fondos insuficientes)
+	FakeToken3DS      = "tkn_test_3ds"      // 200 con action_code REVIEW (requiere 3DS)
+)
+
+// NewFakeServer levanta una API de Culqi local (httptest) que exige Bearer secretKey y
+// responde POST /v2/charges según el token (ver FakeToken*), GET /v2/charges/{id} con
+// los cargos creados, POST /v2/refunds hasta el monto de cada cargo y GET /v2/refunds
+// filtrado por charge_id.
+// Pensado para tests y desarrollo; el llamador debe cerrar el server.
+func NewFakeServer(secretKey string) *httptest.Server {
+	var (
+		mu       sync.Mutex
+		charges  = make(map[string]map[string]interface{})
+		refunded = make(map[string]int64)
+		refunds  []map[string]interface{}
+		sequence int
+	)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer "+secretKey {
+			writeFakeError(w, http.StatusUnauthorized, "authentication_error", "llave secreta inválida")
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/charges":
+			var req struct {
+				Amount       int64  `json:"amount"`
+				CurrencyCode string `json:"currency_code"`
+				Email        string `json:"email"`
+				SourceID     string `json:"source_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Amount <= 0 || req.Email == "" {
+				writeFakeError(w, http.StatusBadRequest, "invalid_request_error", "amount y email son requeridos")
+				return
+			}
+
+			sequence++
+			chargeID := fmt.Sprintf("chr_test_%04d", sequence)
+			switch req.SourceID {
+			case FakeTokenSuccess:
+				charge := map[string]interface{}{
+					"object":        "charge",
+					"id":            chargeID,
+					"amount":        req.Amount,
+					"currency_code": req.CurrencyCode,
+					"email":         req.Email,
+					"capture":       true,
+					"outcome":       map[string]string{"type": "venta_exitosa", "code": "AUT0000"},
+				}
+				charges[chargeID] = charge
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(charge)
+			case FakeTokenDeclined:
+				w.WriteHeader(http.StatusPaymentRequired)
+				json.NewEncoder(w).Encode(map[string]string{
+					"object":           "error",
+					"type":             "card_error",
+					"charge_id":        chargeID,
+					"code":             "card_declined",
+					"decline_code":     "insufficient_funds",
+					"merchant_message": "La tarjeta no tiene fondos suficientes.",
+					"user_message":     "Su tarjeta no tiene fondos suficientes.",
+				})
+			case FakeToken3DS:
+				json.NewEncoder(w).Encode(map[string]string{
+					"action_code":  "REVIEW",
+					"user_message": "Se requiere autenticación 3DS.",
+				})
+			default:
+				writeFakeError(w, http.StatusBadRequest, "invalid_request_error", "token inválido")
+			}
+
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v2/charges/"):
+			charge, ok := charges[strings.TrimPrefix(r.URL.Path, "/v2/charges/")]
+			if !ok {
+				writeFakeError(w, http.StatusNotFound, "invalid_request_error", "cargo no encontrado")
+				return
+			}
+			json.NewEncoder(w).Encode(charge)
+
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/refunds":
+			var req struct {
+				Amount   int64             `json:"amount"`
+				ChargeID string            `json:"charge_id"`
+				Reason   string            `json:"reason"`
+				Metadata map[string]string `json:"metadata"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Reason == "" {
+				writeFakeError(w, http.StatusBadRequest, "invalid_request_error", "reason es requerido")
+				return
+			}
+			charge, ok := charges[req.ChargeID]
+			if !ok {
+				writeFakeError(w, http.StatusNotFound, "invalid_request_error", "cargo no encontrado")
+				return
+			}
+			if req.Amount <= 0 || refunded[req.ChargeID]+req.Amount > charge["amount"].(int64) {
+				writeFakeError(w, http.StatusBadRequest, "invalid_request_error", "monto de devolución inválido")
+				return
+			}
+
+			refunded[req.ChargeID] += req.Amount
+			sequence++
+			refund := map[string]interface{}{
+				"object":    "refund",
+				"id":        fmt.Sprintf("ref_test_%04d", sequence),
+				"charge_id": req.ChargeID,
+				"amount":    req.Amount,
+				"reason":    req.Reason,
+				"metadata":  req.Metadata,
+			}
+			refunds = append(refunds, refund)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(refund)
+
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/refunds":
+			chargeID := r.URL.Query().Get("charge_id")
+			data := []map[string]interface{}{}
+			for _, refund := range refunds {
+				if chargeID == "" || refund["charge_id"] == chargeID {
+					data = append(data, refund)
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+
+		default:
+			writeFakeError(w, http.StatusNotFound, "invalid_request_error", "recurso no encontrado")
+		}
+	}))
+}
+
+func writeFakeError(w http.ResponseWriter, status int, errType, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"object":           "error",
+		"type":             errType,
+		"merchant_message": message,
+		"user_message":     message,
+	})
+}
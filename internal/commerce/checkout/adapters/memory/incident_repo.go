@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+)
+
+// IncidentRepository implementa domain.IncidentRepository en memoria.
+type IncidentRepository struct {
+	mu        sync.RWMutex
+	incidents map[string]domain.Incident
+}
+
+// NewIncidentRepository crea un repositorio de incidentes en memoria.
+func NewIncidentRepository() *IncidentRepository {
+	return &IncidentRepository{
+		incidents: make(map[string]domain.Incident),
+	}
+}
+
+// Create guarda un incidente nuevo.
+func (r *IncidentRepository) Create(ctx context.Context, incident domain.Incident) (domain.Incident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.incidents[incident.ID] = incident
+	return incident, nil
+}
+
+// GetByID busca un incidente por ID.
+func (r *IncidentRepository) GetByID(ctx context.Context, id string) (domain.Incident, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	incident, exists := r.incidents[id]
+	if !exists {
+		return domain.Incident{}, domain.ErrIncidentNotFound
+	}
+	return incident, nil
+}
+
+// Update reemplaza un incidente existente.
+func (r *IncidentRepository) Update(ctx context.Context, incident domain.Incident) (domain.Incident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.incidents[incident.ID]; !exists {
+		return domain.Incident{}, domain.ErrIncidentNotFound
+	}
+	r.incidents[incident.ID] = incident
+	return incident, nil
+}
+
+// List lista los incidentes que cumplen query, del más antiguo al más reciente.
+func (r *IncidentRepository) List(ctx context.Context, query domain.IncidentQuery) ([]domain.Incident, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	incidents := make([]domain.Incident, 0)
+	for _, incident := range r.incidents {
+		if query.Matches(incident) {
+			incidents = append(incidents, incident)
+		}
+	}
+	sort.Slice(incidents, func(i, j int) bool {
+		if incidents[i].CreatedAt.Equal(incidents[j].CreatedAt) {
+			return incidents[i].ID < incidents[j].ID
+		}
+		return incidents[i].CreatedAt.Before(incidents[j].CreatedAt)
+	})
+	return incidents, nil
+}
@@ -2,21 +2,29 @@ package memory
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/platform/audit"
+	"paku-commerce/internal/platform/transaction"
 )
 
 // OrderRepository implementa domain.OrderRepository en memoria.
 type OrderRepository struct {
-	mu     sync.RWMutex
-	orders map[string]domain.Order
+	mu      sync.RWMutex
+	orders  map[string]domain.Order
+	events  map[string][]domain.OrderEvent
+	refunds map[string][]domain.Refund
 }
 
 // NewOrderRepository crea un repositorio de órdenes en memoria.
 func NewOrderRepository() *OrderRepository {
 	return &OrderRepository{
-		orders: make(map[string]domain.Order),
+		orders:  make(map[string]domain.Order),
+		events:  make(map[string][]domain.OrderEvent),
+		refunds: make(map[string][]domain.Refund),
 	}
 }
 
@@ -25,6 +33,11 @@ func (r *OrderRepository) Create(ctx context.Context, order domain.Order) (domai
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	order.Version = 1
+
+	r.recordUndo(ctx, order.ID, order.Version)
+	r.appendEvents(ctx, &order)
+	r.appendRefunds(ctx, &order)
 	r.orders[order.ID] = order
 	return order, nil
 }
@@ -41,15 +54,165 @@ func (r *OrderRepository) GetByID(ctx context.Context, id string) (domain.Order,
 	return order, nil
 }
 
-// Update actualiza una orden existente.
+// Update actualiza una orden existente si su versión coincide (compare-and-swap).
 func (r *OrderRepository) Update(ctx context.Context, order domain.Order) (domain.Order, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.orders[order.ID]; !exists {
+	stored, exists := r.orders[order.ID]
+	if !exists {
 		return domain.Order{}, domain.ErrOrderNotFound
 	}
+	if stored.Version != order.Version {
+		return domain.Order{}, domain.ErrOrderVersionConflict
+	}
 
+	order.Version++
+
+	r.recordUndo(ctx, order.ID, order.Version)
+	r.appendEvents(ctx, &order)
+	r.appendRefunds(ctx, &order)
 	r.orders[order.ID] = order
 	return order, nil
 }
+
+// ListOverduePending lista las órdenes esperando pago (pending_payment o failed) vencidas,
+// de la más antigua a la más nueva.
+func (r *OrderRepository) ListOverduePending(ctx context.Context, now time.Time) ([]domain.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	overdue := make([]domain.Order, 0)
+	for _, order := range r.orders {
+		if order.IsPaymentOverdue(now) {
+			overdue = append(overdue, order)
+		}
+	}
+	sort.Slice(overdue, func(i, j int) bool {
+		return overdue[i].ExpiresAt.Before(*overdue[j].ExpiresAt)
+	})
+	return overdue, nil
+}
+
+// ListHoldRetriesDue lista las órdenes payment_received_booking_failed con reintento vencido,
+// de la más antigua a la más nueva.
+func (r *OrderRepository) ListHoldRetriesDue(ctx context.Context, now time.Time) ([]domain.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	due := make([]domain.Order, 0)
+	for _, order := range r.orders {
+		if order.Status == domain.OrderStatusPaymentReceivedBookingFailed &&
+			order.NextHoldRetryAt != nil && !order.NextHoldRetryAt.After(now) {
+			due = append(due, order)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextHoldRetryAt.Before(*due[j].NextHoldRetryAt)
+	})
+	return due, nil
+}
+
+// ListByUser lista las órdenes del usuario que cumplen query, de la más nueva a la más antigua.
+func (r *OrderRepository) ListByUser(ctx context.Context, query domain.OrderQuery) ([]domain.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orders := make([]domain.Order, 0)
+	for _, order := range r.orders {
+		if !query.Matches(order) {
+			continue
+		}
+		if query.After != nil && !query.After.IsAfter(order) {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].ID > orders[j].ID
+		}
+		return orders[i].CreatedAt.After(orders[j].CreatedAt)
+	})
+
+	if query.Limit > 0 && len(orders) > query.Limit {
+		orders = orders[:query.Limit]
+	}
+	return orders, nil
+}
+
+// ListEvents retorna el historial de la orden, del más antiguo al más reciente.
+func (r *OrderRepository) ListEvents(ctx context.Context, orderID string) ([]domain.OrderEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.orders[orderID]; !exists {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	events := make([]domain.OrderEvent, len(r.events[orderID]))
+	copy(events, r.events[orderID])
+	return events, nil
+}
+
+// ListRefunds retorna los reembolsos de la orden, del más antiguo al más reciente.
+func (r *OrderRepository) ListRefunds(ctx context.Context, orderID string) ([]domain.Refund, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.orders[orderID]; !exists {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	refunds := make([]domain.Refund, len(r.refunds[orderID]))
+	copy(refunds, r.refunds[orderID])
+	return refunds, nil
+}
+
+// appendRefunds guarda los reembolsos pendientes de la orden.
+// Debe llamarse con el lock tomado.
+func (r *OrderRepository) appendRefunds(ctx context.Context, order *domain.Order) {
+	refunds := order.TakeNewRefunds()
+	if len(refunds) == 0 {
+		return
+	}
+
+	r.refunds[order.ID] = append(r.refunds[order.ID], refunds...)
+}
+
+// appendEvents agrega los eventos pendientes de la orden al historial.
+// Debe llamarse con el lock tomado.
+func (r *OrderRepository) appendEvents(ctx context.Context, order *domain.Order) {
+	events := order.TakeNewEvents(audit.Actor(ctx), audit.RequestID(ctx))
+	if len(events) == 0 {
+		return
+	}
+
+	r.events[order.ID] = append(r.events[order.ID], events...)
+}
+
+// recordUndo registra cómo restaurar la orden, sus eventos y sus reembolsos si la
+// transacción se revierte. Solo restaura si la orden sigue en written (la versión que
+// escribió la transacción): si otro request ya la actualizó, construyó sobre esta
+// escritura y pisarla perdería la suya. Como todo evento o reembolso entra por el
+// compare-and-swap de versión, con la misma versión nadie agregó otros después.
+// Debe llamarse con el lock tomado, antes de escribir.
+func (r *OrderRepository) recordUndo(ctx context.Context, id string, written int) {
+	previous, existed := r.orders[id]
+	previousEvents := len(r.events[id])
+	previousRefunds := len(r.refunds[id])
+	transaction.RecordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if current, exists := r.orders[id]; !exists || current.Version != written {
+			return
+		}
+		if existed {
+			r.orders[id] = previous
+		} else {
+			delete(r.orders, id)
+		}
+		r.events[id] = r.events[id][:previousEvents]
+		r.refunds[id] = r.refunds[id][:previousRefunds]
+	})
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+)
+
+// PaymentEventInbox implementa domain.PaymentEventInbox en memoria.
+type PaymentEventInbox struct {
+	mu      sync.RWMutex
+	records map[string]domain.PaymentEventRecord
+}
+
+// NewPaymentEventInbox crea un inbox de webhooks de pagos en memoria.
+func NewPaymentEventInbox() *PaymentEventInbox {
+	return &PaymentEventInbox{
+		records: make(map[string]domain.PaymentEventRecord),
+	}
+}
+
+// Get retorna el registro del evento del proveedor.
+func (r *PaymentEventInbox) Get(ctx context.Context, provider, eventID string) (domain.PaymentEventRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, exists := r.records[provider+"/"+eventID]
+	if !exists {
+		return domain.PaymentEventRecord{}, domain.ErrPaymentEventNotFound
+	}
+	return record, nil
+}
+
+// Claim toma el evento para procesarlo si nadie lo tiene (ver domain.PaymentEventInbox).
+func (r *PaymentEventInbox) Claim(ctx context.Context, event domain.PaymentEvent, now, staleBefore time.Time) (domain.PaymentEventRecord, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := event.Provider + "/" + event.ID
+	record, exists := r.records[key]
+	switch {
+	case !exists:
+		record = domain.PaymentEventRecord{Event: event, ReceivedAt: now}
+	case record.Status == domain.PaymentEventStatusFailed:
+	case record.Status == domain.PaymentEventStatusProcessing && record.ClaimedAt.Before(staleBefore):
+	default:
+		return record, false, nil
+	}
+
+	record.Status = domain.PaymentEventStatusProcessing
+	record.Attempts++
+	record.ClaimedAt = now
+	r.records[key] = record
+	return record, true, nil
+}
+
+// Save crea o reemplaza el registro del evento.
+func (r *PaymentEventInbox) Save(ctx context.Context, record domain.PaymentEventRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[record.Event.Provider+"/"+record.Event.ID] = record
+	return nil
+}
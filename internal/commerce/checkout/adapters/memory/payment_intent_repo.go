@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+)
+
+// PaymentIntentRepository implementa domain.PaymentIntentRepository en memoria.
+type PaymentIntentRepository struct {
+	mu      sync.RWMutex
+	intents map[string]domain.PaymentIntent
+}
+
+// NewPaymentIntentRepository crea un repositorio de intentos de pago en memoria.
+func NewPaymentIntentRepository() *PaymentIntentRepository {
+	return &PaymentIntentRepository{
+		intents: make(map[string]domain.PaymentIntent),
+	}
+}
+
+// Create guarda un intento nuevo (a lo sumo uno pending por orden).
+func (r *PaymentIntentRepository) Create(ctx context.Context, intent domain.PaymentIntent) (domain.PaymentIntent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if intent.IsPending() {
+		for _, existing := range r.intents {
+			if existing.OrderID == intent.OrderID && existing.IsPending() {
+				return domain.PaymentIntent{}, domain.ErrPaymentIntentPending
+			}
+		}
+	}
+
+	r.intents[intent.ID] = intent
+	return intent, nil
+}
+
+// GetByID busca un intento por ID.
+func (r *PaymentIntentRepository) GetByID(ctx context.Context, id string) (domain.PaymentIntent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	intent, exists := r.intents[id]
+	if !exists {
+		return domain.PaymentIntent{}, domain.ErrPaymentIntentNotFound
+	}
+	return intent, nil
+}
+
+// GetLatestByOrder retorna el intento más reciente de la orden.
+func (r *PaymentIntentRepository) GetLatestByOrder(ctx context.Context, orderID string) (domain.PaymentIntent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var (
+		latest domain.PaymentIntent
+		found  bool
+	)
+	for _, intent := range r.intents {
+		if intent.OrderID != orderID {
+			continue
+		}
+		if !found || paymentIntentBefore(latest, intent) {
+			latest, found = intent, true
+		}
+	}
+	if !found {
+		return domain.PaymentIntent{}, domain.ErrPaymentIntentNotFound
+	}
+	return latest, nil
+}
+
+// GetByProviderRef busca un intento por el ID del cobro en el proveedor.
+func (r *PaymentIntentRepository) GetByProviderRef(ctx context.Context, providerRef string) (domain.PaymentIntent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, intent := range r.intents {
+		if intent.ProviderRef == providerRef {
+			return intent, nil
+		}
+	}
+	return domain.PaymentIntent{}, domain.ErrPaymentIntentNotFound
+}
+
+// Claim toma el intento pending si nadie lo tiene (ver domain.PaymentIntentRepository).
+func (r *PaymentIntentRepository) Claim(ctx context.Context, id string, now, staleBefore time.Time) (domain.PaymentIntent, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	intent, exists := r.intents[id]
+	if !exists {
+		return domain.PaymentIntent{}, false, domain.ErrPaymentIntentNotFound
+	}
+	if !intent.IsPending() || (intent.ClaimedAt != nil && !intent.ClaimedAt.Before(staleBefore)) {
+		return intent, false, nil
+	}
+	intent.ClaimedAt = &now
+	r.intents[id] = intent
+	return intent, true, nil
+}
+
+// Update reemplaza un intento que sigue pending.
+func (r *PaymentIntentRepository) Update(ctx context.Context, intent domain.PaymentIntent) (domain.PaymentIntent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, exists := r.intents[intent.ID]
+	if !exists {
+		return domain.PaymentIntent{}, domain.ErrPaymentIntentNotFound
+	}
+	if !current.IsPending() {
+		return domain.PaymentIntent{}, domain.ErrPaymentIntentSettled
+	}
+	r.intents[intent.ID] = intent
+	return intent, nil
+}
+
+// ListPending lista los intentos pending, del más antiguo al más reciente.
+func (r *PaymentIntentRepository) ListPending(ctx context.Context) ([]domain.PaymentIntent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	intents := make([]domain.PaymentIntent, 0)
+	for _, intent := range r.intents {
+		if intent.IsPending() {
+			intents = append(intents, intent)
+		}
+	}
+	sort.Slice(intents, func(i, j int) bool {
+		return paymentIntentBefore(intents[i], intents[j])
+	})
+	return intents, nil
+}
+
+// paymentIntentBefore ordena los intentos por CreatedAt y luego ID.
+func paymentIntentBefore(a, b domain.PaymentIntent) bool {
+	if a.CreatedAt.Equal(b.CreatedAt) {
+		return a.ID < b.ID
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
@@ -0,0 +1,150 @@
+// Package paymentwebhook verifica webhooks de pagos firmados con HMAC-SHA256.
+//
+// Firma (header X-Payment-Signature): "t=<unix>,v1=<hex(hmac_sha256(secret, t + "." + body))>".
+// Cuerpo:
+//
+//	{"id": "evt_1", "type": "payment.succeeded", "created_at": "2026-04-01T12:00:00Z",
+//	 "data": {"order_id": "...", "payment_ref": "...", "refund_id": "...",
+//	          "amount": 1500, "currency": "PEN", "reason": "..."}}
+package paymentwebhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// DefaultTolerance es la antigüedad máxima aceptada de una firma (protección contra replay).
+const DefaultTolerance = 5 * time.Minute
+
+// Verifier implementa payments.WebhookVerifier para un proveedor con secreto compartido.
+type Verifier struct {
+	Provider  string
+	Secret    string
+	Tolerance time.Duration // cero = DefaultTolerance
+	Now       func() time.Time
+}
+
+type envelope struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      struct {
+		OrderID    string `json:"order_id"`
+		PaymentRef string `json:"payment_ref"`
+		RefundID   string `json:"refund_id"`
+		Amount     int64  `json:"amount"`
+		Currency   string `json:"currency"`
+		Reason     string `json:"reason"`
+	} `json:"data"`
+}
+
+// VerifyWebhook valida la firma y la antigüedad del payload y lo traduce a un PaymentEvent.
+func (v Verifier) VerifyWebhook(payload []byte, signature string) (checkoutdomain.PaymentEvent, error) {
+	timestamp, mac, err := parseSignature(signature)
+	if err != nil {
+		return checkoutdomain.PaymentEvent{}, err
+	}
+	if !hmac.Equal(mac, v.sign(timestamp, payload)) {
+		return checkoutdomain.PaymentEvent{}, payments.ErrInvalidWebhookSignature
+	}
+
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	signedAt := time.Unix(timestamp, 0)
+	if now.Sub(signedAt) > tolerance || signedAt.Sub(now) > tolerance {
+		return checkoutdomain.PaymentEvent{}, fmt.Errorf("%w: timestamp outside tolerance", payments.ErrInvalidWebhookSignature)
+	}
+
+	var body envelope
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return checkoutdomain.PaymentEvent{}, fmt.Errorf("%w: %v", payments.ErrInvalidWebhookPayload, err)
+	}
+	if body.ID == "" || body.Type == "" {
+		return checkoutdomain.PaymentEvent{}, fmt.Errorf("%w: id and type are required", payments.ErrInvalidWebhookPayload)
+	}
+
+	occurredAt := body.CreatedAt
+	if occurredAt.IsZero() {
+		occurredAt = signedAt
+	}
+	currency := pricingdomain.Currency(body.Data.Currency)
+	if currency == "" {
+		currency = pricingdomain.CurrencyPEN
+	}
+
+	return checkoutdomain.PaymentEvent{
+		Provider:   v.Provider,
+		ID:         body.ID,
+		Type:       checkoutdomain.PaymentEventType(body.Type),
+		OrderID:    body.Data.OrderID,
+		PaymentRef: body.Data.PaymentRef,
+		RefundRef:  body.Data.RefundID,
+		Amount:     pricingdomain.NewMoney(body.Data.Amount, currency),
+		Reason:     body.Data.Reason,
+		OccurredAt: occurredAt.UTC(),
+	}, nil
+}
+
+// Sign construye el header de firma de payload en el instante at.
+// Lo usan los tests y las herramientas de desarrollo que simulan al proveedor.
+func Sign(secret string, payload []byte, at time.Time) string {
+	timestamp := at.Unix()
+	mac := Verifier{Secret: secret}.sign(timestamp, payload)
+	return "t=" + strconv.FormatInt(timestamp, 10) + ",v1=" + hex.EncodeToString(mac)
+}
+
+func (v Verifier) sign(timestamp int64, payload []byte) []byte {
+	h := hmac.New(sha256.New, []byte(v.Secret))
+	h.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	h.Write([]byte("."))
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// parseSignature extrae el timestamp y el MAC del header "t=<unix>,v1=<hex>".
+func parseSignature(signature string) (int64, []byte, error) {
+	var (
+		timestamp int64
+		mac       []byte
+	)
+	for _, part := range strings.Split(signature, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, nil, payments.ErrInvalidWebhookSignature
+			}
+			timestamp = parsed
+		case "v1":
+			decoded, err := hex.DecodeString(value)
+			if err != nil {
+				return 0, nil, payments.ErrInvalidWebhookSignature
+			}
+			mac = decoded
+		}
+	}
+	if timestamp == 0 || mac == nil {
+		return 0, nil, payments.ErrInvalidWebhookSignature
+	}
+	return timestamp, mac, nil
+}
@@ -0,0 +1,59 @@
+package paymentwebhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+)
+
+const testSecret = "whsec_test"
+
+func TestVerifyWebhook_ValidSignature_ParsesEvent(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":"evt_1","type":"payment.refunded","created_at":"2026-04-01T11:59:00Z",
+		"data":{"order_id":"order_1","payment_ref":"tx_1","refund_id":"re_1","amount":1500,"currency":"PEN"}}`)
+	verifier := Verifier{Provider: "acme", Secret: testSecret, Now: func() time.Time { return now }}
+
+	event, err := verifier.VerifyWebhook(payload, Sign(testSecret, payload, now))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Provider != "acme" || event.ID != "evt_1" || event.Type != checkoutdomain.PaymentEventRefunded ||
+		event.OrderID != "order_1" || event.PaymentRef != "tx_1" || event.RefundRef != "re_1" || event.Amount.Amount != 1500 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if !event.OccurredAt.Equal(now.Add(-time.Minute)) {
+		t.Errorf("expected occurred_at from created_at, got: %v", event.OccurredAt)
+	}
+}
+
+func TestVerifyWebhook_RejectsTamperedOrStaleSignatures(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"order_id":"order_1","payment_ref":"tx_1"}}`)
+	verifier := Verifier{Provider: "acme", Secret: testSecret, Now: func() time.Time { return now }}
+
+	cases := map[string]string{
+		"wrong secret": Sign("other", payload, now),
+		"stale":        Sign(testSecret, payload, now.Add(-10*time.Minute)),
+		"malformed":    "v1=deadbeef",
+		"empty":        "",
+	}
+	for name, signature := range cases {
+		if _, err := verifier.VerifyWebhook(payload, signature); !errors.Is(err, payments.ErrInvalidWebhookSignature) {
+			t.Errorf("%s: expected ErrInvalidWebhookSignature, got: %v", name, err)
+		}
+	}
+
+	tampered := []byte(`{"id":"evt_1","type":"payment.succeeded","data":{"order_id":"order_2","payment_ref":"tx_1"}}`)
+	if _, err := verifier.VerifyWebhook(tampered, Sign(testSecret, payload, now)); !errors.Is(err, payments.ErrInvalidWebhookSignature) {
+		t.Errorf("tampered body: expected ErrInvalidWebhookSignature, got: %v", err)
+	}
+
+	invalid := []byte(`{"type":"payment.succeeded"}`)
+	if _, err := verifier.VerifyWebhook(invalid, Sign(testSecret, invalid, now)); !errors.Is(err, payments.ErrInvalidWebhookPayload) {
+		t.Errorf("missing id: expected ErrInvalidWebhookPayload, got: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package petshttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	platformpets "paku-commerce/internal/commerce/platform/pets"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+)
+
+// Config contiene la configuración del cliente pets HTTP.
+type Config struct {
+	BaseURL string
+	APIKey  string
+	Timeout time.Duration
+}
+
+// Client implementa platformpets.Client usando HTTP.
+type Client struct {
+	httpClient *http.Client
+	cfg        Config
+}
+
+// NewClient crea un nuevo cliente HTTP para el servicio de mascotas.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("BaseURL is required")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	return &Client{
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		cfg:        cfg,
+	}, nil
+}
+
+// petProfileResponse es el payload de GET /api/v1/pets/{id}.
+type petProfileResponse struct {
+	Species  string `json:"species"`
+	WeightKg int    `json:"weight_kg"`
+	CoatType string `json:"coat_type"`
+}
+
+// GetPetProfile obtiene el perfil de una mascota por ID.
+func (c *Client) GetPetProfile(ctx context.Context, petID string) (servicedomain.PetProfile, error) {
+	endpoint := c.cfg.BaseURL + "/api/v1/pets/" + url.PathEscape(petID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return servicedomain.PetProfile{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Timeout o error de red: se reporta como servicio no disponible.
+		return servicedomain.PetProfile{}, fmt.Errorf("%w: %v", platformpets.ErrPetsUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		var result petProfileResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return servicedomain.PetProfile{}, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return servicedomain.PetProfile{
+			Species:  result.Species,
+			WeightKg: result.WeightKg,
+			CoatType: result.CoatType,
+		}, nil
+	}
+
+	return servicedomain.PetProfile{}, c.parseError(resp)
+}
+
+// setHeaders configura headers comunes para requests.
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+}
+
+// parseError parsea errores HTTP del servicio de mascotas.
+func (c *Client) parseError(resp *http.Response) error {
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	var errorResponse struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	_ = json.Unmarshal(bodyBytes, &errorResponse)
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return platformpets.ErrPetNotFound
+	case http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return platformpets.ErrPetsUnavailable
+	default:
+		return &HttpError{
+			StatusCode: resp.StatusCode,
+			Code:       errorResponse.Error.Code,
+			Message:    errorResponse.Error.Message,
+		}
+	}
+}
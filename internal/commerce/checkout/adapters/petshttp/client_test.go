@@ -0,0 +1,122 @@
+package petshttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platformpets "paku-commerce/internal/commerce/platform/pets"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+)
+
+func TestGetPetProfile_OK_ReturnsProfile(t *testing.T) {
+	server := NewFakeServer(map[string]servicedomain.PetProfile{
+		"pet_1": {Species: "dog", WeightKg: 15, CoatType: "double"},
+	})
+	defer server.Close()
+
+	client, err := NewClient(Config{BaseURL: server.URL, Timeout: 1 * time.Second})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	profile, err := client.GetPetProfile(context.Background(), "pet_1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := servicedomain.PetProfile{Species: "dog", WeightKg: 15, CoatType: "double"}
+	if profile != expected {
+		t.Errorf("expected %+v, got %+v", expected, profile)
+	}
+}
+
+func TestGetPetProfile_404_ReturnsErrPetNotFound(t *testing.T) {
+	server := NewFakeServer(nil)
+	defer server.Close()
+
+	client, _ := NewClient(Config{BaseURL: server.URL})
+
+	_, err := client.GetPetProfile(context.Background(), "pet_missing")
+	if !errors.Is(err, platformpets.ErrPetNotFound) {
+		t.Errorf("expected ErrPetNotFound, got: %v", err)
+	}
+}
+
+func TestGetPetProfile_SendsAuthorizationHeader(t *testing.T) {
+	var gotAuth, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+		w.Write([]byte(`{"species":"cat","weight_kg":4,"coat_type":"short"}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(Config{BaseURL: server.URL, APIKey: "secret"})
+
+	if _, err := client.GetPetProfile(context.Background(), "pet_2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("expected Bearer secret, got %q", gotAuth)
+	}
+	if gotPath != "/api/v1/pets/pet_2" {
+		t.Errorf("unexpected path: %s", gotPath)
+	}
+}
+
+func TestGetPetProfile_503_ReturnsErrPetsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(Config{BaseURL: server.URL})
+
+	_, err := client.GetPetProfile(context.Background(), "pet_1")
+	if !errors.Is(err, platformpets.ErrPetsUnavailable) {
+		t.Errorf("expected ErrPetsUnavailable, got: %v", err)
+	}
+}
+
+func TestGetPetProfile_Timeout_ReturnsErrPetsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(Config{BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+
+	_, err := client.GetPetProfile(context.Background(), "pet_1")
+	if !errors.Is(err, platformpets.ErrPetsUnavailable) {
+		t.Errorf("expected ErrPetsUnavailable, got: %v", err)
+	}
+}
+
+func TestGetPetProfile_500_ReturnsHttpError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":{"code":"internal","message":"boom"}}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(Config{BaseURL: server.URL})
+
+	_, err := client.GetPetProfile(context.Background(), "pet_1")
+	var httpErr *HttpError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected HttpError, got: %v", err)
+	}
+	if httpErr.StatusCode != 500 || httpErr.Code != "internal" {
+		t.Errorf("unexpected HttpError: %+v", httpErr)
+	}
+}
+
+func TestNewClient_WithoutBaseURL_ReturnsError(t *testing.T) {
+	if _, err := NewClient(Config{}); err == nil {
+		t.Error("expected error for empty BaseURL")
+	}
+}
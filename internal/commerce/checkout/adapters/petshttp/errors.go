@@ -0,0 +1,17 @@
+package petshttp
+
+import "fmt"
+
+// HttpError contiene detalles de un error HTTP del servicio de mascotas.
+type HttpError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *HttpError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("pets http error (status=%d, code=%s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("pets http error (status=%d)", e.StatusCode)
+}
@@ -0,0 +1,39 @@
+package petshttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+)
+
+// NewFakeServer levanta un servicio de mascotas local (httptest) que responde
+// GET /api/v1/pets/{id} con los perfiles dados y 404 pet_not_found para el resto.
+// Pensado para tests y desarrollo; el llamador debe cerrar el server.
+func NewFakeServer(profiles map[string]servicedomain.PetProfile) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		petID, ok := strings.CutPrefix(r.URL.Path, "/api/v1/pets/")
+		if r.Method != http.MethodGet || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		profile, ok := profiles[petID]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]string{"code": "pet_not_found", "message": "pet not found"},
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(petProfileResponse{
+			Species:  profile.Species,
+			WeightKg: profile.WeightKg,
+			CoatType: profile.CoatType,
+		})
+	}))
+}
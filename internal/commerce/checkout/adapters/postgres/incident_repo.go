@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+	dbpostgres "paku-commerce/internal/db/postgres"
+)
+
+// IncidentRepository implementa domain.IncidentRepository sobre PostgreSQL.
+type IncidentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIncidentRepository crea un repositorio de incidentes en PostgreSQL.
+func NewIncidentRepository(pool *pgxpool.Pool) *IncidentRepository {
+	return &IncidentRepository{pool: pool}
+}
+
+const selectIncidentSQL = `
+	SELECT id, order_id, hold_id, operation, error, status, attempts,
+	       resolution, resolved_by, created_at, updated_at, resolved_at
+	FROM order_incidents`
+
+// Create guarda un incidente nuevo.
+func (r *IncidentRepository) Create(ctx context.Context, incident domain.Incident) (domain.Incident, error) {
+	_, err := dbpostgres.Conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO order_incidents (
+			id, order_id, hold_id, operation, error, status, attempts,
+			resolution, resolved_by, created_at, updated_at, resolved_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		incident.ID, incident.OrderID, incident.HoldID, string(incident.Operation), incident.Error,
+		string(incident.Status), incident.Attempts, incident.Resolution, incident.ResolvedBy,
+		incident.CreatedAt, incident.UpdatedAt, incident.ResolvedAt,
+	)
+	if err != nil {
+		return domain.Incident{}, fmt.Errorf("failed to insert incident: %w", err)
+	}
+	return incident, nil
+}
+
+// GetByID busca un incidente por ID.
+func (r *IncidentRepository) GetByID(ctx context.Context, id string) (domain.Incident, error) {
+	row := dbpostgres.Conn(ctx, r.pool).QueryRow(ctx, selectIncidentSQL+` WHERE id = $1`, id)
+
+	incident, err := scanIncident(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Incident{}, domain.ErrIncidentNotFound
+	}
+	if err != nil {
+		return domain.Incident{}, fmt.Errorf("failed to get incident: %w", err)
+	}
+	return incident, nil
+}
+
+// Update reemplaza el estado de un incidente existente.
+func (r *IncidentRepository) Update(ctx context.Context, incident domain.Incident) (domain.Incident, error) {
+	tag, err := dbpostgres.Conn(ctx, r.pool).Exec(ctx, `
+		UPDATE order_incidents
+		SET error = $2, status = $3, attempts = $4, resolution = $5, resolved_by = $6,
+		    updated_at = $7, resolved_at = $8
+		WHERE id = $1`,
+		incident.ID, incident.Error, string(incident.Status), incident.Attempts,
+		incident.Resolution, incident.ResolvedBy, incident.UpdatedAt, incident.ResolvedAt,
+	)
+	if err != nil {
+		return domain.Incident{}, fmt.Errorf("failed to update incident: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.Incident{}, domain.ErrIncidentNotFound
+	}
+	return incident, nil
+}
+
+// List lista los incidentes que cumplen query, del más antiguo al más reciente.
+func (r *IncidentRepository) List(ctx context.Context, query domain.IncidentQuery) ([]domain.Incident, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, selectIncidentSQL+`
+		WHERE ($1 = '' OR status = $1)
+		  AND ($2 = '' OR order_id = $2)
+		ORDER BY created_at, id`,
+		string(query.Status), query.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := make([]domain.Incident, 0)
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+	return incidents, nil
+}
+
+func scanIncident(row pgx.Row) (domain.Incident, error) {
+	var (
+		incident  domain.Incident
+		operation string
+		status    string
+	)
+	if err := row.Scan(
+		&incident.ID, &incident.OrderID, &incident.HoldID, &operation, &incident.Error, &status,
+		&incident.Attempts, &incident.Resolution, &incident.ResolvedBy,
+		&incident.CreatedAt, &incident.UpdatedAt, &incident.ResolvedAt,
+	); err != nil {
+		return domain.Incident{}, err
+	}
+	incident.Operation = domain.IncidentOperation(operation)
+	incident.Status = domain.IncidentStatus(status)
+	return incident, nil
+}
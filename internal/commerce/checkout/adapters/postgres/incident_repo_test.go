@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/db/postgres/pgtest"
+	"paku-commerce/internal/platform/id"
+)
+
+func TestIncidentRepository_RoundTrip(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewIncidentRepository(pool)
+	ctx := context.Background()
+
+	orderID := "order_" + id.NewRequestID()
+	createdAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	incident := domain.NewIncident("inc_"+id.NewRequestID(), orderID, "hold_pg_1",
+		domain.IncidentOperationCancelHold, errors.New("booking unavailable"), createdAt)
+
+	if _, err := repo.Create(ctx, incident); err != nil {
+		t.Fatalf("unexpected error on create: %v", err)
+	}
+
+	open, err := repo.List(ctx, domain.IncidentQuery{Status: domain.IncidentStatusOpen, OrderID: orderID})
+	if err != nil {
+		t.Fatalf("unexpected error on list: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != incident.ID || open[0].HoldID != "hold_pg_1" || open[0].Operation != domain.IncidentOperationCancelHold {
+		t.Fatalf("expected the open incident, got: %+v", open)
+	}
+
+	resolvedAt := createdAt.Add(time.Hour)
+	incident.RecordRetryFailure(errors.New("still down"), createdAt.Add(time.Minute))
+	if err := incident.Resolve("hold released by hand", "admin", resolvedAt); err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if _, err := repo.Update(ctx, incident); err != nil {
+		t.Fatalf("unexpected error on update: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, incident.ID)
+	if err != nil {
+		t.Fatalf("unexpected error on get: %v", err)
+	}
+	if got.Status != domain.IncidentStatusResolved || got.Attempts != 1 || got.Error != "still down" ||
+		got.Resolution != "hold released by hand" || got.ResolvedBy != "admin" {
+		t.Errorf("unexpected incident: %+v", got)
+	}
+	if got.ResolvedAt == nil || !got.ResolvedAt.Equal(resolvedAt) {
+		t.Errorf("expected resolved_at %v, got: %v", resolvedAt, got.ResolvedAt)
+	}
+
+	open, _ = repo.List(ctx, domain.IncidentQuery{Status: domain.IncidentStatusOpen, OrderID: orderID})
+	if len(open) != 0 {
+		t.Errorf("expected no open incidents, got: %+v", open)
+	}
+
+	if _, err := repo.GetByID(ctx, "inc_missing"); !errors.Is(err, domain.ErrIncidentNotFound) {
+		t.Errorf("expected ErrIncidentNotFound on get, got: %v", err)
+	}
+	if _, err := repo.Update(ctx, domain.Incident{ID: "inc_missing"}); !errors.Is(err, domain.ErrIncidentNotFound) {
+		t.Errorf("expected ErrIncidentNotFound on update, got: %v", err)
+	}
+}
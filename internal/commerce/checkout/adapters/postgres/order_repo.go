@@ -1 +1,593 @@
 package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	dbpostgres "paku-commerce/internal/db/postgres"
+	"paku-commerce/internal/platform/audit"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// OrderRepository implementa domain.OrderRepository sobre PostgreSQL.
+type OrderRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOrderRepository crea un repositorio de órdenes en PostgreSQL.
+func NewOrderRepository(pool *pgxpool.Pool) *OrderRepository {
+	return &OrderRepository{pool: pool}
+}
+
+const selectOrderSQL = `
+	SELECT id, user_id, status, created_at,
+	       pet_species, pet_weight_kg, pet_coat_type,
+	       subtotal_amount, subtotal_currency,
+	       total_discount_amount, total_discount_currency,
+	       total_amount, total_currency,
+	       coupon_code, booking_hold_id, payment_ref, paid_at,
+	       version, pet_id, expires_at, cancel_reason,
+	       processing_at, failed_at, cancelled_at, refunded_at, fulfilled_at, no_show_at,
+	       slot_id, appointment_at, cancellation_fee_amount, cancellation_fee_currency,
+	       refunded_amount, refunded_currency,
+	       booking_failed_at, hold_confirm_attempts, next_hold_retry_at,
+	       payment_provider
+	FROM orders`
+
+// Create guarda una orden con sus items en una transacción.
+func (r *OrderRepository) Create(ctx context.Context, order domain.Order) (domain.Order, error) {
+	order.Version = 1
+	events := order.TakeNewEvents(audit.Actor(ctx), audit.RequestID(ctx))
+	refunds := order.TakeNewRefunds()
+
+	err := pgx.BeginFunc(ctx, dbpostgres.Conn(ctx, r.pool), func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO orders (
+				id, status, created_at,
+				pet_species, pet_weight_kg, pet_coat_type,
+				subtotal_amount, subtotal_currency,
+				total_discount_amount, total_discount_currency,
+				total_amount, total_currency,
+				coupon_code, booking_hold_id, payment_ref, paid_at,
+				version, pet_id, expires_at, cancel_reason,
+				processing_at, failed_at, cancelled_at, refunded_at, fulfilled_at, no_show_at,
+				user_id, slot_id, appointment_at, cancellation_fee_amount, cancellation_fee_currency,
+				refunded_amount, refunded_currency,
+				booking_failed_at, hold_confirm_attempts, next_hold_retry_at,
+				payment_provider
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+				$21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37)`,
+			order.ID, string(order.Status), order.CreatedAt,
+			order.PetProfile.Species, order.PetProfile.WeightKg, order.PetProfile.CoatType,
+			order.Subtotal.Amount, string(order.Subtotal.Currency),
+			order.TotalDiscount.Amount, string(order.TotalDiscount.Currency),
+			order.Total.Amount, string(order.Total.Currency),
+			order.CouponCode, order.BookingHoldID, order.PaymentRef, order.PaidAt,
+			order.Version, order.PetID, order.ExpiresAt, string(order.CancelReason),
+			order.ProcessingAt, order.FailedAt, order.CancelledAt, order.RefundedAt, order.FulfilledAt, order.NoShowAt,
+			order.UserID, order.SlotID, order.AppointmentAt,
+			order.CancellationFee.Amount, string(order.CancellationFee.Currency),
+			order.RefundedAmount.Amount, string(order.RefundedAmount.Currency),
+			order.BookingFailedAt, order.HoldConfirmAttempts, order.NextHoldRetryAt,
+			string(order.PaymentProvider),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert order: %w", err)
+		}
+
+		if err := insertOrderEvents(ctx, tx, events); err != nil {
+			return err
+		}
+		if err := insertOrderRefunds(ctx, tx, refunds); err != nil {
+			return err
+		}
+		if err := insertOrderPets(ctx, tx, order.ID, order.Pets); err != nil {
+			return err
+		}
+		return insertOrderItems(ctx, tx, order.ID, order.Items)
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	return order, nil
+}
+
+// GetByID busca una orden por ID junto con sus mascotas e items.
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (domain.Order, error) {
+	row := dbpostgres.Conn(ctx, r.pool).QueryRow(ctx, selectOrderSQL+` WHERE id = $1`, id)
+
+	order, err := scanOrder(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Order{}, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if err := loadOrderLines(ctx, dbpostgres.Conn(ctx, r.pool), &order); err != nil {
+		return domain.Order{}, err
+	}
+
+	return order, nil
+}
+
+// ListOverduePending lista las órdenes esperando pago (pending_payment o failed) vencidas,
+// de la más antigua a la más nueva.
+func (r *OrderRepository) ListOverduePending(ctx context.Context, now time.Time) ([]domain.Order, error) {
+	q := dbpostgres.Conn(ctx, r.pool)
+	rows, err := q.Query(ctx, selectOrderSQL+`
+		WHERE status IN ($1, $2) AND expires_at < $3
+		ORDER BY expires_at`, string(domain.OrderStatusPendingPayment), string(domain.OrderStatusFailed), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overdue orders: %w", err)
+	}
+
+	return collectOrders(ctx, q, rows)
+}
+
+// ListHoldRetriesDue lista las órdenes payment_received_booking_failed con reintento vencido,
+// de la más antigua a la más nueva.
+func (r *OrderRepository) ListHoldRetriesDue(ctx context.Context, now time.Time) ([]domain.Order, error) {
+	q := dbpostgres.Conn(ctx, r.pool)
+	rows, err := q.Query(ctx, selectOrderSQL+`
+		WHERE status = $1 AND next_hold_retry_at <= $2
+		ORDER BY next_hold_retry_at`, string(domain.OrderStatusPaymentReceivedBookingFailed), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hold retries: %w", err)
+	}
+
+	return collectOrders(ctx, q, rows)
+}
+
+// ListByUser lista las órdenes del usuario que cumplen query, de la más nueva a la más antigua.
+func (r *OrderRepository) ListByUser(ctx context.Context, query domain.OrderQuery) ([]domain.Order, error) {
+	var afterCreatedAt *time.Time
+	var afterID string
+	if query.After != nil {
+		afterCreatedAt = &query.After.CreatedAt
+		afterID = query.After.ID
+	}
+	var limit *int
+	if query.Limit > 0 {
+		limit = &query.Limit
+	}
+
+	q := dbpostgres.Conn(ctx, r.pool)
+	rows, err := q.Query(ctx, selectOrderSQL+`
+		WHERE user_id = $1
+		  AND ($2 = '' OR status = $2)
+		  AND ($3::timestamptz IS NULL OR created_at >= $3)
+		  AND ($4::timestamptz IS NULL OR created_at < $4)
+		  AND ($5::timestamptz IS NULL OR (created_at, id) < ($5, $6))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $7`,
+		query.UserID, string(query.Status), query.From, query.To, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user orders: %w", err)
+	}
+
+	return collectOrders(ctx, q, rows)
+}
+
+// collectOrders escanea las filas de selectOrderSQL y completa mascotas e items de cada orden.
+func collectOrders(ctx context.Context, q dbpostgres.DBTX, rows pgx.Rows) ([]domain.Order, error) {
+	orders := make([]domain.Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	for i := range orders {
+		if err := loadOrderLines(ctx, q, &orders[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return orders, nil
+}
+
+// loadOrderLines completa las mascotas e items de la orden.
+func loadOrderLines(ctx context.Context, q dbpostgres.DBTX, order *domain.Order) error {
+	pets, err := listOrderPets(ctx, q, order.ID)
+	if err != nil {
+		return err
+	}
+	order.Pets = pets
+
+	items, err := listOrderItems(ctx, q, order.ID)
+	if err != nil {
+		return err
+	}
+	order.Items = items
+
+	return nil
+}
+
+// Update actualiza una orden existente y reemplaza sus mascotas e items (compare-and-swap por version).
+func (r *OrderRepository) Update(ctx context.Context, order domain.Order) (domain.Order, error) {
+	events := order.TakeNewEvents(audit.Actor(ctx), audit.RequestID(ctx))
+	refunds := order.TakeNewRefunds()
+
+	err := pgx.BeginFunc(ctx, dbpostgres.Conn(ctx, r.pool), func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE orders SET
+				status = $2,
+				created_at = $3,
+				pet_species = $4, pet_weight_kg = $5, pet_coat_type = $6,
+				subtotal_amount = $7, subtotal_currency = $8,
+				total_discount_amount = $9, total_discount_currency = $10,
+				total_amount = $11, total_currency = $12,
+				coupon_code = $13, booking_hold_id = $14, payment_ref = $15, paid_at = $16,
+				pet_id = $18, expires_at = $19, cancel_reason = $20,
+				processing_at = $21, failed_at = $22, cancelled_at = $23,
+				refunded_at = $24, fulfilled_at = $25, no_show_at = $26,
+				user_id = $27, slot_id = $28, appointment_at = $29,
+				cancellation_fee_amount = $30, cancellation_fee_currency = $31,
+				refunded_amount = $32, refunded_currency = $33,
+				booking_failed_at = $34, hold_confirm_attempts = $35, next_hold_retry_at = $36,
+				payment_provider = $37,
+				version = version + 1
+			WHERE id = $1 AND version = $17`,
+			order.ID, string(order.Status), order.CreatedAt,
+			order.PetProfile.Species, order.PetProfile.WeightKg, order.PetProfile.CoatType,
+			order.Subtotal.Amount, string(order.Subtotal.Currency),
+			order.TotalDiscount.Amount, string(order.TotalDiscount.Currency),
+			order.Total.Amount, string(order.Total.Currency),
+			order.CouponCode, order.BookingHoldID, order.PaymentRef, order.PaidAt,
+			order.Version, order.PetID, order.ExpiresAt, string(order.CancelReason),
+			order.ProcessingAt, order.FailedAt, order.CancelledAt, order.RefundedAt, order.FulfilledAt, order.NoShowAt,
+			order.UserID, order.SlotID, order.AppointmentAt,
+			order.CancellationFee.Amount, string(order.CancellationFee.Currency),
+			order.RefundedAmount.Amount, string(order.RefundedAmount.Currency),
+			order.BookingFailedAt, order.HoldConfirmAttempts, order.NextHoldRetryAt,
+			string(order.PaymentProvider),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`, order.ID).Scan(&exists); err != nil {
+				return fmt.Errorf("failed to check order: %w", err)
+			}
+			if !exists {
+				return domain.ErrOrderNotFound
+			}
+			return domain.ErrOrderVersionConflict
+		}
+
+		if err := insertOrderEvents(ctx, tx, events); err != nil {
+			return err
+		}
+		if err := insertOrderRefunds(ctx, tx, refunds); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM order_items WHERE order_id = $1`, order.ID); err != nil {
+			return fmt.Errorf("failed to delete order items: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM order_pets WHERE order_id = $1`, order.ID); err != nil {
+			return fmt.Errorf("failed to delete order pets: %w", err)
+		}
+
+		if err := insertOrderPets(ctx, tx, order.ID, order.Pets); err != nil {
+			return err
+		}
+		return insertOrderItems(ctx, tx, order.ID, order.Items)
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	order.Version++
+	return order, nil
+}
+
+// ListEvents retorna el historial de la orden, del más antiguo al más reciente.
+func (r *OrderRepository) ListEvents(ctx context.Context, orderID string) ([]domain.OrderEvent, error) {
+	q := dbpostgres.Conn(ctx, r.pool)
+
+	var exists bool
+	if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`, orderID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check order: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT order_id, event_type, from_status, to_status, actor, request_id, data, occurred_at
+		FROM order_events
+		WHERE order_id = $1
+		ORDER BY id`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]domain.OrderEvent, 0)
+	for rows.Next() {
+		var (
+			event                           domain.OrderEvent
+			eventType, fromStatus, toStatus string
+			data                            map[string]string
+		)
+		if err := rows.Scan(
+			&event.OrderID, &eventType, &fromStatus, &toStatus,
+			&event.Actor, &event.RequestID, &data, &event.OccurredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan order event: %w", err)
+		}
+		event.Type = domain.OrderEventType(eventType)
+		event.FromStatus = domain.OrderStatus(fromStatus)
+		event.ToStatus = domain.OrderStatus(toStatus)
+		if len(data) > 0 {
+			event.Data = data
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list order events: %w", err)
+	}
+
+	return events, nil
+}
+
+// ListRefunds retorna los reembolsos de la orden, del más antiguo al más reciente.
+func (r *OrderRepository) ListRefunds(ctx context.Context, orderID string) ([]domain.Refund, error) {
+	q := dbpostgres.Conn(ctx, r.pool)
+
+	var exists bool
+	if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`, orderID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check order: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT order_id, refund_key, payment_ref, amount, currency, lines, provider_ref, created_at
+		FROM order_refunds
+		WHERE order_id = $1
+		ORDER BY created_at, refund_key`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order refunds: %w", err)
+	}
+	defer rows.Close()
+
+	refunds := make([]domain.Refund, 0)
+	for rows.Next() {
+		var (
+			refund   domain.Refund
+			currency string
+			lines    []int32
+		)
+		if err := rows.Scan(
+			&refund.OrderID, &refund.Key, &refund.PaymentRef,
+			&refund.Amount.Amount, &currency, &lines, &refund.ProviderRef, &refund.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan order refund: %w", err)
+		}
+		refund.Amount.Currency = pricingdomain.Currency(currency)
+		for _, line := range lines {
+			refund.Lines = append(refund.Lines, int(line))
+		}
+		refunds = append(refunds, refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list order refunds: %w", err)
+	}
+
+	return refunds, nil
+}
+
+func insertOrderRefunds(ctx context.Context, tx pgx.Tx, refunds []domain.Refund) error {
+	for _, refund := range refunds {
+		lines := make([]int32, 0, len(refund.Lines))
+		for _, line := range refund.Lines {
+			lines = append(lines, int32(line))
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO order_refunds (
+				order_id, refund_key, payment_ref, amount, currency, lines, provider_ref, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			refund.OrderID, refund.Key, refund.PaymentRef,
+			refund.Amount.Amount, string(refund.Amount.Currency), lines, refund.ProviderRef, refund.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert order refund: %w", err)
+		}
+	}
+	return nil
+}
+
+func insertOrderEvents(ctx context.Context, tx pgx.Tx, events []domain.OrderEvent) error {
+	for _, event := range events {
+		data := event.Data
+		if data == nil {
+			data = map[string]string{}
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO order_events (
+				order_id, event_type, from_status, to_status, actor, request_id, data, occurred_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			event.OrderID, string(event.Type), string(event.FromStatus), string(event.ToStatus),
+			event.Actor, event.RequestID, data, event.OccurredAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert order event: %w", err)
+		}
+	}
+	return nil
+}
+
+func insertOrderItems(ctx context.Context, tx pgx.Tx, orderID string, items []domain.OrderItem) error {
+	for i, item := range items {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO order_items (
+				order_id, position, item_type, item_id, qty,
+				unit_price_amount, unit_price_currency,
+				line_total_amount, line_total_currency,
+				pet_id, refunded
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			orderID, i, string(item.ItemType), item.ItemID, item.Qty,
+			item.UnitPrice.Amount, string(item.UnitPrice.Currency),
+			item.LineTotal.Amount, string(item.LineTotal.Currency),
+			item.PetID, item.Refunded,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert order item: %w", err)
+		}
+	}
+	return nil
+}
+
+func listOrderItems(ctx context.Context, q dbpostgres.DBTX, orderID string) ([]domain.OrderItem, error) {
+	rows, err := q.Query(ctx, `
+		SELECT item_type, item_id, qty,
+		       unit_price_amount, unit_price_currency,
+		       line_total_amount, line_total_currency,
+		       pet_id, refunded
+		FROM order_items
+		WHERE order_id = $1
+		ORDER BY position`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]domain.OrderItem, 0)
+	for rows.Next() {
+		var (
+			item                            domain.OrderItem
+			itemType                        string
+			unitPriceCurrency, lineCurrency string
+		)
+		if err := rows.Scan(
+			&itemType, &item.ItemID, &item.Qty,
+			&item.UnitPrice.Amount, &unitPriceCurrency,
+			&item.LineTotal.Amount, &lineCurrency,
+			&item.PetID, &item.Refunded,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan order item: %w", err)
+		}
+		item.ItemType = domain.ItemType(itemType)
+		item.UnitPrice.Currency = pricingdomain.Currency(unitPriceCurrency)
+		item.LineTotal.Currency = pricingdomain.Currency(lineCurrency)
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list order items: %w", err)
+	}
+
+	return items, nil
+}
+
+func insertOrderPets(ctx context.Context, tx pgx.Tx, orderID string, pets []domain.OrderPet) error {
+	for i, pet := range pets {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO order_pets (
+				order_id, position, pet_id,
+				pet_species, pet_weight_kg, pet_coat_type,
+				subtotal_amount, subtotal_currency
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			orderID, i, pet.PetID,
+			pet.PetProfile.Species, pet.PetProfile.WeightKg, pet.PetProfile.CoatType,
+			pet.Subtotal.Amount, string(pet.Subtotal.Currency),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert order pet: %w", err)
+		}
+	}
+	return nil
+}
+
+func listOrderPets(ctx context.Context, q dbpostgres.DBTX, orderID string) ([]domain.OrderPet, error) {
+	rows, err := q.Query(ctx, `
+		SELECT pet_id, pet_species, pet_weight_kg, pet_coat_type,
+		       subtotal_amount, subtotal_currency
+		FROM order_pets
+		WHERE order_id = $1
+		ORDER BY position`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order pets: %w", err)
+	}
+	defer rows.Close()
+
+	pets := make([]domain.OrderPet, 0)
+	for rows.Next() {
+		var (
+			pet      domain.OrderPet
+			currency string
+		)
+		if err := rows.Scan(
+			&pet.PetID, &pet.PetProfile.Species, &pet.PetProfile.WeightKg, &pet.PetProfile.CoatType,
+			&pet.Subtotal.Amount, &currency,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan order pet: %w", err)
+		}
+		pet.Subtotal.Currency = pricingdomain.Currency(currency)
+		pets = append(pets, pet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list order pets: %w", err)
+	}
+
+	return pets, nil
+}
+
+func scanOrder(row pgx.Row) (domain.Order, error) {
+	var (
+		order                                             domain.Order
+		status                                            string
+		pet                                               servicedomain.PetProfile
+		subtotalCurrency, discountCurrency, totalCurrency string
+		cancelReason, feeCurrency, refundedCurrency       string
+		paymentProvider                                   string
+	)
+
+	err := row.Scan(
+		&order.ID, &order.UserID, &status, &order.CreatedAt,
+		&pet.Species, &pet.WeightKg, &pet.CoatType,
+		&order.Subtotal.Amount, &subtotalCurrency,
+		&order.TotalDiscount.Amount, &discountCurrency,
+		&order.Total.Amount, &totalCurrency,
+		&order.CouponCode, &order.BookingHoldID, &order.PaymentRef, &order.PaidAt,
+		&order.Version, &order.PetID, &order.ExpiresAt, &cancelReason,
+		&order.ProcessingAt, &order.FailedAt, &order.CancelledAt, &order.RefundedAt, &order.FulfilledAt, &order.NoShowAt,
+		&order.SlotID, &order.AppointmentAt, &order.CancellationFee.Amount, &feeCurrency,
+		&order.RefundedAmount.Amount, &refundedCurrency,
+		&order.BookingFailedAt, &order.HoldConfirmAttempts, &order.NextHoldRetryAt,
+		&paymentProvider,
+	)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	order.Status = domain.OrderStatus(status)
+	order.CancelReason = domain.CancelReason(cancelReason)
+	order.PaymentProvider = domain.PaymentProvider(paymentProvider)
+	order.CancellationFee.Currency = pricingdomain.Currency(feeCurrency)
+	order.RefundedAmount.Currency = pricingdomain.Currency(refundedCurrency)
+	order.PetProfile = pet
+	order.Subtotal.Currency = pricingdomain.Currency(subtotalCurrency)
+	order.TotalDiscount.Currency = pricingdomain.Currency(discountCurrency)
+	order.Total.Currency = pricingdomain.Currency(totalCurrency)
+
+	return order, nil
+}
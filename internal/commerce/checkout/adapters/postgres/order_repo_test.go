@@ -0,0 +1,311 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	"paku-commerce/internal/db/postgres/pgtest"
+	"paku-commerce/internal/platform/audit"
+	"paku-commerce/internal/platform/id"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+func TestOrderRepository_RoundTrip(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewOrderRepository(pool)
+	ctx := context.Background()
+
+	coupon := "BANO10"
+	holdID := "hold_pg_1"
+	expiresAt := time.Date(2026, 1, 12, 10, 30, 0, 0, time.UTC)
+	appointmentAt := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)
+	order := domain.Order{
+		ID:        "order_" + id.NewRequestID(),
+		Status:    domain.OrderStatusPendingPayment,
+		CreatedAt: time.Date(2026, 1, 12, 10, 0, 0, 0, time.UTC),
+		PetID:     "pet_max",
+		PetProfile: servicedomain.PetProfile{
+			Species:  servicedomain.SpeciesDog,
+			WeightKg: 15,
+			CoatType: servicedomain.CoatTypeDouble,
+		},
+		Pets: []domain.OrderPet{
+			{
+				PetProfile: servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 15, CoatType: servicedomain.CoatTypeDouble},
+				Subtotal:   pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+			},
+			{
+				PetID:      "luna",
+				PetProfile: servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 8, CoatType: servicedomain.CoatTypeDouble},
+				Subtotal:   pricingdomain.NewMoney(4000, pricingdomain.CurrencyPEN),
+			},
+		},
+		Items: []domain.OrderItem{
+			{
+				ItemType:  domain.ItemTypeService,
+				ItemID:    "bath",
+				Qty:       1,
+				UnitPrice: pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+				LineTotal: pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+			},
+			{
+				ItemType:  domain.ItemTypeService,
+				ItemID:    "deshedding",
+				Qty:       2,
+				UnitPrice: pricingdomain.NewMoney(2000, pricingdomain.CurrencyPEN),
+				LineTotal: pricingdomain.NewMoney(4000, pricingdomain.CurrencyPEN),
+				PetID:     "luna",
+			},
+		},
+		Subtotal:      pricingdomain.NewMoney(8500, pricingdomain.CurrencyPEN),
+		TotalDiscount: pricingdomain.NewMoney(850, pricingdomain.CurrencyPEN),
+		Total:         pricingdomain.NewMoney(7650, pricingdomain.CurrencyPEN),
+		CouponCode:    &coupon,
+		BookingHoldID: &holdID,
+		SlotID:        "slot_1",
+		AppointmentAt: &appointmentAt,
+		ExpiresAt:     &expiresAt,
+	}
+
+	order, err := repo.Create(ctx, order)
+	if err != nil {
+		t.Fatalf("unexpected error on create: %v", err)
+	}
+	if order.Version != 1 {
+		t.Errorf("expected version 1 after create, got: %d", order.Version)
+	}
+
+	got, err := repo.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error on get: %v", err)
+	}
+
+	got.CreatedAt = got.CreatedAt.UTC()
+	gotExpiresAt := got.ExpiresAt.UTC()
+	got.ExpiresAt = &gotExpiresAt
+	gotAppointmentAt := got.AppointmentAt.UTC()
+	got.AppointmentAt = &gotAppointmentAt
+	if !reflect.DeepEqual(got, order) {
+		t.Errorf("order did not round-trip:\n got: %+v\nwant: %+v", got, order)
+	}
+
+	overdue, err := repo.ListOverduePending(ctx, expiresAt.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error on list overdue: %v", err)
+	}
+	found := false
+	for _, o := range overdue {
+		found = found || o.ID == order.ID
+	}
+	if !found {
+		t.Errorf("expected order %s in overdue list", order.ID)
+	}
+
+	// Marcar como pagada y verificar update
+	paidAt := time.Date(2026, 1, 12, 11, 0, 0, 0, time.UTC)
+	if err := order.MarkPaid("tx_pg_1", paidAt); err != nil {
+		t.Fatalf("unexpected error on mark paid: %v", err)
+	}
+	order.PaymentProvider = domain.PaymentProviderQR
+	updated, err := repo.Update(audit.WithRequestID(audit.WithActor(ctx, audit.ActorAdmin), "req_pg_1"), order)
+	if err != nil {
+		t.Fatalf("unexpected error on update: %v", err)
+	}
+	if len(updated.NewEvents) != 0 {
+		t.Errorf("expected pending events to be cleared, got: %+v", updated.NewEvents)
+	}
+	if updated.Version != 2 {
+		t.Errorf("expected version 2 after update, got: %d", updated.Version)
+	}
+
+	// Update con versión vieja: conflicto
+	if _, err := repo.Update(ctx, order); !errors.Is(err, domain.ErrOrderVersionConflict) {
+		t.Errorf("expected ErrOrderVersionConflict on stale update, got: %v", err)
+	}
+
+	got, err = repo.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error on get: %v", err)
+	}
+	if got.Status != domain.OrderStatusPaid {
+		t.Errorf("expected status paid, got: %v", got.Status)
+	}
+	if got.PaymentRef == nil || *got.PaymentRef != "tx_pg_1" {
+		t.Errorf("expected payment_ref tx_pg_1")
+	}
+	if got.PaymentProvider != domain.PaymentProviderQR {
+		t.Errorf("expected payment provider qr, got: %q", got.PaymentProvider)
+	}
+	if got.PaidAt == nil || !got.PaidAt.Equal(paidAt) {
+		t.Errorf("expected paid_at %v, got: %v", paidAt, got.PaidAt)
+	}
+	if len(got.Items) != 2 {
+		t.Errorf("expected 2 items after update, got: %d", len(got.Items))
+	}
+
+	// El pago quedó en el historial con actor, request ID y ref
+	events, err := repo.ListEvents(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error on list events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got: %+v", events)
+	}
+	event := events[0]
+	if event.Type != domain.OrderEventPaymentConfirmed || event.FromStatus != domain.OrderStatusPendingPayment ||
+		event.ToStatus != domain.OrderStatusPaid {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.Actor != audit.ActorAdmin || event.RequestID != "req_pg_1" || event.Data["payment_ref"] != "tx_pg_1" {
+		t.Errorf("unexpected event metadata: %+v", event)
+	}
+	if !event.OccurredAt.Equal(paidAt) {
+		t.Errorf("expected occurred_at %v, got: %v", paidAt, event.OccurredAt)
+	}
+}
+
+func TestOrderRepository_ListByUser(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewOrderRepository(pool)
+	ctx := context.Background()
+
+	userID := "user_" + id.NewRequestID()
+	base := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	ids := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		order := domain.Order{
+			ID:            "order_" + id.NewRequestID(),
+			UserID:        userID,
+			Status:        domain.OrderStatusPendingPayment,
+			CreatedAt:     base.Add(time.Duration(i) * time.Minute),
+			Subtotal:      pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+			TotalDiscount: pricingdomain.NewMoney(0, pricingdomain.CurrencyPEN),
+			Total:         pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+		}
+		if _, err := repo.Create(ctx, order); err != nil {
+			t.Fatalf("unexpected error on create: %v", err)
+		}
+		ids = append(ids, order.ID)
+	}
+
+	first, err := repo.ListByUser(ctx, domain.OrderQuery{UserID: userID, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error on list: %v", err)
+	}
+	if len(first) != 2 || first[0].ID != ids[2] || first[1].ID != ids[1] {
+		t.Fatalf("expected newest two orders, got: %+v", first)
+	}
+
+	last := first[1]
+	rest, err := repo.ListByUser(ctx, domain.OrderQuery{
+		UserID: userID,
+		Status: domain.OrderStatusPendingPayment,
+		After:  &domain.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID},
+		Limit:  2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error on list after cursor: %v", err)
+	}
+	if len(rest) != 1 || rest[0].ID != ids[0] || rest[0].UserID != userID {
+		t.Errorf("expected oldest order after cursor, got: %+v", rest)
+	}
+
+	to := base.Add(time.Minute)
+	window, err := repo.ListByUser(ctx, domain.OrderQuery{UserID: userID, To: &to})
+	if err != nil {
+		t.Fatalf("unexpected error on list window: %v", err)
+	}
+	if len(window) != 1 || window[0].ID != ids[0] {
+		t.Errorf("expected only the first order before %v, got: %+v", to, window)
+	}
+}
+
+func TestOrderRepository_Refunds(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewOrderRepository(pool)
+	ctx := context.Background()
+
+	pen := pricingdomain.CurrencyPEN
+	order, err := repo.Create(ctx, domain.Order{
+		ID:        "order_" + id.NewRequestID(),
+		Status:    domain.OrderStatusPendingPayment,
+		CreatedAt: time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC),
+		Items: []domain.OrderItem{
+			{ItemType: domain.ItemTypeService, ItemID: "bath", Qty: 1, UnitPrice: pricingdomain.NewMoney(4500, pen), LineTotal: pricingdomain.NewMoney(4500, pen)},
+			{ItemType: domain.ItemTypeService, ItemID: "deshedding", Qty: 1, UnitPrice: pricingdomain.NewMoney(2000, pen), LineTotal: pricingdomain.NewMoney(2000, pen)},
+		},
+		Subtotal:      pricingdomain.NewMoney(6500, pen),
+		TotalDiscount: pricingdomain.NewMoney(0, pen),
+		Total:         pricingdomain.NewMoney(6500, pen),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error on create: %v", err)
+	}
+
+	paidAt := time.Date(2026, 2, 1, 10, 5, 0, 0, time.UTC)
+	if err := order.MarkPaid("tx_pg_refund", paidAt); err != nil {
+		t.Fatalf("failed to mark paid: %v", err)
+	}
+	refundedAt := paidAt.Add(time.Hour)
+	if err := order.ApplyRefund(domain.Refund{
+		Key:         "rf_pg_1",
+		PaymentRef:  "tx_pg_refund",
+		Amount:      pricingdomain.NewMoney(2000, pen),
+		Lines:       []int{1},
+		ProviderRef: "re_pg_1",
+	}, refundedAt); err != nil {
+		t.Fatalf("failed to apply refund: %v", err)
+	}
+	if _, err := repo.Update(ctx, order); err != nil {
+		t.Fatalf("unexpected error on update: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error on get: %v", err)
+	}
+	if got.RefundedAmount.Amount != 2000 || got.NetPaid().Amount != 4500 {
+		t.Errorf("expected refunded 2000 and net paid 4500, got: %v %v", got.RefundedAmount, got.NetPaid())
+	}
+	if got.Items[0].Refunded || !got.Items[1].Refunded {
+		t.Errorf("expected only line 1 refunded, got: %+v", got.Items)
+	}
+
+	refunds, err := repo.ListRefunds(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error on list refunds: %v", err)
+	}
+	if len(refunds) != 1 {
+		t.Fatalf("expected 1 refund, got: %d", len(refunds))
+	}
+	refund := refunds[0]
+	if refund.Key != "rf_pg_1" || refund.Amount.Amount != 2000 || !refund.SameLines([]int{1}) || refund.ProviderRef != "re_pg_1" {
+		t.Errorf("unexpected refund: %+v", refund)
+	}
+	if !refund.CreatedAt.Equal(refundedAt) {
+		t.Errorf("expected created_at %v, got: %v", refundedAt, refund.CreatedAt)
+	}
+}
+
+func TestOrderRepository_NotFound(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewOrderRepository(pool)
+	ctx := context.Background()
+
+	if _, err := repo.GetByID(ctx, "order_missing"); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Errorf("expected ErrOrderNotFound on get, got: %v", err)
+	}
+
+	if _, err := repo.Update(ctx, domain.Order{ID: "order_missing"}); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Errorf("expected ErrOrderNotFound on update, got: %v", err)
+	}
+
+	if _, err := repo.ListEvents(ctx, "order_missing"); !errors.Is(err, domain.ErrOrderNotFound) {
+		t.Errorf("expected ErrOrderNotFound on list events, got: %v", err)
+	}
+}
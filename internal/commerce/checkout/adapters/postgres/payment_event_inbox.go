@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+	dbpostgres "paku-commerce/internal/db/postgres"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// PaymentEventInbox implementa domain.PaymentEventInbox sobre PostgreSQL.
+type PaymentEventInbox struct {
+	pool *pgxpool.Pool
+}
+
+// NewPaymentEventInbox crea un inbox de webhooks de pagos en PostgreSQL.
+func NewPaymentEventInbox(pool *pgxpool.Pool) *PaymentEventInbox {
+	return &PaymentEventInbox{pool: pool}
+}
+
+const paymentEventColumns = `provider, event_id, type, order_id, payment_ref, refund_ref, amount, currency,
+		reason, occurred_at, status, error, attempts, received_at, claimed_at, processed_at`
+
+// Get retorna el registro del evento del proveedor.
+func (r *PaymentEventInbox) Get(ctx context.Context, provider, eventID string) (domain.PaymentEventRecord, error) {
+	row := dbpostgres.Conn(ctx, r.pool).QueryRow(ctx, `
+		SELECT `+paymentEventColumns+`
+		FROM payment_webhook_events
+		WHERE provider = $1 AND event_id = $2`,
+		provider, eventID,
+	)
+	record, err := scanPaymentEvent(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.PaymentEventRecord{}, domain.ErrPaymentEventNotFound
+	}
+	if err != nil {
+		return domain.PaymentEventRecord{}, fmt.Errorf("failed to get payment event: %w", err)
+	}
+	return record, nil
+}
+
+// Claim toma el evento para procesarlo si nadie lo tiene (ver domain.PaymentEventInbox).
+// El INSERT ... ON CONFLICT bloquea la fila, así que dos entregas concurrentes no pueden
+// tomar el mismo evento.
+func (r *PaymentEventInbox) Claim(ctx context.Context, event domain.PaymentEvent, now, staleBefore time.Time) (domain.PaymentEventRecord, bool, error) {
+	row := dbpostgres.Conn(ctx, r.pool).QueryRow(ctx, `
+		INSERT INTO payment_webhook_events (
+			provider, event_id, type, order_id, payment_ref, refund_ref, amount, currency,
+			reason, occurred_at, status, error, attempts, received_at, claimed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, '', 1, $12, $12)
+		ON CONFLICT (provider, event_id) DO UPDATE
+		SET status = EXCLUDED.status, attempts = payment_webhook_events.attempts + 1,
+		    claimed_at = EXCLUDED.claimed_at
+		WHERE payment_webhook_events.status = $13
+		   OR (payment_webhook_events.status = $11 AND payment_webhook_events.claimed_at < $14)
+		RETURNING `+paymentEventColumns,
+		event.Provider, event.ID, string(event.Type), event.OrderID, event.PaymentRef, event.RefundRef,
+		event.Amount.Amount, string(event.Amount.Currency), event.Reason, event.OccurredAt,
+		string(domain.PaymentEventStatusProcessing), now, string(domain.PaymentEventStatusFailed), staleBefore,
+	)
+	record, err := scanPaymentEvent(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// El evento existe y no se pudo tomar: ya procesado o en curso
+		record, err := r.Get(ctx, event.Provider, event.ID)
+		return record, false, err
+	}
+	if err != nil {
+		return domain.PaymentEventRecord{}, false, fmt.Errorf("failed to claim payment event: %w", err)
+	}
+	return record, true, nil
+}
+
+// Save crea o reemplaza el registro del evento.
+func (r *PaymentEventInbox) Save(ctx context.Context, record domain.PaymentEventRecord) error {
+	event := record.Event
+	_, err := dbpostgres.Conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO payment_webhook_events (`+paymentEventColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (provider, event_id) DO UPDATE
+		SET status = EXCLUDED.status, error = EXCLUDED.error, attempts = EXCLUDED.attempts,
+		    claimed_at = EXCLUDED.claimed_at, processed_at = EXCLUDED.processed_at`,
+		event.Provider, event.ID, string(event.Type), event.OrderID, event.PaymentRef, event.RefundRef,
+		event.Amount.Amount, string(event.Amount.Currency), event.Reason, event.OccurredAt,
+		string(record.Status), record.Error, record.Attempts, record.ReceivedAt, record.ClaimedAt, record.ProcessedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save payment event: %w", err)
+	}
+	return nil
+}
+
+func scanPaymentEvent(row pgx.Row) (domain.PaymentEventRecord, error) {
+	var (
+		record    domain.PaymentEventRecord
+		eventType string
+		currency  string
+		status    string
+	)
+	err := row.Scan(
+		&record.Event.Provider, &record.Event.ID, &eventType, &record.Event.OrderID,
+		&record.Event.PaymentRef, &record.Event.RefundRef, &record.Event.Amount.Amount, &currency,
+		&record.Event.Reason, &record.Event.OccurredAt, &status, &record.Error, &record.Attempts,
+		&record.ReceivedAt, &record.ClaimedAt, &record.ProcessedAt,
+	)
+	if err != nil {
+		return domain.PaymentEventRecord{}, err
+	}
+	record.Event.Type = domain.PaymentEventType(eventType)
+	record.Event.Amount.Currency = pricingdomain.Currency(currency)
+	record.Status = domain.PaymentEventStatus(status)
+	return record, nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/db/postgres/pgtest"
+	"paku-commerce/internal/platform/id"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+func TestPaymentEventInbox_RoundTrip(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	inbox := NewPaymentEventInbox(pool)
+	ctx := context.Background()
+
+	receivedAt := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	record := domain.PaymentEventRecord{
+		Event: domain.PaymentEvent{
+			Provider:   "acme",
+			ID:         "evt_" + id.NewRequestID(),
+			Type:       domain.PaymentEventRefunded,
+			OrderID:    "order_pg_1",
+			PaymentRef: "tx_pg_1",
+			RefundRef:  "re_pg_1",
+			Amount:     pricingdomain.NewMoney(1500, pricingdomain.CurrencyPEN),
+			OccurredAt: receivedAt.Add(-time.Minute),
+		},
+		Status:     domain.PaymentEventStatusFailed,
+		Error:      "database unavailable",
+		Attempts:   1,
+		ReceivedAt: receivedAt,
+	}
+	if err := inbox.Save(ctx, record); err != nil {
+		t.Fatalf("unexpected error on save: %v", err)
+	}
+
+	processedAt := receivedAt.Add(time.Minute)
+	record.Status = domain.PaymentEventStatusProcessed
+	record.Error = ""
+	record.Attempts = 2
+	record.ProcessedAt = &processedAt
+	if err := inbox.Save(ctx, record); err != nil {
+		t.Fatalf("unexpected error on resave: %v", err)
+	}
+
+	got, err := inbox.Get(ctx, "acme", record.Event.ID)
+	if err != nil {
+		t.Fatalf("unexpected error on get: %v", err)
+	}
+	if got.Status != domain.PaymentEventStatusProcessed || got.Attempts != 2 || got.Error != "" ||
+		got.Event.Type != domain.PaymentEventRefunded || got.Event.Amount != record.Event.Amount || got.Event.RefundRef != "re_pg_1" {
+		t.Errorf("unexpected record: %+v", got)
+	}
+	if got.ProcessedAt == nil || !got.ProcessedAt.Equal(processedAt) {
+		t.Errorf("expected processed_at %v, got: %v", processedAt, got.ProcessedAt)
+	}
+
+	if _, err := inbox.Get(ctx, "other", record.Event.ID); !errors.Is(err, domain.ErrPaymentEventNotFound) {
+		t.Errorf("expected ErrPaymentEventNotFound, got: %v", err)
+	}
+}
+
+func TestPaymentEventInbox_Claim(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	inbox := NewPaymentEventInbox(pool)
+	ctx := context.Background()
+
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	event := domain.PaymentEvent{
+		Provider:   "acme",
+		ID:         "evt_" + id.NewRequestID(),
+		Type:       domain.PaymentEventSucceeded,
+		OrderID:    "order_pg_1",
+		PaymentRef: "tx_pg_1",
+		OccurredAt: now,
+	}
+	staleBefore := now.Add(-5 * time.Minute)
+
+	first, claimed, err := inbox.Claim(ctx, event, now, staleBefore)
+	if err != nil || !claimed || first.Status != domain.PaymentEventStatusProcessing || first.Attempts != 1 {
+		t.Fatalf("expected first claim, got: %+v %v %v", first, claimed, err)
+	}
+
+	// Una entrega concurrente no lo toma mientras el claim siga vigente
+	if _, claimed, err := inbox.Claim(ctx, event, now.Add(time.Second), staleBefore); err != nil || claimed {
+		t.Errorf("expected concurrent claim rejected, got: %v %v", claimed, err)
+	}
+
+	// Tras fallar, la reentrega lo retoma
+	first.Status = domain.PaymentEventStatusFailed
+	first.Error = "database unavailable"
+	if err := inbox.Save(ctx, first); err != nil {
+		t.Fatalf("unexpected error on save: %v", err)
+	}
+	retried, claimed, err := inbox.Claim(ctx, event, now.Add(time.Minute), staleBefore)
+	if err != nil || !claimed || retried.Attempts != 2 {
+		t.Fatalf("expected failed event reclaimed, got: %+v %v %v", retried, claimed, err)
+	}
+
+	processedAt := now.Add(time.Minute)
+	retried.Status = domain.PaymentEventStatusProcessed
+	retried.Error = ""
+	retried.ProcessedAt = &processedAt
+	if err := inbox.Save(ctx, retried); err != nil {
+		t.Fatalf("unexpected error on save: %v", err)
+	}
+	done, claimed, err := inbox.Claim(ctx, event, now.Add(time.Hour), now.Add(time.Hour))
+	if err != nil || claimed || !done.IsDone() {
+		t.Errorf("expected processed event not reclaimed, got: %+v %v %v", done, claimed, err)
+	}
+}
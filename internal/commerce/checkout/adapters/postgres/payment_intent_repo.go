@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+	dbpostgres "paku-commerce/internal/db/postgres"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// PaymentIntentRepository implementa domain.PaymentIntentRepository sobre PostgreSQL.
+type PaymentIntentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPaymentIntentRepository crea un repositorio de intentos de pago en PostgreSQL.
+func NewPaymentIntentRepository(pool *pgxpool.Pool) *PaymentIntentRepository {
+	return &PaymentIntentRepository{pool: pool}
+}
+
+const selectPaymentIntentSQL = `
+	SELECT id, order_id, method, amount, currency, provider_ref, qr_data, status,
+	       failure_reason, expires_at, created_at, updated_at, completed_at, claimed_at
+	FROM payment_intents`
+
+const (
+	// uniqueViolation es el SQLSTATE de PostgreSQL para claves duplicadas.
+	uniqueViolation = "23505"
+	// pendingOrderIndex admite un solo intento pending por orden.
+	pendingOrderIndex = "payment_intents_pending_order_idx"
+)
+
+// Create guarda un intento nuevo. El índice único parcial payment_intents_pending_order_idx
+// impide un segundo intento pending para la misma orden.
+func (r *PaymentIntentRepository) Create(ctx context.Context, intent domain.PaymentIntent) (domain.PaymentIntent, error) {
+	_, err := dbpostgres.Conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO payment_intents (
+			id, order_id, method, amount, currency, provider_ref, qr_data, status,
+			failure_reason, expires_at, created_at, updated_at, completed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		intent.ID, intent.OrderID, string(intent.Method), intent.Amount.Amount, string(intent.Amount.Currency),
+		intent.ProviderRef, intent.QRData, string(intent.Status), intent.FailureReason,
+		intent.ExpiresAt, intent.CreatedAt, intent.UpdatedAt, intent.CompletedAt,
+	)
+	if err != nil {
+		// Dos creaciones concurrentes para la misma orden: gana la primera.
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == pendingOrderIndex {
+			return domain.PaymentIntent{}, domain.ErrPaymentIntentPending
+		}
+		return domain.PaymentIntent{}, fmt.Errorf("failed to insert payment intent: %w", err)
+	}
+	return intent, nil
+}
+
+// GetByID busca un intento por ID.
+func (r *PaymentIntentRepository) GetByID(ctx context.Context, id string) (domain.PaymentIntent, error) {
+	return r.getOne(ctx, selectPaymentIntentSQL+` WHERE id = $1`, id)
+}
+
+// GetLatestByOrder retorna el intento más reciente de la orden.
+func (r *PaymentIntentRepository) GetLatestByOrder(ctx context.Context, orderID string) (domain.PaymentIntent, error) {
+	return r.getOne(ctx, selectPaymentIntentSQL+`
+		WHERE order_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1`, orderID)
+}
+
+// GetByProviderRef busca un intento por el ID del cobro en el proveedor.
+func (r *PaymentIntentRepository) GetByProviderRef(ctx context.Context, providerRef string) (domain.PaymentIntent, error) {
+	return r.getOne(ctx, selectPaymentIntentSQL+` WHERE provider_ref = $1`, providerRef)
+}
+
+// Claim toma el intento pending si nadie lo tiene (ver domain.PaymentIntentRepository).
+func (r *PaymentIntentRepository) Claim(ctx context.Context, id string, now, staleBefore time.Time) (domain.PaymentIntent, bool, error) {
+	row := dbpostgres.Conn(ctx, r.pool).QueryRow(ctx, `
+		UPDATE payment_intents SET claimed_at = $2
+		WHERE id = $1 AND status = $4 AND (claimed_at IS NULL OR claimed_at < $3)
+		RETURNING id, order_id, method, amount, currency, provider_ref, qr_data, status,
+		          failure_reason, expires_at, created_at, updated_at, completed_at, claimed_at`,
+		id, now, staleBefore, string(domain.PaymentIntentStatusPending))
+
+	intent, err := scanPaymentIntent(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Resuelto, tomado por otra conciliación o inexistente
+		current, err := r.GetByID(ctx, id)
+		if err != nil {
+			return domain.PaymentIntent{}, false, err
+		}
+		return current, false, nil
+	}
+	if err != nil {
+		return domain.PaymentIntent{}, false, fmt.Errorf("failed to claim payment intent: %w", err)
+	}
+	return intent, true, nil
+}
+
+// Update reemplaza el estado de un intento que sigue pending (compare-and-swap por status).
+func (r *PaymentIntentRepository) Update(ctx context.Context, intent domain.PaymentIntent) (domain.PaymentIntent, error) {
+	tag, err := dbpostgres.Conn(ctx, r.pool).Exec(ctx, `
+		UPDATE payment_intents
+		SET status = $2, failure_reason = $3, updated_at = $4, completed_at = $5, claimed_at = $6
+		WHERE id = $1 AND status = $7`,
+		intent.ID, string(intent.Status), intent.FailureReason, intent.UpdatedAt, intent.CompletedAt, intent.ClaimedAt,
+		string(domain.PaymentIntentStatusPending),
+	)
+	if err != nil {
+		return domain.PaymentIntent{}, fmt.Errorf("failed to update payment intent: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.GetByID(ctx, intent.ID); err != nil {
+			return domain.PaymentIntent{}, err
+		}
+		return domain.PaymentIntent{}, domain.ErrPaymentIntentSettled
+	}
+	return intent, nil
+}
+
+// ListPending lista los intentos pending, del más antiguo al más reciente.
+func (r *PaymentIntentRepository) ListPending(ctx context.Context) ([]domain.PaymentIntent, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, selectPaymentIntentSQL+`
+		WHERE status = $1
+		ORDER BY created_at, id`,
+		string(domain.PaymentIntentStatusPending))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment intents: %w", err)
+	}
+	defer rows.Close()
+
+	intents := make([]domain.PaymentIntent, 0)
+	for rows.Next() {
+		intent, err := scanPaymentIntent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment intent: %w", err)
+		}
+		intents = append(intents, intent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list payment intents: %w", err)
+	}
+	return intents, nil
+}
+
+func (r *PaymentIntentRepository) getOne(ctx context.Context, query string, arg string) (domain.PaymentIntent, error) {
+	row := dbpostgres.Conn(ctx, r.pool).QueryRow(ctx, query, arg)
+
+	intent, err := scanPaymentIntent(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.PaymentIntent{}, domain.ErrPaymentIntentNotFound
+	}
+	if err != nil {
+		return domain.PaymentIntent{}, fmt.Errorf("failed to get payment intent: %w", err)
+	}
+	return intent, nil
+}
+
+func scanPaymentIntent(row pgx.Row) (domain.PaymentIntent, error) {
+	var (
+		intent   domain.PaymentIntent
+		method   string
+		currency string
+		status   string
+	)
+	if err := row.Scan(
+		&intent.ID, &intent.OrderID, &method, &intent.Amount.Amount, &currency, &intent.ProviderRef,
+		&intent.QRData, &status, &intent.FailureReason, &intent.ExpiresAt,
+		&intent.CreatedAt, &intent.UpdatedAt, &intent.CompletedAt, &intent.ClaimedAt,
+	); err != nil {
+		return domain.PaymentIntent{}, err
+	}
+	intent.Method = domain.PaymentMethod(method)
+	intent.Amount.Currency = pricingdomain.Currency(currency)
+	intent.Status = domain.PaymentIntentStatus(status)
+	return intent, nil
+}
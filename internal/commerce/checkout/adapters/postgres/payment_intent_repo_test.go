@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/db/postgres/pgtest"
+	"paku-commerce/internal/platform/id"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+func TestPaymentIntentRepository_RoundTrip(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewPaymentIntentRepository(pool)
+	ctx := context.Background()
+
+	createdAt := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	orderID := "order_pg_" + id.NewRequestID()
+	first := domain.PaymentIntent{
+		ID:          "pi_" + id.NewRequestID(),
+		OrderID:     orderID,
+		Method:      domain.PaymentMethodYape,
+		Amount:      pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+		ProviderRef: "qr_" + id.NewRequestID(),
+		QRData:      "paku-sim://yape/qr_1",
+		Status:      domain.PaymentIntentStatusPending,
+		ExpiresAt:   createdAt.Add(15 * time.Minute),
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	second := first
+	second.ID = "pi_" + id.NewRequestID()
+	second.Method = domain.PaymentMethodPlin
+	second.ProviderRef = "qr_" + id.NewRequestID()
+	second.CreatedAt = createdAt.Add(time.Minute)
+	if _, err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("unexpected error on create: %v", err)
+	}
+
+	// Un solo intento pending por orden
+	if _, err := repo.Create(ctx, second); !errors.Is(err, domain.ErrPaymentIntentPending) {
+		t.Fatalf("expected ErrPaymentIntentPending for a second pending intent, got: %v", err)
+	}
+
+	// El primero vence; el segundo queda como el más reciente de la orden
+	if err := first.MarkExpired(createdAt.Add(20 * time.Minute)); err != nil {
+		t.Fatalf("unexpected error on expire: %v", err)
+	}
+	if _, err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("unexpected error on update: %v", err)
+	}
+	if _, err := repo.Create(ctx, second); err != nil {
+		t.Fatalf("unexpected error on create: %v", err)
+	}
+
+	latest, err := repo.GetLatestByOrder(ctx, orderID)
+	if err != nil {
+		t.Fatalf("unexpected error on get latest: %v", err)
+	}
+	if latest.ID != second.ID || latest.Method != domain.PaymentMethodPlin || latest.Amount != second.Amount {
+		t.Errorf("unexpected latest intent: %+v", latest)
+	}
+
+	got, err := repo.GetByProviderRef(ctx, first.ProviderRef)
+	if err != nil {
+		t.Fatalf("unexpected error on get by provider ref: %v", err)
+	}
+	if got.Status != domain.PaymentIntentStatusExpired || got.CompletedAt == nil {
+		t.Errorf("expected expired intent, got: %+v", got)
+	}
+
+	pending, err := repo.ListPending(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error on list pending: %v", err)
+	}
+	var found bool
+	for _, intent := range pending {
+		if intent.ID == first.ID {
+			t.Errorf("expired intent listed as pending")
+		}
+		found = found || intent.ID == second.ID
+	}
+	if !found {
+		t.Errorf("expected pending intent %s in %+v", second.ID, pending)
+	}
+
+	if _, err := repo.GetByID(ctx, "pi_missing"); !errors.Is(err, domain.ErrPaymentIntentNotFound) {
+		t.Errorf("expected ErrPaymentIntentNotFound, got: %v", err)
+	}
+}
+
+func TestPaymentIntentRepository_ClaimAndSettleOnce(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewPaymentIntentRepository(pool)
+	ctx := context.Background()
+
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	intent := domain.PaymentIntent{
+		ID:          "pi_" + id.NewRequestID(),
+		OrderID:     "order_pg_" + id.NewRequestID(),
+		Method:      domain.PaymentMethodYape,
+		Amount:      pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+		ProviderRef: "qr_" + id.NewRequestID(),
+		Status:      domain.PaymentIntentStatusPending,
+		ExpiresAt:   now.Add(15 * time.Minute),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := repo.Create(ctx, intent); err != nil {
+		t.Fatalf("unexpected error on create: %v", err)
+	}
+
+	claimed, ok, err := repo.Claim(ctx, intent.ID, now, now.Add(-5*time.Minute))
+	if err != nil || !ok || claimed.ClaimedAt == nil {
+		t.Fatalf("expected first claim to win, got: %v %v %+v", ok, err, claimed)
+	}
+	if _, ok, err := repo.Claim(ctx, intent.ID, now.Add(time.Minute), now.Add(-4*time.Minute)); err != nil || ok {
+		t.Errorf("expected concurrent claim to lose, got: %v %v", ok, err)
+	}
+	// Claim vencido: otra conciliación lo retoma
+	if _, ok, err := repo.Claim(ctx, intent.ID, now.Add(10*time.Minute), now.Add(5*time.Minute)); err != nil || !ok {
+		t.Errorf("expected stale claim to be retaken, got: %v %v", ok, err)
+	}
+
+	if err := claimed.MarkSucceeded("", now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error on settle: %v", err)
+	}
+	if _, err := repo.Update(ctx, claimed); err != nil {
+		t.Fatalf("unexpected error on update: %v", err)
+	}
+	// Una segunda resolución no pisa la primera
+	if _, err := repo.Update(ctx, claimed); !errors.Is(err, domain.ErrPaymentIntentSettled) {
+		t.Errorf("expected ErrPaymentIntentSettled, got: %v", err)
+	}
+	if _, ok, err := repo.Claim(ctx, intent.ID, now.Add(time.Hour), now.Add(time.Hour)); err != nil || ok {
+		t.Errorf("expected settled intent not claimable, got: %v %v", ok, err)
+	}
+}
@@ -0,0 +1,201 @@
+// Package qrsimulator implementa payments.QRPaymentsClient en memoria: simula un proveedor
+// de pagos QR (Yape/Plin) para tests y desarrollo local, sin transferencias reales.
+package qrsimulator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// Simulator es un proveedor de pagos QR en memoria. Los cobros quedan pending hasta que
+// se llama a Complete o Fail, vencen en su ExpiresAt y, con AutoCompleteAfter, se pagan
+// solos (para probar el flujo completo en local sin intervención).
+type Simulator struct {
+	AutoCompleteAfter time.Duration // > 0: el cobro se paga solo tras este tiempo
+	Now               func() time.Time
+
+	mu          sync.Mutex
+	payments    map[string]*simulatedPayment // por PaymentRef
+	byReference map[string]string            // Reference -> PaymentRef
+	refunds     map[string]payments.RefundResult
+	refunded    map[string]int64
+	sequence    int
+}
+
+type simulatedPayment struct {
+	payment   payments.QRPayment
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// NewSimulator crea un simulador sin cobros.
+func NewSimulator() *Simulator {
+	return &Simulator{
+		payments:    make(map[string]*simulatedPayment),
+		byReference: make(map[string]string),
+		refunds:     make(map[string]payments.RefundResult),
+		refunded:    make(map[string]int64),
+	}
+}
+
+// CreateQRPayment genera un cobro pending, idempotente por Reference.
+func (s *Simulator) CreateQRPayment(ctx context.Context, req payments.QRPaymentRequest) (payments.QRPayment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ref, exists := s.byReference[req.Reference]; exists {
+		return s.current(ref), nil
+	}
+
+	s.sequence++
+	ref := fmt.Sprintf("qr_sim_%04d", s.sequence)
+	query := url.Values{}
+	query.Set("amount", fmt.Sprintf("%d", req.Amount.Amount))
+	query.Set("currency", string(req.Amount.Currency))
+	query.Set("order_id", req.OrderID)
+
+	s.payments[ref] = &simulatedPayment{
+		payment: payments.QRPayment{
+			PaymentRef: ref,
+			QRData:     fmt.Sprintf("paku-sim://%s/%s?%s", req.Method, ref, query.Encode()),
+			Amount:     req.Amount,
+			Status:     payments.ChargeStatusPending,
+		},
+		createdAt: s.now(),
+		expiresAt: req.ExpiresAt,
+	}
+	s.byReference[req.Reference] = ref
+	return s.current(ref), nil
+}
+
+// GetQRPayment retorna el estado actual del cobro.
+func (s *Simulator) GetQRPayment(ctx context.Context, paymentRef string) (payments.QRPayment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.payments[paymentRef]; !exists {
+		return payments.QRPayment{}, payments.ErrPaymentNotFound
+	}
+	return s.current(paymentRef), nil
+}
+
+// CancelQRPayment anula el cobro si sigue pending y retorna su estado final.
+func (s *Simulator) CancelQRPayment(ctx context.Context, paymentRef string) (payments.QRPayment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.payments[paymentRef]; !exists {
+		return payments.QRPayment{}, payments.ErrPaymentNotFound
+	}
+	if current := s.current(paymentRef); current.Status != payments.ChargeStatusPending {
+		return current, nil
+	}
+	simulated := s.payments[paymentRef]
+	simulated.payment.Status = payments.ChargeStatusCancelled
+	return simulated.payment, nil
+}
+
+// ValidatePayment verifica el cobro QR contra expected.
+func (s *Simulator) ValidatePayment(ctx context.Context, paymentRef string, expected pricingdomain.Money) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.payments[paymentRef]; !exists {
+		return payments.ErrPaymentNotFound
+	}
+	payment := s.current(paymentRef)
+	return payments.Charge{PaymentRef: paymentRef, Amount: payment.Amount, Status: payment.Status}.Verify(expected)
+}
+
+// Refund devuelve parte o todo un cobro pagado, idempotente por IdempotencyKey.
+func (s *Simulator) Refund(ctx context.Context, req payments.RefundRequest) (payments.RefundResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if result, exists := s.refunds[req.IdempotencyKey]; exists {
+		return result, nil
+	}
+	if _, exists := s.payments[req.PaymentRef]; !exists {
+		return payments.RefundResult{}, payments.ErrPaymentNotFound
+	}
+	payment := s.current(req.PaymentRef)
+	if payment.Status != payments.ChargeStatusSucceeded {
+		return payments.RefundResult{}, fmt.Errorf("%w: status %s", payments.ErrPaymentNotCompleted, payment.Status)
+	}
+	if s.refunded[req.PaymentRef]+req.Amount.Amount > payment.Amount.Amount {
+		return payments.RefundResult{}, fmt.Errorf("refund exceeds payment %s", req.PaymentRef)
+	}
+
+	s.sequence++
+	result := payments.RefundResult{ProviderRef: fmt.Sprintf("qr_sim_refund_%04d", s.sequence)}
+	s.refunds[req.IdempotencyKey] = result
+	s.refunded[req.PaymentRef] += req.Amount.Amount
+	return result, nil
+}
+
+// Complete simula que el comprador pagó el QR.
+func (s *Simulator) Complete(paymentRef string) error {
+	return s.settle(paymentRef, payments.ChargeStatusSucceeded, "")
+}
+
+// Fail simula que el proveedor rechazó la transferencia.
+func (s *Simulator) Fail(paymentRef, reason string) error {
+	return s.settle(paymentRef, payments.ChargeStatusFailed, reason)
+}
+
+func (s *Simulator) settle(paymentRef string, status payments.ChargeStatus, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.payments[paymentRef]; !exists {
+		return payments.ErrPaymentNotFound
+	}
+	if current := s.current(paymentRef); current.Status != payments.ChargeStatusPending {
+		return fmt.Errorf("qr payment %s is already %s", paymentRef, current.Status)
+	}
+
+	now := s.now()
+	simulated := s.payments[paymentRef]
+	simulated.payment.Status = status
+	simulated.payment.FailureReason = reason
+	if status == payments.ChargeStatusSucceeded {
+		simulated.payment.PaidAt = &now
+	}
+	return nil
+}
+
+// current aplica el pago automático y el vencimiento y retorna el cobro. Requiere s.mu.
+func (s *Simulator) current(paymentRef string) payments.QRPayment {
+	simulated := s.payments[paymentRef]
+	if simulated.payment.Status != payments.ChargeStatusPending {
+		return simulated.payment
+	}
+
+	now := s.now()
+	if s.AutoCompleteAfter > 0 {
+		paidAt := simulated.createdAt.Add(s.AutoCompleteAfter)
+		inTime := simulated.expiresAt.IsZero() || !paidAt.After(simulated.expiresAt)
+		if inTime && !now.Before(paidAt) {
+			simulated.payment.Status = payments.ChargeStatusSucceeded
+			simulated.payment.PaidAt = &paidAt
+			return simulated.payment
+		}
+	}
+	if !simulated.expiresAt.IsZero() && now.After(simulated.expiresAt) {
+		simulated.payment.Status = payments.ChargeStatusExpired
+	}
+	return simulated.payment
+}
+
+func (s *Simulator) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,86 @@
+package qrsimulator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+func qrRequest(reference string, expiresAt time.Time) payments.QRPaymentRequest {
+	return payments.QRPaymentRequest{
+		Reference: reference,
+		OrderID:   "order_1",
+		Method:    checkoutdomain.PaymentMethodPlin,
+		Amount:    pricingdomain.NewMoney(4500, pricingdomain.CurrencyPEN),
+		ExpiresAt: expiresAt,
+	}
+}
+
+func TestSimulator_CompleteThenRefund(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	simulator := NewSimulator()
+	simulator.Now = func() time.Time { return now }
+	ctx := context.Background()
+
+	payment, err := simulator.CreateQRPayment(ctx, qrRequest("pi_1", now.Add(15*time.Minute)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again, _ := simulator.CreateQRPayment(ctx, qrRequest("pi_1", now.Add(15*time.Minute))); again.PaymentRef != payment.PaymentRef {
+		t.Errorf("expected the same payment for the same reference, got: %s", again.PaymentRef)
+	}
+	if err := simulator.ValidatePayment(ctx, payment.PaymentRef, payment.Amount); !errors.Is(err, payments.ErrPaymentNotCompleted) {
+		t.Errorf("expected ErrPaymentNotCompleted while pending, got: %v", err)
+	}
+
+	if err := simulator.Complete(payment.PaymentRef); err != nil {
+		t.Fatalf("unexpected error on complete: %v", err)
+	}
+	got, _ := simulator.GetQRPayment(ctx, payment.PaymentRef)
+	if got.Status != payments.ChargeStatusSucceeded || got.PaidAt == nil {
+		t.Errorf("expected succeeded payment, got: %+v", got)
+	}
+	if err := simulator.ValidatePayment(ctx, payment.PaymentRef, payment.Amount); err != nil {
+		t.Errorf("expected valid payment, got: %v", err)
+	}
+
+	refund := payments.RefundRequest{PaymentRef: payment.PaymentRef, Amount: payment.Amount, IdempotencyKey: "order_1:rf_1"}
+	first, err := simulator.Refund(ctx, refund)
+	if err != nil {
+		t.Fatalf("unexpected error on refund: %v", err)
+	}
+	if again, err := simulator.Refund(ctx, refund); err != nil || again != first {
+		t.Errorf("expected idempotent refund, got: %+v %v", again, err)
+	}
+}
+
+func TestSimulator_AutoCompleteAndExpiry(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	simulator := NewSimulator()
+	simulator.AutoCompleteAfter = time.Minute
+	simulator.Now = func() time.Time { return now }
+	ctx := context.Background()
+
+	paid, _ := simulator.CreateQRPayment(ctx, qrRequest("pi_paid", now.Add(15*time.Minute)))
+	// El pago automático llegaría después del vencimiento: el QR vence
+	expired, _ := simulator.CreateQRPayment(ctx, qrRequest("pi_expired", now.Add(30*time.Second)))
+
+	now = now.Add(2 * time.Minute)
+	if got, _ := simulator.GetQRPayment(ctx, paid.PaymentRef); got.Status != payments.ChargeStatusSucceeded {
+		t.Errorf("expected auto-completed payment, got: %s", got.Status)
+	}
+	if got, _ := simulator.GetQRPayment(ctx, expired.PaymentRef); got.Status != payments.ChargeStatusExpired {
+		t.Errorf("expected expired payment, got: %s", got.Status)
+	}
+	if err := simulator.Complete(expired.PaymentRef); err == nil {
+		t.Error("expected error completing an expired payment")
+	}
+	if _, err := simulator.GetQRPayment(ctx, "qr_missing"); !errors.Is(err, payments.ErrPaymentNotFound) {
+		t.Errorf("expected ErrPaymentNotFound, got: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultHoldRetryMaxAttempts: intentos de ConfirmHold (incluido el del pago) antes de compensar.
+	DefaultHoldRetryMaxAttempts = 5
+	// DefaultHoldRetryBaseDelay: espera antes del primer reintento; se duplica en cada intento.
+	DefaultHoldRetryBaseDelay = time.Minute
+	// DefaultHoldRetryMaxDelay: tope de la espera entre reintentos.
+	DefaultHoldRetryMaxDelay = 30 * time.Minute
+)
+
+// HoldRetryPolicy define el backoff exponencial para reintentar ConfirmHold
+// de una orden payment_received_booking_failed.
+type HoldRetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration // 0 = sin tope
+}
+
+// DefaultHoldRetryPolicy retorna la política por defecto (5 intentos, 1m, 2m, 4m, ... hasta 30m).
+func DefaultHoldRetryPolicy() HoldRetryPolicy {
+	return HoldRetryPolicy{
+		MaxAttempts: DefaultHoldRetryMaxAttempts,
+		BaseDelay:   DefaultHoldRetryBaseDelay,
+		MaxDelay:    DefaultHoldRetryMaxDelay,
+	}
+}
+
+// NextRetryAt retorna cuándo reintentar tras attempts intentos fallidos,
+// o nil si se agotó el presupuesto de reintentos.
+func (p HoldRetryPolicy) NextRetryAt(attempts int, now time.Time) *time.Time {
+	if attempts >= p.MaxAttempts {
+		return nil
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			delay = p.MaxDelay
+			break
+		}
+	}
+	next := now.Add(delay)
+	return &next
+}
+
+// NeedsReview indica si la orden cobrada sin cita agotó sus reintentos y espera revisión manual.
+func (o Order) NeedsReview() bool {
+	return o.Status == OrderStatusPaymentReceivedBookingFailed && o.NextHoldRetryAt == nil
+}
+
+// MarkBookingFailed registra que el pago se cobró pero booking no confirmó el hold:
+// la orden pasa de paid a payment_received_booking_failed con el primer reintento en nextRetryAt.
+func (o *Order) MarkBookingFailed(cause error, at time.Time, nextRetryAt *time.Time) error {
+	if err := o.transition(OrderStatusPaymentReceivedBookingFailed, at, map[string]string{
+		"error":   cause.Error(),
+		"attempt": "1",
+	}); err != nil {
+		return err
+	}
+	o.HoldConfirmAttempts = 1
+	o.NextHoldRetryAt = nextRetryAt
+	return nil
+}
+
+// RecordHoldRetryFailure registra un reintento fallido de ConfirmHold.
+// nextRetryAt nil indica que se agotaron los reintentos.
+func (o *Order) RecordHoldRetryFailure(cause error, at time.Time, nextRetryAt *time.Time) {
+	o.HoldConfirmAttempts++
+	o.NextHoldRetryAt = nextRetryAt
+	o.RecordEvent(OrderEventHoldConfirmFailed, at, map[string]string{
+		"error":   cause.Error(),
+		"attempt": strconv.Itoa(o.HoldConfirmAttempts),
+	})
+}
+
+// MarkHoldConfirmed completa la saga: booking confirmó el hold y la orden vuelve a paid
+// (PaidAt conserva la hora del cobro).
+func (o *Order) MarkHoldConfirmed(at time.Time) error {
+	if err := o.transition(OrderStatusPaid, at, nil); err != nil {
+		return err
+	}
+	o.NextHoldRetryAt = nil
+	data := map[string]string{"attempt": strconv.Itoa(o.HoldConfirmAttempts + 1)}
+	if o.BookingHoldID != nil {
+		data["hold_id"] = *o.BookingHoldID
+	}
+	o.RecordEvent(OrderEventHoldConfirmed, at, data)
+	return nil
+}
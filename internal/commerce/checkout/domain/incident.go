@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrIncidentNotFound      = errors.New("incident not found")
+	ErrIncidentResolved      = errors.New("incident is already resolved")
+	ErrIncidentNotRetryable  = errors.New("incident operation cannot be retried")
+	ErrMissingResolutionNote = errors.New("resolution note is required")
+)
+
+// IncidentOperation es la operación externa que falló y quedó pendiente de revisión.
+type IncidentOperation string
+
+const (
+	// IncidentOperationCancelHold: no se pudo liberar un hold de booking.
+	IncidentOperationCancelHold IncidentOperation = "cancel_hold"
+	// IncidentOperationConfirmHold: la orden se cobró pero booking no confirmó el hold.
+	IncidentOperationConfirmHold IncidentOperation = "confirm_hold"
+	// IncidentOperationRefund: falló el reembolso automático de una orden cobrada sin cita.
+	IncidentOperationRefund IncidentOperation = "refund"
+	// IncidentOperationCancellationRefund: falló el reembolso de una orden pagada que el
+	// usuario canceló.
+	IncidentOperationCancellationRefund IncidentOperation = "cancellation_refund"
+	// IncidentOperationConfirmPayment: llegó un pago QR que la orden ya no puede aceptar
+	// (cancelada, vencida, monto distinto); se revisa y reembolsa a mano.
+	IncidentOperationConfirmPayment IncidentOperation = "confirm_payment"
+	// IncidentOperationRecordRefund: el proveedor emitió un reembolso que la orden no pudo
+	// registrar (p. ej. otro reembolso cubrió las mismas líneas); se concilia a mano.
+	IncidentOperationRecordRefund IncidentOperation = "record_refund"
+	// IncidentOperationCancelPaymentIntent: no se pudo anular en el proveedor el QR
+	// pendiente de una orden cancelada; si se paga, el sync abre confirm_payment.
+	IncidentOperationCancelPaymentIntent IncidentOperation = "cancel_payment_intent"
+)
+
+// IncidentStatus es el estado de un incidente en la cola de revisión.
+type IncidentStatus string
+
+const (
+	IncidentStatusOpen     IncidentStatus = "open"
+	IncidentStatusResolved IncidentStatus = "resolved"
+)
+
+// IsValid indica si el estado es conocido.
+func (s IncidentStatus) IsValid() bool {
+	return s == IncidentStatusOpen || s == IncidentStatusResolved
+}
+
+// Incident es una falla de un side effect (booking/pagos) que checkout no pudo completar
+// y requiere que un operador la reintente o la resuelva a mano.
+type Incident struct {
+	ID         string
+	OrderID    string // "" si el hold no llegó a tener orden
+	HoldID     string
+	Operation  IncidentOperation
+	Error      string // último error de la operación
+	Status     IncidentStatus
+	Attempts   int    // reintentos manuales fallidos
+	Resolution string // nota al resolver
+	ResolvedBy string // actor que resolvió (ver audit)
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+// NewIncident crea un incidente abierto para la operación fallida.
+func NewIncident(id, orderID, holdID string, operation IncidentOperation, cause error, at time.Time) Incident {
+	return Incident{
+		ID:        id,
+		OrderID:   orderID,
+		HoldID:    holdID,
+		Operation: operation,
+		Error:     cause.Error(),
+		Status:    IncidentStatusOpen,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+}
+
+// RecordRetryFailure registra un reintento fallido: el incidente sigue abierto con el nuevo error.
+func (i *Incident) RecordRetryFailure(cause error, at time.Time) {
+	i.Attempts++
+	i.Error = cause.Error()
+	i.UpdatedAt = at
+}
+
+// Resolve cierra el incidente con la nota del operador.
+func (i *Incident) Resolve(note, actor string, at time.Time) error {
+	if i.Status == IncidentStatusResolved {
+		return ErrIncidentResolved
+	}
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return ErrMissingResolutionNote
+	}
+	i.Status = IncidentStatusResolved
+	i.Resolution = note
+	i.ResolvedBy = actor
+	i.UpdatedAt = at
+	i.ResolvedAt = &at
+	return nil
+}
+
+// IncidentQuery filtra la cola de revisión.
+type IncidentQuery struct {
+	Status  IncidentStatus // "" = cualquier estado
+	OrderID string         // "" = cualquier orden
+}
+
+// Matches indica si el incidente cumple los filtros de la query.
+func (q IncidentQuery) Matches(incident Incident) bool {
+	if q.Status != "" && incident.Status != q.Status {
+		return false
+	}
+	if q.OrderID != "" && incident.OrderID != q.OrderID {
+		return false
+	}
+	return true
+}
+
+// IncidentRepository define el acceso a la cola de revisión.
+type IncidentRepository interface {
+	Create(ctx context.Context, incident Incident) (Incident, error)
+	GetByID(ctx context.Context, id string) (Incident, error)
+	Update(ctx context.Context, incident Incident) (Incident, error)
+	// List lista los incidentes que cumplen query, del más antiguo al más reciente.
+	List(ctx context.Context, query IncidentQuery) ([]Incident, error)
+}
@@ -0,0 +1,8 @@
+package domain
+
+// ItemViolation describe por qué un item de una intención de compra no cumple las reglas del catálogo.
+type ItemViolation struct {
+	Index  int // posición del item en la lista
+	Item   PurchaseItem
+	Reason error
+}
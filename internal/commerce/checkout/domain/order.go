@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	servicedomain "paku-commerce/internal/commerce/service/domain"
@@ -13,8 +14,89 @@ type OrderStatus string
 
 const (
 	OrderStatusPendingPayment OrderStatus = "pending_payment"
+	OrderStatusProcessing     OrderStatus = "processing" // pago en autorización con el proveedor
 	OrderStatusPaid           OrderStatus = "paid"
+	OrderStatusFailed         OrderStatus = "failed" // el pago fue rechazado; se puede reintentar
 	OrderStatusCancelled      OrderStatus = "cancelled"
+	OrderStatusRefunded       OrderStatus = "refunded"
+	OrderStatusFulfilled      OrderStatus = "fulfilled" // la cita se realizó
+	OrderStatusNoShow         OrderStatus = "no_show"   // la mascota no se presentó a la cita
+
+	// OrderStatusPaymentReceivedBookingFailed: se cobró pero booking no confirmó el hold;
+	// se reintenta ConfirmHold con backoff y luego se reembolsa o pasa a revisión manual.
+	OrderStatusPaymentReceivedBookingFailed OrderStatus = "payment_received_booking_failed"
+)
+
+// orderTransitions define las transiciones permitidas desde cada estado.
+// cancelled y refunded son terminales; fulfilled solo admite un reembolso.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPendingPayment:               {OrderStatusProcessing, OrderStatusPaid, OrderStatusFailed, OrderStatusCancelled},
+	OrderStatusProcessing:                   {OrderStatusPaid, OrderStatusFailed},
+	OrderStatusFailed:                       {OrderStatusProcessing, OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:                         {OrderStatusRefunded, OrderStatusFulfilled, OrderStatusNoShow, OrderStatusCancelled, OrderStatusPaymentReceivedBookingFailed},
+	OrderStatusPaymentReceivedBookingFailed: {OrderStatusPaid, OrderStatusRefunded},
+	OrderStatusFulfilled:                    {OrderStatusRefunded},
+	OrderStatusNoShow:                       {OrderStatusRefunded},
+}
+
+// IsValid indica si s es un estado conocido de la máquina de estados.
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPendingPayment, OrderStatusProcessing, OrderStatusPaid, OrderStatusFailed,
+		OrderStatusCancelled, OrderStatusRefunded, OrderStatusFulfilled, OrderStatusNoShow,
+		OrderStatusPaymentReceivedBookingFailed:
+		return true
+	}
+	return false
+}
+
+// CanTransition indica si la máquina de estados permite pasar de from a to.
+func CanTransition(from, to OrderStatus) bool {
+	for _, allowed := range orderTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError describe una transición de estado no permitida.
+// Es ErrInvalidOrderState (y ErrOrderCancelled si la orden estaba cancelada) para errors.Is.
+type TransitionError struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("invalid order transition from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() []error {
+	if e.From == OrderStatusCancelled {
+		return []error{ErrInvalidOrderState, ErrOrderCancelled}
+	}
+	return []error{ErrInvalidOrderState}
+}
+
+// OrderPaymentTTL es el plazo para pagar una orden pending_payment antes de que expire.
+const OrderPaymentTTL = 30 * time.Minute
+
+// CancelReason indica por qué se canceló una orden ("" = no cancelada).
+type CancelReason string
+
+const (
+	CancelReasonRequested   CancelReason = "requested"    // cancelación explícita (usuario o admin)
+	CancelReasonExpired     CancelReason = "expired"      // venció el plazo de pago (ExpiresAt)
+	CancelReasonCartExpired CancelReason = "cart_expired" // expiró el carrito que originó la orden
+)
+
+// PaymentProvider identifica al proveedor que capturó el cobro de una orden: sus
+// reembolsos deben salir por el mismo proveedor.
+type PaymentProvider string
+
+const (
+	PaymentProviderCard PaymentProvider = "card" // tarjeta (Culqi o stub)
+	PaymentProviderQR   PaymentProvider = "qr"   // Yape/Plin
 )
 
 var (
@@ -25,28 +107,78 @@ var (
 
 // Order representa una orden de compra.
 type Order struct {
-	ID            string
-	Status        OrderStatus
-	CreatedAt     time.Time
-	PetProfile    servicedomain.PetProfile
-	Items         []OrderItem
-	Subtotal      pricingdomain.Money
-	TotalDiscount pricingdomain.Money
-	Total         pricingdomain.Money
-	CouponCode    *string
-	BookingHoldID *string
-	PaymentRef    *string
-	PaidAt        *time.Time
+	ID                  string
+	UserID              string // dueño de la orden (X-User-ID); "" = orden anónima
+	Status              OrderStatus
+	CreatedAt           time.Time
+	PetID               string                   // ID del pet principal en el servicio de mascotas ("" = perfil inline)
+	PetProfile          servicedomain.PetProfile // pet principal (snapshot al crear la orden)
+	Pets                []OrderPet               // una entrada por mascota con items, en orden de aparición
+	Items               []OrderItem
+	Subtotal            pricingdomain.Money
+	TotalDiscount       pricingdomain.Money
+	Total               pricingdomain.Money
+	CouponCode          *string
+	BookingHoldID       *string
+	SlotID              string     // slot reservado en booking ("" = orden sin cita)
+	AppointmentAt       *time.Time // inicio de la cita (para la política de cancelación)
+	PaymentRef          *string
+	PaymentProvider     PaymentProvider // proveedor que capturó el cobro ("" = tarjeta, órdenes anteriores)
+	PaidAt              *time.Time
+	ProcessingAt        *time.Time // timestamps de cada transición (nil = no ocurrió)
+	FailedAt            *time.Time
+	CancelledAt         *time.Time
+	RefundedAt          *time.Time
+	FulfilledAt         *time.Time
+	NoShowAt            *time.Time
+	BookingFailedAt     *time.Time          // cobrada pero sin hold confirmado (payment_received_booking_failed)
+	ExpiresAt           *time.Time          // plazo de pago; nil = sin vencimiento (órdenes anteriores al plazo)
+	CancelReason        CancelReason        // motivo si Status es cancelled
+	CancellationFee     pricingdomain.Money // penalidad retenida al cancelar una orden pagada
+	RefundedAmount      pricingdomain.Money // suma de los reembolsos emitidos (ver NetPaid)
+	HoldConfirmAttempts int                 // intentos fallidos de ConfirmHold tras el cobro
+	NextHoldRetryAt     *time.Time          // próximo reintento de ConfirmHold (nil = sin reintento programado)
+	Version             int                 // control de concurrencia optimista (lo gestiona el repositorio)
+	NewEvents           []OrderEvent        // eventos aún no persistidos en el historial (ver TakeNewEvents)
+	NewRefunds          []Refund            // reembolsos aún no persistidos (ver TakeNewRefunds)
 }
 
-// MarkPaid marca la orden como pagada de forma idempotente.
-func (o *Order) MarkPaid(paymentRef string, paidAt time.Time) error {
-	if o.Status == OrderStatusCancelled {
-		return ErrOrderCancelled
+// OrderPet resume lo comprado para una mascota de la orden.
+type OrderPet struct {
+	PetID      string // "" = pet principal
+	PetProfile servicedomain.PetProfile
+	Subtotal   pricingdomain.Money // suma de sus líneas, antes de descuentos
+}
+
+// IsPaid indica si la orden ya fue pagada (incluye estados posteriores al pago).
+func (o Order) IsPaid() bool {
+	switch o.Status {
+	case OrderStatusPaid, OrderStatusFulfilled, OrderStatusNoShow, OrderStatusRefunded,
+		OrderStatusPaymentReceivedBookingFailed:
+		return true
 	}
+	return false
+}
 
-	if o.Status == OrderStatusPaid {
-		// Idempotencia: si ya está paid con la misma ref, OK
+// IsPaymentOverdue indica si la orden sigue esperando pago (pending_payment o failed)
+// después de su plazo de pago.
+func (o Order) IsPaymentOverdue(now time.Time) bool {
+	awaitingPayment := o.Status == OrderStatusPendingPayment || o.Status == OrderStatusFailed
+	return awaitingPayment && o.ExpiresAt != nil && now.After(*o.ExpiresAt)
+}
+
+// MarkProcessing marca que el pago está en autorización con el proveedor.
+func (o *Order) MarkProcessing(at time.Time) error {
+	if o.Status == OrderStatusProcessing {
+		return nil
+	}
+	return o.transition(OrderStatusProcessing, at, nil)
+}
+
+// MarkPaid marca la orden como pagada de forma idempotente.
+func (o *Order) MarkPaid(paymentRef string, paidAt time.Time) error {
+	if o.IsPaid() {
+		// Idempotencia: si ya está pagada con la misma ref, OK
 		if o.PaymentRef != nil && *o.PaymentRef == paymentRef {
 			return nil
 		}
@@ -54,31 +186,113 @@ func (o *Order) MarkPaid(paymentRef string, paidAt time.Time) error {
 		return ErrPaymentConflict
 	}
 
-	if o.Status == OrderStatusPendingPayment {
-		o.Status = OrderStatusPaid
-		o.PaymentRef = &paymentRef
-		o.PaidAt = &paidAt
-		return nil
+	if err := o.transition(OrderStatusPaid, paidAt, map[string]string{"payment_ref": paymentRef}); err != nil {
+		return err
+	}
+	o.PaymentRef = &paymentRef
+	return nil
+}
+
+// PaidWith retorna el proveedor que capturó el cobro (tarjeta si no se registró).
+func (o Order) PaidWith() PaymentProvider {
+	if o.PaymentProvider == "" {
+		return PaymentProviderCard
 	}
+	return o.PaymentProvider
+}
 
-	return ErrInvalidOrderState
+// MarkFailed marca que el proveedor rechazó el pago (se puede reintentar).
+// reason, si no está vacío, queda en el evento del historial.
+func (o *Order) MarkFailed(reason string, at time.Time) error {
+	if o.Status == OrderStatusFailed {
+		return nil
+	}
+	var data map[string]string
+	if reason != "" {
+		data = map[string]string{"reason": reason}
+	}
+	return o.transition(OrderStatusFailed, at, data)
 }
 
-// MarkCancelled marca la orden como cancelada.
-func (o *Order) MarkCancelled() error {
+// MarkCancelled marca la orden como cancelada con el motivo dado.
+// Una orden pagada solo se cancela aplicando la política (CancelWithPolicy).
+func (o *Order) MarkCancelled(reason CancelReason, at time.Time) error {
 	if o.Status == OrderStatusCancelled {
 		// Idempotente: ya cancelada
 		return nil
 	}
+	if o.IsPaid() {
+		return &TransitionError{From: o.Status, To: OrderStatusCancelled}
+	}
+	if err := o.transition(OrderStatusCancelled, at, map[string]string{"cancel_reason": string(reason)}); err != nil {
+		return err
+	}
+	o.CancelReason = reason
+	return nil
+}
 
-	if o.Status == OrderStatusPaid {
-		return ErrInvalidOrderState
+// MarkRefunded marca la orden como reembolsada.
+func (o *Order) MarkRefunded(at time.Time) error {
+	if o.Status == OrderStatusRefunded {
+		return nil
 	}
+	return o.transition(OrderStatusRefunded, at, nil)
+}
 
-	if o.Status == OrderStatusPendingPayment {
-		o.Status = OrderStatusCancelled
+// MarkFulfilled marca que la cita se realizó.
+func (o *Order) MarkFulfilled(at time.Time) error {
+	if o.Status == OrderStatusFulfilled {
 		return nil
 	}
+	return o.transition(OrderStatusFulfilled, at, nil)
+}
+
+// MarkNoShow marca que la mascota no se presentó a la cita.
+func (o *Order) MarkNoShow(at time.Time) error {
+	if o.Status == OrderStatusNoShow {
+		return nil
+	}
+	return o.transition(OrderStatusNoShow, at, nil)
+}
+
+// transition aplica el cambio de estado si está permitido, registra su timestamp
+// y agrega el evento al historial con data como detalle.
+func (o *Order) transition(to OrderStatus, at time.Time, data map[string]string) error {
+	if !CanTransition(o.Status, to) {
+		return &TransitionError{From: o.Status, To: to}
+	}
+
+	from := o.Status
+	o.Status = to
+	switch to {
+	case OrderStatusProcessing:
+		o.ProcessingAt = &at
+	case OrderStatusPaid:
+		if o.PaidAt == nil {
+			// Al volver desde payment_received_booking_failed se conserva la hora del cobro
+			o.PaidAt = &at
+		}
+	case OrderStatusFailed:
+		o.FailedAt = &at
+	case OrderStatusCancelled:
+		o.CancelledAt = &at
+	case OrderStatusRefunded:
+		o.RefundedAt = &at
+	case OrderStatusFulfilled:
+		o.FulfilledAt = &at
+	case OrderStatusNoShow:
+		o.NoShowAt = &at
+	case OrderStatusPaymentReceivedBookingFailed:
+		o.BookingFailedAt = &at
+	}
 
-	return ErrInvalidOrderState
+	o.NewEvents = append(o.NewEvents, OrderEvent{
+		OrderID:    o.ID,
+		Type:       transitionEvents[to],
+		FromStatus: from,
+		ToStatus:   to,
+		Data:       data,
+		OccurredAt: at,
+	})
+	return nil
 }
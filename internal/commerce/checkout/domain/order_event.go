@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// OrderEventType identifica un hecho del historial de una orden.
+type OrderEventType string
+
+const (
+	OrderEventCreated           OrderEventType = "created"
+	OrderEventPaymentProcessing OrderEventType = "payment_processing"
+	OrderEventPaymentConfirmed  OrderEventType = "payment_confirmed"
+	OrderEventPaymentFailed     OrderEventType = "payment_failed"
+	OrderEventHoldConfirmed     OrderEventType = "hold_confirmed"
+	OrderEventBookingFailed     OrderEventType = "booking_failed"      // cobrada pero ConfirmHold falló
+	OrderEventHoldConfirmFailed OrderEventType = "hold_confirm_failed" // reintento de ConfirmHold fallido
+	OrderEventManualReview      OrderEventType = "manual_review_required"
+	OrderEventCancelled         OrderEventType = "cancelled"
+	OrderEventRefunded          OrderEventType = "refunded"
+	OrderEventRefundIssued      OrderEventType = "refund_issued" // reembolso parcial (la orden conserva su estado)
+	OrderEventFulfilled         OrderEventType = "fulfilled"
+	OrderEventNoShow            OrderEventType = "no_show"
+)
+
+// transitionEvents asocia cada estado destino con el evento que registra la transición.
+var transitionEvents = map[OrderStatus]OrderEventType{
+	OrderStatusProcessing: OrderEventPaymentProcessing,
+	OrderStatusPaid:       OrderEventPaymentConfirmed,
+	OrderStatusFailed:     OrderEventPaymentFailed,
+	OrderStatusCancelled:  OrderEventCancelled,
+	OrderStatusRefunded:   OrderEventRefunded,
+	OrderStatusFulfilled:  OrderEventFulfilled,
+	OrderStatusNoShow:     OrderEventNoShow,
+
+	OrderStatusPaymentReceivedBookingFailed: OrderEventBookingFailed,
+}
+
+// OrderEvent es una entrada del historial (append-only) de una orden.
+type OrderEvent struct {
+	OrderID    string
+	Type       OrderEventType
+	FromStatus OrderStatus // "" si el evento no cambia el estado
+	ToStatus   OrderStatus
+	Actor      string            // "user:<id>", "admin", "system:cart-expiry", ...
+	RequestID  string            // "" fuera de un request HTTP
+	Data       map[string]string // detalle del evento (payment_ref, hold_id, cancel_reason, refund_key)
+	OccurredAt time.Time
+}
+
+// RecordEvent agrega un evento pendiente de persistir al historial de la orden.
+func (o *Order) RecordEvent(eventType OrderEventType, at time.Time, data map[string]string) {
+	o.NewEvents = append(o.NewEvents, OrderEvent{
+		OrderID:    o.ID,
+		Type:       eventType,
+		ToStatus:   o.Status,
+		Data:       data,
+		OccurredAt: at,
+	})
+}
+
+// TakeNewEvents retorna los eventos pendientes con actor y request ID, y los quita de la orden.
+// Lo usan los repositorios al persistir la orden.
+func (o *Order) TakeNewEvents(actor, requestID string) []OrderEvent {
+	events := o.NewEvents
+	o.NewEvents = nil
+	for i := range events {
+		events[i].OrderID = o.ID
+		events[i].Actor = actor
+		events[i].RequestID = requestID
+	}
+	return events
+}
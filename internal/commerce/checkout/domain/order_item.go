@@ -9,4 +9,6 @@ type OrderItem struct {
 	Qty       int
 	UnitPrice pricingdomain.Money
 	LineTotal pricingdomain.Money
+	PetID     string // "" = pet principal
+	Refunded  bool   // la línea ya se reembolsó (total o por línea)
 }
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+var ErrPaymentEventNotFound = errors.New("payment event not found")
+
+// PaymentEventType es el tipo normalizado de un evento del proveedor de pagos.
+type PaymentEventType string
+
+const (
+	PaymentEventSucceeded PaymentEventType = "payment.succeeded"
+	PaymentEventFailed    PaymentEventType = "payment.failed"
+	PaymentEventRefunded  PaymentEventType = "payment.refunded"
+)
+
+// PaymentEvent es un evento de webhook del proveedor, ya verificado y normalizado.
+type PaymentEvent struct {
+	Provider   string
+	ID         string // ID del evento en el proveedor: clave de idempotencia del inbox
+	Type       PaymentEventType
+	OrderID    string
+	PaymentRef string
+	RefundRef  string              // payment.refunded: ID del reembolso en el proveedor
+	Amount     pricingdomain.Money // payment.refunded: monto devuelto (cero = todo lo reembolsable)
+	Reason     string              // payment.failed: motivo del rechazo
+	OccurredAt time.Time
+}
+
+// PaymentEventStatus es el resultado de procesar un evento del inbox.
+type PaymentEventStatus string
+
+const (
+	// PaymentEventStatusProcessed: el evento se aplicó a la orden.
+	PaymentEventStatusProcessed PaymentEventStatus = "processed"
+	// PaymentEventStatusIgnored: el evento no aplica (tipo desconocido, orden inexistente,
+	// ya aplicado por otra vía, ...). No se reprocesa.
+	PaymentEventStatusIgnored PaymentEventStatus = "ignored"
+	// PaymentEventStatusFailed: error transitorio; se reprocesa cuando el proveedor reintente.
+	PaymentEventStatusFailed PaymentEventStatus = "failed"
+	// PaymentEventStatusProcessing: una entrega tomó el evento y lo está aplicando.
+	// Las entregas concurrentes no lo reprocesan mientras el claim siga vigente.
+	PaymentEventStatusProcessing PaymentEventStatus = "processing"
+)
+
+// PaymentEventRecord es la entrada del inbox de webhooks de un evento recibido.
+type PaymentEventRecord struct {
+	Event       PaymentEvent
+	Status      PaymentEventStatus
+	Error       string // motivo por el que se ignoró o falló
+	Attempts    int    // entregas procesadas (el proveedor reintenta mientras falle)
+	ReceivedAt  time.Time
+	ClaimedAt   time.Time  // última vez que una entrega tomó el evento para procesarlo
+	ProcessedAt *time.Time // nil mientras no se procese con éxito
+}
+
+// IsDone indica si el evento ya se procesó y las nuevas entregas son duplicadas.
+func (r PaymentEventRecord) IsDone() bool {
+	return r.Status == PaymentEventStatusProcessed || r.Status == PaymentEventStatusIgnored
+}
+
+// PaymentEventInbox persiste los eventos de webhook recibidos para deduplicarlos por (proveedor, ID).
+type PaymentEventInbox interface {
+	// Get retorna el registro del evento (ErrPaymentEventNotFound si nunca se recibió).
+	Get(ctx context.Context, provider, eventID string) (PaymentEventRecord, error)
+	// Claim toma el evento de forma atómica para procesarlo: lo crea como processing si nunca
+	// se recibió, o lo retoma si falló o si su claim es anterior a staleBefore (la entrega que
+	// lo tomó no terminó). Con claimed=false retorna el registro vigente: ya procesado o en
+	// curso en otra entrega.
+	Claim(ctx context.Context, event PaymentEvent, now, staleBefore time.Time) (record PaymentEventRecord, claimed bool, err error)
+	// Save crea o reemplaza el registro del evento.
+	Save(ctx context.Context, record PaymentEventRecord) error
+}
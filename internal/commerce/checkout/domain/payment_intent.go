@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+var (
+	ErrPaymentIntentNotFound = errors.New("payment intent not found")
+	ErrInvalidPaymentMethod  = errors.New("invalid payment method")
+	// ErrPaymentIntentPending indica que la orden ya tiene un QR vigente con otro método:
+	// hay que esperar a que se pague, falle o venza antes de generar otro.
+	ErrPaymentIntentPending = errors.New("order already has a pending payment intent")
+	ErrPaymentIntentSettled = errors.New("payment intent is already settled")
+)
+
+// PaymentMethod es la billetera con la que se paga un intento de pago QR.
+type PaymentMethod string
+
+const (
+	PaymentMethodYape PaymentMethod = "yape"
+	PaymentMethodPlin PaymentMethod = "plin"
+)
+
+// IsValid indica si el método es una billetera soportada.
+func (m PaymentMethod) IsValid() bool {
+	return m == PaymentMethodYape || m == PaymentMethodPlin
+}
+
+// PaymentIntentStatus es el estado de un intento de pago QR.
+type PaymentIntentStatus string
+
+const (
+	// PaymentIntentStatusPending: el QR está vigente y la transferencia aún no llega.
+	PaymentIntentStatusPending PaymentIntentStatus = "pending"
+	// PaymentIntentStatusSucceeded: la transferencia llegó al proveedor.
+	PaymentIntentStatusSucceeded PaymentIntentStatus = "succeeded"
+	// PaymentIntentStatusFailed: el proveedor rechazó la transferencia.
+	PaymentIntentStatusFailed PaymentIntentStatus = "failed"
+	// PaymentIntentStatusExpired: el QR venció sin pago.
+	PaymentIntentStatusExpired PaymentIntentStatus = "expired"
+	// PaymentIntentStatusCancelled: el QR se anuló en el proveedor (la orden se canceló).
+	PaymentIntentStatusCancelled PaymentIntentStatus = "cancelled"
+)
+
+// PaymentIntent es una solicitud de pago por QR (Yape/Plin) de una orden. El pago es
+// asíncrono: el comprador escanea el QR en su app y checkout se entera por polling al
+// proveedor o por su webhook, y recién entonces confirma el pago de la orden.
+type PaymentIntent struct {
+	ID            string
+	OrderID       string
+	Method        PaymentMethod
+	Amount        pricingdomain.Money // total de la orden al generar el QR
+	ProviderRef   string              // ID del cobro en el proveedor; es el PaymentRef de la orden
+	QRData        string              // contenido del QR a mostrar al comprador
+	Status        PaymentIntentStatus
+	FailureReason string // motivo si falló, o si se cobró pero la orden no se pudo pagar
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   *time.Time // cuándo dejó de estar pending (nil = pending)
+	ClaimedAt     *time.Time // una conciliación lo tomó para aplicar su resultado (ver PaymentIntentRepository.Claim)
+}
+
+// IsPending indica si el intento sigue esperando la transferencia.
+func (i PaymentIntent) IsPending() bool {
+	return i.Status == PaymentIntentStatusPending
+}
+
+// MarkSucceeded registra que la transferencia llegó. reason, si no está vacío, explica
+// por qué la orden no se pudo marcar pagada pese al cobro.
+func (i *PaymentIntent) MarkSucceeded(reason string, at time.Time) error {
+	return i.settle(PaymentIntentStatusSucceeded, reason, at)
+}
+
+// MarkFailed registra que el proveedor rechazó la transferencia.
+func (i *PaymentIntent) MarkFailed(reason string, at time.Time) error {
+	return i.settle(PaymentIntentStatusFailed, reason, at)
+}
+
+// MarkExpired registra que el QR venció sin pago.
+func (i *PaymentIntent) MarkExpired(at time.Time) error {
+	return i.settle(PaymentIntentStatusExpired, "", at)
+}
+
+// MarkCancelled registra que el QR se anuló antes de pagarse.
+func (i *PaymentIntent) MarkCancelled(at time.Time) error {
+	return i.settle(PaymentIntentStatusCancelled, "", at)
+}
+
+func (i *PaymentIntent) settle(status PaymentIntentStatus, reason string, at time.Time) error {
+	if !i.IsPending() {
+		return ErrPaymentIntentSettled
+	}
+	i.Status = status
+	i.FailureReason = reason
+	i.UpdatedAt = at
+	i.CompletedAt = &at
+	return nil
+}
+
+// PaymentIntentRepository define el acceso a los intentos de pago QR.
+type PaymentIntentRepository interface {
+	// Create guarda un intento nuevo. Una orden tiene a lo sumo un intento pending: si ya
+	// hay otro, retorna ErrPaymentIntentPending.
+	Create(ctx context.Context, intent PaymentIntent) (PaymentIntent, error)
+	GetByID(ctx context.Context, id string) (PaymentIntent, error)
+	// GetLatestByOrder retorna el intento más reciente de la orden.
+	GetLatestByOrder(ctx context.Context, orderID string) (PaymentIntent, error)
+	// GetByProviderRef busca el intento por el ID del cobro en el proveedor.
+	GetByProviderRef(ctx context.Context, providerRef string) (PaymentIntent, error)
+	// Claim toma de forma atómica un intento pending para aplicar su resultado: solo una
+	// conciliación a la vez (worker, GET o webhook) confirma el pago. Lo retoma si el claim
+	// anterior es previo a staleBefore (esa conciliación murió a mitad del proceso).
+	// claimed=false si no está pending o si otra conciliación lo tiene.
+	Claim(ctx context.Context, id string, now, staleBefore time.Time) (intent PaymentIntent, claimed bool, err error)
+	// Update reemplaza el estado de un intento solo si sigue pending (compare-and-swap);
+	// si ya se resolvió retorna ErrPaymentIntentSettled.
+	Update(ctx context.Context, intent PaymentIntent) (PaymentIntent, error)
+	// ListPending lista los intentos pending, del más antiguo al más reciente.
+	ListPending(ctx context.Context) ([]PaymentIntent, error)
+}
@@ -1,3 +1,98 @@
 package domain
 
-// TODO: Este archivo está reservado para políticas y validaciones de checkout futuras.
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// ErrCancellationWindowClosed indica que la cita ya empezó y la orden no se puede cancelar.
+var ErrCancellationWindowClosed = errors.New("order cannot be cancelled after the appointment started")
+
+const (
+	// DefaultFreeCancellationWindow: cancelación gratis hasta 24 horas antes de la cita.
+	DefaultFreeCancellationWindow = 24 * time.Hour
+	// DefaultLateCancellationFeePercent: penalidad sobre el total al cancelar dentro de la ventana.
+	DefaultLateCancellationFeePercent = 50
+)
+
+// CancellationPolicy define cuánto cuesta cancelar una orden pagada según la hora de la cita.
+//   - antes de AppointmentAt - FreeWindow: sin penalidad
+//   - después: LateFeePercent de lo reembolsable (lo pagado menos reembolsos previos)
+//   - desde AppointmentAt: no se puede cancelar
+//
+// Las órdenes sin pagar (o sin cita asociada) siempre se cancelan sin penalidad.
+type CancellationPolicy struct {
+	FreeWindow     time.Duration
+	LateFeePercent int64 // 0-100
+}
+
+// DefaultCancellationPolicy retorna la política por defecto (24 h gratis, 50% después).
+func DefaultCancellationPolicy() CancellationPolicy {
+	return CancellationPolicy{
+		FreeWindow:     DefaultFreeCancellationWindow,
+		LateFeePercent: DefaultLateCancellationFeePercent,
+	}
+}
+
+// CancellationQuote es el resultado de aplicar la política a una orden.
+type CancellationQuote struct {
+	Fee    pricingdomain.Money // penalidad que se retiene
+	Refund pricingdomain.Money // monto a devolver (0 si la orden no estaba pagada o ya se devolvió todo)
+}
+
+// Evaluate calcula penalidad y reembolso de cancelar la orden en now.
+func (p CancellationPolicy) Evaluate(order Order, now time.Time) (CancellationQuote, error) {
+	zero := pricingdomain.Zero(order.Total.Currency)
+	if !order.IsPaid() {
+		return CancellationQuote{Fee: zero, Refund: zero}, nil
+	}
+	refundable := order.RefundableAmount()
+	if order.AppointmentAt == nil {
+		return CancellationQuote{Fee: zero, Refund: refundable}, nil
+	}
+
+	if !now.Before(*order.AppointmentAt) {
+		return CancellationQuote{}, ErrCancellationWindowClosed
+	}
+	if now.Before(order.AppointmentAt.Add(-p.FreeWindow)) {
+		return CancellationQuote{Fee: zero, Refund: refundable}, nil
+	}
+
+	fee := pricingdomain.NewMoney(refundable.Amount*p.LateFeePercent/100, refundable.Currency)
+	refund, err := refundable.Sub(fee)
+	if err != nil {
+		return CancellationQuote{}, err
+	}
+	return CancellationQuote{Fee: fee, Refund: refund}, nil
+}
+
+// CancelWithPolicy cancela la orden (incluso pagada) aplicando la política y
+// registra penalidad y reembolso en la orden y en su historial.
+func (o *Order) CancelWithPolicy(policy CancellationPolicy, reason CancelReason, at time.Time) (CancellationQuote, error) {
+	if o.Status == OrderStatusCancelled {
+		// Idempotente: ya cancelada con la penalidad registrada; Refund es lo que falta devolver
+		return CancellationQuote{Fee: o.CancellationFee, Refund: o.RefundableAmount()}, nil
+	}
+	if !CanTransition(o.Status, OrderStatusCancelled) {
+		return CancellationQuote{}, &TransitionError{From: o.Status, To: OrderStatusCancelled}
+	}
+
+	quote, err := policy.Evaluate(*o, at)
+	if err != nil {
+		return CancellationQuote{}, err
+	}
+
+	if err := o.transition(OrderStatusCancelled, at, map[string]string{
+		"cancel_reason": string(reason),
+		"fee":           strconv.FormatInt(quote.Fee.Amount, 10),
+		"refund":        strconv.FormatInt(quote.Refund.Amount, 10),
+	}); err != nil {
+		return CancellationQuote{}, err
+	}
+	o.CancelReason = reason
+	o.CancellationFee = quote.Fee
+	return quote, nil
+}
@@ -1,6 +1,13 @@
 package domain
 
-import servicedomain "paku-commerce/internal/commerce/service/domain"
+import (
+	"errors"
+
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+)
+
+// ErrInvalidPets indica mascotas sin ID o con IDs repetidos en la intención.
+var ErrInvalidPets = errors.New("pets must have unique non-empty ids")
 
 // ItemType identifica el tipo de item a comprar.
 type ItemType string
@@ -15,12 +22,50 @@ type PurchaseItem struct {
 	ItemType ItemType
 	ItemID   string
 	Qty      int
+	PetID    string // "" = pet principal (PetProfile de la intención)
+}
+
+// Pet es una mascota adicional de la compra; sus items la referencian por ID.
+type Pet struct {
+	ID         string
+	PetProfile servicedomain.PetProfile
 }
 
 // PurchaseIntent representa la intención de compra del usuario.
+// Los items sin PetID son del pet principal (PetProfile); el resto, de la mascota en Pets
+// con ese ID. Cada mascota se evalúa (elegibilidad, addons, precio) con su propio perfil.
+// PetID identifica al pet principal en el servicio de mascotas; si PetProfile viene vacío,
+// el perfil se resuelve server-side a partir de ese ID (igual para cada Pet sin perfil).
 type PurchaseIntent struct {
+	PetID         string
 	PetProfile    servicedomain.PetProfile
+	Pets          []Pet
 	Items         []PurchaseItem
 	CouponCode    *string
 	BookingHoldID *string
 }
+
+// PetProfileFor retorna el perfil de la mascota petID ("" = pet principal).
+func (i PurchaseIntent) PetProfileFor(petID string) (servicedomain.PetProfile, bool) {
+	if petID == "" {
+		return i.PetProfile, true
+	}
+	for _, pet := range i.Pets {
+		if pet.ID == petID {
+			return pet.PetProfile, true
+		}
+	}
+	return servicedomain.PetProfile{}, false
+}
+
+// ValidatePets verifica que cada mascota adicional tenga un ID único y no vacío.
+func ValidatePets(pets []Pet) error {
+	seen := make(map[string]bool, len(pets))
+	for _, pet := range pets {
+		if pet.ID == "" || seen[pet.ID] {
+			return ErrInvalidPets
+		}
+		seen[pet.ID] = true
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+var (
+	ErrRefundWithoutPayment = errors.New("order has no payment to refund")
+	ErrNothingToRefund      = errors.New("order has nothing left to refund")
+	ErrInvalidRefundLine    = errors.New("invalid refund line")
+	ErrLineAlreadyRefunded  = errors.New("order line already refunded")
+	ErrRefundExceedsNetPaid = errors.New("refund exceeds net paid amount")
+	ErrRefundKeyConflict    = errors.New("refund key already used with different lines")
+)
+
+// Refund es un reembolso emitido sobre el pago (PaymentRef) de una orden.
+type Refund struct {
+	OrderID     string
+	Key         string // clave de idempotencia (única por orden)
+	PaymentRef  string
+	Amount      pricingdomain.Money
+	Lines       []int  // posiciones de Order.Items reembolsadas (vacío = reembolso total)
+	ProviderRef string // ID del reembolso en el proveedor de pagos
+	CreatedAt   time.Time
+}
+
+// SameLines indica si el reembolso cubre exactamente las líneas dadas (mismo pedido).
+func (r Refund) SameLines(lines []int) bool {
+	if len(r.Lines) != len(lines) {
+		return false
+	}
+	for i := range lines {
+		if r.Lines[i] != lines[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NetPaid es lo cobrado menos lo reembolsado (0 si la orden no se pagó).
+func (o Order) NetPaid() pricingdomain.Money {
+	if o.PaidAt == nil {
+		return pricingdomain.Zero(o.Total.Currency)
+	}
+	return pricingdomain.NewMoney(o.Total.Amount-o.RefundedAmount.Amount, o.Total.Currency)
+}
+
+// RefundableAmount es cuánto queda por reembolsar: el neto pagado, menos la penalidad
+// retenida si la orden se canceló después de pagar.
+func (o Order) RefundableAmount() pricingdomain.Money {
+	refundable := o.NetPaid()
+	if o.Status == OrderStatusCancelled {
+		refundable.Amount -= o.CancellationFee.Amount
+	}
+	if refundable.Amount < 0 {
+		refundable.Amount = 0
+	}
+	return refundable
+}
+
+// PlanRefund calcula el monto a reembolsar. Sin líneas reembolsa todo lo reembolsable;
+// con líneas, cada una devuelve su LineTotal prorrateado por el descuento de la orden.
+func (o Order) PlanRefund(lines []int) (pricingdomain.Money, error) {
+	if !o.IsRefundable() {
+		return pricingdomain.Money{}, ErrRefundWithoutPayment
+	}
+	refundable := o.RefundableAmount()
+	if refundable.Amount == 0 {
+		return pricingdomain.Money{}, ErrNothingToRefund
+	}
+	if len(lines) == 0 {
+		return refundable, nil
+	}
+
+	amount := pricingdomain.Zero(o.Total.Currency)
+	seen := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		if line < 0 || line >= len(o.Items) || seen[line] {
+			return pricingdomain.Money{}, ErrInvalidRefundLine
+		}
+		seen[line] = true
+		if o.Items[line].Refunded {
+			return pricingdomain.Money{}, ErrLineAlreadyRefunded
+		}
+		amount.Amount += o.lineRefund(o.Items[line])
+	}
+
+	// La última línea pendiente se lleva el resto (absorbe el redondeo del prorrateo)
+	if o.pendingLines() == len(lines) {
+		return refundable, nil
+	}
+	if amount.Amount > refundable.Amount {
+		return pricingdomain.Money{}, ErrRefundExceedsNetPaid
+	}
+	return amount, nil
+}
+
+// IsRefundable indica si la orden tiene un pago sobre el cual reembolsar:
+// pagada (incluye fulfilled y no_show) o cancelada después de pagar.
+func (o Order) IsRefundable() bool {
+	if o.PaymentRef == nil || o.PaidAt == nil {
+		return false
+	}
+	return o.IsPaid() || o.Status == OrderStatusCancelled
+}
+
+// ApplyRefund registra un reembolso emitido: acumula RefundedAmount, marca las líneas y,
+// si ya no queda nada pagado, pasa la orden a refunded (las canceladas siguen cancelled).
+// Sin líneas, solo marca todas las líneas si devuelve todo lo reembolsable (un reembolso
+// parcial iniciado desde el proveedor no corresponde a ninguna línea).
+func (o *Order) ApplyRefund(refund Refund, at time.Time) error {
+	refundable := o.RefundableAmount()
+	if refund.Amount.Amount > refundable.Amount {
+		return ErrRefundExceedsNetPaid
+	}
+	for _, line := range refund.Lines {
+		if line < 0 || line >= len(o.Items) {
+			return ErrInvalidRefundLine
+		}
+	}
+
+	o.RefundedAmount = pricingdomain.NewMoney(o.RefundedAmount.Amount+refund.Amount.Amount, o.Total.Currency)
+
+	// Copia: Items puede compartir el arreglo con la orden almacenada en el repositorio
+	items := append([]OrderItem(nil), o.Items...)
+	if len(refund.Lines) == 0 && refund.Amount.Amount == refundable.Amount {
+		for i := range items {
+			items[i].Refunded = true
+		}
+	}
+	for _, line := range refund.Lines {
+		items[line].Refunded = true
+	}
+	o.Items = items
+
+	refund.OrderID = o.ID
+	refund.CreatedAt = at
+	o.NewRefunds = append(o.NewRefunds, refund)
+
+	data := map[string]string{
+		"refund_key":   refund.Key,
+		"amount":       strconv.FormatInt(refund.Amount.Amount, 10),
+		"provider_ref": refund.ProviderRef,
+	}
+	if len(refund.Lines) > 0 {
+		data["lines"] = formatLines(refund.Lines)
+	}
+
+	if o.NetPaid().Amount == 0 && CanTransition(o.Status, OrderStatusRefunded) {
+		return o.transition(OrderStatusRefunded, at, data)
+	}
+	o.RecordEvent(OrderEventRefundIssued, at, data)
+	return nil
+}
+
+// TakeNewRefunds retorna los reembolsos pendientes de persistir y los quita de la orden.
+func (o *Order) TakeNewRefunds() []Refund {
+	refunds := o.NewRefunds
+	o.NewRefunds = nil
+	for i := range refunds {
+		refunds[i].OrderID = o.ID
+	}
+	return refunds
+}
+
+// pendingLines cuenta las líneas aún no reembolsadas.
+func (o Order) pendingLines() int {
+	pending := 0
+	for _, item := range o.Items {
+		if !item.Refunded {
+			pending++
+		}
+	}
+	return pending
+}
+
+// lineRefund prorratea el LineTotal de la línea por el descuento total de la orden.
+func (o Order) lineRefund(item OrderItem) int64 {
+	if o.Subtotal.Amount == 0 {
+		return 0
+	}
+	return item.LineTotal.Amount * o.Total.Amount / o.Subtotal.Amount
+}
+
+func formatLines(lines []int) string {
+	parts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		parts = append(parts, strconv.Itoa(line))
+	}
+	return strings.Join(parts, ",")
+}
@@ -3,13 +3,75 @@ package domain
 import (
 	"context"
 	"errors"
+	"time"
 )
 
-var ErrOrderNotFound = errors.New("order not found")
+var (
+	ErrOrderNotFound        = errors.New("order not found")
+	ErrOrderVersionConflict = errors.New("order was modified concurrently")
+)
+
+// OrderCursor marca la última orden de una página: la siguiente empieza después de ella.
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// OrderQuery filtra las órdenes de un usuario.
+type OrderQuery struct {
+	UserID string
+	Status OrderStatus  // "" = cualquier estado
+	From   *time.Time   // CreatedAt >= From
+	To     *time.Time   // CreatedAt < To
+	After  *OrderCursor // nil = primera página
+	Limit  int
+}
+
+// Matches indica si la orden cumple los filtros de la query (sin cursor ni límite).
+func (q OrderQuery) Matches(order Order) bool {
+	if order.UserID != q.UserID {
+		return false
+	}
+	if q.Status != "" && order.Status != q.Status {
+		return false
+	}
+	if q.From != nil && order.CreatedAt.Before(*q.From) {
+		return false
+	}
+	if q.To != nil && !order.CreatedAt.Before(*q.To) {
+		return false
+	}
+	return true
+}
+
+// IsAfter indica si la orden va después del cursor en el orden descendente (CreatedAt, ID).
+func (c OrderCursor) IsAfter(order Order) bool {
+	if order.CreatedAt.Equal(c.CreatedAt) {
+		return order.ID < c.ID
+	}
+	return order.CreatedAt.Before(c.CreatedAt)
+}
 
 // OrderRepository define el acceso a órdenes.
 type OrderRepository interface {
+	// Create persiste una orden nueva con Version=1.
 	Create(ctx context.Context, order Order) (Order, error)
 	GetByID(ctx context.Context, id string) (Order, error)
+	// Create y Update agregan los NewEvents de la orden a su historial (append-only)
+	// y guardan sus NewRefunds.
+	// Update es un compare-and-swap: solo persiste si la versión almacenada es order.Version
+	// (si no, retorna ErrOrderVersionConflict) y retorna la orden con la versión incrementada.
 	Update(ctx context.Context, order Order) (Order, error)
+	// ListOverduePending lista las órdenes pending_payment o failed con ExpiresAt anterior a now.
+	ListOverduePending(ctx context.Context, now time.Time) ([]Order, error)
+	// ListHoldRetriesDue lista las órdenes payment_received_booking_failed con NextHoldRetryAt
+	// anterior o igual a now, de la más antigua a la más nueva.
+	ListHoldRetriesDue(ctx context.Context, now time.Time) ([]Order, error)
+	// ListByUser lista las órdenes del usuario que cumplen query, de la más nueva a la más antigua
+	// (por CreatedAt y luego ID), hasta query.Limit órdenes.
+	ListByUser(ctx context.Context, query OrderQuery) ([]Order, error)
+	// ListEvents retorna el historial de la orden, del más antiguo al más reciente.
+	ListEvents(ctx context.Context, orderID string) ([]OrderEvent, error)
+	// ListRefunds retorna los reembolsos de la orden, del más antiguo al más reciente.
+	ListRefunds(ctx context.Context, orderID string) ([]Refund, error)
 }
@@ -16,16 +16,27 @@ type PetProfileDTO struct {
 	CoatType string `json:"coat_type"`
 }
 
+// PetDTO representa una mascota adicional de la compra.
+// Sin pet_profile, el perfil se resuelve por pet_id en el servicio de mascotas.
+type PetDTO struct {
+	PetID      string        `json:"pet_id"`
+	PetProfile PetProfileDTO `json:"pet_profile"`
+}
+
 // ItemDTO representa un item a comprar.
 type ItemDTO struct {
-	Type string `json:"type"` // "service" | "product"
-	ID   string `json:"id"`
-	Qty  int    `json:"qty"`
+	Type  string `json:"type"` // "service" | "product"
+	ID    string `json:"id"`
+	Qty   int    `json:"qty"`
+	PetID string `json:"pet_id,omitempty"` // vacío = pet_profile principal
 }
 
 // QuoteRequestDTO es el request para /checkout/quote.
+// Se envía pet_profile inline o solo pet_id (el perfil se resuelve server-side).
 type QuoteRequestDTO struct {
+	PetID         string        `json:"pet_id,omitempty"`
 	PetProfile    PetProfileDTO `json:"pet_profile"`
+	Pets          []PetDTO      `json:"pets,omitempty"`
 	Items         []ItemDTO     `json:"items"`
 	CouponCode    *string       `json:"coupon_code"`
 	BookingHoldID *string       `json:"booking_hold_id"`
@@ -64,21 +75,47 @@ type OrderItemDTO struct {
 	Qty       int      `json:"qty"`
 	UnitPrice MoneyDTO `json:"unit_price"`
 	LineTotal MoneyDTO `json:"line_total"`
+	PetID     string   `json:"pet_id,omitempty"`
+	Refunded  bool     `json:"refunded,omitempty"` // la línea ya se reembolsó
+}
+
+// OrderPetDTO resume lo comprado para una mascota de la orden.
+type OrderPetDTO struct {
+	PetID      string        `json:"pet_id,omitempty"` // vacío = pet_profile principal
+	PetProfile PetProfileDTO `json:"pet_profile"`
+	Subtotal   MoneyDTO      `json:"subtotal"`
 }
 
 // OrderDTO representa una orden.
 type OrderDTO struct {
-	ID            string         `json:"id"`
-	Status        string         `json:"status"`
-	CreatedAt     string         `json:"created_at"`
-	Subtotal      MoneyDTO       `json:"subtotal"`
-	TotalDiscount MoneyDTO       `json:"total_discount"`
-	Total         MoneyDTO       `json:"total"`
-	CouponCode    *string        `json:"coupon_code,omitempty"`
-	BookingHoldID *string        `json:"booking_hold_id,omitempty"`
-	PaymentRef    *string        `json:"payment_ref,omitempty"`
-	PaidAt        *string        `json:"paid_at,omitempty"`
-	Items         []OrderItemDTO `json:"items"`
+	ID              string         `json:"id"`
+	UserID          string         `json:"user_id,omitempty"`
+	Status          string         `json:"status"`
+	CreatedAt       string         `json:"created_at"`
+	PetID           string         `json:"pet_id,omitempty"` // ID del pet principal, si se resolvió por ID
+	Subtotal        MoneyDTO       `json:"subtotal"`
+	TotalDiscount   MoneyDTO       `json:"total_discount"`
+	Total           MoneyDTO       `json:"total"`
+	CouponCode      *string        `json:"coupon_code,omitempty"`
+	BookingHoldID   *string        `json:"booking_hold_id,omitempty"`
+	SlotID          string         `json:"slot_id,omitempty"`
+	AppointmentAt   *string        `json:"appointment_at,omitempty"` // inicio de la cita (ventana de cancelación)
+	PaymentRef      *string        `json:"payment_ref,omitempty"`
+	PaidAt          *string        `json:"paid_at,omitempty"`
+	ProcessingAt    *string        `json:"processing_at,omitempty"`
+	FailedAt        *string        `json:"failed_at,omitempty"`
+	CancelledAt     *string        `json:"cancelled_at,omitempty"`
+	RefundedAt      *string        `json:"refunded_at,omitempty"`
+	FulfilledAt     *string        `json:"fulfilled_at,omitempty"`
+	NoShowAt        *string        `json:"no_show_at,omitempty"`
+	ExpiresAt       *string        `json:"expires_at,omitempty"`       // plazo de pago de pending_payment
+	CancelReason    string         `json:"cancel_reason,omitempty"`    // requested | expired | cart_expired
+	CancellationFee *MoneyDTO      `json:"cancellation_fee,omitempty"` // penalidad retenida al cancelar una orden pagada
+	RefundedAmount  *MoneyDTO      `json:"refunded_amount,omitempty"`  // suma de los reembolsos emitidos
+	NetPaid         *MoneyDTO      `json:"net_paid,omitempty"`         // total cobrado menos reembolsos (solo órdenes pagadas)
+	Pets            []OrderPetDTO  `json:"pets"`
+	Items           []OrderItemDTO `json:"items"`
+	Version         int            `json:"version"`
 }
 
 // CreateOrderResponseDTO es el response para POST /checkout/orders.
@@ -97,6 +134,133 @@ type ConfirmPaymentResponseDTO struct {
 	Order OrderDTO `json:"order"`
 }
 
+// CancelOrderResponseDTO es el response para POST /checkout/orders/{id}/cancel.
+type CancelOrderResponseDTO struct {
+	Order  OrderDTO `json:"order"`
+	Fee    MoneyDTO `json:"fee"`    // penalidad retenida según la política
+	Refund MoneyDTO `json:"refund"` // monto a devolver (0 si la orden no estaba pagada)
+}
+
+// RefundOrderRequestDTO es el request para POST /checkout/orders/{id}/refunds.
+type RefundOrderRequestDTO struct {
+	RefundKey string `json:"refund_key"`      // clave de idempotencia
+	Lines     []int  `json:"lines,omitempty"` // posiciones de items; vacío = reembolso total
+}
+
+// RefundDTO representa un reembolso emitido sobre el pago de una orden.
+type RefundDTO struct {
+	RefundKey   string   `json:"refund_key"`
+	PaymentRef  string   `json:"payment_ref"`
+	Amount      MoneyDTO `json:"amount"`
+	Lines       []int    `json:"lines,omitempty"`
+	ProviderRef string   `json:"provider_ref,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// RefundOrderResponseDTO es el response para POST /checkout/orders/{id}/refunds.
+type RefundOrderResponseDTO struct {
+	Order  OrderDTO  `json:"order"`
+	Refund RefundDTO `json:"refund"`
+}
+
+// ListOrderRefundsResponseDTO es el response para GET /checkout/orders/{id}/refunds.
+type ListOrderRefundsResponseDTO struct {
+	NetPaid MoneyDTO    `json:"net_paid"`
+	Refunds []RefundDTO `json:"refunds"`
+}
+
+// IncidentDTO representa un incidente de la cola de revisión manual.
+type IncidentDTO struct {
+	ID         string  `json:"id"`
+	OrderID    string  `json:"order_id,omitempty"`
+	HoldID     string  `json:"hold_id,omitempty"`
+	Operation  string  `json:"operation"` // cancel_hold | confirm_hold | refund | cancellation_refund | confirm_payment
+	Error      string  `json:"error"`     // último error de la operación
+	Status     string  `json:"status"`    // open | resolved
+	Attempts   int     `json:"attempts"`  // reintentos manuales fallidos
+	Resolution string  `json:"resolution,omitempty"`
+	ResolvedBy string  `json:"resolved_by,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+	UpdatedAt  string  `json:"updated_at"`
+	ResolvedAt *string `json:"resolved_at,omitempty"`
+}
+
+// ListIncidentsResponseDTO es el response para GET /checkout/incidents.
+type ListIncidentsResponseDTO struct {
+	Incidents []IncidentDTO `json:"incidents"`
+}
+
+// ResolveIncidentRequestDTO es el request para POST /checkout/incidents/{id}/resolve.
+type ResolveIncidentRequestDTO struct {
+	Note string `json:"note"` // qué se hizo para resolverlo
+}
+
+// IncidentResponseDTO es el response con un incidente (retry y resolve).
+type IncidentResponseDTO struct {
+	Incident IncidentDTO `json:"incident"`
+}
+
+// PaymentWebhookResponseDTO es el response de POST /payments/webhooks/{provider}.
+type PaymentWebhookResponseDTO struct {
+	EventID   string `json:"event_id"`
+	Status    string `json:"status"` // processed | ignored
+	Duplicate bool   `json:"duplicate"`
+	Reason    string `json:"reason,omitempty"` // por qué se ignoró
+}
+
+// CreatePaymentIntentRequestDTO es el request para POST /checkout/orders/{id}/payment.
+type CreatePaymentIntentRequestDTO struct {
+	Method string `json:"method"` // yape | plin
+}
+
+// PaymentIntentDTO representa un intento de pago por QR.
+type PaymentIntentDTO struct {
+	ID            string   `json:"id"`
+	OrderID       string   `json:"order_id"`
+	Method        string   `json:"method"` // yape | plin
+	Amount        MoneyDTO `json:"amount"`
+	ProviderRef   string   `json:"provider_ref"`
+	QRData        string   `json:"qr_data"` // contenido a renderizar como QR
+	Status        string   `json:"status"`  // pending | succeeded | failed | expired
+	FailureReason string   `json:"failure_reason,omitempty"`
+	ExpiresAt     string   `json:"expires_at"`
+	CreatedAt     string   `json:"created_at"`
+	CompletedAt   *string  `json:"completed_at,omitempty"`
+}
+
+// PaymentIntentResponseDTO es el response de /checkout/orders/{id}/payment.
+type PaymentIntentResponseDTO struct {
+	Payment     PaymentIntentDTO `json:"payment"`
+	OrderStatus string           `json:"order_status"` // paid cuando la transferencia ya se aplicó
+}
+
+// OrderResponseDTO es el response con una orden (consulta y transiciones como fulfill o no-show).
+type OrderResponseDTO struct {
+	Order OrderDTO `json:"order"`
+}
+
+// ListOrdersResponseDTO es el response para GET /checkout/orders.
+type ListOrdersResponseDTO struct {
+	Orders     []OrderDTO `json:"orders"`
+	NextCursor string     `json:"next_cursor,omitempty"` // ausente en la última página
+}
+
+// OrderEventDTO representa una entrada del historial de una orden.
+type OrderEventDTO struct {
+	Type       string            `json:"type"`
+	FromStatus string            `json:"from_status,omitempty"`
+	ToStatus   string            `json:"to_status"`
+	Actor      string            `json:"actor"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Data       map[string]string `json:"data,omitempty"`
+	OccurredAt string            `json:"occurred_at"`
+}
+
+// ListOrderEventsResponseDTO es el response para GET /checkout/orders/{id}/events.
+type ListOrderEventsResponseDTO struct {
+	Events []OrderEventDTO `json:"events"`
+}
+
 // StartCheckoutRequestDTO es el request para POST /checkout/start.
 type StartCheckoutRequestDTO struct {
 	SlotID string `json:"slot_id"`
@@ -127,6 +291,27 @@ func (dto PetProfileDTO) toPetProfile() servicedomain.PetProfile {
 	}
 }
 
+// toPetProfileDTO convierte dominio a DTO.
+func toPetProfileDTO(pet servicedomain.PetProfile) PetProfileDTO {
+	return PetProfileDTO{
+		Species:  pet.Species,
+		WeightKg: pet.WeightKg,
+		CoatType: pet.CoatType,
+	}
+}
+
+// toPets convierte DTOs de mascotas adicionales a dominio.
+func toPets(dtos []PetDTO) []checkoutdomain.Pet {
+	if len(dtos) == 0 {
+		return nil
+	}
+	pets := make([]checkoutdomain.Pet, 0, len(dtos))
+	for _, dto := range dtos {
+		pets = append(pets, checkoutdomain.Pet{ID: dto.PetID, PetProfile: dto.PetProfile.toPetProfile()})
+	}
+	return pets
+}
+
 // toPurchaseItems convierte DTOs a dominio.
 func toPurchaseItems(dtos []ItemDTO) []checkoutdomain.PurchaseItem {
 	items := make([]checkoutdomain.PurchaseItem, 0, len(dtos))
@@ -135,6 +320,7 @@ func toPurchaseItems(dtos []ItemDTO) []checkoutdomain.PurchaseItem {
 			ItemType: checkoutdomain.ItemType(dto.Type),
 			ItemID:   dto.ID,
 			Qty:      dto.Qty,
+			PetID:    dto.PetID,
 		})
 	}
 	return items
@@ -158,30 +344,128 @@ func toOrderDTO(order checkoutdomain.Order) OrderDTO {
 			Qty:       item.Qty,
 			UnitPrice: toMoneyDTO(item.UnitPrice),
 			LineTotal: toMoneyDTO(item.LineTotal),
+			PetID:     item.PetID,
+			Refunded:  item.Refunded,
+		})
+	}
+
+	pets := make([]OrderPetDTO, 0, len(order.Pets))
+	for _, pet := range order.Pets {
+		pets = append(pets, OrderPetDTO{
+			PetID:      pet.PetID,
+			PetProfile: toPetProfileDTO(pet.PetProfile),
+			Subtotal:   toMoneyDTO(pet.Subtotal),
 		})
 	}
 
 	dto := OrderDTO{
 		ID:            order.ID,
+		UserID:        order.UserID,
 		Status:        string(order.Status),
 		CreatedAt:     order.CreatedAt.Format(time.RFC3339),
+		PetID:         order.PetID,
 		Subtotal:      toMoneyDTO(order.Subtotal),
 		TotalDiscount: toMoneyDTO(order.TotalDiscount),
 		Total:         toMoneyDTO(order.Total),
 		CouponCode:    order.CouponCode,
 		BookingHoldID: order.BookingHoldID,
+		SlotID:        order.SlotID,
+		AppointmentAt: formatOptionalTime(order.AppointmentAt),
 		PaymentRef:    order.PaymentRef,
+		CancelReason:  string(order.CancelReason),
+		Pets:          pets,
 		Items:         items,
+		Version:       order.Version,
+		PaidAt:        formatOptionalTime(order.PaidAt),
+		ProcessingAt:  formatOptionalTime(order.ProcessingAt),
+		FailedAt:      formatOptionalTime(order.FailedAt),
+		CancelledAt:   formatOptionalTime(order.CancelledAt),
+		RefundedAt:    formatOptionalTime(order.RefundedAt),
+		FulfilledAt:   formatOptionalTime(order.FulfilledAt),
+		NoShowAt:      formatOptionalTime(order.NoShowAt),
+		ExpiresAt:     formatOptionalTime(order.ExpiresAt),
+	}
+	if order.CancellationFee.Amount > 0 {
+		fee := toMoneyDTO(order.CancellationFee)
+		dto.CancellationFee = &fee
+	}
+	if order.RefundedAmount.Amount > 0 {
+		refunded := toMoneyDTO(order.RefundedAmount)
+		dto.RefundedAmount = &refunded
 	}
-
 	if order.PaidAt != nil {
-		paidAtStr := order.PaidAt.Format(time.RFC3339)
-		dto.PaidAt = &paidAtStr
+		netPaid := toMoneyDTO(order.NetPaid())
+		dto.NetPaid = &netPaid
 	}
 
 	return dto
 }
 
+// toOrderEventDTOs convierte el historial de una orden a DTOs.
+func toOrderEventDTOs(events []checkoutdomain.OrderEvent) []OrderEventDTO {
+	dtos := make([]OrderEventDTO, 0, len(events))
+	for _, event := range events {
+		dtos = append(dtos, OrderEventDTO{
+			Type:       string(event.Type),
+			FromStatus: string(event.FromStatus),
+			ToStatus:   string(event.ToStatus),
+			Actor:      event.Actor,
+			RequestID:  event.RequestID,
+			Data:       event.Data,
+			OccurredAt: event.OccurredAt.Format(time.RFC3339),
+		})
+	}
+	return dtos
+}
+
+// toRefundDTO convierte un reembolso a DTO.
+func toRefundDTO(refund checkoutdomain.Refund) RefundDTO {
+	return RefundDTO{
+		RefundKey:   refund.Key,
+		PaymentRef:  refund.PaymentRef,
+		Amount:      toMoneyDTO(refund.Amount),
+		Lines:       refund.Lines,
+		ProviderRef: refund.ProviderRef,
+		CreatedAt:   refund.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// toIncidentDTO convierte un incidente a DTO.
+func toIncidentDTO(incident checkoutdomain.Incident) IncidentDTO {
+	return IncidentDTO{
+		ID:         incident.ID,
+		OrderID:    incident.OrderID,
+		HoldID:     incident.HoldID,
+		Operation:  string(incident.Operation),
+		Error:      incident.Error,
+		Status:     string(incident.Status),
+		Attempts:   incident.Attempts,
+		Resolution: incident.Resolution,
+		ResolvedBy: incident.ResolvedBy,
+		CreatedAt:  incident.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  incident.UpdatedAt.Format(time.RFC3339),
+		ResolvedAt: formatOptionalTime(incident.ResolvedAt),
+	}
+}
+
+// toIncidentDTOs convierte incidentes a DTOs.
+func toIncidentDTOs(incidents []checkoutdomain.Incident) []IncidentDTO {
+	dtos := make([]IncidentDTO, 0, len(incidents))
+	for _, incident := range incidents {
+		dtos = append(dtos, toIncidentDTO(incident))
+	}
+	return dtos
+}
+
+// formatOptionalTime formatea un timestamp opcional en RFC3339 (nil si no existe).
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
+
 // toCartSnapshotDTO convierte Cart a CartSnapshotDTO.
 func toCartSnapshotDTO(cart cartdomain.Cart) CartSnapshotDTO {
 	return CartSnapshotDTO{
@@ -192,3 +476,20 @@ func toCartSnapshotDTO(cart cartdomain.Cart) CartSnapshotDTO {
 		ExpiresAt:     cart.ExpiresAt.Format(time.RFC3339),
 	}
 }
+
+// toPaymentIntentDTO convierte un intento de pago a DTO.
+func toPaymentIntentDTO(intent checkoutdomain.PaymentIntent) PaymentIntentDTO {
+	return PaymentIntentDTO{
+		ID:            intent.ID,
+		OrderID:       intent.OrderID,
+		Method:        string(intent.Method),
+		Amount:        toMoneyDTO(intent.Amount),
+		ProviderRef:   intent.ProviderRef,
+		QRData:        intent.QRData,
+		Status:        string(intent.Status),
+		FailureReason: intent.FailureReason,
+		ExpiresAt:     intent.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:     intent.CreatedAt.Format(time.RFC3339),
+		CompletedAt:   formatOptionalTime(intent.CompletedAt),
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
 	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
 	pricingusecases "paku-commerce/internal/pricing/usecases"
 	promotionsdomain "paku-commerce/internal/promotions/domain"
 	promotionsusecases "paku-commerce/internal/promotions/usecases"
@@ -18,13 +20,31 @@ type ErrorResponse struct {
 
 // mapErrorToHTTPStatus mapea errores de dominio/usecase a status HTTP.
 func mapErrorToHTTPStatus(err error) int {
+	// 400 - Bad Request (filtros, reembolsos, resoluciones o métodos de pago inválidos)
+	if errors.Is(err, checkoutusecases.ErrInvalidOrderCursor) ||
+		errors.Is(err, checkoutusecases.ErrInvalidOrderFilter) ||
+		errors.Is(err, checkoutusecases.ErrInvalidIncidentFilter) ||
+		errors.Is(err, checkoutdomain.ErrMissingResolutionNote) ||
+		errors.Is(err, checkoutusecases.ErrMissingRefundKey) ||
+		errors.Is(err, checkoutdomain.ErrInvalidRefundLine) ||
+		errors.Is(err, checkoutdomain.ErrInvalidPaymentMethod) {
+		return http.StatusBadRequest
+	}
+
 	// 404 - Not Found
-	if errors.Is(err, checkoutdomain.ErrOrderNotFound) {
+	if errors.Is(err, checkoutdomain.ErrOrderNotFound) ||
+		errors.Is(err, checkoutdomain.ErrIncidentNotFound) ||
+		errors.Is(err, checkoutdomain.ErrPaymentIntentNotFound) {
 		return http.StatusNotFound
 	}
 
 	// 409 - Conflict
-	if errors.Is(err, checkoutdomain.ErrPaymentConflict) {
+	if errors.Is(err, checkoutdomain.ErrPaymentConflict) ||
+		errors.Is(err, checkoutdomain.ErrOrderVersionConflict) ||
+		errors.Is(err, checkoutdomain.ErrRefundKeyConflict) ||
+		errors.Is(err, checkoutusecases.ErrRefundInProgress) ||
+		errors.Is(err, checkoutusecases.ErrRefundNotRecorded) ||
+		errors.Is(err, checkoutdomain.ErrPaymentIntentPending) {
 		return http.StatusConflict
 	}
 
@@ -33,14 +53,46 @@ func mapErrorToHTTPStatus(err error) int {
 		errors.Is(err, checkoutusecases.ErrServiceNotEligible) ||
 		errors.Is(err, checkoutusecases.ErrInvalidQuantity) ||
 		errors.Is(err, checkoutusecases.ErrUnknownService) ||
+		errors.Is(err, checkoutusecases.ErrUnknownProduct) ||
+		errors.Is(err, checkoutusecases.ErrUnknownItemType) ||
+		errors.Is(err, checkoutusecases.ErrUnknownPet) ||
+		errors.Is(err, checkoutusecases.ErrInvalidAppointmentOutcome) ||
+		errors.Is(err, checkoutdomain.ErrInvalidPets) ||
+		errors.Is(err, platformpets.ErrPetNotFound) ||
+		errors.Is(err, checkoutusecases.ErrPetProfileMismatch) ||
 		errors.Is(err, pricingusecases.ErrNoPriceRule) ||
+		errors.Is(err, pricingusecases.ErrUnknownPet) ||
 		errors.Is(err, promotionsusecases.ErrInvalidCoupon) ||
 		errors.Is(err, promotionsdomain.ErrCouponNotFound) ||
 		errors.Is(err, checkoutdomain.ErrOrderCancelled) ||
-		errors.Is(err, checkoutdomain.ErrInvalidOrderState) {
+		errors.Is(err, checkoutdomain.ErrCancellationWindowClosed) ||
+		errors.Is(err, checkoutdomain.ErrRefundWithoutPayment) ||
+		errors.Is(err, checkoutdomain.ErrNothingToRefund) ||
+		errors.Is(err, checkoutdomain.ErrLineAlreadyRefunded) ||
+		errors.Is(err, checkoutdomain.ErrRefundExceedsNetPaid) ||
+		errors.Is(err, checkoutdomain.ErrIncidentResolved) ||
+		errors.Is(err, checkoutdomain.ErrIncidentNotRetryable) ||
+		errors.Is(err, checkoutdomain.ErrInvalidOrderState) ||
+		errors.Is(err, payments.ErrPaymentNotFound) ||
+		errors.Is(err, payments.ErrPaymentAmountMismatch) ||
+		errors.Is(err, payments.ErrPaymentCurrencyMismatch) ||
+		errors.Is(err, payments.ErrPaymentNotCompleted) {
 		return http.StatusUnprocessableEntity
 	}
 
+	// 502 - Bad Gateway (el proveedor de pagos o booking rechazó o falló)
+	if errors.Is(err, checkoutusecases.ErrRefundFailed) ||
+		errors.Is(err, checkoutusecases.ErrIncidentRetryFailed) ||
+		errors.Is(err, payments.ErrPaymentsUnavailable) {
+		return http.StatusBadGateway
+	}
+
+	// 503 - Service Unavailable (dependencias externas)
+	if errors.Is(err, platformpets.ErrPetsUnavailable) ||
+		errors.Is(err, checkoutusecases.ErrPaymentEventFailed) {
+		return http.StatusServiceUnavailable
+	}
+
 	// 500 - Internal Server Error (default)
 	return http.StatusInternalServerError
 }
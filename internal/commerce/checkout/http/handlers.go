@@ -1,15 +1,21 @@
 package http
 
 import (
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	cartdomain "paku-commerce/internal/commerce/cart/domain"
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
 	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	"paku-commerce/internal/platform/audit"
 )
 
 // CheckoutHandlers contiene los handlers de checkout.
@@ -18,6 +24,24 @@ type CheckoutHandlers struct {
 	CreateOrderUC    *checkoutusecases.CreateOrder
 	ConfirmPaymentUC *checkoutusecases.ConfirmPayment
 	StartCheckoutUC  *checkoutusecases.StartCheckout
+
+	RecordAppointmentOutcomeUC *checkoutusecases.RecordAppointmentOutcome
+	RequestCancellationUC      *checkoutusecases.RequestCancellation
+	RefundOrderUC              *checkoutusecases.RefundOrder
+	ListOrderRefundsUC         *checkoutusecases.ListOrderRefunds
+	ListOrderEventsUC          *checkoutusecases.ListOrderEvents
+	GetOrderUC                 *checkoutusecases.GetOrder
+	ListUserOrdersUC           *checkoutusecases.ListUserOrders
+	ListIncidentsUC            *checkoutusecases.ListIncidents
+	RetryIncidentUC            *checkoutusecases.RetryIncident
+	ResolveIncidentUC          *checkoutusecases.ResolveIncident
+	HandlePaymentWebhookUC     *checkoutusecases.HandlePaymentWebhook
+	CreatePaymentIntentUC      *checkoutusecases.CreatePaymentIntent
+	GetPaymentIntentUC         *checkoutusecases.GetPaymentIntent
+	AdminToken                 string // habilita fulfill/no-show, reembolsos, el historial y la cola de revisión ("" = deshabilitado)
+
+	// WebhookVerifiers autentica los webhooks de pagos por proveedor (sin verifier: 404).
+	WebhookVerifiers map[string]payments.WebhookVerifier
 }
 
 // HandleQuote maneja POST /checkout/quote.
@@ -31,6 +55,7 @@ type CheckoutHandlers struct {
 // @Failure      400   {object}  ErrorResponse
 // @Failure      422   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
+// @Failure      503   {object}  ErrorResponse
 // @Router       /api/v1/commerce/checkout/quote [post]
 func (h *CheckoutHandlers) HandleQuote(w http.ResponseWriter, r *http.Request) {
 	var req QuoteRequestDTO
@@ -48,7 +73,9 @@ func (h *CheckoutHandlers) HandleQuote(w http.ResponseWriter, r *http.Request) {
 	// Construir input
 	input := checkoutusecases.QuoteCheckoutInput{
 		Intent: checkoutdomain.PurchaseIntent{
+			PetID:         req.PetID,
 			PetProfile:    req.PetProfile.toPetProfile(),
+			Pets:          toPets(req.Pets),
 			Items:         toPurchaseItems(req.Items),
 			CouponCode:    req.CouponCode,
 			BookingHoldID: req.BookingHoldID,
@@ -90,11 +117,13 @@ func (h *CheckoutHandlers) HandleQuote(w http.ResponseWriter, r *http.Request) {
 // @Tags         checkout
 // @Accept       json
 // @Produce      json
-// @Param        body  body      QuoteRequestDTO  true  "Order request"
-// @Success      201   {object}  CreateOrderResponseDTO
-// @Failure      400   {object}  ErrorResponse
-// @Failure      422   {object}  ErrorResponse
-// @Failure      500   {object}  ErrorResponse
+// @Param        X-User-ID  header    string           false  "User ID (dueño de la orden)"
+// @Param        body       body      QuoteRequestDTO  true   "Order request"
+// @Success      201        {object}  CreateOrderResponseDTO
+// @Failure      400        {object}  ErrorResponse
+// @Failure      422        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Failure      503        {object}  ErrorResponse
 // @Router       /api/v1/commerce/checkout/orders [post]
 func (h *CheckoutHandlers) HandleCreateOrder(w http.ResponseWriter, r *http.Request) {
 	var req QuoteRequestDTO
@@ -111,8 +140,11 @@ func (h *CheckoutHandlers) HandleCreateOrder(w http.ResponseWriter, r *http.Requ
 
 	// Construir input
 	input := checkoutusecases.CreateOrderInput{
+		UserID: r.Header.Get("X-User-ID"),
 		Intent: checkoutdomain.PurchaseIntent{
+			PetID:         req.PetID,
 			PetProfile:    req.PetProfile.toPetProfile(),
+			Pets:          toPets(req.Pets),
 			Items:         toPurchaseItems(req.Items),
 			CouponCode:    req.CouponCode,
 			BookingHoldID: req.BookingHoldID,
@@ -133,6 +165,109 @@ func (h *CheckoutHandlers) HandleCreateOrder(w http.ResponseWriter, r *http.Requ
 	respondJSON(w, http.StatusCreated, resp)
 }
 
+// HandleGetOrder maneja GET /checkout/orders/{id}.
+// @Summary      Get order
+// @Description  Obtener una orden del usuario (con X-Admin-Token se puede consultar cualquier orden)
+// @Tags         checkout
+// @Produce      json
+// @Param        id             path      string  true   "Order ID"
+// @Param        X-User-ID      header    string  false  "User ID"
+// @Param        X-Admin-Token  header    string  false  "Admin token"
+// @Success      200            {object}  OrderResponseDTO
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id} [get]
+// @Security     UserID
+func (h *CheckoutHandlers) HandleGetOrder(w http.ResponseWriter, r *http.Request) {
+	input := checkoutusecases.GetOrderInput{OrderID: chi.URLParam(r, "id")}
+	if r.Header.Get("X-Admin-Token") != "" {
+		if !h.authorizeAdmin(w, r) {
+			return
+		}
+	} else {
+		input.UserID = r.Header.Get("X-User-ID")
+		if input.UserID == "" {
+			respondError(w, http.StatusBadRequest, "X-User-ID header is required")
+			return
+		}
+	}
+
+	output, err := h.GetOrderUC.Execute(r.Context(), input)
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, OrderResponseDTO{Order: toOrderDTO(output.Order)})
+}
+
+// HandleListOrders maneja GET /checkout/orders.
+// @Summary      List user orders
+// @Description  Historial de órdenes del usuario, de la más nueva a la más antigua, paginado por cursor
+// @Tags         checkout
+// @Produce      json
+// @Param        X-User-ID  header    string  true   "User ID"
+// @Param        status     query     string  false  "Filtrar por estado"
+// @Param        from       query     string  false  "Creadas desde (RFC3339, inclusive)"
+// @Param        to         query     string  false  "Creadas hasta (RFC3339, exclusive)"
+// @Param        cursor     query     string  false  "next_cursor de la página anterior"
+// @Param        limit      query     int     false  "Tamaño de página (default 20, máx 100)"
+// @Success      200        {object}  ListOrdersResponseDTO
+// @Failure      400        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders [get]
+// @Security     UserID
+func (h *CheckoutHandlers) HandleListOrders(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "X-User-ID header is required")
+		return
+	}
+
+	query := r.URL.Query()
+	input := checkoutusecases.ListUserOrdersInput{
+		UserID: userID,
+		Status: checkoutdomain.OrderStatus(query.Get("status")),
+		Cursor: query.Get("cursor"),
+	}
+
+	from, err := parseOptionalTime(query.Get("from"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid from format (use RFC3339)")
+		return
+	}
+	to, err := parseOptionalTime(query.Get("to"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid to format (use RFC3339)")
+		return
+	}
+	input.From, input.To = from, to
+
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		input.Limit = parsed
+	}
+
+	output, err := h.ListUserOrdersUC.Execute(r.Context(), input)
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	orders := make([]OrderDTO, 0, len(output.Orders))
+	for _, order := range output.Orders {
+		orders = append(orders, toOrderDTO(order))
+	}
+
+	respondJSON(w, http.StatusOK, ListOrdersResponseDTO{Orders: orders, NextCursor: output.NextCursor})
+}
+
 // HandleConfirmPayment maneja POST /checkout/orders/{id}/confirm-payment.
 // @Summary      Confirm payment
 // @Description  Confirmar el pago de una orden
@@ -144,6 +279,7 @@ func (h *CheckoutHandlers) HandleCreateOrder(w http.ResponseWriter, r *http.Requ
 // @Success      200   {object}  ConfirmPaymentResponseDTO
 // @Failure      400   {object}  ErrorResponse
 // @Failure      404   {object}  ErrorResponse
+// @Failure      409   {object}  ErrorResponse
 // @Failure      500   {object}  ErrorResponse
 // @Router       /api/v1/commerce/checkout/orders/{id}/confirm-payment [post]
 func (h *CheckoutHandlers) HandleConfirmPayment(w http.ResponseWriter, r *http.Request) {
@@ -207,6 +343,8 @@ func (h *CheckoutHandlers) HandleConfirmPayment(w http.ResponseWriter, r *http.R
 // @Param        body       body      StartCheckoutRequestDTO  true  "Start checkout request"
 // @Success      200        {object}  StartCheckoutResponseDTO
 // @Failure      400        {object}  ErrorResponse
+// @Failure      409        {object}  ErrorResponse
+// @Failure      422        {object}  ErrorResponse
 // @Failure      500        {object}  ErrorResponse
 // @Router       /api/v1/commerce/checkout/start [post]
 // @Security     UserID
@@ -249,11 +387,551 @@ func (h *CheckoutHandlers) HandleStartCheckout(w http.ResponseWriter, r *http.Re
 	respondJSON(w, http.StatusOK, resp)
 }
 
+// HandleCancelOrder maneja POST /checkout/orders/{id}/cancel.
+// @Summary      Cancel order
+// @Description  Cancelar una orden aplicando la política de cancelación: gratis hasta N horas antes de la cita, penalidad porcentual después y sin cancelación una vez iniciada (con X-Admin-Token se puede cancelar cualquier orden)
+// @Tags         checkout
+// @Produce      json
+// @Param        id             path      string  true   "Order ID"
+// @Param        X-User-ID      header    string  false  "User ID"
+// @Param        X-Admin-Token  header    string  false  "Admin token"
+// @Success      200            {object}  CancelOrderResponseDTO
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      409            {object}  ErrorResponse
+// @Failure      422            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id}/cancel [post]
+// @Security     UserID
+func (h *CheckoutHandlers) HandleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := checkoutusecases.RequestCancellationInput{OrderID: chi.URLParam(r, "id")}
+	if r.Header.Get("X-Admin-Token") != "" {
+		if !h.authorizeAdmin(w, r) {
+			return
+		}
+		ctx = audit.WithActor(ctx, audit.ActorAdmin)
+	} else {
+		input.UserID = r.Header.Get("X-User-ID")
+		if input.UserID == "" {
+			respondError(w, http.StatusBadRequest, "X-User-ID header is required")
+			return
+		}
+	}
+
+	output, err := h.RequestCancellationUC.Execute(ctx, input)
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, CancelOrderResponseDTO{
+		Order:  toOrderDTO(output.Order),
+		Fee:    toMoneyDTO(output.Fee),
+		Refund: toMoneyDTO(output.Refund),
+	})
+}
+
+// HandleRefundOrder maneja POST /checkout/orders/{id}/refunds.
+// @Summary      Refund order
+// @Description  Reembolsar el pago de una orden: total (sin lines) o por líneas, prorrateadas por el descuento. Idempotente por refund_key (requiere X-Admin-Token)
+// @Tags         checkout
+// @Accept       json
+// @Produce      json
+// @Param        id             path      string                 true  "Order ID"
+// @Param        X-Admin-Token  header    string                 true  "Admin token"
+// @Param        body           body      RefundOrderRequestDTO  true  "Refund request"
+// @Success      200            {object}  RefundOrderResponseDTO
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      409            {object}  ErrorResponse
+// @Failure      422            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Failure      502            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id}/refunds [post]
+func (h *CheckoutHandlers) HandleRefundOrder(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	orderID := chi.URLParam(r, "id")
+	if orderID == "" {
+		respondError(w, http.StatusBadRequest, "order ID is required")
+		return
+	}
+
+	var req RefundOrderRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	ctx := audit.WithActor(r.Context(), audit.ActorAdmin)
+	output, err := h.RefundOrderUC.Execute(ctx, checkoutusecases.RefundOrderInput{
+		OrderID: orderID,
+		Key:     req.RefundKey,
+		Lines:   req.Lines,
+	})
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, RefundOrderResponseDTO{
+		Order:  toOrderDTO(output.Order),
+		Refund: toRefundDTO(output.Refund),
+	})
+}
+
+// HandleListOrderRefunds maneja GET /checkout/orders/{id}/refunds.
+// @Summary      List order refunds
+// @Description  Reembolsos emitidos sobre el pago de una orden y su neto pagado (requiere X-Admin-Token)
+// @Tags         checkout
+// @Produce      json
+// @Param        id             path      string  true  "Order ID"
+// @Param        X-Admin-Token  header    string  true  "Admin token"
+// @Success      200            {object}  ListOrderRefundsResponseDTO
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id}/refunds [get]
+func (h *CheckoutHandlers) HandleListOrderRefunds(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	orderID := chi.URLParam(r, "id")
+	if orderID == "" {
+		respondError(w, http.StatusBadRequest, "order ID is required")
+		return
+	}
+
+	output, err := h.ListOrderRefundsUC.Execute(r.Context(), checkoutusecases.ListOrderRefundsInput{OrderID: orderID})
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	refunds := make([]RefundDTO, 0, len(output.Refunds))
+	for _, refund := range output.Refunds {
+		refunds = append(refunds, toRefundDTO(refund))
+	}
+	respondJSON(w, http.StatusOK, ListOrderRefundsResponseDTO{
+		NetPaid: toMoneyDTO(output.Order.NetPaid()),
+		Refunds: refunds,
+	})
+}
+
+// HandleFulfillOrder maneja POST /checkout/orders/{id}/fulfill.
+// @Summary      Fulfill order
+// @Description  Marcar una orden pagada como fulfilled después de la cita (requiere X-Admin-Token)
+// @Tags         checkout
+// @Produce      json
+// @Param        id             path      string  true  "Order ID"
+// @Param        X-Admin-Token  header    string  true  "Admin token"
+// @Success      200            {object}  OrderResponseDTO
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      409            {object}  ErrorResponse
+// @Failure      422            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id}/fulfill [post]
+func (h *CheckoutHandlers) HandleFulfillOrder(w http.ResponseWriter, r *http.Request) {
+	h.handleAppointmentOutcome(w, r, checkoutusecases.AppointmentOutcomeFulfilled)
+}
+
+// HandleNoShowOrder maneja POST /checkout/orders/{id}/no-show.
+// @Summary      Mark order as no-show
+// @Description  Marcar una orden pagada como no_show si la mascota no se presentó (requiere X-Admin-Token)
+// @Tags         checkout
+// @Produce      json
+// @Param        id             path      string  true  "Order ID"
+// @Param        X-Admin-Token  header    string  true  "Admin token"
+// @Success      200            {object}  OrderResponseDTO
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      409            {object}  ErrorResponse
+// @Failure      422            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id}/no-show [post]
+func (h *CheckoutHandlers) HandleNoShowOrder(w http.ResponseWriter, r *http.Request) {
+	h.handleAppointmentOutcome(w, r, checkoutusecases.AppointmentOutcomeNoShow)
+}
+
+func (h *CheckoutHandlers) handleAppointmentOutcome(w http.ResponseWriter, r *http.Request, outcome checkoutusecases.AppointmentOutcome) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	orderID := chi.URLParam(r, "id")
+	if orderID == "" {
+		respondError(w, http.StatusBadRequest, "order ID is required")
+		return
+	}
+
+	ctx := audit.WithActor(r.Context(), audit.ActorAdmin)
+	output, err := h.RecordAppointmentOutcomeUC.Execute(ctx, checkoutusecases.RecordAppointmentOutcomeInput{
+		OrderID: orderID,
+		Outcome: outcome,
+	})
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, OrderResponseDTO{Order: toOrderDTO(output.Order)})
+}
+
+// HandleListOrderEvents maneja GET /checkout/orders/{id}/events.
+// @Summary      List order events
+// @Description  Historial de la orden (creación, pago, hold, cancelación, ...) con actor y request ID (requiere X-Admin-Token)
+// @Tags         checkout
+// @Produce      json
+// @Param        id             path      string  true  "Order ID"
+// @Param        X-Admin-Token  header    string  true  "Admin token"
+// @Success      200            {object}  ListOrderEventsResponseDTO
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id}/events [get]
+func (h *CheckoutHandlers) HandleListOrderEvents(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	orderID := chi.URLParam(r, "id")
+	if orderID == "" {
+		respondError(w, http.StatusBadRequest, "order ID is required")
+		return
+	}
+
+	output, err := h.ListOrderEventsUC.Execute(r.Context(), checkoutusecases.ListOrderEventsInput{OrderID: orderID})
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, ListOrderEventsResponseDTO{Events: toOrderEventDTOs(output.Events)})
+}
+
+// HandleListIncidents maneja GET /checkout/incidents.
+// @Summary      List incidents
+// @Description  Cola de revisión manual: side effects de booking/pagos que checkout no pudo completar, del más antiguo al más reciente (requiere X-Admin-Token)
+// @Tags         checkout
+// @Produce      json
+// @Param        X-Admin-Token  header    string  true   "Admin token"
+// @Param        status         query     string  false  "open | resolved (default: todos)"
+// @Param        order_id       query     string  false  "Filtrar por orden"
+// @Success      200            {object}  ListIncidentsResponseDTO
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/incidents [get]
+func (h *CheckoutHandlers) HandleListIncidents(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	output, err := h.ListIncidentsUC.Execute(r.Context(), checkoutusecases.ListIncidentsInput{
+		Status:  checkoutdomain.IncidentStatus(query.Get("status")),
+		OrderID: query.Get("order_id"),
+	})
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, ListIncidentsResponseDTO{Incidents: toIncidentDTOs(output.Incidents)})
+}
+
+// HandleRetryIncident maneja POST /checkout/incidents/{id}/retry.
+// @Summary      Retry incident
+// @Description  Reintentar la operación fallida del incidente; si funciona queda resuelto, si no responde 502 y sigue abierto con el nuevo error (requiere X-Admin-Token)
+// @Tags         checkout
+// @Produce      json
+// @Param        id             path      string  true  "Incident ID"
+// @Param        X-Admin-Token  header    string  true  "Admin token"
+// @Success      200            {object}  IncidentResponseDTO
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      422            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Failure      502            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/incidents/{id}/retry [post]
+func (h *CheckoutHandlers) HandleRetryIncident(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	incidentID := chi.URLParam(r, "id")
+	if incidentID == "" {
+		respondError(w, http.StatusBadRequest, "incident ID is required")
+		return
+	}
+
+	ctx := audit.WithActor(r.Context(), audit.ActorAdmin)
+	output, err := h.RetryIncidentUC.Execute(ctx, checkoutusecases.RetryIncidentInput{IncidentID: incidentID})
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, IncidentResponseDTO{Incident: toIncidentDTO(output.Incident)})
+}
+
+// HandleResolveIncident maneja POST /checkout/incidents/{id}/resolve.
+// @Summary      Resolve incident
+// @Description  Cerrar a mano un incidente con una nota de lo que se hizo (requiere X-Admin-Token)
+// @Tags         checkout
+// @Accept       json
+// @Produce      json
+// @Param        id             path      string                     true  "Incident ID"
+// @Param        X-Admin-Token  header    string                     true  "Admin token"
+// @Param        body           body      ResolveIncidentRequestDTO  true  "Resolution"
+// @Success      200            {object}  IncidentResponseDTO
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      403            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      422            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/incidents/{id}/resolve [post]
+func (h *CheckoutHandlers) HandleResolveIncident(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	incidentID := chi.URLParam(r, "id")
+	if incidentID == "" {
+		respondError(w, http.StatusBadRequest, "incident ID is required")
+		return
+	}
+
+	var req ResolveIncidentRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	ctx := audit.WithActor(r.Context(), audit.ActorAdmin)
+	output, err := h.ResolveIncidentUC.Execute(ctx, checkoutusecases.ResolveIncidentInput{
+		IncidentID: incidentID,
+		Note:       req.Note,
+	})
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, IncidentResponseDTO{Incident: toIncidentDTO(output.Incident)})
+}
+
+// maxWebhookBodyBytes limita el cuerpo de los webhooks de pagos.
+const maxWebhookBodyBytes = 64 << 10
+
+// HandleCreatePaymentIntent maneja POST /checkout/orders/{id}/payment.
+// @Summary      Create QR payment
+// @Description  Generar un QR de Yape o Plin para pagar una orden pending_payment o failed. Si ya hay un QR vigente con el mismo método se retorna (200); el pago se confirma cuando la transferencia llega al proveedor (ver GET)
+// @Tags         checkout
+// @Accept       json
+// @Produce      json
+// @Param        id             path      string                         true   "Order ID"
+// @Param        X-User-ID      header    string                         false  "User ID"
+// @Param        X-Admin-Token  header    string                         false  "Admin token"
+// @Param        body           body      CreatePaymentIntentRequestDTO  true   "Método de pago"
+// @Success      201            {object}  PaymentIntentResponseDTO
+// @Success      200            {object}  PaymentIntentResponseDTO
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      409            {object}  ErrorResponse
+// @Failure      422            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Failure      502            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id}/payment [post]
+// @Security     UserID
+func (h *CheckoutHandlers) HandleCreatePaymentIntent(w http.ResponseWriter, r *http.Request) {
+	input := checkoutusecases.CreatePaymentIntentInput{OrderID: chi.URLParam(r, "id")}
+	if r.Header.Get("X-Admin-Token") != "" {
+		if !h.authorizeAdmin(w, r) {
+			return
+		}
+	} else {
+		input.UserID = r.Header.Get("X-User-ID")
+		if input.UserID == "" {
+			respondError(w, http.StatusBadRequest, "X-User-ID header is required")
+			return
+		}
+	}
+
+	var req CreatePaymentIntentRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	input.Method = checkoutdomain.PaymentMethod(req.Method)
+
+	output, err := h.CreatePaymentIntentUC.Execute(r.Context(), input)
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	if output.Created {
+		status = http.StatusCreated
+	}
+	respondJSON(w, status, PaymentIntentResponseDTO{
+		Payment:     toPaymentIntentDTO(output.Intent),
+		OrderStatus: string(output.Order.Status),
+	})
+}
+
+// HandleGetPaymentIntent maneja GET /checkout/orders/{id}/payment.
+// @Summary      Get QR payment status
+// @Description  Estado del último QR de pago de la orden. Mientras está pending se consulta al proveedor, así el frontend puede hacer polling hasta que order_status sea paid
+// @Tags         checkout
+// @Produce      json
+// @Param        id             path      string  true   "Order ID"
+// @Param        X-User-ID      header    string  false  "User ID"
+// @Param        X-Admin-Token  header    string  false  "Admin token"
+// @Success      200            {object}  PaymentIntentResponseDTO
+// @Failure      400            {object}  ErrorResponse
+// @Failure      401            {object}  ErrorResponse
+// @Failure      404            {object}  ErrorResponse
+// @Failure      500            {object}  ErrorResponse
+// @Router       /api/v1/commerce/checkout/orders/{id}/payment [get]
+// @Security     UserID
+func (h *CheckoutHandlers) HandleGetPaymentIntent(w http.ResponseWriter, r *http.Request) {
+	input := checkoutusecases.GetPaymentIntentInput{OrderID: chi.URLParam(r, "id")}
+	if r.Header.Get("X-Admin-Token") != "" {
+		if !h.authorizeAdmin(w, r) {
+			return
+		}
+	} else {
+		input.UserID = r.Header.Get("X-User-ID")
+		if input.UserID == "" {
+			respondError(w, http.StatusBadRequest, "X-User-ID header is required")
+			return
+		}
+	}
+
+	output, err := h.GetPaymentIntentUC.Execute(r.Context(), input)
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, PaymentIntentResponseDTO{
+		Payment:     toPaymentIntentDTO(output.Intent),
+		OrderStatus: string(output.Order.Status),
+	})
+}
+
+// HandlePaymentWebhook maneja POST /payments/webhooks/{provider}.
+// @Summary      Payment provider webhook
+// @Description  Recibir eventos del proveedor de pagos (payment.succeeded, payment.failed, payment.refunded) firmados en X-Payment-Signature. Cada evento se aplica una sola vez por ID; 503 pide al proveedor que reintente la entrega
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        provider             path      string  true  "Proveedor de pagos"
+// @Param        X-Payment-Signature  header    string  true  "t=<unix>,v1=<hmac-sha256 hex>"
+// @Success      200                  {object}  PaymentWebhookResponseDTO
+// @Failure      400                  {object}  ErrorResponse
+// @Failure      401                  {object}  ErrorResponse
+// @Failure      404                  {object}  ErrorResponse
+// @Failure      413                  {object}  ErrorResponse
+// @Failure      500                  {object}  ErrorResponse
+// @Failure      503                  {object}  ErrorResponse
+// @Router       /api/v1/commerce/payments/webhooks/{provider} [post]
+func (h *CheckoutHandlers) HandlePaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	verifier, ok := h.WebhookVerifiers[provider]
+	if !ok {
+		respondError(w, http.StatusNotFound, "unknown payment provider")
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		respondError(w, http.StatusRequestEntityTooLarge, "webhook body too large")
+		return
+	}
+
+	event, err := verifier.VerifyWebhook(payload, r.Header.Get("X-Payment-Signature"))
+	if errors.Is(err, payments.ErrInvalidWebhookSignature) {
+		respondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	event.Provider = provider
+
+	ctx := audit.WithActor(r.Context(), audit.PaymentProviderActor(provider))
+	output, err := h.HandlePaymentWebhookUC.Execute(ctx, checkoutusecases.HandlePaymentWebhookInput{Event: event})
+	if err != nil {
+		respondError(w, mapErrorToHTTPStatus(err), err.Error())
+		return
+	}
+
+	response := PaymentWebhookResponseDTO{
+		EventID:   event.ID,
+		Status:    string(output.Record.Status),
+		Duplicate: output.Duplicate,
+	}
+	if output.Record.Status == checkoutdomain.PaymentEventStatusIgnored {
+		response.Reason = output.Record.Error
+	}
+	respondJSON(w, http.StatusOK, response)
+}
+
+// parseOptionalTime parsea un timestamp RFC3339 opcional ("" = nil).
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// authorizeAdmin valida X-Admin-Token; sin AdminToken configurado las operaciones admin están deshabilitadas.
+func (h *CheckoutHandlers) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if h.AdminToken == "" {
+		respondError(w, http.StatusForbidden, "admin operations are disabled")
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(h.AdminToken)) != 1 {
+		respondError(w, http.StatusUnauthorized, "invalid admin token")
+		return false
+	}
+	return true
+}
+
 func mapStartCheckoutErrorToHTTPStatus(err error) int {
 	if err == cartdomain.ErrCartNotFound || err == cartdomain.ErrInvalidUserID || err == cartdomain.ErrEmptyItems {
 		return http.StatusBadRequest
 	}
-	return http.StatusInternalServerError
+	// El carrito cambió mientras se iniciaba el checkout: el cliente debe reintentar.
+	if errors.Is(err, cartdomain.ErrCartRevisionConflict) {
+		return http.StatusConflict
+	}
+	// Errores de cotización (p. ej. el cupón del carrito ya no aplica)
+	return mapErrorToHTTPStatus(err)
 }
 
 // respondJSON escribe una respuesta JSON.
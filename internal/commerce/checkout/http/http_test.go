@@ -2,19 +2,33 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	carthttp "paku-commerce/internal/commerce/cart/http"
+	"paku-commerce/internal/commerce/checkout/adapters/paymentwebhook"
+	"paku-commerce/internal/commerce/checkout/adapters/petshttp"
+	"paku-commerce/internal/commerce/checkout/adapters/qrsimulator"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	"paku-commerce/internal/commerce/runtime"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
 )
 
+const testAdminToken = "test-admin-token"
+
 func setupTestRouter() http.Handler {
 	// Wire handlers con repos singleton compartidos
 	checkoutHandlers := WireCheckoutHandlers()
+	checkoutHandlers.AdminToken = testAdminToken
 	cartHandlers := carthttp.WireCartHandlers()
 
 	r := chi.NewRouter()
@@ -111,6 +125,120 @@ func TestHTTP_CreateOrder(t *testing.T) {
 	if resp.Order.Status != "pending_payment" {
 		t.Errorf("expected status pending_payment, got: %s", resp.Order.Status)
 	}
+
+	if resp.Order.ExpiresAt == nil {
+		t.Errorf("expected payment deadline (expires_at) on pending order")
+	}
+}
+
+// usePetsFakeServer apunta el PetsClient compartido a un servicio de mascotas local.
+func usePetsFakeServer(t *testing.T, profiles map[string]servicedomain.PetProfile) {
+	t.Helper()
+	server := petshttp.NewFakeServer(profiles)
+	previous := runtime.PetsClientSingleton
+	if err := runtime.InitPets(runtime.PetsConfig{BaseURL: server.URL}); err != nil {
+		t.Fatalf("failed to init pets client: %v", err)
+	}
+	t.Cleanup(func() {
+		runtime.PetsClientSingleton = previous
+		server.Close()
+	})
+}
+
+func useQRSimulator(t *testing.T) *qrsimulator.Simulator {
+	t.Helper()
+	previous := runtime.QRPaymentsSingleton
+	if err := runtime.InitPayments(runtime.PaymentsConfig{QRProvider: runtime.QRProviderSimulator}); err != nil {
+		t.Fatalf("failed to init QR simulator: %v", err)
+	}
+	t.Cleanup(func() {
+		runtime.QRPaymentsSingleton = previous
+	})
+	return runtime.QRPaymentsSingleton.(*qrsimulator.Simulator)
+}
+
+func TestHTTP_CreateOrder_ByPetID_SnapshotsResolvedProfile(t *testing.T) {
+	usePetsFakeServer(t, map[string]servicedomain.PetProfile{
+		"pet_max": {Species: servicedomain.SpeciesDog, WeightKg: 25, CoatType: servicedomain.CoatTypeDouble},
+	})
+	router := setupTestRouter()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"pet_id": "pet_max",
+		"items": []map[string]interface{}{
+			{"type": "service", "id": "bath", "qty": 1},
+		},
+	})
+	req := httptest.NewRequest("POST", "/checkout/orders", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CreateOrderResponseDTO
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Order.PetID != "pet_max" {
+		t.Errorf("expected pet_id pet_max, got: %q", resp.Order.PetID)
+	}
+	if len(resp.Order.Pets) != 1 || resp.Order.Pets[0].PetProfile.WeightKg != 25 {
+		t.Errorf("expected resolved 25kg profile in order pets, got: %+v", resp.Order.Pets)
+	}
+	if resp.Order.Subtotal.Amount != 6000 {
+		t.Errorf("expected bath priced for 25kg (6000), got: %d", resp.Order.Subtotal.Amount)
+	}
+}
+
+func TestHTTP_Quote_UnknownPetID_Returns422(t *testing.T) {
+	usePetsFakeServer(t, nil)
+	router := setupTestRouter()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"pet_id": "pet_ghost",
+		"items": []map[string]interface{}{
+			{"type": "service", "id": "bath", "qty": 1},
+		},
+	})
+	req := httptest.NewRequest("POST", "/checkout/quote", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHTTP_Quote_PetIDWithFakeProfile_Returns422(t *testing.T) {
+	usePetsFakeServer(t, map[string]servicedomain.PetProfile{
+		"pet_max": {Species: servicedomain.SpeciesDog, WeightKg: 25, CoatType: servicedomain.CoatTypeDouble},
+	})
+	router := setupTestRouter()
+
+	// pet_id real con un perfil inline más liviano para cotizar más barato
+	body, _ := json.Marshal(map[string]interface{}{
+		"pet_id":      "pet_max",
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 5, "coat_type": "short"},
+		"items": []map[string]interface{}{
+			{"type": "service", "id": "bath", "qty": 1},
+		},
+	})
+	req := httptest.NewRequest("POST", "/checkout/quote", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
 }
 
 func TestHTTP_ConfirmPayment(t *testing.T) {
@@ -174,6 +302,577 @@ func TestHTTP_ConfirmPayment(t *testing.T) {
 	}
 }
 
+// createPaidOrder crea una orden por HTTP y confirma su pago.
+func createPaidOrder(t *testing.T, router http.Handler) string {
+	t.Helper()
+
+	createBody, _ := json.Marshal(map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 10, "coat_type": "short"},
+		"items":       []map[string]interface{}{{"type": "service", "id": "bath", "qty": 1}},
+	})
+	createRec := httptest.NewRecorder()
+	router.ServeHTTP(createRec, httptest.NewRequest("POST", "/checkout/orders", bytes.NewReader(createBody)))
+	if createRec.Code != http.StatusCreated {
+		t.Fatalf("failed to create order: %d %s", createRec.Code, createRec.Body.String())
+	}
+	var createResp CreateOrderResponseDTO
+	json.NewDecoder(createRec.Body).Decode(&createResp)
+
+	confirmBody, _ := json.Marshal(map[string]interface{}{"payment_ref": "tx_" + createResp.Order.ID})
+	confirmRec := httptest.NewRecorder()
+	router.ServeHTTP(confirmRec, httptest.NewRequest("POST", "/checkout/orders/"+createResp.Order.ID+"/confirm-payment", bytes.NewReader(confirmBody)))
+	if confirmRec.Code != http.StatusOK {
+		t.Fatalf("failed to confirm payment: %d %s", confirmRec.Code, confirmRec.Body.String())
+	}
+
+	return createResp.Order.ID
+}
+
+func TestHTTP_FulfillOrder(t *testing.T) {
+	router := setupTestRouter()
+	orderID := createPaidOrder(t, router)
+
+	req := httptest.NewRequest("POST", "/checkout/orders/"+orderID+"/fulfill", nil)
+	req.Header.Set("X-Admin-Token", testAdminToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var resp OrderResponseDTO
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Order.Status != "fulfilled" {
+		t.Errorf("expected status fulfilled, got: %s", resp.Order.Status)
+	}
+	if resp.Order.FulfilledAt == nil || resp.Order.PaidAt == nil {
+		t.Errorf("expected paid_at and fulfilled_at, got: %+v", resp.Order)
+	}
+
+	// Una orden fulfilled no puede pasar a no_show
+	noShowReq := httptest.NewRequest("POST", "/checkout/orders/"+orderID+"/no-show", nil)
+	noShowReq.Header.Set("X-Admin-Token", testAdminToken)
+	noShowRec := httptest.NewRecorder()
+	router.ServeHTTP(noShowRec, noShowReq)
+
+	if noShowRec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got: %d, body: %s", noShowRec.Code, noShowRec.Body.String())
+	}
+}
+
+func TestHTTP_NoShow_UnpaidOrder_Returns422(t *testing.T) {
+	router := setupTestRouter()
+
+	createBody, _ := json.Marshal(map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 10, "coat_type": "short"},
+		"items":       []map[string]interface{}{{"type": "service", "id": "bath", "qty": 1}},
+	})
+	createRec := httptest.NewRecorder()
+	router.ServeHTTP(createRec, httptest.NewRequest("POST", "/checkout/orders", bytes.NewReader(createBody)))
+	var createResp CreateOrderResponseDTO
+	json.NewDecoder(createRec.Body).Decode(&createResp)
+
+	req := httptest.NewRequest("POST", "/checkout/orders/"+createResp.Order.ID+"/no-show", nil)
+	req.Header.Set("X-Admin-Token", testAdminToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHTTP_FulfillOrder_RequiresAdminToken(t *testing.T) {
+	router := setupTestRouter()
+	orderID := createPaidOrder(t, router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("POST", "/checkout/orders/"+orderID+"/fulfill", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got: %d", rec.Code)
+	}
+
+	// Sin ADMIN_TOKEN configurado el endpoint está deshabilitado
+	handlers := WireCheckoutHandlers()
+	r := chi.NewRouter()
+	RegisterRoutes(r, handlers)
+
+	req := httptest.NewRequest("POST", "/checkout/orders/"+orderID+"/fulfill", nil)
+	req.Header.Set("X-Admin-Token", testAdminToken)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 when disabled, got: %d", rec.Code)
+	}
+}
+
+func TestHTTP_ListOrderEvents(t *testing.T) {
+	router := setupTestRouter()
+	orderID := createPaidOrder(t, router)
+
+	req := httptest.NewRequest("GET", "/checkout/orders/"+orderID+"/events", nil)
+	req.Header.Set("X-Admin-Token", testAdminToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var resp ListOrderEventsResponseDTO
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Events) != 2 || resp.Events[0].Type != "created" || resp.Events[1].Type != "payment_confirmed" {
+		t.Fatalf("expected created and payment_confirmed events, got: %+v", resp.Events)
+	}
+	if resp.Events[1].Data["payment_ref"] != "tx_"+orderID || resp.Events[1].FromStatus != "pending_payment" {
+		t.Errorf("unexpected payment event: %+v", resp.Events[1])
+	}
+
+	// Sin token no se expone el historial
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/checkout/orders/"+orderID+"/events", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without token, got: %d", rec.Code)
+	}
+
+	// Orden inexistente
+	req = httptest.NewRequest("GET", "/checkout/orders/order_missing/events", nil)
+	req.Header.Set("X-Admin-Token", testAdminToken)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got: %d", rec.Code)
+	}
+}
+
+// createUserOrder crea una orden por HTTP con X-User-ID.
+func createUserOrder(t *testing.T, router http.Handler, userID string) OrderDTO {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 10, "coat_type": "short"},
+		"items":       []map[string]interface{}{{"type": "service", "id": "bath", "qty": 1}},
+	})
+	req := httptest.NewRequest("POST", "/checkout/orders", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", userID)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("failed to create order: %d %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CreateOrderResponseDTO
+	json.NewDecoder(rec.Body).Decode(&resp)
+	return resp.Order
+}
+
+func TestHTTP_GetOrder_Ownership(t *testing.T) {
+	router := setupTestRouter()
+	order := createUserOrder(t, router, "user_get_owner")
+	if order.UserID != "user_get_owner" {
+		t.Fatalf("expected user_id on created order, got: %q", order.UserID)
+	}
+
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/checkout/orders/"+order.ID, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(map[string]string{"X-User-ID": "user_get_owner"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for owner, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var resp OrderResponseDTO
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Order.ID != order.ID {
+		t.Errorf("expected order %s, got: %s", order.ID, resp.Order.ID)
+	}
+
+	if rec := get(map[string]string{"X-User-ID": "user_get_other"}); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for another user, got: %d", rec.Code)
+	}
+	if rec := get(nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without X-User-ID, got: %d", rec.Code)
+	}
+	if rec := get(map[string]string{"X-Admin-Token": testAdminToken}); rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 for admin, got: %d", rec.Code)
+	}
+}
+
+func TestHTTP_CancelOrder(t *testing.T) {
+	router := setupTestRouter()
+
+	cancel := func(orderID string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/checkout/orders/"+orderID+"/cancel", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Orden sin pagar: solo el dueño la cancela, sin penalidad ni reembolso
+	order := createUserOrder(t, router, "user_cancel_owner")
+	if rec := cancel(order.ID, map[string]string{"X-User-ID": "user_cancel_other"}); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for another user, got: %d", rec.Code)
+	}
+
+	rec := cancel(order.ID, map[string]string{"X-User-ID": "user_cancel_owner"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var resp CancelOrderResponseDTO
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Order.Status != "cancelled" || resp.Order.CancelReason != "requested" {
+		t.Errorf("expected cancelled/requested, got: %s/%s", resp.Order.Status, resp.Order.CancelReason)
+	}
+	if resp.Fee.Amount != 0 || resp.Refund.Amount != 0 {
+		t.Errorf("expected no fee and no refund, got fee=%d refund=%d", resp.Fee.Amount, resp.Refund.Amount)
+	}
+
+	// Orden pagada sin cita: el admin la cancela con reembolso total
+	paidID := createPaidOrder(t, router)
+	rec = cancel(paidID, map[string]string{"X-Admin-Token": testAdminToken})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for admin, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	resp = CancelOrderResponseDTO{}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Fee.Amount != 0 || resp.Refund != resp.Order.Total {
+		t.Errorf("expected full refund of %+v, got fee=%+v refund=%+v", resp.Order.Total, resp.Fee, resp.Refund)
+	}
+
+	if rec := cancel(paidID, map[string]string{"X-Admin-Token": "wrong"}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 with wrong admin token, got: %d", rec.Code)
+	}
+}
+
+func TestHTTP_RefundOrder(t *testing.T) {
+	router := setupTestRouter()
+	orderID := createPaidOrder(t, router)
+
+	refund := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/checkout/orders/"+orderID+"/refunds", bytes.NewReader([]byte(body)))
+		req.Header.Set("X-Admin-Token", token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := refund("wrong", `{"refund_key": "rf_http"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 with wrong admin token, got: %d", rec.Code)
+	}
+	if rec := refund(testAdminToken, `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without refund_key, got: %d", rec.Code)
+	}
+
+	rec := refund(testAdminToken, `{"refund_key": "rf_http"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var resp RefundOrderResponseDTO
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Order.Status != "refunded" {
+		t.Errorf("expected refunded, got: %s", resp.Order.Status)
+	}
+	if resp.Refund.Amount != resp.Order.Total || resp.Refund.PaymentRef != "tx_"+orderID {
+		t.Errorf("expected full refund on tx_%s, got: %+v", orderID, resp.Refund)
+	}
+	if resp.Order.NetPaid == nil || resp.Order.NetPaid.Amount != 0 {
+		t.Errorf("expected net_paid 0, got: %+v", resp.Order.NetPaid)
+	}
+
+	// Reintento con la misma clave: mismo reembolso
+	if rec := refund(testAdminToken, `{"refund_key": "rf_http"}`); rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 on retry, got: %d", rec.Code)
+	}
+	if rec := refund(testAdminToken, `{"refund_key": "rf_http_2"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 with nothing left to refund, got: %d", rec.Code)
+	}
+
+	listReq := httptest.NewRequest("GET", "/checkout/orders/"+orderID+"/refunds", nil)
+	listReq.Header.Set("X-Admin-Token", testAdminToken)
+	listRec := httptest.NewRecorder()
+	router.ServeHTTP(listRec, listReq)
+	if listRec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on list, got: %d", listRec.Code)
+	}
+	var list ListOrderRefundsResponseDTO
+	json.NewDecoder(listRec.Body).Decode(&list)
+	if len(list.Refunds) != 1 || list.Refunds[0].RefundKey != "rf_http" || list.NetPaid.Amount != 0 {
+		t.Errorf("expected a single refund and net_paid 0, got: %+v", list)
+	}
+}
+
+func TestHTTP_Incidents(t *testing.T) {
+	router := setupTestRouter()
+	recordIncident := checkoutusecases.RecordIncident{Repo: runtime.IncidentRepoSingleton}
+	record := func(holdID string) string {
+		output, err := recordIncident.Execute(context.Background(), checkoutusecases.RecordIncidentInput{
+			OrderID:   "order_http_incident",
+			HoldID:    holdID,
+			Operation: checkoutdomain.IncidentOperationCancelHold,
+			Err:       errors.New("booking unavailable"),
+		})
+		if err != nil {
+			t.Fatalf("failed to record incident: %v", err)
+		}
+		return output.Incident.ID
+	}
+	retried, resolved := record("hold_http_1"), record("hold_http_2")
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req.Header.Set("X-Admin-Token", token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("GET", "/checkout/incidents", "wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 with wrong admin token, got: %d", rec.Code)
+	}
+	if rec := do("GET", "/checkout/incidents?status=closed", testAdminToken, ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 with unknown status, got: %d", rec.Code)
+	}
+
+	rec := do("GET", "/checkout/incidents?status=open&order_id=order_http_incident", testAdminToken, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var list ListIncidentsResponseDTO
+	json.NewDecoder(rec.Body).Decode(&list)
+	if len(list.Incidents) != 2 || list.Incidents[0].Operation != "cancel_hold" || list.Incidents[0].Error != "booking unavailable" {
+		t.Fatalf("expected 2 open cancel_hold incidents, got: %+v", list.Incidents)
+	}
+
+	// Retry: el stub de booking libera el hold y el incidente queda resuelto
+	rec = do("POST", "/checkout/incidents/"+retried+"/retry", testAdminToken, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on retry, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var retry IncidentResponseDTO
+	json.NewDecoder(rec.Body).Decode(&retry)
+	if retry.Incident.Status != "resolved" || retry.Incident.ResolvedBy != "admin" {
+		t.Errorf("expected incident resolved by admin, got: %+v", retry.Incident)
+	}
+	if rec := do("POST", "/checkout/incidents/"+retried+"/retry", testAdminToken, ""); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 retrying a resolved incident, got: %d", rec.Code)
+	}
+
+	// Resolve: requiere nota
+	if rec := do("POST", "/checkout/incidents/"+resolved+"/resolve", testAdminToken, `{"note": ""}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without note, got: %d", rec.Code)
+	}
+	rec = do("POST", "/checkout/incidents/"+resolved+"/resolve", testAdminToken, `{"note": "hold expired in booking"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on resolve, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var resolve IncidentResponseDTO
+	json.NewDecoder(rec.Body).Decode(&resolve)
+	if resolve.Incident.Resolution != "hold expired in booking" || resolve.Incident.ResolvedAt == nil {
+		t.Errorf("expected resolution note, got: %+v", resolve.Incident)
+	}
+	if rec := do("POST", "/checkout/incidents/inc_missing/resolve", testAdminToken, `{"note": "x"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown incident, got: %d", rec.Code)
+	}
+
+	rec = do("GET", "/checkout/incidents?status=open&order_id=order_http_incident", testAdminToken, "")
+	json.NewDecoder(rec.Body).Decode(&list)
+	if len(list.Incidents) != 0 {
+		t.Errorf("expected empty queue, got: %+v", list.Incidents)
+	}
+}
+
+func TestHTTP_PaymentWebhook(t *testing.T) {
+	checkoutHandlers := WireCheckoutHandlers()
+	checkoutHandlers.WebhookVerifiers = map[string]payments.WebhookVerifier{
+		"acme": paymentwebhook.Verifier{Provider: "acme", Secret: "whsec_http"},
+	}
+	router := chi.NewRouter()
+	RegisterRoutes(router, checkoutHandlers)
+
+	createBody, _ := json.Marshal(map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 10, "coat_type": "short"},
+		"items":       []map[string]interface{}{{"type": "service", "id": "bath", "qty": 1}},
+	})
+	createRec := httptest.NewRecorder()
+	router.ServeHTTP(createRec, httptest.NewRequest("POST", "/checkout/orders", bytes.NewReader(createBody)))
+	var created CreateOrderResponseDTO
+	json.NewDecoder(createRec.Body).Decode(&created)
+
+	payload := []byte(`{"id":"evt_http_` + created.Order.ID + `","type":"payment.succeeded",` +
+		`"data":{"order_id":"` + created.Order.ID + `","payment_ref":"tx_webhook"}}`)
+	deliver := func(provider string, body []byte, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/payments/webhooks/"+provider, bytes.NewReader(body))
+		req.Header.Set("X-Payment-Signature", signature)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	signature := paymentwebhook.Sign("whsec_http", payload, time.Now())
+
+	if rec := deliver("unknown", payload, signature); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown provider, got: %d", rec.Code)
+	}
+	if rec := deliver("acme", payload, paymentwebhook.Sign("wrong", payload, time.Now())); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 with invalid signature, got: %d", rec.Code)
+	}
+
+	rec := deliver("acme", payload, signature)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var first PaymentWebhookResponseDTO
+	json.NewDecoder(rec.Body).Decode(&first)
+	if first.Status != "processed" || first.Duplicate {
+		t.Errorf("expected processed event, got: %+v", first)
+	}
+
+	// El proveedor reentrega el mismo evento
+	rec = deliver("acme", payload, signature)
+	var again PaymentWebhookResponseDTO
+	json.NewDecoder(rec.Body).Decode(&again)
+	if rec.Code != http.StatusOK || !again.Duplicate {
+		t.Errorf("expected duplicate delivery acknowledged, got: %d %+v", rec.Code, again)
+	}
+
+	order, _ := runtime.OrderRepoSingleton.GetByID(context.Background(), created.Order.ID)
+	if order.Status != checkoutdomain.OrderStatusPaid || *order.PaymentRef != "tx_webhook" {
+		t.Errorf("expected order paid by the webhook, got: %v", order.Status)
+	}
+	events, _ := runtime.OrderRepoSingleton.ListEvents(context.Background(), created.Order.ID)
+	if last := events[len(events)-1]; last.Actor != "payments:acme" {
+		t.Errorf("expected event recorded by payments:acme, got: %q", last.Actor)
+	}
+}
+
+func TestHTTP_QRPayment_NotRegisteredWithoutProvider(t *testing.T) {
+	router := setupTestRouter()
+	order := createUserOrder(t, router, "user_qr_none")
+
+	req := httptest.NewRequest("POST", "/checkout/orders/"+order.ID+"/payment", bytes.NewReader([]byte(`{"method":"yape"}`)))
+	req.Header.Set("X-User-ID", "user_qr_none")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound && rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected QR routes not registered without QR_PROVIDER, got: %d", rec.Code)
+	}
+}
+
+func TestHTTP_QRPayment(t *testing.T) {
+	simulator := useQRSimulator(t)
+	router := setupTestRouter()
+	order := createUserOrder(t, router, "user_qr")
+
+	do := func(method, userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/checkout/orders/"+order.ID+"/payment", bytes.NewReader([]byte(body)))
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("GET", "user_qr", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 before any QR, got: %d", rec.Code)
+	}
+	if rec := do("POST", "user_qr", `{"method":"card"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown method, got: %d", rec.Code)
+	}
+	if rec := do("POST", "user_qr_other", `{"method":"yape"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for another user, got: %d", rec.Code)
+	}
+
+	rec := do("POST", "user_qr", `{"method":"yape"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var created PaymentIntentResponseDTO
+	json.NewDecoder(rec.Body).Decode(&created)
+	if created.Payment.Status != "pending" || created.Payment.QRData == "" || created.OrderStatus != "pending_payment" {
+		t.Errorf("unexpected payment: %+v", created)
+	}
+	if rec := do("POST", "user_qr", `{"method":"yape"}`); rec.Code != http.StatusOK {
+		t.Errorf("expected status 200 for the same pending QR, got: %d", rec.Code)
+	}
+	if rec := do("POST", "user_qr", `{"method":"plin"}`); rec.Code != http.StatusConflict {
+		t.Errorf("expected status 409 with a pending QR of another method, got: %d", rec.Code)
+	}
+
+	// El comprador paga el QR: el siguiente GET concilia con el proveedor
+	if err := simulator.Complete(created.Payment.ProviderRef); err != nil {
+		t.Fatalf("failed to complete QR payment: %v", err)
+	}
+	rec = do("GET", "user_qr", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+	}
+	var synced PaymentIntentResponseDTO
+	json.NewDecoder(rec.Body).Decode(&synced)
+	if synced.Payment.Status != "succeeded" || synced.OrderStatus != "paid" || synced.Payment.CompletedAt == nil {
+		t.Errorf("expected succeeded payment and paid order, got: %+v", synced)
+	}
+}
+
+func TestHTTP_ListOrders_CursorPagination(t *testing.T) {
+	router := setupTestRouter()
+	userID := "user_list_orders"
+	created := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		created[createUserOrder(t, router, userID).ID] = true
+	}
+	createUserOrder(t, router, "user_list_other")
+
+	seen := map[string]bool{}
+	url := "/checkout/orders?status=pending_payment&limit=2"
+	pages := 0
+	for url != "" {
+		req := httptest.NewRequest("GET", url, nil)
+		req.Header.Set("X-User-ID", userID)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got: %d, body: %s", rec.Code, rec.Body.String())
+		}
+
+		var resp ListOrdersResponseDTO
+		json.NewDecoder(rec.Body).Decode(&resp)
+		for _, order := range resp.Orders {
+			if !created[order.ID] || seen[order.ID] {
+				t.Errorf("unexpected or repeated order %s", order.ID)
+			}
+			seen[order.ID] = true
+		}
+
+		pages++
+		url = ""
+		if resp.NextCursor != "" {
+			url = "/checkout/orders?status=pending_payment&limit=2&cursor=" + resp.NextCursor
+		}
+	}
+
+	if len(seen) != 3 || pages != 2 {
+		t.Errorf("expected 3 orders in 2 pages, got: %d orders in %d pages", len(seen), pages)
+	}
+
+	req := httptest.NewRequest("GET", "/checkout/orders?from=yesterday", nil)
+	req.Header.Set("X-User-ID", userID)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid from, got: %d", rec.Code)
+	}
+}
+
 func TestHTTP_AddonWithoutParent_Returns422(t *testing.T) {
 	router := setupTestRouter()
 
@@ -315,6 +1014,60 @@ func TestHTTP_StartCheckout_ReplaceHold(t *testing.T) {
 	}
 }
 
+func TestHTTP_StartCheckout_MultiPet(t *testing.T) {
+	router := setupTestRouter()
+
+	// 1. Cart con dos mascotas: pet principal 15kg y "luna" 30kg
+	cartBody := map[string]interface{}{
+		"pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 15, "coat_type": "double"},
+		"pets": []map[string]interface{}{
+			{"pet_id": "luna", "pet_profile": map[string]interface{}{"species": "dog", "weight_kg": 30, "coat_type": "short"}},
+		},
+		"items": []map[string]interface{}{
+			{"type": "service", "id": "bath", "qty": 1},
+			{"type": "service", "id": "deshedding", "qty": 1},
+			{"type": "service", "id": "bath", "qty": 1, "pet_id": "luna"},
+		},
+	}
+
+	body, _ := json.Marshal(cartBody)
+	cartReq := httptest.NewRequest("PUT", "/cart/me", bytes.NewReader(body))
+	cartReq.Header.Set("Content-Type", "application/json")
+	cartReq.Header.Set("X-User-ID", "user_multi_pet")
+	cartRec := httptest.NewRecorder()
+	router.ServeHTTP(cartRec, cartReq)
+	if cartRec.Code != http.StatusOK {
+		t.Fatalf("expected cart status 200, got: %d, body: %s", cartRec.Code, cartRec.Body.String())
+	}
+
+	// 2. Un solo start checkout para ambas mascotas
+	startBytes, _ := json.Marshal(map[string]interface{}{"slot_id": "slot_multi"})
+	startReq := httptest.NewRequest("POST", "/checkout/start", bytes.NewReader(startBytes))
+	startReq.Header.Set("Content-Type", "application/json")
+	startReq.Header.Set("X-User-ID", "user_multi_pet")
+	startRec := httptest.NewRecorder()
+	router.ServeHTTP(startRec, startReq)
+	if startRec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got: %d, body: %s", startRec.Code, startRec.Body.String())
+	}
+
+	var resp StartCheckoutResponseDTO
+	json.NewDecoder(startRec.Body).Decode(&resp)
+
+	if len(resp.Order.Pets) != 2 {
+		t.Fatalf("expected 2 pets in order, got: %+v", resp.Order.Pets)
+	}
+	if resp.Order.Pets[0].PetID != "" || resp.Order.Pets[0].Subtotal.Amount != 6500 {
+		t.Errorf("expected main pet subtotal 6500, got: %+v", resp.Order.Pets[0])
+	}
+	if resp.Order.Pets[1].PetID != "luna" || resp.Order.Pets[1].Subtotal.Amount != 6000 {
+		t.Errorf("expected luna subtotal 6000, got: %+v", resp.Order.Pets[1])
+	}
+	if resp.Order.Subtotal.Amount != 12500 {
+		t.Errorf("expected order subtotal 12500, got: %d", resp.Order.Subtotal.Amount)
+	}
+}
+
 func TestHTTP_E2E_CartToStartToConfirmPayment(t *testing.T) {
 	router := setupTestRouter()
 
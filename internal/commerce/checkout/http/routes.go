@@ -9,7 +9,24 @@ func RegisterRoutes(r chi.Router, handlers *CheckoutHandlers) {
 	r.Route("/checkout", func(r chi.Router) {
 		r.Post("/quote", handlers.HandleQuote)
 		r.Post("/orders", handlers.HandleCreateOrder)
+		r.Get("/orders", handlers.HandleListOrders)
+		r.Get("/orders/{id}", handlers.HandleGetOrder)
 		r.Post("/orders/{id}/confirm-payment", handlers.HandleConfirmPayment)
+		r.Post("/orders/{id}/cancel", handlers.HandleCancelOrder)
+		r.Post("/orders/{id}/refunds", handlers.HandleRefundOrder)
+		r.Get("/orders/{id}/refunds", handlers.HandleListOrderRefunds)
+		r.Post("/orders/{id}/fulfill", handlers.HandleFulfillOrder)
+		r.Post("/orders/{id}/no-show", handlers.HandleNoShowOrder)
+		r.Get("/orders/{id}/events", handlers.HandleListOrderEvents)
+		if handlers.CreatePaymentIntentUC != nil {
+			// Solo con un proveedor QR configurado (QR_PROVIDER)
+			r.Post("/orders/{id}/payment", handlers.HandleCreatePaymentIntent)
+			r.Get("/orders/{id}/payment", handlers.HandleGetPaymentIntent)
+		}
 		r.Post("/start", handlers.HandleStartCheckout)
+		r.Get("/incidents", handlers.HandleListIncidents)
+		r.Post("/incidents/{id}/retry", handlers.HandleRetryIncident)
+		r.Post("/incidents/{id}/resolve", handlers.HandleResolveIncident)
 	})
+	r.Post("/payments/webhooks/{provider}", handlers.HandlePaymentWebhook)
 }
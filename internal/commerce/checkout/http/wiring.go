@@ -1,28 +1,40 @@
 package http
 
 import (
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
 	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	checkoutworker "paku-commerce/internal/commerce/checkout/worker"
 	platformbooking "paku-commerce/internal/commerce/platform/booking"
 	"paku-commerce/internal/commerce/runtime"
-	servicememory "paku-commerce/internal/commerce/service/adapters/memory"
-	pricingmemory "paku-commerce/internal/pricing/adapters/memory"
 	pricingusecases "paku-commerce/internal/pricing/usecases"
-	promotionsmemory "paku-commerce/internal/promotions/adapters/memory"
 	promotionsusecases "paku-commerce/internal/promotions/usecases"
 )
 
 // WireCheckoutHandlers construye todas las dependencias y retorna handlers.
 func WireCheckoutHandlers() *CheckoutHandlers {
 	// Repos (singletons)
-	serviceRepo := servicememory.NewServiceRepository()
-	priceRuleRepo := pricingmemory.NewPriceRuleRepository()
-	promotionsRepo := promotionsmemory.NewPromotionsRepository()
+	serviceRepo := runtime.ServiceRepoSingleton
+	priceRuleRepo := runtime.PriceRuleRepoSingleton
+	promotionsRepo := runtime.PromotionsRepoSingleton
 	orderRepo := runtime.OrderRepoSingleton
 	cartRepo := runtime.CartRepoSingleton
+	incidentRepo := runtime.IncidentRepoSingleton
+	paymentIntentRepo := runtime.PaymentIntentRepoSingleton
 
 	// Booking stub (no-op)
 	bookingClient := &platformbooking.StubClient{}
 
+	// Payments (stub o Culqi según CULQI_SECRET_KEY)
+	paymentsClient := runtime.PaymentsClientSingleton
+
+	// Cola de revisión manual (side effects que no se pudieron completar)
+	recordIncidentUC := &checkoutusecases.RecordIncident{
+		Repo: incidentRepo,
+		Now:  nil, // usa time.Now() por defecto
+	}
+
 	// Usecases: pricing
 	quoteItemsUC := &pricingusecases.QuoteItems{
 		RuleRepo: priceRuleRepo,
@@ -35,9 +47,11 @@ func WireCheckoutHandlers() *CheckoutHandlers {
 
 	// Usecases: checkout
 	quoteCheckoutUC := &checkoutusecases.QuoteCheckout{
-		ServiceRepo:  serviceRepo,
-		PriceQuoteUC: quoteItemsUC,
-		PromotionsUC: applyDiscountsUC,
+		ServiceRepo:   serviceRepo,
+		PriceRuleRepo: priceRuleRepo,
+		PriceQuoteUC:  quoteItemsUC,
+		PromotionsUC:  applyDiscountsUC,
+		Pets:          runtime.PetsClientSingleton,
 	}
 
 	createOrderUC := &checkoutusecases.CreateOrder{
@@ -47,21 +61,215 @@ func WireCheckoutHandlers() *CheckoutHandlers {
 	}
 
 	confirmPaymentUC := &checkoutusecases.ConfirmPayment{
-		Repo:    orderRepo,
-		Booking: bookingClient,
-		Now:     nil, // usa time.Now() por defecto
+		Repo:      orderRepo,
+		Booking:   bookingClient,
+		Payments:  paymentsClient,
+		HoldRetry: runtime.HoldRetryPolicyFromEnv(),
+		Now:       nil, // usa time.Now() por defecto
 	}
 
 	startCheckoutUC := &checkoutusecases.StartCheckout{
 		CartRepo:      cartRepo,
 		Booking:       bookingClient,
+		Slots:         bookingClient,
 		CreateOrderUC: createOrderUC,
+		Tx:            runtime.TxManagerSingleton,
+
+		RecordIncidentUC: recordIncidentUC,
+	}
+
+	recordAppointmentOutcomeUC := &checkoutusecases.RecordAppointmentOutcome{
+		Repo: orderRepo,
+		Now:  nil, // usa time.Now() por defecto
+	}
+
+	// Reembolsos: salen por el proveedor que capturó el cobro (tarjeta o QR)
+	refundOrderUC := &checkoutusecases.RefundOrder{
+		Repo:       orderRepo,
+		Payments:   paymentsClient,
+		QRPayments: runtime.QRPaymentsSingleton,
+		Locker:     runtime.LockerSingleton,
+
+		RecordIncidentUC: recordIncidentUC,
+		Now:              nil, // usa time.Now() por defecto
+	}
+
+	requestCancellationUC := &checkoutusecases.RequestCancellation{
+		Repo:             orderRepo,
+		Booking:          bookingClient,
+		Policy:           runtime.CancellationPolicyFromEnv(),
+		RefundOrderUC:    refundOrderUC,
+		RecordIncidentUC: recordIncidentUC,
+
+		CancelPaymentIntentUC: newCancelPendingPaymentIntent(recordIncidentUC),
+		Now:                   nil, // usa time.Now() por defecto
+	}
+
+	retryIncidentUC := &checkoutusecases.RetryIncident{
+		Repo:             incidentRepo,
+		Orders:           orderRepo,
+		Booking:          bookingClient,
+		RefundOrderUC:    refundOrderUC,
+		RecordIncidentUC: recordIncidentUC,
+
+		CancelPaymentIntentUC: newCancelPendingPaymentIntent(nil),
+		Now:                   nil, // usa time.Now() por defecto
+	}
+
+	// Pagos QR (Yape/Plin): el cobro se verifica contra el proveedor QR, no el de tarjetas.
+	// Sin proveedor QR configurado no hay usecases QR y sus rutas no se registran.
+	var (
+		syncPaymentIntentUC   *checkoutusecases.SyncPaymentIntent
+		createPaymentIntentUC *checkoutusecases.CreatePaymentIntent
+		getPaymentIntentUC    *checkoutusecases.GetPaymentIntent
+	)
+	if qrPayments := runtime.QRPaymentsSingleton; qrPayments != nil {
+		syncPaymentIntentUC = &checkoutusecases.SyncPaymentIntent{
+			Intents: paymentIntentRepo,
+			Orders:  orderRepo,
+			QR:      qrPayments,
+			ConfirmPaymentUC: &checkoutusecases.ConfirmPayment{
+				Repo:      orderRepo,
+				Booking:   bookingClient,
+				Payments:  qrPayments,
+				Provider:  checkoutdomain.PaymentProviderQR,
+				HoldRetry: runtime.HoldRetryPolicyFromEnv(),
+			},
+			RecordIncidentUC: recordIncidentUC,
+			Now:              nil, // usa time.Now() por defecto
+		}
+
+		createPaymentIntentUC = &checkoutusecases.CreatePaymentIntent{
+			Orders:  orderRepo,
+			Intents: paymentIntentRepo,
+			QR:      qrPayments,
+			SyncUC:  syncPaymentIntentUC,
+			Now:     nil, // usa time.Now() por defecto
+		}
+
+		getPaymentIntentUC = &checkoutusecases.GetPaymentIntent{
+			Orders:  orderRepo,
+			Intents: paymentIntentRepo,
+			SyncUC:  syncPaymentIntentUC,
+		}
+	}
+
+	handlePaymentWebhookUC := &checkoutusecases.HandlePaymentWebhook{
+		Inbox:               runtime.PaymentEventInboxSingleton,
+		Orders:              orderRepo,
+		ConfirmPaymentUC:    confirmPaymentUC,
+		SyncPaymentIntentUC: syncPaymentIntentUC,
+		Now:                 nil, // usa time.Now() por defecto
 	}
 
 	return &CheckoutHandlers{
-		QuoteCheckoutUC:  quoteCheckoutUC,
-		CreateOrderUC:    createOrderUC,
-		ConfirmPaymentUC: confirmPaymentUC,
-		StartCheckoutUC:  startCheckoutUC,
+		QuoteCheckoutUC:            quoteCheckoutUC,
+		CreateOrderUC:              createOrderUC,
+		ConfirmPaymentUC:           confirmPaymentUC,
+		StartCheckoutUC:            startCheckoutUC,
+		RecordAppointmentOutcomeUC: recordAppointmentOutcomeUC,
+		RequestCancellationUC:      requestCancellationUC,
+		RefundOrderUC:              refundOrderUC,
+		ListOrderRefundsUC:         &checkoutusecases.ListOrderRefunds{Repo: orderRepo},
+		ListOrderEventsUC:          &checkoutusecases.ListOrderEvents{Repo: orderRepo},
+		GetOrderUC:                 &checkoutusecases.GetOrder{Repo: orderRepo},
+		ListUserOrdersUC:           &checkoutusecases.ListUserOrders{Repo: orderRepo},
+		ListIncidentsUC:            &checkoutusecases.ListIncidents{Repo: incidentRepo},
+		RetryIncidentUC:            retryIncidentUC,
+		ResolveIncidentUC:          &checkoutusecases.ResolveIncident{Repo: incidentRepo},
+		HandlePaymentWebhookUC:     handlePaymentWebhookUC,
+		CreatePaymentIntentUC:      createPaymentIntentUC,
+		GetPaymentIntentUC:         getPaymentIntentUC,
+		WebhookVerifiers:           runtime.PaymentWebhookVerifiersFromEnv(),
+		AdminToken:                 runtime.WorkersConfigFromEnv().AdminToken,
+	}
+}
+
+// WireOrderExpiryWorker construye el barrido periódico de órdenes pending_payment vencidas.
+func WireOrderExpiryWorker(interval time.Duration) *checkoutworker.OrderExpiryWorker {
+	orderRepo := runtime.OrderRepoSingleton
+	recordIncidentUC := &checkoutusecases.RecordIncident{Repo: runtime.IncidentRepoSingleton}
+
+	return &checkoutworker.OrderExpiryWorker{
+		ExpirePendingOrdersUC: &checkoutusecases.ExpirePendingOrders{
+			Repo: orderRepo,
+			CancelOrderUC: &checkoutusecases.CancelOrder{
+				Repo:             orderRepo,
+				Booking:          &platformbooking.StubClient{},
+				RecordIncidentUC: recordIncidentUC,
+
+				CancelPaymentIntentUC: newCancelPendingPaymentIntent(recordIncidentUC),
+			},
+			Locker: runtime.LockerSingleton,
+		},
+		Interval: interval,
+	}
+}
+
+// WireHoldRetryWorker construye los reintentos periódicos de ConfirmHold de órdenes
+// cobradas sin hold confirmado (payment_received_booking_failed).
+func WireHoldRetryWorker(interval time.Duration) *checkoutworker.HoldRetryWorker {
+	orderRepo := runtime.OrderRepoSingleton
+
+	return &checkoutworker.HoldRetryWorker{
+		RetryHoldConfirmationsUC: &checkoutusecases.RetryHoldConfirmations{
+			Repo:    orderRepo,
+			Booking: &platformbooking.StubClient{},
+			RefundOrderUC: &checkoutusecases.RefundOrder{
+				Repo:       orderRepo,
+				Payments:   runtime.PaymentsClientSingleton,
+				QRPayments: runtime.QRPaymentsSingleton,
+				Locker:     runtime.LockerSingleton,
+
+				RecordIncidentUC: &checkoutusecases.RecordIncident{Repo: runtime.IncidentRepoSingleton},
+			},
+			RecordIncidentUC: &checkoutusecases.RecordIncident{Repo: runtime.IncidentRepoSingleton},
+			Policy:           runtime.HoldRetryPolicyFromEnv(),
+			AutoRefund:       runtime.HoldFailureAutoRefundFromEnv(),
+			Locker:           runtime.LockerSingleton,
+		},
+		Interval: interval,
+	}
+}
+
+// WirePaymentIntentWorker construye el polling periódico de los pagos QR (Yape/Plin) pendientes.
+// Requiere un proveedor QR configurado (runtime.QRPaymentsSingleton no nil).
+func WirePaymentIntentWorker(interval time.Duration) *checkoutworker.PaymentIntentWorker {
+	orderRepo := runtime.OrderRepoSingleton
+	paymentIntentRepo := runtime.PaymentIntentRepoSingleton
+	qrPayments := runtime.QRPaymentsSingleton
+
+	return &checkoutworker.PaymentIntentWorker{
+		SyncPendingPaymentIntentsUC: &checkoutusecases.SyncPendingPaymentIntents{
+			Intents: paymentIntentRepo,
+			SyncUC: &checkoutusecases.SyncPaymentIntent{
+				Intents: paymentIntentRepo,
+				Orders:  orderRepo,
+				QR:      qrPayments,
+				ConfirmPaymentUC: &checkoutusecases.ConfirmPayment{
+					Repo:      orderRepo,
+					Booking:   &platformbooking.StubClient{},
+					Payments:  qrPayments,
+					Provider:  checkoutdomain.PaymentProviderQR,
+					HoldRetry: runtime.HoldRetryPolicyFromEnv(),
+				},
+				RecordIncidentUC: &checkoutusecases.RecordIncident{Repo: runtime.IncidentRepoSingleton},
+			},
+			Locker: runtime.LockerSingleton,
+		},
+		Interval: interval,
+	}
+}
+
+// newCancelPendingPaymentIntent construye la anulación del QR pendiente de las órdenes
+// canceladas. Sin proveedor QR configurado no hay intentos que anular y retorna nil.
+func newCancelPendingPaymentIntent(recordIncidentUC *checkoutusecases.RecordIncident) *checkoutusecases.CancelPendingPaymentIntent {
+	if runtime.QRPaymentsSingleton == nil {
+		return nil
+	}
+	return &checkoutusecases.CancelPendingPaymentIntent{
+		Intents:          runtime.PaymentIntentRepoSingleton,
+		QR:               runtime.QRPaymentsSingleton,
+		RecordIncidentUC: recordIncidentUC,
 	}
 }
@@ -1,10 +1,93 @@
 package payments
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+var (
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrPaymentAmountMismatch   = errors.New("payment amount does not match order total")
+	ErrPaymentCurrencyMismatch = errors.New("payment currency does not match order currency")
+	ErrPaymentNotCompleted     = errors.New("payment is not completed")
+
+	// ErrCardDeclined indica que el emisor rechazó la tarjeta.
+	ErrCardDeclined = errors.New("card declined")
+	// ErrAuthenticationRequired indica que el cobro requiere autenticación 3DS del titular.
+	ErrAuthenticationRequired = errors.New("card authentication (3DS) required")
+	// ErrPaymentsUnavailable indica que el proveedor de pagos está caído o no respondió.
+	ErrPaymentsUnavailable = errors.New("payment provider unavailable")
+)
+
+// ChargeStatus es el estado de un cobro en el proveedor.
+type ChargeStatus string
+
+const (
+	ChargeStatusPending   ChargeStatus = "pending"
+	ChargeStatusSucceeded ChargeStatus = "succeeded"
+	ChargeStatusFailed    ChargeStatus = "failed"
+	ChargeStatusExpired   ChargeStatus = "expired"   // cobro QR que venció sin pago
+	ChargeStatusCancelled ChargeStatus = "cancelled" // cobro QR anulado antes de pagarse
+)
+
+// Charge es un cobro tal como lo reporta el proveedor.
+type Charge struct {
+	PaymentRef string
+	Amount     pricingdomain.Money
+	Status     ChargeStatus
+}
+
+// Verify compara el cobro con lo que la orden espera cobrar.
+func (c Charge) Verify(expected pricingdomain.Money) error {
+	if c.Amount.Currency != expected.Currency {
+		return fmt.Errorf("%w: charged %s, expected %s", ErrPaymentCurrencyMismatch, c.Amount.Currency, expected.Currency)
+	}
+	if c.Amount.Amount != expected.Amount {
+		return fmt.Errorf("%w: charged %d, expected %d", ErrPaymentAmountMismatch, c.Amount.Amount, expected.Amount)
+	}
+	if c.Status != ChargeStatusSucceeded {
+		return fmt.Errorf("%w: status %s", ErrPaymentNotCompleted, c.Status)
+	}
+	return nil
+}
+
+// CardChargeRequest describe un cobro con una tarjeta tokenizada en el frontend.
+type CardChargeRequest struct {
+	Token   string              // token de un solo uso de la tarjeta
+	Email   string              // email del titular (lo exige el proveedor)
+	Amount  pricingdomain.Money // en unidades mínimas (céntimos)
+	OrderID string              // referencia de la orden en la metadata del cobro
+}
+
+// CardCharger cobra tarjetas tokenizadas. Retorna ErrCardDeclined si el emisor rechaza
+// la tarjeta y ErrAuthenticationRequired si el titular debe autenticarse (3DS) antes de reintentar.
+type CardCharger interface {
+	ChargeCard(ctx context.Context, req CardChargeRequest) (Charge, error)
+}
+
+// RefundRequest describe un reembolso a emitir sobre un pago.
+type RefundRequest struct {
+	PaymentRef     string
+	Amount         pricingdomain.Money
+	IdempotencyKey string // el proveedor no emite dos reembolsos con la misma clave
+}
+
+// RefundResult es la respuesta del proveedor a un reembolso.
+type RefundResult struct {
+	ProviderRef string // ID del reembolso en el proveedor
+}
 
 // PaymentsClient define la integración con el proveedor de pagos.
-// TODO: implementar en fase de integración
 type PaymentsClient interface {
-	// ValidatePayment verifica que una referencia de pago sea válida.
-	ValidatePayment(ctx context.Context, paymentRef string) error
+	// ValidatePayment consulta el cobro paymentRef y verifica que esté completado por
+	// expected (monto y moneda). Retorna ErrPaymentNotFound si el proveedor no lo conoce;
+	// ErrPaymentAmountMismatch, ErrPaymentCurrencyMismatch o ErrPaymentNotCompleted si no coincide.
+	ValidatePayment(ctx context.Context, paymentRef string, expected pricingdomain.Money) error
+
+	// Refund devuelve Amount del pago PaymentRef. Reintentar con la misma
+	// IdempotencyKey retorna el reembolso ya emitido.
+	Refund(ctx context.Context, req RefundRequest) (RefundResult, error)
 }
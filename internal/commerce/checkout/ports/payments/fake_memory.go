@@ -0,0 +1,72 @@
+package payments
+
+import (
+	"context"
+	"sync"
+
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// FakeProvider es un proveedor de pagos en memoria para tests: solo conoce los cobros
+// registrados con AddCharge y lleva la cuenta de lo reembolsado sobre cada uno.
+type FakeProvider struct {
+	mu       sync.Mutex
+	charges  map[string]Charge
+	refunds  map[string]RefundResult // por IdempotencyKey
+	refunded map[string]int64        // monto reembolsado por PaymentRef
+}
+
+// NewFakeProvider crea un proveedor de pagos en memoria sin cobros.
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		charges:  make(map[string]Charge),
+		refunds:  make(map[string]RefundResult),
+		refunded: make(map[string]int64),
+	}
+}
+
+// AddCharge registra (o reemplaza) un cobro en el proveedor.
+func (p *FakeProvider) AddCharge(charge Charge) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.charges[charge.PaymentRef] = charge
+}
+
+// ValidatePayment verifica el cobro registrado contra expected.
+func (p *FakeProvider) ValidatePayment(ctx context.Context, paymentRef string, expected pricingdomain.Money) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, exists := p.charges[paymentRef]
+	if !exists {
+		return ErrPaymentNotFound
+	}
+	return charge.Verify(expected)
+}
+
+// Refund emite un reembolso idempotente por IdempotencyKey sobre un cobro registrado.
+func (p *FakeProvider) Refund(ctx context.Context, req RefundRequest) (RefundResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, exists := p.refunds[req.IdempotencyKey]; exists {
+		return result, nil
+	}
+	if _, exists := p.charges[req.PaymentRef]; !exists {
+		return RefundResult{}, ErrPaymentNotFound
+	}
+
+	result := RefundResult{ProviderRef: "re_" + req.IdempotencyKey}
+	p.refunds[req.IdempotencyKey] = result
+	p.refunded[req.PaymentRef] += req.Amount.Amount
+	return result, nil
+}
+
+// Refunded retorna el monto total reembolsado sobre paymentRef.
+func (p *FakeProvider) Refunded(paymentRef string) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.refunded[paymentRef]
+}
@@ -0,0 +1,46 @@
+package payments
+
+import (
+	"context"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// QRPaymentRequest describe un cobro por QR de billetera (Yape/Plin).
+type QRPaymentRequest struct {
+	Reference string // ID del intento de pago: el proveedor no crea dos cobros con la misma referencia
+	OrderID   string
+	Method    checkoutdomain.PaymentMethod
+	Amount    pricingdomain.Money
+	ExpiresAt time.Time // el QR deja de aceptar pagos después de este instante
+}
+
+// QRPayment es un cobro por QR tal como lo reporta el proveedor.
+type QRPayment struct {
+	PaymentRef    string
+	QRData        string // contenido del QR a mostrar al comprador
+	Amount        pricingdomain.Money
+	Status        ChargeStatus // pending hasta que llega la transferencia, falla o vence
+	FailureReason string       // motivo si Status es failed
+	PaidAt        *time.Time   // cuándo llegó la transferencia (nil si no llegó)
+}
+
+// QRPaymentsClient integra un proveedor de pagos por QR. El cobro es asíncrono: se crea
+// pending y se consulta (o se recibe por webhook) hasta que la transferencia llega.
+// Como PaymentsClient, verifica los cobros al confirmar la orden y emite sus reembolsos.
+type QRPaymentsClient interface {
+	PaymentsClient
+
+	// CreateQRPayment genera el QR del cobro. Reintentar con la misma Reference retorna
+	// el cobro ya creado.
+	CreateQRPayment(ctx context.Context, req QRPaymentRequest) (QRPayment, error)
+
+	// GetQRPayment consulta el estado del cobro (ErrPaymentNotFound si no existe).
+	GetQRPayment(ctx context.Context, paymentRef string) (QRPayment, error)
+
+	// CancelQRPayment anula un cobro pending: el QR deja de aceptar pagos. Retorna el cobro
+	// con su estado final; si ya se resolvió (p. ej. la transferencia llegó), no lo modifica.
+	CancelQRPayment(ctx context.Context, paymentRef string) (QRPayment, error)
+}
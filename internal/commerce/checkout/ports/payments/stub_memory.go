@@ -0,0 +1,20 @@
+package payments
+
+import (
+	"context"
+
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// StubPaymentsClient es un stub no-op de PaymentsClient para desarrollo.
+type StubPaymentsClient struct{}
+
+// ValidatePayment acepta cualquier pago (stub).
+func (s *StubPaymentsClient) ValidatePayment(ctx context.Context, paymentRef string, expected pricingdomain.Money) error {
+	return nil
+}
+
+// Refund acepta cualquier reembolso; el ID se deriva de la clave de idempotencia (stub).
+func (s *StubPaymentsClient) Refund(ctx context.Context, req RefundRequest) (RefundResult, error) {
+	return RefundResult{ProviderRef: "refund_" + req.IdempotencyKey}, nil
+}
@@ -0,0 +1,20 @@
+package payments
+
+import (
+	"errors"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+var (
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrInvalidWebhookPayload   = errors.New("invalid webhook payload")
+)
+
+// WebhookVerifier autentica y normaliza los webhooks de un proveedor de pagos.
+type WebhookVerifier interface {
+	// VerifyWebhook valida la firma del payload y lo traduce a un evento de checkout.
+	// Retorna ErrInvalidWebhookSignature si la firma no corresponde y
+	// ErrInvalidWebhookPayload si el cuerpo no se puede interpretar.
+	VerifyWebhook(payload []byte, signature string) (checkoutdomain.PaymentEvent, error)
+}
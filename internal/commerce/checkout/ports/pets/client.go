@@ -1,14 +1,7 @@
 package pets
 
-import (
-	"context"
+import platformpets "paku-commerce/internal/commerce/platform/pets"
 
-	servicedomain "paku-commerce/internal/commerce/service/domain"
-)
-
-// PetsClient define la integración con el servicio de mascotas.
-// TODO: implementar en fase de integración
-type PetsClient interface {
-	// GetPetProfile obtiene el perfil de una mascota por ID.
-	GetPetProfile(ctx context.Context, petID string) (servicedomain.PetProfile, error)
-}
+// PetsClient es un alias del interface unificado en platform/pets.
+// Deprecated: usar directamente platform/pets.Client
+type PetsClient = platformpets.Client
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
 	platformbooking "paku-commerce/internal/commerce/platform/booking"
@@ -10,6 +11,7 @@ import (
 // CancelOrderInput contiene el ID de la orden a cancelar.
 type CancelOrderInput struct {
 	OrderID string
+	Reason  checkoutdomain.CancelReason // "" = CancelReasonRequested
 }
 
 // CancelOrderOutput contiene la orden cancelada.
@@ -19,12 +21,35 @@ type CancelOrderOutput struct {
 
 // CancelOrder cancela una orden de forma idempotente.
 type CancelOrder struct {
-	Repo    checkoutdomain.OrderRepository
-	Booking platformbooking.Client
+	Repo             checkoutdomain.OrderRepository
+	Booking          platformbooking.Client
+	RecordIncidentUC *RecordIncident // opcional: registra en la cola de revisión los holds que no se liberaron
+	// CancelPaymentIntentUC (opcional) anula en el proveedor el QR pendiente de la orden.
+	CancelPaymentIntentUC *CancelPendingPaymentIntent
+	Now                   func() time.Time
 }
 
-// Execute cancela la orden y libera el hold de booking si existe.
+// Execute cancela la orden, libera el hold de booking si existe y anula el QR pendiente.
+// Si otra operación modificó la orden en paralelo, recarga y reintenta.
 func (uc CancelOrder) Execute(ctx context.Context, input CancelOrderInput) (CancelOrderOutput, error) {
+	var output CancelOrderOutput
+	err := retryOnVersionConflict(func() error {
+		var err error
+		output, err = uc.execute(ctx, input)
+		return err
+	})
+	if err != nil {
+		return CancelOrderOutput{}, err
+	}
+
+	// Anular el QR pendiente (best-effort; repetir la cancelación reintenta la anulación)
+	if uc.CancelPaymentIntentUC != nil {
+		_, _ = uc.CancelPaymentIntentUC.Execute(ctx, CancelPendingPaymentIntentInput{OrderID: output.Order.ID})
+	}
+	return output, nil
+}
+
+func (uc CancelOrder) execute(ctx context.Context, input CancelOrderInput) (CancelOrderOutput, error) {
 	// 1. Cargar la orden
 	order, err := uc.Repo.GetByID(ctx, input.OrderID)
 	if err != nil {
@@ -35,7 +60,15 @@ func (uc CancelOrder) Execute(ctx context.Context, input CancelOrderInput) (Canc
 	wasAlreadyCancelled := order.Status == checkoutdomain.OrderStatusCancelled
 
 	// 3. Marcar como cancelada
-	err = order.MarkCancelled()
+	reason := input.Reason
+	if reason == "" {
+		reason = checkoutdomain.CancelReasonRequested
+	}
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+	err = order.MarkCancelled(reason, now)
 	if err != nil {
 		return CancelOrderOutput{}, err
 	}
@@ -45,18 +78,24 @@ func (uc CancelOrder) Execute(ctx context.Context, input CancelOrderInput) (Canc
 		return CancelOrderOutput{Order: order}, nil
 	}
 
-	// 5. Cancelar hold de booking si existe (solo en transición real)
-	if order.BookingHoldID != nil && *order.BookingHoldID != "" {
-		// No fallar si la cancelación de hold falla (best-effort)
-		// TODO: confirm with architect si queremos retry o compensación
-		_ = uc.Booking.CancelHold(ctx, *order.BookingHoldID)
-	}
-
-	// 6. Persistir orden cancelada
+	// 5. Persistir orden cancelada (compare-and-swap: si se pagó en paralelo, conflicto)
 	updatedOrder, err := uc.Repo.Update(ctx, order)
 	if err != nil {
 		return CancelOrderOutput{}, err
 	}
 
+	// 6. Cancelar hold de booking si existe (solo tras persistir la transición)
+	if updatedOrder.BookingHoldID != nil && *updatedOrder.BookingHoldID != "" {
+		// No fallar si la cancelación de hold falla: queda en la cola de revisión
+		if err := uc.Booking.CancelHold(ctx, *updatedOrder.BookingHoldID); err != nil {
+			reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+				OrderID:   updatedOrder.ID,
+				HoldID:    *updatedOrder.BookingHoldID,
+				Operation: checkoutdomain.IncidentOperationCancelHold,
+				Err:       err,
+			})
+		}
+	}
+
 	return CancelOrderOutput{Order: updatedOrder}, nil
 }
@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// racingOrderRepo simula un ConfirmPayment concurrente que gana justo antes del primer Update.
+type racingOrderRepo struct {
+	*checkoutmemory.OrderRepository
+	raced bool
+}
+
+func (r *racingOrderRepo) Update(ctx context.Context, order checkoutdomain.Order) (checkoutdomain.Order, error) {
+	if !r.raced {
+		r.raced = true
+		concurrent, _ := r.OrderRepository.GetByID(ctx, order.ID)
+		_ = concurrent.MarkPaid("tx_concurrent", time.Now())
+		if _, err := r.OrderRepository.Update(ctx, concurrent); err != nil {
+			return checkoutdomain.Order{}, err
+		}
+	}
+	return r.OrderRepository.Update(ctx, order)
+}
+
+func TestCancelOrder_ConcurrentPayment_DoesNotOverwritePaidOrder(t *testing.T) {
+	repo := &racingOrderRepo{OrderRepository: checkoutmemory.NewOrderRepository()}
+	order := createTestOrder(t, repo)
+
+	holdID := "hold_race"
+	order.BookingHoldID = &holdID
+	if _, err := repo.OrderRepository.Update(context.Background(), order); err != nil {
+		t.Fatalf("failed to set hold: %v", err)
+	}
+
+	booking := &recordingBookingClient{}
+	uc := &CancelOrder{Repo: repo, Booking: booking}
+
+	_, err := uc.Execute(context.Background(), CancelOrderInput{OrderID: order.ID})
+	if !errors.Is(err, checkoutdomain.ErrInvalidOrderState) {
+		t.Fatalf("expected ErrInvalidOrderState after reload, got: %v", err)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected order to remain paid, got: %v", stored.Status)
+	}
+	if len(booking.cancelled) != 0 {
+		t.Errorf("expected hold not to be cancelled, got: %v", booking.cancelled)
+	}
+}
+
+func TestOrderRepository_StaleUpdate_ReturnsVersionConflict(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTestOrder(t, repo)
+
+	if order.Version != 1 {
+		t.Fatalf("expected version 1 after create, got: %d", order.Version)
+	}
+
+	updated, err := repo.Update(context.Background(), order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("expected version 2 after update, got: %d", updated.Version)
+	}
+
+	// Segunda escritura con la versión vieja
+	if _, err := repo.Update(context.Background(), order); !errors.Is(err, checkoutdomain.ErrOrderVersionConflict) {
+		t.Errorf("expected ErrOrderVersionConflict, got: %v", err)
+	}
+}
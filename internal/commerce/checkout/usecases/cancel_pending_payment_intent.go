@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+)
+
+// CancelPendingPaymentIntentInput contiene la orden cuyo QR pendiente se anula.
+type CancelPendingPaymentIntentInput struct {
+	OrderID string
+}
+
+// CancelPendingPaymentIntentOutput contiene el intento después de la anulación.
+type CancelPendingPaymentIntentOutput struct {
+	Intent    checkoutdomain.PaymentIntent
+	Cancelled bool // false = no había QR pendiente o el proveedor ya lo había resuelto
+}
+
+// CancelPendingPaymentIntent anula en el proveedor el QR pendiente de una orden que se
+// canceló, para que el cliente no pueda pagar una orden que ya no lo acepta. Toma el
+// mismo claim que SyncPaymentIntent, así que nunca corre en paralelo con un sync.
+type CancelPendingPaymentIntent struct {
+	Intents          checkoutdomain.PaymentIntentRepository
+	QR               payments.QRPaymentsClient
+	RecordIncidentUC *RecordIncident // opcional: registra en la cola de revisión los QR que no se anularon
+	Now              func() time.Time
+}
+
+// Execute anula el QR pendiente de la orden, si existe. Si el proveedor ya lo resolvió
+// (pagado o rechazado) libera el claim y deja el intento al sync, que concilia el pago
+// con la orden cancelada (incidente confirm_payment).
+func (uc CancelPendingPaymentIntent) Execute(ctx context.Context, input CancelPendingPaymentIntentInput) (CancelPendingPaymentIntentOutput, error) {
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	// 1. Buscar el QR pendiente de la orden
+	intent, err := uc.Intents.GetLatestByOrder(ctx, input.OrderID)
+	if errors.Is(err, checkoutdomain.ErrPaymentIntentNotFound) {
+		return CancelPendingPaymentIntentOutput{}, nil
+	}
+	if err != nil {
+		return CancelPendingPaymentIntentOutput{}, err
+	}
+	if !intent.IsPending() {
+		return CancelPendingPaymentIntentOutput{Intent: intent}, nil
+	}
+
+	// 2. Reclamar el intento (si un sync lo tiene, ese sync lo resuelve)
+	intent, claimed, err := uc.Intents.Claim(ctx, intent.ID, now, now.Add(-PaymentIntentClaimTimeout))
+	if err != nil {
+		return CancelPendingPaymentIntentOutput{}, err
+	}
+	if !claimed {
+		return CancelPendingPaymentIntentOutput{Intent: intent}, nil
+	}
+
+	// 3. Anular en el proveedor
+	qr, err := uc.QR.CancelQRPayment(ctx, intent.ProviderRef)
+	if err != nil {
+		reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+			OrderID:   intent.OrderID,
+			Operation: checkoutdomain.IncidentOperationCancelPaymentIntent,
+			Err:       err,
+		})
+		return uc.release(ctx, intent, err)
+	}
+
+	// 4. Cerrar el intento según lo que reportó el proveedor
+	switch qr.Status {
+	case payments.ChargeStatusCancelled:
+		err = intent.MarkCancelled(now)
+	case payments.ChargeStatusExpired:
+		err = intent.MarkExpired(now)
+	default:
+		// Pagado o rechazado antes de la anulación: lo concilia SyncPaymentIntent
+		return uc.release(ctx, intent, nil)
+	}
+	if err != nil {
+		return CancelPendingPaymentIntentOutput{}, err
+	}
+	intent.ClaimedAt = nil
+	updated, err := uc.Intents.Update(ctx, intent)
+	if err != nil {
+		return CancelPendingPaymentIntentOutput{}, err
+	}
+	return CancelPendingPaymentIntentOutput{Intent: updated, Cancelled: updated.Status == checkoutdomain.PaymentIntentStatusCancelled}, nil
+}
+
+// release suelta el claim para que el sync (o una cancelación repetida) retome el intento.
+func (uc CancelPendingPaymentIntent) release(ctx context.Context, intent checkoutdomain.PaymentIntent, cause error) (CancelPendingPaymentIntentOutput, error) {
+	intent.ClaimedAt = nil
+	if released, err := uc.Intents.Update(ctx, intent); err == nil {
+		intent = released
+	}
+	return CancelPendingPaymentIntentOutput{Intent: intent}, cause
+}
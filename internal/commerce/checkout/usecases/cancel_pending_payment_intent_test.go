@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	bookingstub "paku-commerce/internal/commerce/checkout/ports/booking"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+)
+
+func TestCancelOrder_CancelsPendingQRAtProvider(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	intent := createQRIntent(t, f, order)
+
+	uc := CancelOrder{
+		Repo:    f.orders,
+		Booking: &bookingstub.StubBookingClient{},
+		CancelPaymentIntentUC: &CancelPendingPaymentIntent{
+			Intents: f.intents,
+			QR:      f.qr,
+			Now:     func() time.Time { return f.now },
+		},
+	}
+	if _, err := uc.Execute(context.Background(), CancelOrderInput{OrderID: order.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, _ := f.intents.GetByID(context.Background(), intent.ID)
+	if stored.Status != checkoutdomain.PaymentIntentStatusCancelled || stored.ClaimedAt != nil {
+		t.Errorf("expected intent cancelled and unclaimed, got: %+v", stored)
+	}
+	if qr, _ := f.qr.GetQRPayment(context.Background(), intent.ProviderRef); qr.Status != payments.ChargeStatusCancelled {
+		t.Errorf("expected QR cancelled at the provider, got: %s", qr.Status)
+	}
+	// El QR anulado ya no se puede pagar
+	if err := f.qr.Complete(intent.ProviderRef); err == nil {
+		t.Error("expected a cancelled QR to reject the payment")
+	}
+}
+
+func TestCancelPendingPaymentIntent_PaidBeforeCancel_LeavesItToSync(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	intent := createQRIntent(t, f, order)
+	if err := f.qr.Complete(intent.ProviderRef); err != nil {
+		t.Fatalf("failed to complete QR payment: %v", err)
+	}
+
+	uc := CancelPendingPaymentIntent{Intents: f.intents, QR: f.qr, Now: func() time.Time { return f.now }}
+	output, err := uc.Execute(context.Background(), CancelPendingPaymentIntentInput{OrderID: order.ID})
+	if err != nil || output.Cancelled {
+		t.Fatalf("expected nothing cancelled, got: %+v %v", output, err)
+	}
+	if !output.Intent.IsPending() || output.Intent.ClaimedAt != nil {
+		t.Errorf("expected intent pending and released for the sync, got: %+v", output.Intent)
+	}
+}
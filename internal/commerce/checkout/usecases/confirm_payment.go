@@ -5,6 +5,7 @@ import (
 	"time"
 
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
 	platformbooking "paku-commerce/internal/commerce/platform/booking"
 )
 
@@ -20,21 +21,25 @@ type ConfirmPaymentOutput struct {
 	Order checkoutdomain.Order
 }
 
-// ConfirmPayment confirma el pago de una orden de forma idempotente.
+// ConfirmPayment confirma el pago de una orden de forma idempotente, después de verificar
+// con el proveedor que el cobro existe, está completado y corresponde al total de la orden.
+// Si booking no confirma el hold, el cobro igual se registra: la orden queda
+// payment_received_booking_failed y RetryHoldConfirmations reintenta con backoff.
 type ConfirmPayment struct {
-	Repo    checkoutdomain.OrderRepository
-	Booking platformbooking.Client
-	Now     func() time.Time
+	Repo      checkoutdomain.OrderRepository
+	Booking   platformbooking.Client
+	Payments  payments.PaymentsClient
+	Provider  checkoutdomain.PaymentProvider // proveedor detrás de Payments ("" = tarjeta); queda en la orden
+	HoldRetry checkoutdomain.HoldRetryPolicy // cero = checkoutdomain.DefaultHoldRetryPolicy()
+	Now       func() time.Time
 }
 
 // Execute confirma el pago y actualiza la orden.
+// Si otra operación modificó la orden en paralelo, recarga y reintenta cada escritura por
+// separado: la transición a paid y luego el resultado del hold. Si el registro del hold
+// agota sus reintentos, retorna ErrOrderVersionConflict sin volver a la transición (que
+// ya no haría nada: la orden figura pagada con esta ref).
 func (uc ConfirmPayment) Execute(ctx context.Context, input ConfirmPaymentInput) (ConfirmPaymentOutput, error) {
-	// 1. Cargar la orden
-	order, err := uc.Repo.GetByID(ctx, input.OrderID)
-	if err != nil {
-		return ConfirmPaymentOutput{}, err
-	}
-
 	// Determinar timestamp de pago
 	paidAt := input.PaidAt
 	if paidAt.IsZero() {
@@ -45,35 +50,123 @@ func (uc ConfirmPayment) Execute(ctx context.Context, input ConfirmPaymentInput)
 		}
 	}
 
-	// 2. Intentar marcar como pagada (idempotente)
-	wasAlreadyPaid := order.Status == checkoutdomain.OrderStatusPaid &&
-		order.PaymentRef != nil &&
-		*order.PaymentRef == input.PaymentRef
+	// 1-5. Verificar el cobro y persistir la transición a paid
+	var (
+		order      checkoutdomain.Order
+		transition bool
+	)
+	err := retryOnVersionConflict(func() error {
+		var err error
+		order, transition, err = uc.markPaid(ctx, input, paidAt)
+		return err
+	})
+	if err != nil {
+		return ConfirmPaymentOutput{}, err
+	}
+
+	// Ya estaba pagada con la misma ref: sin side effects
+	if !transition {
+		return ConfirmPaymentOutput{Order: order}, nil
+	}
+
+	// 6. Confirmar hold de booking si existe (solo tras persistir la transición)
+	if order.BookingHoldID == nil || *order.BookingHoldID == "" {
+		return ConfirmPaymentOutput{Order: order}, nil
+	}
+	holdErr := uc.Booking.ConfirmHold(ctx, *order.BookingHoldID)
 
-	err = order.MarkPaid(input.PaymentRef, paidAt)
+	// 7. Registrar el resultado del hold. Si falla, saga: el dinero ya se cobró y el hold
+	// se reintenta en segundo plano (ver RetryHoldConfirmations)
+	var output ConfirmPaymentOutput
+	err = retryOnVersionConflict(func() error {
+		var err error
+		output, err = uc.recordHoldOutcome(ctx, order.ID, input.PaymentRef, paidAt, holdErr)
+		return err
+	})
 	if err != nil {
 		return ConfirmPaymentOutput{}, err
 	}
+	return output, nil
+}
+
+// markPaid verifica el cobro y persiste la orden pagada. transition es false si la orden
+// ya estaba pagada con la misma ref (idempotencia).
+func (uc ConfirmPayment) markPaid(ctx context.Context, input ConfirmPaymentInput, paidAt time.Time) (checkoutdomain.Order, bool, error) {
+	// 1. Cargar la orden
+	order, err := uc.Repo.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return checkoutdomain.Order{}, false, err
+	}
+
+	// 2. Verificar el cobro con el proveedor (solo en transición real: una orden ya
+	// pagada se resuelve por idempotencia o conflicto de payment_ref)
+	if !order.IsPaid() {
+		if err := uc.Payments.ValidatePayment(ctx, input.PaymentRef, order.Total); err != nil {
+			return checkoutdomain.Order{}, false, err
+		}
+	}
+
+	// 3. Intentar marcar como pagada (idempotente)
+	wasAlreadyPaid := order.IsPaid() &&
+		order.PaymentRef != nil &&
+		*order.PaymentRef == input.PaymentRef
+
+	if err := order.MarkPaid(input.PaymentRef, paidAt); err != nil {
+		return checkoutdomain.Order{}, false, err
+	}
 
-	// 3. Si ya estaba pagada con la misma ref, retornar sin side effects
+	// 4. Si ya estaba pagada con la misma ref, retornar sin side effects
 	if wasAlreadyPaid {
+		return order, false, nil
+	}
+	order.PaymentProvider = uc.Provider
+	if order.PaymentProvider == "" {
+		order.PaymentProvider = checkoutdomain.PaymentProviderCard
+	}
+
+	// 5. Persistir el pago (compare-and-swap: si se canceló en paralelo, conflicto y se
+	// reintenta sin haber tocado booking)
+	updatedOrder, err := uc.Repo.Update(ctx, order)
+	if err != nil {
+		return checkoutdomain.Order{}, false, err
+	}
+	return updatedOrder, true, nil
+}
+
+// recordHoldOutcome persiste el resultado de ConfirmHold sobre la orden recién pagada.
+// Recarga la orden en cada intento: si ya no está pagada con este cobro (otra operación
+// la cambió), la retorna sin modificar.
+func (uc ConfirmPayment) recordHoldOutcome(ctx context.Context, orderID, paymentRef string, paidAt time.Time, holdErr error) (ConfirmPaymentOutput, error) {
+	order, err := uc.Repo.GetByID(ctx, orderID)
+	if err != nil {
+		return ConfirmPaymentOutput{}, err
+	}
+	if order.Status != checkoutdomain.OrderStatusPaid || order.PaymentRef == nil || *order.PaymentRef != paymentRef {
 		return ConfirmPaymentOutput{Order: order}, nil
 	}
 
-	// 4. Confirmar hold de booking si existe (solo en transición real)
-	if order.BookingHoldID != nil && *order.BookingHoldID != "" {
-		if err := uc.Booking.ConfirmHold(ctx, *order.BookingHoldID); err != nil {
-			// No persistir cambios si booking falla
-			// TODO: confirm with architect si preferimos estrategia de compensación
+	if holdErr != nil {
+		now := time.Now()
+		if uc.Now != nil {
+			now = uc.Now()
+		}
+		if err := order.MarkBookingFailed(holdErr, now, uc.holdRetryPolicy().NextRetryAt(1, now)); err != nil {
 			return ConfirmPaymentOutput{}, err
 		}
+	} else {
+		order.RecordEvent(checkoutdomain.OrderEventHoldConfirmed, paidAt, map[string]string{"hold_id": *order.BookingHoldID})
 	}
 
-	// 5. Persistir orden actualizada
 	updatedOrder, err := uc.Repo.Update(ctx, order)
 	if err != nil {
 		return ConfirmPaymentOutput{}, err
 	}
-
 	return ConfirmPaymentOutput{Order: updatedOrder}, nil
 }
+
+func (uc ConfirmPayment) holdRetryPolicy() checkoutdomain.HoldRetryPolicy {
+	if uc.HoldRetry.MaxAttempts <= 0 {
+		return checkoutdomain.DefaultHoldRetryPolicy()
+	}
+	return uc.HoldRetry
+}
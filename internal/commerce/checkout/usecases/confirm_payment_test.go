@@ -9,9 +9,11 @@ import (
 	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
 	bookingstub "paku-commerce/internal/commerce/checkout/ports/booking"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
 	servicememory "paku-commerce/internal/commerce/service/adapters/memory"
 	servicedomain "paku-commerce/internal/commerce/service/domain"
 	pricingmemory "paku-commerce/internal/pricing/adapters/memory"
+	pricingdomain "paku-commerce/internal/pricing/domain"
 	pricingusecases "paku-commerce/internal/pricing/usecases"
 	promotionsmemory "paku-commerce/internal/promotions/adapters/memory"
 	promotionsusecases "paku-commerce/internal/promotions/usecases"
@@ -73,10 +75,13 @@ func TestConfirmPayment_FirstConfirmation(t *testing.T) {
 	order := createTestOrder(t, orderRepo)
 
 	fixedNow := time.Date(2026, 1, 12, 12, 0, 0, 0, time.UTC)
+	provider := payments.NewFakeProvider()
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_1", Amount: order.Total, Status: payments.ChargeStatusSucceeded})
 	uc := &ConfirmPayment{
-		Repo:    orderRepo,
-		Booking: &bookingstub.StubBookingClient{},
-		Now:     func() time.Time { return fixedNow },
+		Repo:     orderRepo,
+		Booking:  &bookingstub.StubBookingClient{},
+		Payments: provider,
+		Now:      func() time.Time { return fixedNow },
 	}
 
 	// Execute
@@ -110,10 +115,13 @@ func TestConfirmPayment_Idempotency(t *testing.T) {
 	order := createTestOrder(t, orderRepo)
 
 	fixedNow := time.Date(2026, 1, 12, 12, 0, 0, 0, time.UTC)
+	provider := payments.NewFakeProvider()
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_1", Amount: order.Total, Status: payments.ChargeStatusSucceeded})
 	uc := &ConfirmPayment{
-		Repo:    orderRepo,
-		Booking: &bookingstub.StubBookingClient{},
-		Now:     func() time.Time { return fixedNow },
+		Repo:     orderRepo,
+		Booking:  &bookingstub.StubBookingClient{},
+		Payments: provider,
+		Now:      func() time.Time { return fixedNow },
 	}
 
 	// Primera confirmación
@@ -153,10 +161,13 @@ func TestConfirmPayment_Conflict(t *testing.T) {
 	order := createTestOrder(t, orderRepo)
 
 	fixedNow := time.Date(2026, 1, 12, 12, 0, 0, 0, time.UTC)
+	provider := payments.NewFakeProvider()
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_1", Amount: order.Total, Status: payments.ChargeStatusSucceeded})
 	uc := &ConfirmPayment{
-		Repo:    orderRepo,
-		Booking: &bookingstub.StubBookingClient{},
-		Now:     func() time.Time { return fixedNow },
+		Repo:     orderRepo,
+		Booking:  &bookingstub.StubBookingClient{},
+		Payments: provider,
+		Now:      func() time.Time { return fixedNow },
 	}
 
 	// Primera confirmación con tx_1
@@ -169,7 +180,8 @@ func TestConfirmPayment_Conflict(t *testing.T) {
 		t.Fatalf("unexpected error on first confirm: %v", err)
 	}
 
-	// Segunda confirmación con tx_2 (conflicto)
+	// Segunda confirmación con tx_2 (conflicto, aunque el cobro exista)
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_2", Amount: order.Total, Status: payments.ChargeStatusSucceeded})
 	_, err = uc.Execute(context.Background(), ConfirmPaymentInput{
 		OrderID:    order.ID,
 		PaymentRef: "tx_2",
@@ -181,3 +193,123 @@ func TestConfirmPayment_Conflict(t *testing.T) {
 		t.Errorf("expected ErrPaymentConflict, got: %v", err)
 	}
 }
+
+func TestConfirmPayment_ValidatesChargeWithProvider(t *testing.T) {
+	orderRepo := checkoutmemory.NewOrderRepository()
+	order := createTestOrder(t, orderRepo)
+	provider := payments.NewFakeProvider()
+	uc := &ConfirmPayment{
+		Repo:     orderRepo,
+		Booking:  &bookingstub.StubBookingClient{},
+		Payments: provider,
+	}
+
+	short := order.Total
+	short.Amount--
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_short", Amount: short, Status: payments.ChargeStatusSucceeded})
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_usd", Amount: pricingdomain.NewMoney(order.Total.Amount, "USD"), Status: payments.ChargeStatusSucceeded})
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_pending", Amount: order.Total, Status: payments.ChargeStatusPending})
+
+	cases := map[string]error{
+		"tx_unknown": payments.ErrPaymentNotFound,
+		"tx_short":   payments.ErrPaymentAmountMismatch,
+		"tx_usd":     payments.ErrPaymentCurrencyMismatch,
+		"tx_pending": payments.ErrPaymentNotCompleted,
+	}
+	for paymentRef, expected := range cases {
+		_, err := uc.Execute(context.Background(), ConfirmPaymentInput{OrderID: order.ID, PaymentRef: paymentRef})
+		if !errors.Is(err, expected) {
+			t.Errorf("%s: expected %v, got: %v", paymentRef, expected, err)
+		}
+	}
+	stored, _ := orderRepo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPendingPayment || stored.PaymentRef != nil {
+		t.Fatalf("expected order still pending after rejected payments, got: %v", stored.Status)
+	}
+
+	// El proveedor completa el cobro: ahora sí se confirma
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_pending", Amount: order.Total, Status: payments.ChargeStatusSucceeded})
+	output, err := uc.Execute(context.Background(), ConfirmPaymentInput{OrderID: order.ID, PaymentRef: "tx_pending"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected paid order, got: %v", output.Order.Status)
+	}
+}
+
+// cancellingOrderRepo simula un CancelOrder concurrente que gana justo antes del primer Update.
+type cancellingOrderRepo struct {
+	*checkoutmemory.OrderRepository
+	raced bool
+}
+
+func (r *cancellingOrderRepo) Update(ctx context.Context, order checkoutdomain.Order) (checkoutdomain.Order, error) {
+	if !r.raced {
+		r.raced = true
+		concurrent, _ := r.OrderRepository.GetByID(ctx, order.ID)
+		_ = concurrent.MarkCancelled(checkoutdomain.CancelReasonRequested, time.Now())
+		if _, err := r.OrderRepository.Update(ctx, concurrent); err != nil {
+			return checkoutdomain.Order{}, err
+		}
+	}
+	return r.OrderRepository.Update(ctx, order)
+}
+
+func TestConfirmPayment_ConcurrentCancel_DoesNotConfirmHold(t *testing.T) {
+	repo := &cancellingOrderRepo{OrderRepository: checkoutmemory.NewOrderRepository()}
+	order := createHeldTestOrder(t, repo.OrderRepository)
+
+	provider := payments.NewFakeProvider()
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_1", Amount: order.Total, Status: payments.ChargeStatusSucceeded})
+	booking := &flakyBookingClient{}
+	uc := &ConfirmPayment{Repo: repo, Booking: booking, Payments: provider}
+
+	_, err := uc.Execute(context.Background(), ConfirmPaymentInput{OrderID: order.ID, PaymentRef: "tx_1"})
+	if !errors.Is(err, checkoutdomain.ErrOrderCancelled) {
+		t.Fatalf("expected ErrOrderCancelled after reload, got: %v", err)
+	}
+	if len(booking.confirmed) != 0 {
+		t.Errorf("expected hold not to be confirmed on a cancelled order, got: %v", booking.confirmed)
+	}
+}
+
+// contendedOrderRepo deja pasar el primer Update y luego simula otra escritura antes de cada uno.
+type contendedOrderRepo struct {
+	*checkoutmemory.OrderRepository
+	updates int
+}
+
+func (r *contendedOrderRepo) Update(ctx context.Context, order checkoutdomain.Order) (checkoutdomain.Order, error) {
+	r.updates++
+	if r.updates > 1 {
+		concurrent, _ := r.OrderRepository.GetByID(ctx, order.ID)
+		if _, err := r.OrderRepository.Update(ctx, concurrent); err != nil {
+			return checkoutdomain.Order{}, err
+		}
+	}
+	return r.OrderRepository.Update(ctx, order)
+}
+
+func TestConfirmPayment_HoldOutcomeConflicts_ReturnsConflict(t *testing.T) {
+	repo := &contendedOrderRepo{OrderRepository: checkoutmemory.NewOrderRepository()}
+	order := createHeldTestOrder(t, repo.OrderRepository)
+
+	provider := payments.NewFakeProvider()
+	provider.AddCharge(payments.Charge{PaymentRef: "tx_1", Amount: order.Total, Status: payments.ChargeStatusSucceeded})
+	booking := &flakyBookingClient{failures: 1}
+	uc := &ConfirmPayment{Repo: repo, Booking: booking, Payments: provider}
+
+	// El resultado del hold no se pudo registrar: no se reporta como éxito idempotente
+	_, err := uc.Execute(context.Background(), ConfirmPaymentInput{OrderID: order.ID, PaymentRef: "tx_1"})
+	if !errors.Is(err, checkoutdomain.ErrOrderVersionConflict) {
+		t.Fatalf("expected ErrOrderVersionConflict, got: %v", err)
+	}
+	if booking.failures != 0 || len(booking.confirmed) != 0 {
+		t.Errorf("expected a single ConfirmHold attempt, got failures=%d confirmed=%v", booking.failures, booking.confirmed)
+	}
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid || stored.PaymentRef == nil || *stored.PaymentRef != "tx_1" {
+		t.Errorf("expected the paid transition persisted, got: %v", stored.Status)
+	}
+}
@@ -8,11 +8,15 @@ import (
 	"time"
 
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	pricingdomain "paku-commerce/internal/pricing/domain"
 )
 
 // CreateOrderInput contiene la intención de compra.
 type CreateOrderInput struct {
-	Intent checkoutdomain.PurchaseIntent
+	UserID        string     // dueño de la orden ("" = anónima)
+	SlotID        string     // slot reservado ("" = orden sin cita)
+	AppointmentAt *time.Time // inicio de la cita, si se conoce
+	Intent        checkoutdomain.PurchaseIntent
 }
 
 // CreateOrderOutput contiene la orden creada.
@@ -24,6 +28,7 @@ type CreateOrderOutput struct {
 type CreateOrder struct {
 	QuoteCheckoutUC *QuoteCheckout
 	OrderRepo       checkoutdomain.OrderRepository
+	PaymentTTL      time.Duration // 0 = checkoutdomain.OrderPaymentTTL
 	Now             func() time.Time
 }
 
@@ -38,6 +43,7 @@ func (uc CreateOrder) Execute(ctx context.Context, input CreateOrderInput) (Crea
 	}
 
 	quote := quoteOutput.Quote
+	intent := quoteOutput.Intent // snapshot con perfiles resueltos
 
 	// 2. Construir items de la orden
 	orderItems := make([]checkoutdomain.OrderItem, 0, len(quote.Quote.Items))
@@ -48,15 +54,27 @@ func (uc CreateOrder) Execute(ctx context.Context, input CreateOrderInput) (Crea
 			Qty:       qItem.Qty,
 			UnitPrice: qItem.UnitPrice,
 			LineTotal: qItem.LineTotal,
+			PetID:     qItem.PetID,
 		})
 	}
 
+	orderPets, err := summarizePets(intent, orderItems)
+	if err != nil {
+		return CreateOrderOutput{}, err
+	}
+
 	// 3. Construir orden
 	now := time.Now()
 	if uc.Now != nil {
 		now = uc.Now()
 	}
 
+	paymentTTL := uc.PaymentTTL
+	if paymentTTL <= 0 {
+		paymentTTL = checkoutdomain.OrderPaymentTTL
+	}
+	expiresAt := now.Add(paymentTTL)
+
 	orderID, err := generateOrderID()
 	if err != nil {
 		return CreateOrderOutput{}, fmt.Errorf("failed to generate order ID: %w", err)
@@ -64,16 +82,27 @@ func (uc CreateOrder) Execute(ctx context.Context, input CreateOrderInput) (Crea
 
 	order := checkoutdomain.Order{
 		ID:            orderID,
+		UserID:        input.UserID,
 		Status:        checkoutdomain.OrderStatusPendingPayment,
 		CreatedAt:     now,
-		PetProfile:    input.Intent.PetProfile,
+		PetID:         intent.PetID,
+		PetProfile:    intent.PetProfile,
+		Pets:          orderPets,
 		Items:         orderItems,
 		Subtotal:      quote.OriginalSubtotal, // Subtotal original antes de descuentos
 		TotalDiscount: quote.TotalDiscount,
 		Total:         quote.Total,
-		CouponCode:    input.Intent.CouponCode,
-		BookingHoldID: input.Intent.BookingHoldID,
+		CouponCode:    intent.CouponCode,
+		BookingHoldID: intent.BookingHoldID,
+		SlotID:        input.SlotID,
+		AppointmentAt: input.AppointmentAt,
+		ExpiresAt:     &expiresAt,
 	}
+	var createdData map[string]string
+	if intent.BookingHoldID != nil && *intent.BookingHoldID != "" {
+		createdData = map[string]string{"hold_id": *intent.BookingHoldID}
+	}
+	order.RecordEvent(checkoutdomain.OrderEventCreated, now, createdData)
 
 	// 4. Persistir orden
 	createdOrder, err := uc.OrderRepo.Create(ctx, order)
@@ -84,6 +113,35 @@ func (uc CreateOrder) Execute(ctx context.Context, input CreateOrderInput) (Crea
 	return CreateOrderOutput{Order: createdOrder}, nil
 }
 
+// summarizePets agrupa las líneas por mascota (en orden de aparición) con su subtotal.
+func summarizePets(intent checkoutdomain.PurchaseIntent, items []checkoutdomain.OrderItem) ([]checkoutdomain.OrderPet, error) {
+	pets := make([]checkoutdomain.OrderPet, 0, 1)
+	index := make(map[string]int)
+	for _, item := range items {
+		i, ok := index[item.PetID]
+		if !ok {
+			profile, found := intent.PetProfileFor(item.PetID)
+			if !found {
+				return nil, ErrUnknownPet
+			}
+			i = len(pets)
+			index[item.PetID] = i
+			pets = append(pets, checkoutdomain.OrderPet{
+				PetID:      item.PetID,
+				PetProfile: profile,
+				Subtotal:   pricingdomain.Zero(item.LineTotal.Currency),
+			})
+		}
+
+		subtotal, err := pets[i].Subtotal.Add(item.LineTotal)
+		if err != nil {
+			return nil, err
+		}
+		pets[i].Subtotal = subtotal
+	}
+	return pets, nil
+}
+
 // generateOrderID genera un ID único para la orden.
 // TODO: usar internal/platform/id si existe
 func generateOrderID() (string, error) {
@@ -7,6 +7,7 @@ import (
 
 	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
 	servicememory "paku-commerce/internal/commerce/service/adapters/memory"
 	servicedomain "paku-commerce/internal/commerce/service/domain"
 	pricingmemory "paku-commerce/internal/pricing/adapters/memory"
@@ -86,3 +87,147 @@ func TestCreateOrder_Success(t *testing.T) {
 		t.Errorf("expected CreatedAt to match fixed time")
 	}
 }
+
+func TestCreateOrder_PetID_SnapshotsResolvedProfile(t *testing.T) {
+	profile := servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 15, CoatType: servicedomain.CoatTypeDouble}
+	pets := &platformpets.StubClient{Profiles: map[string]servicedomain.PetProfile{"max": profile}}
+
+	uc := newTestCreateOrder(checkoutmemory.NewOrderRepository())
+	uc.QuoteCheckoutUC.Pets = pets
+
+	output, err := uc.Execute(context.Background(), CreateOrderInput{Intent: checkoutdomain.PurchaseIntent{
+		PetID: "max",
+		Items: []checkoutdomain.PurchaseItem{{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order := output.Order
+	if order.PetID != "max" || order.PetProfile != profile {
+		t.Errorf("expected snapshot of max, got: %s %+v", order.PetID, order.PetProfile)
+	}
+	if len(order.Pets) != 1 || order.Pets[0].PetProfile != profile {
+		t.Errorf("expected pet summary with resolved profile, got: %+v", order.Pets)
+	}
+	if order.Subtotal.Amount != 4500 {
+		t.Errorf("expected bath priced for 15kg (4500), got: %d", order.Subtotal.Amount)
+	}
+
+	// El perfil queda congelado en la orden aunque cambie en el servicio de mascotas
+	pets.Profiles["max"] = servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 30, CoatType: servicedomain.CoatTypeDouble}
+	stored, err := uc.OrderRepo.GetByID(context.Background(), order.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.PetProfile != profile {
+		t.Errorf("expected stored snapshot to stay at 15kg, got: %+v", stored.PetProfile)
+	}
+}
+
+func TestCreateOrder_MultiPet_SubtotalsPerPet(t *testing.T) {
+	pricingRepo := pricingmemory.NewPriceRuleRepository()
+	uc := &CreateOrder{
+		QuoteCheckoutUC: &QuoteCheckout{
+			ServiceRepo:   servicememory.NewServiceRepository(),
+			PriceRuleRepo: pricingRepo,
+			PriceQuoteUC:  &pricingusecases.QuoteItems{RuleRepo: pricingRepo},
+			PromotionsUC:  &promotionsusecases.ApplyDiscounts{Repo: promotionsmemory.NewPromotionsRepository()},
+		},
+		OrderRepo: checkoutmemory.NewOrderRepository(),
+		Now:       func() time.Time { return time.Date(2026, 1, 12, 10, 0, 0, 0, time.UTC) },
+	}
+
+	// Pet principal 15kg (bath 45 + deshedding 20) y "luna" 30kg (bath 60)
+	intent := checkoutdomain.PurchaseIntent{
+		PetProfile: servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 15, CoatType: servicedomain.CoatTypeDouble},
+		Pets: []checkoutdomain.Pet{
+			{ID: "luna", PetProfile: servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 30, CoatType: servicedomain.CoatTypeShort}},
+		},
+		Items: []checkoutdomain.PurchaseItem{
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1, PetID: "luna"},
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1},
+		},
+	}
+
+	output, err := uc.Execute(context.Background(), CreateOrderInput{Intent: intent})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order := output.Order
+	if order.Subtotal.Amount != 12500 {
+		t.Errorf("expected subtotal 12500, got: %d", order.Subtotal.Amount)
+	}
+	if len(order.Pets) != 2 {
+		t.Fatalf("expected 2 pets in order, got: %+v", order.Pets)
+	}
+	if order.Pets[0].PetID != "" || order.Pets[0].Subtotal.Amount != 6500 {
+		t.Errorf("expected main pet subtotal 6500, got: %+v", order.Pets[0])
+	}
+	if order.Pets[1].PetID != "luna" || order.Pets[1].Subtotal.Amount != 6000 || order.Pets[1].PetProfile.WeightKg != 30 {
+		t.Errorf("expected luna subtotal 6000, got: %+v", order.Pets[1])
+	}
+	if order.Items[1].PetID != "luna" || order.Items[1].UnitPrice.Amount != 6000 {
+		t.Errorf("expected luna's bath priced for 30kg, got: %+v", order.Items[1])
+	}
+}
+
+func TestCreateOrder_MultiPet_RulesEvaluatedPerPet(t *testing.T) {
+	pricingRepo := pricingmemory.NewPriceRuleRepository()
+	uc := &CreateOrder{
+		QuoteCheckoutUC: &QuoteCheckout{
+			ServiceRepo:   servicememory.NewServiceRepository(),
+			PriceRuleRepo: pricingRepo,
+			PriceQuoteUC:  &pricingusecases.QuoteItems{RuleRepo: pricingRepo},
+			PromotionsUC:  &promotionsusecases.ApplyDiscounts{Repo: promotionsmemory.NewPromotionsRepository()},
+		},
+		OrderRepo: checkoutmemory.NewOrderRepository(),
+	}
+
+	pet := servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 15, CoatType: servicedomain.CoatTypeDouble}
+	tests := []struct {
+		name    string
+		pets    []checkoutdomain.Pet
+		items   []checkoutdomain.PurchaseItem
+		wantErr error
+	}{
+		{
+			name: "addon sin base de la misma mascota",
+			pets: []checkoutdomain.Pet{{ID: "luna", PetProfile: pet}},
+			items: []checkoutdomain.PurchaseItem{
+				{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+				{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1, PetID: "luna"},
+			},
+			wantErr: ErrMissingParentService,
+		},
+		{
+			name: "servicio no elegible para la otra mascota",
+			pets: []checkoutdomain.Pet{{ID: "luna", PetProfile: servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 15, CoatType: servicedomain.CoatTypeShort}}},
+			items: []checkoutdomain.PurchaseItem{
+				{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1, PetID: "luna"},
+				{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1, PetID: "luna"},
+			},
+			wantErr: ErrServiceNotEligible,
+		},
+		{
+			name:    "mascota desconocida",
+			items:   []checkoutdomain.PurchaseItem{{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1, PetID: "rocky"}},
+			wantErr: ErrUnknownPet,
+		},
+		{
+			name:    "IDs de mascota repetidos",
+			pets:    []checkoutdomain.Pet{{ID: "luna", PetProfile: pet}, {ID: "luna", PetProfile: pet}},
+			items:   []checkoutdomain.PurchaseItem{{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1}},
+			wantErr: checkoutdomain.ErrInvalidPets,
+		},
+	}
+
+	for _, tt := range tests {
+		intent := checkoutdomain.PurchaseIntent{PetProfile: pet, Pets: tt.pets, Items: tt.items}
+		if _, err := uc.Execute(context.Background(), CreateOrderInput{Intent: intent}); err != tt.wantErr {
+			t.Errorf("%s: expected %v, got: %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+)
+
+// DefaultPaymentIntentTTL es la vigencia por defecto de un QR de pago.
+const DefaultPaymentIntentTTL = 15 * time.Minute
+
+// CreatePaymentIntentInput contiene la orden a pagar y la billetera elegida.
+type CreatePaymentIntentInput struct {
+	OrderID string
+	UserID  string // "" = sin control de dueño (uso admin)
+	Method  checkoutdomain.PaymentMethod
+}
+
+// CreatePaymentIntentOutput contiene el intento de pago con su QR y la orden a pagar.
+type CreatePaymentIntentOutput struct {
+	Intent  checkoutdomain.PaymentIntent
+	Order   checkoutdomain.Order
+	Created bool // false = se retornó el QR vigente de una solicitud anterior
+}
+
+// CreatePaymentIntent genera un QR de Yape/Plin para pagar una orden pending_payment
+// (o failed, para reintentar el pago). La orden sigue esperando pago hasta que
+// SyncPaymentIntent ve la transferencia en el proveedor.
+type CreatePaymentIntent struct {
+	Orders  checkoutdomain.OrderRepository
+	Intents checkoutdomain.PaymentIntentRepository
+	QR      payments.QRPaymentsClient
+	SyncUC  *SyncPaymentIntent // opcional: cierra el QR vencido que sigue pending antes de emitir otro
+	TTL     time.Duration      // 0 = DefaultPaymentIntentTTL (nunca después del plazo de pago de la orden)
+	Now     func() time.Time
+}
+
+// Execute crea el intento de pago. Si la orden ya tiene un QR vigente con el mismo
+// método lo retorna (idempotente); con otro método retorna ErrPaymentIntentPending.
+// La base garantiza un solo intento pending por orden: si otra solicitud gana la
+// carrera, el QR recién emitido se anula en el proveedor y se retorna el vigente.
+func (uc CreatePaymentIntent) Execute(ctx context.Context, input CreatePaymentIntentInput) (CreatePaymentIntentOutput, error) {
+	if !input.Method.IsValid() {
+		return CreatePaymentIntentOutput{}, fmt.Errorf("%w: %q", checkoutdomain.ErrInvalidPaymentMethod, input.Method)
+	}
+
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	// 1. Cargar la orden y verificar que siga esperando pago
+	order, err := uc.Orders.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return CreatePaymentIntentOutput{}, err
+	}
+	if input.UserID != "" && order.UserID != input.UserID {
+		return CreatePaymentIntentOutput{}, checkoutdomain.ErrOrderNotFound
+	}
+	if order.Status == checkoutdomain.OrderStatusCancelled {
+		return CreatePaymentIntentOutput{}, checkoutdomain.ErrOrderCancelled
+	}
+	if order.Status != checkoutdomain.OrderStatusPendingPayment && order.Status != checkoutdomain.OrderStatusFailed {
+		return CreatePaymentIntentOutput{}, fmt.Errorf("%w: order is %s", checkoutdomain.ErrInvalidOrderState, order.Status)
+	}
+	if order.IsPaymentOverdue(now) {
+		return CreatePaymentIntentOutput{}, fmt.Errorf("%w: payment deadline has passed", checkoutdomain.ErrInvalidOrderState)
+	}
+
+	// 2. Reusar el QR vigente (un solo cobro pendiente por orden)
+	latest, err := uc.Intents.GetLatestByOrder(ctx, order.ID)
+	switch {
+	case err == nil && latest.IsPending() && now.Before(latest.ExpiresAt):
+		if latest.Method != input.Method {
+			return CreatePaymentIntentOutput{}, checkoutdomain.ErrPaymentIntentPending
+		}
+		return CreatePaymentIntentOutput{Intent: latest, Order: order}, nil
+	case err == nil && latest.IsPending():
+		// El QR venció pero nadie lo cerró todavía: sin cerrarlo no se puede emitir otro.
+		if uc.SyncUC == nil {
+			return CreatePaymentIntentOutput{}, checkoutdomain.ErrPaymentIntentPending
+		}
+		synced, err := uc.SyncUC.Execute(ctx, SyncPaymentIntentInput{IntentID: latest.ID})
+		if err != nil {
+			return CreatePaymentIntentOutput{}, err
+		}
+		if synced.Intent.IsPending() {
+			return CreatePaymentIntentOutput{}, checkoutdomain.ErrPaymentIntentPending
+		}
+		// El sync puede haber cobrado la orden: reevaluar desde el inicio
+		retry := uc
+		retry.SyncUC = nil
+		return retry.Execute(ctx, input)
+	case err != nil && !errors.Is(err, checkoutdomain.ErrPaymentIntentNotFound):
+		return CreatePaymentIntentOutput{}, err
+	}
+
+	// 3. Generar el cobro QR en el proveedor
+	ttl := uc.TTL
+	if ttl <= 0 {
+		ttl = DefaultPaymentIntentTTL
+	}
+	expiresAt := now.Add(ttl)
+	if order.ExpiresAt != nil && order.ExpiresAt.Before(expiresAt) {
+		expiresAt = *order.ExpiresAt
+	}
+
+	intentID, err := generatePaymentIntentID()
+	if err != nil {
+		return CreatePaymentIntentOutput{}, fmt.Errorf("failed to generate payment intent ID: %w", err)
+	}
+	qr, err := uc.QR.CreateQRPayment(ctx, payments.QRPaymentRequest{
+		Reference: intentID,
+		OrderID:   order.ID,
+		Method:    input.Method,
+		Amount:    order.Total,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return CreatePaymentIntentOutput{}, err
+	}
+
+	// 4. Persistir el intento pending
+	created, err := uc.Intents.Create(ctx, checkoutdomain.PaymentIntent{
+		ID:          intentID,
+		OrderID:     order.ID,
+		Method:      input.Method,
+		Amount:      order.Total,
+		ProviderRef: qr.PaymentRef,
+		QRData:      qr.QRData,
+		Status:      checkoutdomain.PaymentIntentStatusPending,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if errors.Is(err, checkoutdomain.ErrPaymentIntentPending) {
+		return uc.lostCreateRace(ctx, order, input.Method, qr.PaymentRef)
+	}
+	if err != nil {
+		return CreatePaymentIntentOutput{}, err
+	}
+	return CreatePaymentIntentOutput{Intent: created, Order: order, Created: true}, nil
+}
+
+// lostCreateRace resuelve la creación concurrente: anula el QR huérfano (best-effort,
+// si no se anula vence solo y nunca se concilia) y retorna el intento que quedó vigente.
+func (uc CreatePaymentIntent) lostCreateRace(ctx context.Context, order checkoutdomain.Order, method checkoutdomain.PaymentMethod, paymentRef string) (CreatePaymentIntentOutput, error) {
+	_, _ = uc.QR.CancelQRPayment(ctx, paymentRef)
+
+	latest, err := uc.Intents.GetLatestByOrder(ctx, order.ID)
+	if err != nil {
+		return CreatePaymentIntentOutput{}, err
+	}
+	if !latest.IsPending() || latest.Method != method {
+		return CreatePaymentIntentOutput{}, checkoutdomain.ErrPaymentIntentPending
+	}
+	return CreatePaymentIntentOutput{Intent: latest, Order: order}, nil
+}
+
+// generatePaymentIntentID genera un ID único para el intento de pago.
+func generatePaymentIntentID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "pi_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	"paku-commerce/internal/commerce/checkout/adapters/qrsimulator"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	bookingstub "paku-commerce/internal/commerce/checkout/ports/booking"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+)
+
+// qrPaymentFixture arma el flujo de pagos QR sobre repos en memoria y el simulador.
+type qrPaymentFixture struct {
+	orders    checkoutdomain.OrderRepository
+	intents   *checkoutmemory.PaymentIntentRepository
+	incidents *checkoutmemory.IncidentRepository
+	qr        *qrsimulator.Simulator
+	now       time.Time
+	create    CreatePaymentIntent
+	sync      *SyncPaymentIntent
+}
+
+func newQRPaymentFixture() *qrPaymentFixture {
+	f := &qrPaymentFixture{
+		orders:    checkoutmemory.NewOrderRepository(),
+		intents:   checkoutmemory.NewPaymentIntentRepository(),
+		incidents: checkoutmemory.NewIncidentRepository(),
+		qr:        qrsimulator.NewSimulator(),
+		now:       time.Now(),
+	}
+	clock := func() time.Time { return f.now }
+	f.qr.Now = clock
+	f.create = CreatePaymentIntent{Orders: f.orders, Intents: f.intents, QR: f.qr, Now: clock}
+	f.sync = &SyncPaymentIntent{
+		Intents:          f.intents,
+		Orders:           f.orders,
+		QR:               f.qr,
+		ConfirmPaymentUC: &ConfirmPayment{Repo: f.orders, Booking: &bookingstub.StubBookingClient{}, Payments: f.qr, Provider: checkoutdomain.PaymentProviderQR},
+		RecordIncidentUC: &RecordIncident{Repo: f.incidents, Now: clock},
+		Now:              clock,
+	}
+	f.create.SyncUC = f.sync
+	return f
+}
+
+// staleLatestIntentRepo simula una lectura previa a la creación concurrente: la primera
+// búsqueda del intento vigente no ve el QR que otra solicitud acaba de crear.
+type staleLatestIntentRepo struct {
+	checkoutdomain.PaymentIntentRepository
+	stale bool
+}
+
+func (r *staleLatestIntentRepo) GetLatestByOrder(ctx context.Context, orderID string) (checkoutdomain.PaymentIntent, error) {
+	if r.stale {
+		r.stale = false
+		return checkoutdomain.PaymentIntent{}, checkoutdomain.ErrPaymentIntentNotFound
+	}
+	return r.PaymentIntentRepository.GetLatestByOrder(ctx, orderID)
+}
+
+// cancelRecordingQR registra los QR que se anulan en el simulador.
+type cancelRecordingQR struct {
+	*qrsimulator.Simulator
+	cancelled []string
+}
+
+func (c *cancelRecordingQR) CancelQRPayment(ctx context.Context, paymentRef string) (payments.QRPayment, error) {
+	c.cancelled = append(c.cancelled, paymentRef)
+	return c.Simulator.CancelQRPayment(ctx, paymentRef)
+}
+
+func TestCreatePaymentIntent_GeneratesQRAndIsIdempotent(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+
+	output, err := f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID,
+		Method:  checkoutdomain.PaymentMethodYape,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	intent := output.Intent
+	if !output.Created || intent.Status != checkoutdomain.PaymentIntentStatusPending ||
+		intent.Amount != order.Total || intent.ProviderRef == "" || !strings.HasPrefix(intent.QRData, "paku-sim://yape/") {
+		t.Errorf("unexpected intent: %+v", output)
+	}
+	if order.ExpiresAt != nil && intent.ExpiresAt.After(*order.ExpiresAt) {
+		t.Errorf("expected QR to expire before the order deadline %v, got %v", *order.ExpiresAt, intent.ExpiresAt)
+	}
+
+	// Mismo método: se retorna el QR vigente
+	again, err := f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID,
+		Method:  checkoutdomain.PaymentMethodYape,
+	})
+	if err != nil || again.Created || again.Intent.ID != intent.ID {
+		t.Errorf("expected the same pending intent, got: %+v %v", again, err)
+	}
+
+	// Otro método con un QR vigente: conflicto
+	_, err = f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID,
+		Method:  checkoutdomain.PaymentMethodPlin,
+	})
+	if !errors.Is(err, checkoutdomain.ErrPaymentIntentPending) {
+		t.Errorf("expected ErrPaymentIntentPending, got: %v", err)
+	}
+
+	// Vencido el QR (antes del plazo de la orden) se puede generar otro con cualquier método
+	f.now = intent.ExpiresAt.Add(time.Second)
+	plin, err := f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID,
+		Method:  checkoutdomain.PaymentMethodPlin,
+	})
+	if err != nil || !plin.Created || plin.Intent.ID == intent.ID {
+		t.Errorf("expected a new intent after expiry, got: %+v %v", plin, err)
+	}
+}
+
+func TestCreatePaymentIntent_Rejections(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+
+	if _, err := f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID, Method: "card",
+	}); !errors.Is(err, checkoutdomain.ErrInvalidPaymentMethod) {
+		t.Errorf("expected ErrInvalidPaymentMethod, got: %v", err)
+	}
+
+	if _, err := f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID, UserID: "someone_else", Method: checkoutdomain.PaymentMethodYape,
+	}); !errors.Is(err, checkoutdomain.ErrOrderNotFound) {
+		t.Errorf("expected ErrOrderNotFound for another user's order, got: %v", err)
+	}
+
+	paid := createPaidTestOrder(t, f.orders)
+	if _, err := f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: paid.ID, Method: checkoutdomain.PaymentMethodYape,
+	}); !errors.Is(err, checkoutdomain.ErrInvalidOrderState) {
+		t.Errorf("expected ErrInvalidOrderState for a paid order, got: %v", err)
+	}
+}
+
+func TestCreatePaymentIntent_ConcurrentCreate_ReturnsLiveIntentAndCancelsOrphanQR(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	live := createQRIntent(t, f, order)
+
+	// Otra solicitud no vio el QR vigente y emitió uno nuevo en el proveedor
+	recorder := &cancelRecordingQR{Simulator: f.qr}
+	racing := f.create
+	racing.Intents = &staleLatestIntentRepo{PaymentIntentRepository: f.intents, stale: true}
+	racing.QR = recorder
+	output, err := racing.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID,
+		Method:  checkoutdomain.PaymentMethodYape,
+	})
+	if err != nil || output.Created || output.Intent.ID != live.ID {
+		t.Fatalf("expected the live intent back, got: %+v %v", output.Intent, err)
+	}
+
+	if pending, _ := f.intents.ListPending(context.Background()); len(pending) != 1 {
+		t.Errorf("expected a single pending intent for the order, got %d", len(pending))
+	}
+	if len(recorder.cancelled) != 1 || recorder.cancelled[0] == live.ProviderRef {
+		t.Fatalf("expected only the orphan QR cancelled, got: %v", recorder.cancelled)
+	}
+	if orphan, _ := f.qr.GetQRPayment(context.Background(), recorder.cancelled[0]); orphan.Status != payments.ChargeStatusCancelled {
+		t.Errorf("expected the orphan QR cancelled at the provider, got: %s", orphan.Status)
+	}
+}
+
+func TestCreatePaymentIntent_ExpiredButUnsynced_ClosesItBeforeIssuingAnother(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	first := createQRIntent(t, f, order)
+
+	// El QR venció y el worker todavía no lo cerró: sigue pending en la base
+	f.now = first.ExpiresAt.Add(time.Second)
+	output, err := f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID,
+		Method:  checkoutdomain.PaymentMethodPlin,
+	})
+	if err != nil || !output.Created || output.Intent.ID == first.ID {
+		t.Fatalf("expected a new intent, got: %+v %v", output.Intent, err)
+	}
+	if stored, _ := f.intents.GetByID(context.Background(), first.ID); stored.Status != checkoutdomain.PaymentIntentStatusExpired {
+		t.Errorf("expected the stale intent expired, got: %s", stored.Status)
+	}
+}
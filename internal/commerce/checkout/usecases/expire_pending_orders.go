@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/platform/lock"
+)
+
+// ExpirePendingOrdersLockKey es la clave del lock que serializa la expiración entre réplicas.
+const ExpirePendingOrdersLockKey = "order-expiry"
+
+// ExpirePendingOrdersInput contiene el timestamp de referencia.
+type ExpirePendingOrdersInput struct {
+	Now time.Time
+}
+
+// ExpirePendingOrdersOutput contiene estadísticas de expiración.
+type ExpirePendingOrdersOutput struct {
+	ExpiredCount int
+	// Skipped indica que otra réplica tenía el lock y no se procesó nada.
+	Skipped bool
+}
+
+// ExpirePendingOrders cancela las órdenes pending_payment cuyo plazo de pago venció
+// (motivo expired) y libera sus holds vía CancelOrder.
+type ExpirePendingOrders struct {
+	Repo          checkoutdomain.OrderRepository
+	CancelOrderUC *CancelOrder
+	Locker        lock.Locker // opcional: evita que dos réplicas procesen las mismas órdenes
+}
+
+// Execute expira las órdenes vencidas. Una orden pagada en paralelo no se cancela:
+// CancelOrder detecta el cambio de estado y la orden se omite.
+func (uc ExpirePendingOrders) Execute(ctx context.Context, input ExpirePendingOrdersInput) (ExpirePendingOrdersOutput, error) {
+	if uc.Locker != nil {
+		unlock, acquired, err := uc.Locker.TryLock(ctx, ExpirePendingOrdersLockKey)
+		if err != nil {
+			return ExpirePendingOrdersOutput{}, err
+		}
+		if !acquired {
+			return ExpirePendingOrdersOutput{Skipped: true}, nil
+		}
+		defer unlock()
+	}
+
+	overdue, err := uc.Repo.ListOverduePending(ctx, input.Now)
+	if err != nil {
+		return ExpirePendingOrdersOutput{}, err
+	}
+
+	var output ExpirePendingOrdersOutput
+	for _, order := range overdue {
+		cancelled, err := uc.CancelOrderUC.Execute(ctx, CancelOrderInput{
+			OrderID: order.ID,
+			Reason:  checkoutdomain.CancelReasonExpired,
+		})
+		if err != nil {
+			// Best-effort: pagada en paralelo o error puntual; se reintenta en el próximo barrido si sigue vencida
+			continue
+		}
+		if cancelled.Order.CancelReason == checkoutdomain.CancelReasonExpired {
+			output.ExpiredCount++
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/platform/lock"
+)
+
+func TestExpirePendingOrders_CancelsOverdueAndReleasesHold(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	booking := &recordingBookingClient{}
+
+	overdue := createTestOrder(t, repo)
+	holdID := "hold_overdue"
+	overdue.BookingHoldID = &holdID
+	overdue, _ = repo.Update(context.Background(), overdue)
+
+	paid := createTestOrder(t, repo)
+	_ = paid.MarkPaid("tx_paid", time.Now())
+	paid, _ = repo.Update(context.Background(), paid)
+
+	now := overdue.ExpiresAt.Add(time.Minute)
+	uc := &ExpirePendingOrders{
+		Repo:          repo,
+		CancelOrderUC: &CancelOrder{Repo: repo, Booking: booking},
+		Locker:        lock.NewMemoryLocker(),
+	}
+
+	output, err := uc.Execute(context.Background(), ExpirePendingOrdersInput{Now: now})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.ExpiredCount != 1 {
+		t.Errorf("expected 1 expired order, got: %d", output.ExpiredCount)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), overdue.ID)
+	if stored.Status != checkoutdomain.OrderStatusCancelled || stored.CancelReason != checkoutdomain.CancelReasonExpired {
+		t.Errorf("expected cancelled/expired, got: %s/%s", stored.Status, stored.CancelReason)
+	}
+	if len(booking.cancelled) != 1 || booking.cancelled[0] != holdID {
+		t.Errorf("expected hold %s to be cancelled, got: %v", holdID, booking.cancelled)
+	}
+
+	stored, _ = repo.GetByID(context.Background(), paid.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected paid order untouched, got: %s", stored.Status)
+	}
+
+	// Un segundo barrido no encuentra nada pendiente
+	output, _ = uc.Execute(context.Background(), ExpirePendingOrdersInput{Now: now})
+	if output.ExpiredCount != 0 {
+		t.Errorf("expected no orders on second run, got: %d", output.ExpiredCount)
+	}
+}
+
+func TestExpirePendingOrders_BeforeDeadline_KeepsOrder(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTestOrder(t, repo)
+
+	if order.ExpiresAt == nil {
+		t.Fatal("expected order to have a payment deadline")
+	}
+
+	uc := &ExpirePendingOrders{Repo: repo, CancelOrderUC: &CancelOrder{Repo: repo, Booking: &recordingBookingClient{}}}
+	output, err := uc.Execute(context.Background(), ExpirePendingOrdersInput{Now: order.ExpiresAt.Add(-time.Second)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.ExpiredCount != 0 {
+		t.Errorf("expected no expired orders, got: %d", output.ExpiredCount)
+	}
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// GetOrderInput contiene la orden a consultar y quién la consulta.
+type GetOrderInput struct {
+	OrderID string
+	UserID  string // "" = sin control de dueño (uso admin)
+}
+
+// GetOrderOutput contiene la orden.
+type GetOrderOutput struct {
+	Order checkoutdomain.Order
+}
+
+// GetOrder retorna una orden verificando que pertenezca al usuario.
+type GetOrder struct {
+	Repo checkoutdomain.OrderRepository
+}
+
+// Execute busca la orden. Si pertenece a otro usuario retorna ErrOrderNotFound
+// para no revelar que existe.
+func (uc GetOrder) Execute(ctx context.Context, input GetOrderInput) (GetOrderOutput, error) {
+	order, err := uc.Repo.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return GetOrderOutput{}, err
+	}
+
+	if input.UserID != "" && order.UserID != input.UserID {
+		return GetOrderOutput{}, checkoutdomain.ErrOrderNotFound
+	}
+
+	return GetOrderOutput{Order: order}, nil
+}
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// GetPaymentIntentInput contiene la orden a consultar y quién la consulta.
+type GetPaymentIntentInput struct {
+	OrderID string
+	UserID  string // "" = sin control de dueño (uso admin)
+}
+
+// GetPaymentIntentOutput contiene el último intento de pago y la orden tras conciliarlo.
+type GetPaymentIntentOutput struct {
+	Intent checkoutdomain.PaymentIntent
+	Order  checkoutdomain.Order
+}
+
+// GetPaymentIntent retorna el último intento de pago QR de una orden. Si sigue pending
+// lo concilia con el proveedor, así el frontend que hace polling ve el pago apenas llega.
+type GetPaymentIntent struct {
+	Orders  checkoutdomain.OrderRepository
+	Intents checkoutdomain.PaymentIntentRepository
+	SyncUC  *SyncPaymentIntent // opcional: sin él se retorna el estado persistido
+}
+
+// Execute busca el intento. Si la orden pertenece a otro usuario retorna ErrOrderNotFound;
+// si la conciliación falla, retorna el último estado conocido.
+func (uc GetPaymentIntent) Execute(ctx context.Context, input GetPaymentIntentInput) (GetPaymentIntentOutput, error) {
+	order, err := uc.Orders.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return GetPaymentIntentOutput{}, err
+	}
+	if input.UserID != "" && order.UserID != input.UserID {
+		return GetPaymentIntentOutput{}, checkoutdomain.ErrOrderNotFound
+	}
+
+	intent, err := uc.Intents.GetLatestByOrder(ctx, order.ID)
+	if err != nil {
+		return GetPaymentIntentOutput{}, err
+	}
+	if !intent.IsPending() || uc.SyncUC == nil {
+		return GetPaymentIntentOutput{Intent: intent, Order: order}, nil
+	}
+
+	synced, err := uc.SyncUC.Execute(ctx, SyncPaymentIntentInput{IntentID: intent.ID})
+	if err != nil || synced.Intent.IsPending() {
+		return GetPaymentIntentOutput{Intent: intent, Order: order}, nil
+	}
+	// El intento se resolvió: recargar la orden para reflejar la transición
+	order, err = uc.Orders.GetByID(ctx, order.ID)
+	if err != nil {
+		return GetPaymentIntentOutput{}, err
+	}
+	return GetPaymentIntentOutput{Intent: synced.Intent, Order: order}, nil
+}
@@ -0,0 +1,237 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+)
+
+var (
+	// ErrPaymentEventFailed indica un error transitorio: el evento queda en el inbox como
+	// failed y se reprocesa cuando el proveedor reintente la entrega.
+	ErrPaymentEventFailed = errors.New("payment event processing failed")
+
+	ErrPaymentEventMismatch    = errors.New("payment event does not match the order payment")
+	ErrUnsupportedPaymentEvent = errors.New("unsupported payment event type")
+	errPaymentAlreadySettled   = errors.New("order payment already settled")
+)
+
+// ProviderRefundKeyPrefix antepone la clave de idempotencia de los reembolsos
+// iniciados desde el proveedor (ej. desde su dashboard): "provider:<refund_id>".
+const ProviderRefundKeyPrefix = "provider:"
+
+// PaymentEventClaimTimeout es cuánto se respeta el claim de una entrega en curso; pasado
+// ese tiempo se asume que la entrega murió y otra puede retomar el evento.
+const PaymentEventClaimTimeout = 5 * time.Minute
+
+// HandlePaymentWebhookInput contiene el evento ya verificado del proveedor.
+type HandlePaymentWebhookInput struct {
+	Event checkoutdomain.PaymentEvent
+}
+
+// HandlePaymentWebhookOutput contiene el registro del inbox tras procesar el evento.
+type HandlePaymentWebhookOutput struct {
+	Record    checkoutdomain.PaymentEventRecord
+	Duplicate bool // el evento ya se había procesado en una entrega anterior
+}
+
+// HandlePaymentWebhook aplica los eventos del proveedor de pagos a las órdenes,
+// una sola vez por (proveedor, ID de evento):
+//   - payment.succeeded confirma el pago (ConfirmPayment)
+//   - payment.failed marca la orden failed (se puede reintentar el pago)
+//   - payment.refunded registra un reembolso hecho desde el proveedor
+//
+// Los eventos succeeded/failed de un cobro QR (Yape/Plin) solo disparan la conciliación
+// de su intento de pago (SyncPaymentIntent), que consulta el estado al proveedor.
+type HandlePaymentWebhook struct {
+	Inbox               checkoutdomain.PaymentEventInbox
+	Orders              checkoutdomain.OrderRepository
+	ConfirmPaymentUC    *ConfirmPayment
+	SyncPaymentIntentUC *SyncPaymentIntent // opcional: sin él todos los cobros se tratan como tarjeta
+	Now                 func() time.Time
+}
+
+// Execute procesa el evento. Los eventos que no aplican a la orden (inexistente, ya
+// pagada con otra ref, estado incompatible, ...) se guardan como ignored y no son error:
+// reintentarlos no cambiaría el resultado. Los errores transitorios retornan
+// ErrPaymentEventFailed para que el proveedor vuelva a entregar el evento.
+func (uc HandlePaymentWebhook) Execute(ctx context.Context, input HandlePaymentWebhookInput) (HandlePaymentWebhookOutput, error) {
+	event := input.Event
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	// 1. Deduplicar tomando el evento en el inbox (atómico: una sola entrega lo aplica)
+	record, claimed, err := uc.Inbox.Claim(ctx, event, now, now.Add(-PaymentEventClaimTimeout))
+	if err != nil {
+		return HandlePaymentWebhookOutput{}, fmt.Errorf("%w: %v", ErrPaymentEventFailed, err)
+	}
+	if !claimed {
+		if record.IsDone() {
+			return HandlePaymentWebhookOutput{Record: record, Duplicate: true}, nil
+		}
+		// Otra entrega lo está aplicando: el proveedor reintenta y verá el resultado
+		return HandlePaymentWebhookOutput{Record: record}, fmt.Errorf("%w: event is being processed by another delivery", ErrPaymentEventFailed)
+	}
+
+	// 2. Aplicar el evento a la orden
+	applyErr := uc.apply(ctx, event)
+	switch {
+	case applyErr == nil:
+		record.Status = checkoutdomain.PaymentEventStatusProcessed
+		record.Error = ""
+		record.ProcessedAt = &now
+	case isPermanentPaymentEventError(applyErr):
+		record.Status = checkoutdomain.PaymentEventStatusIgnored
+		record.Error = applyErr.Error()
+		record.ProcessedAt = &now
+	default:
+		record.Status = checkoutdomain.PaymentEventStatusFailed
+		record.Error = applyErr.Error()
+	}
+
+	// 3. Registrar el resultado en el inbox (libera el claim)
+	if err := uc.Inbox.Save(ctx, record); err != nil {
+		return HandlePaymentWebhookOutput{}, fmt.Errorf("%w: %v", ErrPaymentEventFailed, err)
+	}
+	if record.Status == checkoutdomain.PaymentEventStatusFailed {
+		return HandlePaymentWebhookOutput{Record: record}, fmt.Errorf("%w: %v", ErrPaymentEventFailed, applyErr)
+	}
+	return HandlePaymentWebhookOutput{Record: record}, nil
+}
+
+func (uc HandlePaymentWebhook) apply(ctx context.Context, event checkoutdomain.PaymentEvent) error {
+	if handled, err := uc.syncPaymentIntent(ctx, event); handled {
+		return err
+	}
+
+	switch event.Type {
+	case checkoutdomain.PaymentEventSucceeded:
+		if event.PaymentRef == "" {
+			return ErrPaymentEventMismatch
+		}
+		_, err := uc.ConfirmPaymentUC.Execute(ctx, ConfirmPaymentInput{
+			OrderID:    event.OrderID,
+			PaymentRef: event.PaymentRef,
+			PaidAt:     event.OccurredAt,
+		})
+		return err
+	case checkoutdomain.PaymentEventFailed:
+		return retryOnVersionConflict(func() error { return uc.markFailed(ctx, event) })
+	case checkoutdomain.PaymentEventRefunded:
+		return retryOnVersionConflict(func() error { return uc.recordRefund(ctx, event) })
+	default:
+		return ErrUnsupportedPaymentEvent
+	}
+}
+
+// syncPaymentIntent concilia el intento de pago QR del cobro del evento, si lo hay.
+// Retorna false si el cobro no es de un intento QR.
+func (uc HandlePaymentWebhook) syncPaymentIntent(ctx context.Context, event checkoutdomain.PaymentEvent) (bool, error) {
+	if uc.SyncPaymentIntentUC == nil || event.PaymentRef == "" {
+		return false, nil
+	}
+	if event.Type != checkoutdomain.PaymentEventSucceeded && event.Type != checkoutdomain.PaymentEventFailed {
+		return false, nil
+	}
+
+	_, err := uc.SyncPaymentIntentUC.Execute(ctx, SyncPaymentIntentInput{ProviderRef: event.PaymentRef})
+	if errors.Is(err, checkoutdomain.ErrPaymentIntentNotFound) {
+		return false, nil
+	}
+	return true, err
+}
+
+// markFailed registra el rechazo del pago. Un rechazo tardío (la orden ya se pagó o
+// se canceló) se ignora.
+func (uc HandlePaymentWebhook) markFailed(ctx context.Context, event checkoutdomain.PaymentEvent) error {
+	order, err := uc.Orders.GetByID(ctx, event.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.IsPaid() || order.Status == checkoutdomain.OrderStatusCancelled {
+		return errPaymentAlreadySettled
+	}
+	if order.Status == checkoutdomain.OrderStatusFailed {
+		return nil
+	}
+	if err := order.MarkFailed(event.Reason, event.OccurredAt); err != nil {
+		return err
+	}
+	_, err = uc.Orders.Update(ctx, order)
+	return err
+}
+
+// recordRefund registra en la orden un reembolso emitido desde el proveedor. Los
+// reembolsos que checkout ya registró (mismo ProviderRef) no se duplican.
+func (uc HandlePaymentWebhook) recordRefund(ctx context.Context, event checkoutdomain.PaymentEvent) error {
+	if event.RefundRef == "" {
+		return ErrPaymentEventMismatch
+	}
+	order, err := uc.Orders.GetByID(ctx, event.OrderID)
+	if err != nil {
+		return err
+	}
+	if !order.IsRefundable() {
+		return checkoutdomain.ErrRefundWithoutPayment
+	}
+	if event.PaymentRef != "" && event.PaymentRef != *order.PaymentRef {
+		return ErrPaymentEventMismatch
+	}
+
+	refunds, err := uc.Orders.ListRefunds(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	key := ProviderRefundKeyPrefix + event.RefundRef
+	for _, refund := range refunds {
+		if refund.ProviderRef == event.RefundRef || refund.Key == key {
+			return nil
+		}
+	}
+
+	amount := event.Amount
+	if amount.Amount == 0 {
+		amount = order.RefundableAmount()
+	}
+	if amount.Currency != order.Total.Currency {
+		return ErrPaymentEventMismatch
+	}
+	if amount.Amount == 0 {
+		return checkoutdomain.ErrNothingToRefund
+	}
+
+	if err := order.ApplyRefund(checkoutdomain.Refund{
+		Key:         key,
+		PaymentRef:  *order.PaymentRef,
+		Amount:      amount,
+		ProviderRef: event.RefundRef,
+	}, event.OccurredAt); err != nil {
+		return err
+	}
+	_, err = uc.Orders.Update(ctx, order)
+	return err
+}
+
+// isPermanentPaymentEventError indica si reprocesar el evento daría el mismo resultado.
+// Un cobro aún no completado en el proveedor (ErrPaymentNotCompleted) no es permanente:
+// la reentrega lo vuelve a verificar.
+func isPermanentPaymentEventError(err error) bool {
+	return errors.Is(err, checkoutdomain.ErrOrderNotFound) ||
+		errors.Is(err, checkoutdomain.ErrPaymentConflict) ||
+		errors.Is(err, checkoutdomain.ErrInvalidOrderState) ||
+		errors.Is(err, checkoutdomain.ErrRefundWithoutPayment) ||
+		errors.Is(err, checkoutdomain.ErrNothingToRefund) ||
+		errors.Is(err, checkoutdomain.ErrRefundExceedsNetPaid) ||
+		errors.Is(err, payments.ErrPaymentNotFound) ||
+		errors.Is(err, payments.ErrPaymentAmountMismatch) ||
+		errors.Is(err, payments.ErrPaymentCurrencyMismatch) ||
+		errors.Is(err, ErrPaymentEventMismatch) ||
+		errors.Is(err, ErrUnsupportedPaymentEvent) ||
+		errors.Is(err, errPaymentAlreadySettled)
+}
@@ -0,0 +1,249 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	bookingstub "paku-commerce/internal/commerce/checkout/ports/booking"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// unavailableOrderRepository falla las lecturas mientras err no sea nil.
+type unavailableOrderRepository struct {
+	checkoutdomain.OrderRepository
+	err error
+}
+
+func (r *unavailableOrderRepository) GetByID(ctx context.Context, id string) (checkoutdomain.Order, error) {
+	if r.err != nil {
+		return checkoutdomain.Order{}, r.err
+	}
+	return r.OrderRepository.GetByID(ctx, id)
+}
+
+func newPaymentWebhookUC(repo checkoutdomain.OrderRepository, inbox checkoutdomain.PaymentEventInbox) HandlePaymentWebhook {
+	return HandlePaymentWebhook{
+		Inbox:            inbox,
+		Orders:           repo,
+		ConfirmPaymentUC: &ConfirmPayment{Repo: repo, Booking: &bookingstub.StubBookingClient{}, Payments: &recordingPaymentsClient{}},
+	}
+}
+
+func TestHandlePaymentWebhook_Succeeded_DedupesByEventID(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	inbox := checkoutmemory.NewPaymentEventInbox()
+	order := createTestOrder(t, repo)
+	uc := newPaymentWebhookUC(repo, inbox)
+
+	paidAt := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	event := checkoutdomain.PaymentEvent{
+		Provider:   "acme",
+		ID:         "evt_1",
+		Type:       checkoutdomain.PaymentEventSucceeded,
+		OrderID:    order.ID,
+		PaymentRef: "tx_webhook",
+		OccurredAt: paidAt,
+	}
+
+	output, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: event})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Duplicate || output.Record.Status != checkoutdomain.PaymentEventStatusProcessed || output.Record.Attempts != 1 {
+		t.Errorf("expected processed record, got: %+v", output)
+	}
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid || *stored.PaymentRef != "tx_webhook" || !stored.PaidAt.Equal(paidAt) {
+		t.Errorf("expected order paid by the webhook, got: %+v", stored)
+	}
+
+	// Reentrega del mismo evento: no se vuelve a aplicar
+	again, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: event})
+	if err != nil {
+		t.Fatalf("unexpected error on redelivery: %v", err)
+	}
+	if !again.Duplicate || again.Record.Attempts != 1 {
+		t.Errorf("expected duplicate delivery, got: %+v", again)
+	}
+	if after, _ := repo.GetByID(context.Background(), order.ID); after.Version != stored.Version {
+		t.Errorf("expected order untouched by the duplicate, version %d -> %d", stored.Version, after.Version)
+	}
+}
+
+func TestHandlePaymentWebhook_Failed(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	inbox := checkoutmemory.NewPaymentEventInbox()
+	uc := newPaymentWebhookUC(repo, inbox)
+	pending := createTestOrder(t, repo)
+
+	output, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: checkoutdomain.PaymentEvent{
+		Provider: "acme", ID: "evt_failed", Type: checkoutdomain.PaymentEventFailed,
+		OrderID: pending.ID, Reason: "card_declined", OccurredAt: time.Now(),
+	}})
+	if err != nil || output.Record.Status != checkoutdomain.PaymentEventStatusProcessed {
+		t.Fatalf("expected processed event, got: %+v %v", output.Record, err)
+	}
+	stored, _ := repo.GetByID(context.Background(), pending.ID)
+	if stored.Status != checkoutdomain.OrderStatusFailed {
+		t.Errorf("expected failed order, got: %v", stored.Status)
+	}
+
+	// Un rechazo que llega después del pago se ignora
+	paid := createPaidTestOrder(t, repo)
+	output, err = uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: checkoutdomain.PaymentEvent{
+		Provider: "acme", ID: "evt_late", Type: checkoutdomain.PaymentEventFailed,
+		OrderID: paid.ID, OccurredAt: time.Now(),
+	}})
+	if err != nil || output.Record.Status != checkoutdomain.PaymentEventStatusIgnored {
+		t.Errorf("expected ignored event, got: %+v %v", output.Record, err)
+	}
+	if stored, _ := repo.GetByID(context.Background(), paid.ID); stored.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected paid order untouched, got: %v", stored.Status)
+	}
+}
+
+func TestHandlePaymentWebhook_Refunded(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	inbox := checkoutmemory.NewPaymentEventInbox()
+	uc := newPaymentWebhookUC(repo, inbox)
+	order := createTwoLineOrder(t, repo)
+	pen := pricingdomain.CurrencyPEN
+
+	// Reembolso parcial desde el dashboard del proveedor
+	partial := checkoutdomain.PaymentEvent{
+		Provider: "acme", ID: "evt_re_1", Type: checkoutdomain.PaymentEventRefunded,
+		OrderID: order.ID, PaymentRef: "tx_refund", RefundRef: "re_dash_1",
+		Amount: pricingdomain.NewMoney(1000, pen), OccurredAt: time.Now(),
+	}
+	if _, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: partial}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid || stored.RefundedAmount.Amount != 1000 {
+		t.Errorf("expected paid order with 1000 refunded, got: %v %v", stored.Status, stored.RefundedAmount)
+	}
+	for i, item := range stored.Items {
+		if item.Refunded {
+			t.Errorf("expected line %d not refunded after a partial provider refund", i)
+		}
+	}
+
+	// El mismo reembolso notificado con otro ID de evento no se registra dos veces
+	partial.ID = "evt_re_1_bis"
+	if _, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: partial}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	refunds, _ := repo.ListRefunds(context.Background(), order.ID)
+	if len(refunds) != 1 || refunds[0].Key != ProviderRefundKeyPrefix+"re_dash_1" {
+		t.Fatalf("expected a single provider refund, got: %+v", refunds)
+	}
+
+	// Otra ref de pago: se ignora
+	mismatch := partial
+	mismatch.ID, mismatch.RefundRef, mismatch.PaymentRef = "evt_re_other", "re_other", "tx_other"
+	output, _ := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: mismatch})
+	if output.Record.Status != checkoutdomain.PaymentEventStatusIgnored || output.Record.Error != ErrPaymentEventMismatch.Error() {
+		t.Errorf("expected ignored mismatch, got: %+v", output.Record)
+	}
+
+	// Monto cero: reembolsa el resto y la orden queda refunded
+	rest := checkoutdomain.PaymentEvent{
+		Provider: "acme", ID: "evt_re_2", Type: checkoutdomain.PaymentEventRefunded,
+		OrderID: order.ID, RefundRef: "re_dash_2", OccurredAt: time.Now(),
+	}
+	if _, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: rest}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, _ = repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusRefunded || stored.NetPaid().Amount != 0 {
+		t.Errorf("expected fully refunded order, got: %v net %v", stored.Status, stored.NetPaid())
+	}
+}
+
+func TestHandlePaymentWebhook_TransientAndPermanentErrors(t *testing.T) {
+	memoryRepo := checkoutmemory.NewOrderRepository()
+	repo := &unavailableOrderRepository{OrderRepository: memoryRepo, err: errors.New("database unavailable")}
+	inbox := checkoutmemory.NewPaymentEventInbox()
+	uc := newPaymentWebhookUC(repo, inbox)
+	order := createTestOrder(t, memoryRepo)
+
+	event := checkoutdomain.PaymentEvent{
+		Provider: "acme", ID: "evt_retry", Type: checkoutdomain.PaymentEventSucceeded,
+		OrderID: order.ID, PaymentRef: "tx_retry", OccurredAt: time.Now(),
+	}
+
+	// Error transitorio: el evento queda failed y se puede reprocesar
+	output, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: event})
+	if !errors.Is(err, ErrPaymentEventFailed) {
+		t.Fatalf("expected ErrPaymentEventFailed, got: %v", err)
+	}
+	if output.Record.Status != checkoutdomain.PaymentEventStatusFailed || output.Record.ProcessedAt != nil {
+		t.Errorf("expected failed record, got: %+v", output.Record)
+	}
+
+	repo.err = nil
+	output, err = uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: event})
+	if err != nil {
+		t.Fatalf("unexpected error on redelivery: %v", err)
+	}
+	if output.Duplicate || output.Record.Status != checkoutdomain.PaymentEventStatusProcessed || output.Record.Attempts != 2 {
+		t.Errorf("expected processed on the second attempt, got: %+v", output)
+	}
+
+	// Errores permanentes: se ignoran sin pedir reintento
+	permanent := []checkoutdomain.PaymentEvent{
+		{Provider: "acme", ID: "evt_missing", Type: checkoutdomain.PaymentEventSucceeded, OrderID: "order_missing", PaymentRef: "tx_1"},
+		{Provider: "acme", ID: "evt_conflict", Type: checkoutdomain.PaymentEventSucceeded, OrderID: order.ID, PaymentRef: "tx_other"},
+		{Provider: "acme", ID: "evt_unknown", Type: "payment.disputed", OrderID: order.ID},
+	}
+	for _, event := range permanent {
+		output, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: event})
+		if err != nil || output.Record.Status != checkoutdomain.PaymentEventStatusIgnored {
+			t.Errorf("%s: expected ignored event, got: %+v %v", event.ID, output.Record, err)
+		}
+	}
+}
+
+func TestHandlePaymentWebhook_ConcurrentDelivery_AppliesOnce(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	inbox := checkoutmemory.NewPaymentEventInbox()
+	order := createTestOrder(t, repo)
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	uc := newPaymentWebhookUC(repo, inbox)
+	uc.Now = func() time.Time { return now }
+
+	event := checkoutdomain.PaymentEvent{
+		Provider:   "acme",
+		ID:         "evt_race",
+		Type:       checkoutdomain.PaymentEventSucceeded,
+		OrderID:    order.ID,
+		PaymentRef: "tx_race",
+		OccurredAt: now,
+	}
+
+	// Otra entrega tomó el evento y lo está aplicando
+	if _, claimed, err := inbox.Claim(context.Background(), event, now, now.Add(-PaymentEventClaimTimeout)); err != nil || !claimed {
+		t.Fatalf("expected first claim, got: %v %v", claimed, err)
+	}
+	_, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: event})
+	if !errors.Is(err, ErrPaymentEventFailed) {
+		t.Fatalf("expected ErrPaymentEventFailed while the event is in flight, got: %v", err)
+	}
+	if stored, _ := repo.GetByID(context.Background(), order.ID); stored.Status != checkoutdomain.OrderStatusPendingPayment {
+		t.Errorf("expected order untouched by the concurrent delivery, got: %s", stored.Status)
+	}
+
+	// La entrega que lo tomó murió: pasado el timeout otra lo retoma
+	now = now.Add(PaymentEventClaimTimeout + time.Second)
+	output, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: event})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Record.Status != checkoutdomain.PaymentEventStatusProcessed || output.Record.Attempts != 2 {
+		t.Errorf("expected processed record on the second attempt, got: %+v", output.Record)
+	}
+}
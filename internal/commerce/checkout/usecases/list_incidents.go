@@ -0,0 +1,42 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+var ErrInvalidIncidentFilter = errors.New("invalid incident filter")
+
+// ListIncidentsInput contiene los filtros de la cola de revisión.
+type ListIncidentsInput struct {
+	Status  checkoutdomain.IncidentStatus // "" = cualquier estado
+	OrderID string                        // "" = cualquier orden
+}
+
+// ListIncidentsOutput contiene los incidentes encontrados.
+type ListIncidentsOutput struct {
+	Incidents []checkoutdomain.Incident
+}
+
+// ListIncidents lista la cola de revisión manual.
+type ListIncidents struct {
+	Repo checkoutdomain.IncidentRepository
+}
+
+// Execute lista los incidentes del más antiguo al más reciente.
+func (uc ListIncidents) Execute(ctx context.Context, input ListIncidentsInput) (ListIncidentsOutput, error) {
+	if input.Status != "" && !input.Status.IsValid() {
+		return ListIncidentsOutput{}, ErrInvalidIncidentFilter
+	}
+
+	incidents, err := uc.Repo.List(ctx, checkoutdomain.IncidentQuery{
+		Status:  input.Status,
+		OrderID: input.OrderID,
+	})
+	if err != nil {
+		return ListIncidentsOutput{}, err
+	}
+	return ListIncidentsOutput{Incidents: incidents}, nil
+}
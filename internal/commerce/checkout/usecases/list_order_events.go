@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"context"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// ListOrderEventsInput contiene la orden a consultar.
+type ListOrderEventsInput struct {
+	OrderID string
+}
+
+// ListOrderEventsOutput contiene el historial de la orden.
+type ListOrderEventsOutput struct {
+	Events []checkoutdomain.OrderEvent
+}
+
+// ListOrderEvents retorna el historial (timeline) de una orden.
+type ListOrderEvents struct {
+	Repo checkoutdomain.OrderRepository
+}
+
+// Execute lista los eventos de la orden en orden cronológico.
+func (uc ListOrderEvents) Execute(ctx context.Context, input ListOrderEventsInput) (ListOrderEventsOutput, error) {
+	events, err := uc.Repo.ListEvents(ctx, input.OrderID)
+	if err != nil {
+		return ListOrderEventsOutput{}, err
+	}
+	return ListOrderEventsOutput{Events: events}, nil
+}
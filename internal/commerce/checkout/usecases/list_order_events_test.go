@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/platform/audit"
+)
+
+func TestListOrderEvents_RecordsLifecycleWithActor(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTestOrder(t, repo)
+
+	holdID := "hold_events"
+	order.BookingHoldID = &holdID
+	order, _ = repo.Update(context.Background(), order)
+
+	// Pago confirmado por un usuario dentro de un request
+	userCtx := audit.WithRequestID(audit.WithActor(context.Background(), audit.UserActor("user_1")), "req_1")
+	paidAt := time.Date(2026, 1, 13, 10, 0, 0, 0, time.UTC)
+	confirmUC := ConfirmPayment{Repo: repo, Booking: &recordingBookingClient{}, Payments: &recordingPaymentsClient{}}
+	if _, err := confirmUC.Execute(userCtx, ConfirmPaymentInput{OrderID: order.ID, PaymentRef: "tx_events", PaidAt: paidAt}); err != nil {
+		t.Fatalf("unexpected error on confirm: %v", err)
+	}
+
+	// Reintento idempotente: no agrega eventos
+	if _, err := confirmUC.Execute(userCtx, ConfirmPaymentInput{OrderID: order.ID, PaymentRef: "tx_events", PaidAt: paidAt}); err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+
+	output, err := ListOrderEvents{Repo: repo}.Execute(context.Background(), ListOrderEventsInput{OrderID: order.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	types := make([]checkoutdomain.OrderEventType, 0, len(output.Events))
+	for _, event := range output.Events {
+		types = append(types, event.Type)
+	}
+	want := []checkoutdomain.OrderEventType{
+		checkoutdomain.OrderEventCreated,
+		checkoutdomain.OrderEventPaymentConfirmed,
+		checkoutdomain.OrderEventHoldConfirmed,
+	}
+	if len(types) != len(want) {
+		t.Fatalf("expected events %v, got: %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("expected events %v, got: %v", want, types)
+		}
+	}
+
+	created := output.Events[0]
+	if created.Actor != audit.ActorSystem || created.ToStatus != checkoutdomain.OrderStatusPendingPayment {
+		t.Errorf("unexpected created event: %+v", created)
+	}
+	hold := output.Events[2]
+	if hold.Data["hold_id"] != holdID || hold.Actor != "user:user_1" || hold.RequestID != "req_1" {
+		t.Errorf("unexpected hold event: %+v", hold)
+	}
+	paid := output.Events[1]
+	if paid.Data["payment_ref"] != "tx_events" || !paid.OccurredAt.Equal(paidAt) ||
+		paid.FromStatus != checkoutdomain.OrderStatusPendingPayment || paid.ToStatus != checkoutdomain.OrderStatusPaid {
+		t.Errorf("unexpected payment event: %+v", paid)
+	}
+}
+
+func TestListOrderEvents_CancelReasonInHistory(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTestOrder(t, repo)
+
+	ctx := audit.WithActor(context.Background(), audit.ActorCartExpiry)
+	cancelUC := CancelOrder{Repo: repo, Booking: &recordingBookingClient{}}
+	if _, err := cancelUC.Execute(ctx, CancelOrderInput{OrderID: order.ID, Reason: checkoutdomain.CancelReasonCartExpired}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := ListOrderEvents{Repo: repo}.Execute(context.Background(), ListOrderEventsInput{OrderID: order.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := output.Events[len(output.Events)-1]
+	if last.Type != checkoutdomain.OrderEventCancelled || last.Actor != audit.ActorCartExpiry ||
+		last.Data["cancel_reason"] != string(checkoutdomain.CancelReasonCartExpired) {
+		t.Errorf("unexpected cancel event: %+v", last)
+	}
+}
+
+func TestListOrderEvents_OrderNotFound(t *testing.T) {
+	_, err := ListOrderEvents{Repo: checkoutmemory.NewOrderRepository()}.Execute(context.Background(), ListOrderEventsInput{OrderID: "order_missing"})
+	if !errors.Is(err, checkoutdomain.ErrOrderNotFound) {
+		t.Errorf("expected ErrOrderNotFound, got: %v", err)
+	}
+}
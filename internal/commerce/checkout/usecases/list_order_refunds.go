@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"context"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// ListOrderRefundsInput contiene la orden a consultar.
+type ListOrderRefundsInput struct {
+	OrderID string
+}
+
+// ListOrderRefundsOutput contiene la orden (con su neto pagado) y sus reembolsos.
+type ListOrderRefundsOutput struct {
+	Order   checkoutdomain.Order
+	Refunds []checkoutdomain.Refund
+}
+
+// ListOrderRefunds retorna los reembolsos emitidos sobre una orden.
+type ListOrderRefunds struct {
+	Repo checkoutdomain.OrderRepository
+}
+
+// Execute lista los reembolsos de la orden en orden cronológico.
+func (uc ListOrderRefunds) Execute(ctx context.Context, input ListOrderRefundsInput) (ListOrderRefundsOutput, error) {
+	order, err := uc.Repo.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return ListOrderRefundsOutput{}, err
+	}
+	refunds, err := uc.Repo.ListRefunds(ctx, input.OrderID)
+	if err != nil {
+		return ListOrderRefundsOutput{}, err
+	}
+	return ListOrderRefundsOutput{Order: order, Refunds: refunds}, nil
+}
@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+var (
+	ErrInvalidOrderCursor = errors.New("invalid order cursor")
+	ErrInvalidOrderFilter = errors.New("invalid order filter")
+)
+
+const (
+	// DefaultOrdersPageSize es el tamaño de página si no se indica Limit.
+	DefaultOrdersPageSize = 20
+	// MaxOrdersPageSize limita el tamaño de página.
+	MaxOrdersPageSize = 100
+)
+
+// ListUserOrdersInput contiene los filtros del historial de órdenes.
+type ListUserOrdersInput struct {
+	UserID string
+	Status checkoutdomain.OrderStatus // "" = cualquier estado
+	From   *time.Time                 // creadas desde (inclusive)
+	To     *time.Time                 // creadas hasta (exclusive)
+	Cursor string                     // NextCursor de la página anterior ("" = primera página)
+	Limit  int                        // 0 = DefaultOrdersPageSize
+}
+
+// ListUserOrdersOutput contiene una página de órdenes.
+type ListUserOrdersOutput struct {
+	Orders     []checkoutdomain.Order
+	NextCursor string // "" = no hay más páginas
+}
+
+// ListUserOrders lista las órdenes de un usuario, de la más nueva a la más antigua, paginadas por cursor.
+type ListUserOrders struct {
+	Repo checkoutdomain.OrderRepository
+}
+
+// Execute retorna una página de órdenes y el cursor de la siguiente.
+func (uc ListUserOrders) Execute(ctx context.Context, input ListUserOrdersInput) (ListUserOrdersOutput, error) {
+	if input.UserID == "" {
+		return ListUserOrdersOutput{}, ErrInvalidOrderFilter
+	}
+	if input.Status != "" && !input.Status.IsValid() {
+		return ListUserOrdersOutput{}, ErrInvalidOrderFilter
+	}
+	if input.From != nil && input.To != nil && !input.From.Before(*input.To) {
+		return ListUserOrdersOutput{}, ErrInvalidOrderFilter
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = DefaultOrdersPageSize
+	}
+	if limit > MaxOrdersPageSize {
+		limit = MaxOrdersPageSize
+	}
+
+	query := checkoutdomain.OrderQuery{
+		UserID: input.UserID,
+		Status: input.Status,
+		From:   input.From,
+		To:     input.To,
+		Limit:  limit + 1, // uno extra para saber si hay otra página
+	}
+	if input.Cursor != "" {
+		after, err := decodeOrderCursor(input.Cursor)
+		if err != nil {
+			return ListUserOrdersOutput{}, err
+		}
+		query.After = &after
+	}
+
+	orders, err := uc.Repo.ListByUser(ctx, query)
+	if err != nil {
+		return ListUserOrdersOutput{}, err
+	}
+
+	var output ListUserOrdersOutput
+	if len(orders) > limit {
+		orders = orders[:limit]
+		last := orders[limit-1]
+		output.NextCursor = encodeOrderCursor(checkoutdomain.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	output.Orders = orders
+
+	return output, nil
+}
+
+// encodeOrderCursor serializa el cursor como token opaco para el cliente.
+func encodeOrderCursor(cursor checkoutdomain.OrderCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeOrderCursor(token string) (checkoutdomain.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return checkoutdomain.OrderCursor{}, ErrInvalidOrderCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return checkoutdomain.OrderCursor{}, ErrInvalidOrderCursor
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return checkoutdomain.OrderCursor{}, ErrInvalidOrderCursor
+	}
+
+	return checkoutdomain.OrderCursor{CreatedAt: parsed, ID: id}, nil
+}
@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// seedUserOrders crea n órdenes del usuario, una por minuto desde base.
+func seedUserOrders(t *testing.T, repo checkoutdomain.OrderRepository, userID string, base time.Time, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		status := checkoutdomain.OrderStatusPendingPayment
+		if i%2 == 1 {
+			status = checkoutdomain.OrderStatusPaid
+		}
+		order := checkoutdomain.Order{
+			ID:        userID + "_order_" + string(rune('a'+i)),
+			UserID:    userID,
+			Status:    status,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}
+		if _, err := repo.Create(context.Background(), order); err != nil {
+			t.Fatalf("failed to seed order: %v", err)
+		}
+	}
+}
+
+func TestListUserOrders_PaginatesNewestFirst(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	base := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	seedUserOrders(t, repo, "user_1", base, 5)
+	seedUserOrders(t, repo, "user_2", base, 2)
+
+	uc := ListUserOrders{Repo: repo}
+
+	seen := make([]string, 0)
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		output, err := uc.Execute(context.Background(), ListUserOrdersInput{UserID: "user_1", Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, order := range output.Orders {
+			seen = append(seen, order.ID)
+		}
+		cursor = output.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+
+	want := []string{"user_1_order_e", "user_1_order_d", "user_1_order_c", "user_1_order_b", "user_1_order_a"}
+	if len(seen) != len(want) {
+		t.Fatalf("expected %v, got: %v", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("expected %v, got: %v", want, seen)
+		}
+	}
+}
+
+func TestListUserOrders_Filters(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	base := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	seedUserOrders(t, repo, "user_1", base, 5)
+
+	uc := ListUserOrders{Repo: repo}
+
+	paid, err := uc.Execute(context.Background(), ListUserOrdersInput{UserID: "user_1", Status: checkoutdomain.OrderStatusPaid})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paid.Orders) != 2 || paid.NextCursor != "" {
+		t.Errorf("expected 2 paid orders in a single page, got: %d (cursor %q)", len(paid.Orders), paid.NextCursor)
+	}
+
+	from := base.Add(time.Minute)
+	to := base.Add(3 * time.Minute)
+	window, err := uc.Execute(context.Background(), ListUserOrdersInput{UserID: "user_1", From: &from, To: &to})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(window.Orders) != 2 || window.Orders[0].ID != "user_1_order_c" || window.Orders[1].ID != "user_1_order_b" {
+		t.Errorf("expected orders c and b in [from, to), got: %+v", window.Orders)
+	}
+}
+
+func TestListUserOrders_InvalidInput(t *testing.T) {
+	uc := ListUserOrders{Repo: checkoutmemory.NewOrderRepository()}
+
+	if _, err := uc.Execute(context.Background(), ListUserOrdersInput{UserID: "user_1", Cursor: "not-a-cursor"}); !errors.Is(err, ErrInvalidOrderCursor) {
+		t.Errorf("expected ErrInvalidOrderCursor, got: %v", err)
+	}
+	if _, err := uc.Execute(context.Background(), ListUserOrdersInput{UserID: "user_1", Status: "shipped"}); !errors.Is(err, ErrInvalidOrderFilter) {
+		t.Errorf("expected ErrInvalidOrderFilter for unknown status, got: %v", err)
+	}
+	if _, err := uc.Execute(context.Background(), ListUserOrdersInput{}); !errors.Is(err, ErrInvalidOrderFilter) {
+		t.Errorf("expected ErrInvalidOrderFilter without user, got: %v", err)
+	}
+}
+
+func TestGetOrder_ChecksOwnership(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	seedUserOrders(t, repo, "user_1", time.Now(), 1)
+	uc := GetOrder{Repo: repo}
+
+	if _, err := uc.Execute(context.Background(), GetOrderInput{OrderID: "user_1_order_a", UserID: "user_1"}); err != nil {
+		t.Fatalf("expected owner to read the order, got: %v", err)
+	}
+	if _, err := uc.Execute(context.Background(), GetOrderInput{OrderID: "user_1_order_a", UserID: "user_2"}); !errors.Is(err, checkoutdomain.ErrOrderNotFound) {
+		t.Errorf("expected ErrOrderNotFound for another user, got: %v", err)
+	}
+}
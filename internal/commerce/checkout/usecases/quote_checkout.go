@@ -3,9 +3,9 @@ package usecases
 import (
 	"context"
 	"errors"
-	"fmt"
 
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
 	servicedomain "paku-commerce/internal/commerce/service/domain"
 	pricingdomain "paku-commerce/internal/pricing/domain"
 	pricingusecases "paku-commerce/internal/pricing/usecases"
@@ -35,19 +35,27 @@ type QuoteCheckoutInput struct {
 
 // QuoteCheckoutOutput contiene la cotización completa.
 type QuoteCheckoutOutput struct {
-	Quote CheckoutQuote
+	Quote  CheckoutQuote
+	Intent checkoutdomain.PurchaseIntent // intención cotizada, con los perfiles de mascota resueltos
 }
 
 // QuoteCheckout valida y cotiza una intención de compra.
 type QuoteCheckout struct {
-	ServiceRepo  servicedomain.ServiceRepository
-	PriceQuoteUC *pricingusecases.QuoteItems
-	PromotionsUC *promotionsusecases.ApplyDiscounts
+	ServiceRepo   servicedomain.ServiceRepository
+	PriceRuleRepo pricingdomain.PriceRuleRepository // opcional: valida productos conocidos
+	PriceQuoteUC  *pricingusecases.QuoteItems
+	PromotionsUC  *promotionsusecases.ApplyDiscounts
+	Pets          platformpets.Client // opcional: resuelve perfiles enviados solo por pet_id
 }
 
 // Execute ejecuta la cotización del checkout.
 func (uc QuoteCheckout) Execute(ctx context.Context, input QuoteCheckoutInput) (QuoteCheckoutOutput, error) {
-	intent := input.Intent
+	// 0. Resolver perfiles enviados por ID
+	resolved, err := ResolvePetProfiles{Pets: uc.Pets}.Execute(ctx, ResolvePetProfilesInput{Intent: input.Intent})
+	if err != nil {
+		return QuoteCheckoutOutput{}, err
+	}
+	intent := resolved.Intent
 
 	// 1. Validar items
 	if err := uc.validateItems(ctx, intent); err != nil {
@@ -57,14 +65,20 @@ func (uc QuoteCheckout) Execute(ctx context.Context, input QuoteCheckoutInput) (
 	// 2. Construir request para pricing
 	priceRequest := pricingusecases.QuoteItemsInput{
 		PetProfile: intent.PetProfile,
+		Pets:       make(map[string]servicedomain.PetProfile, len(intent.Pets)),
 		Items:      make([]pricingusecases.QuoteRequestItem, 0, len(intent.Items)),
 	}
 
+	for _, pet := range intent.Pets {
+		priceRequest.Pets[pet.ID] = pet.PetProfile
+	}
+
 	for _, item := range intent.Items {
 		priceRequest.Items = append(priceRequest.Items, pricingusecases.QuoteRequestItem{
 			ItemType: pricingdomain.ItemType(item.ItemType),
 			ItemID:   item.ItemID,
 			Qty:      item.Qty,
+			PetID:    item.PetID,
 		})
 	}
 
@@ -99,52 +113,28 @@ func (uc QuoteCheckout) Execute(ctx context.Context, input QuoteCheckoutInput) (
 			TotalDiscount:    promoOutput.TotalDiscount,
 			Total:            total,
 		},
+		Intent: intent,
 	}, nil
 }
 
-// validateItems valida la intención de compra.
+// validateItems valida la intención de compra y retorna la primera violación.
 func (uc QuoteCheckout) validateItems(ctx context.Context, intent checkoutdomain.PurchaseIntent) error {
-	// Mapa para tracking de servicios presentes
-	serviceItems := make(map[string]bool)
-
-	// Primera pasada: registrar servicios
-	for _, item := range intent.Items {
-		if item.Qty <= 0 {
-			return ErrInvalidQuantity
-		}
-		if item.ItemType == checkoutdomain.ItemTypeService {
-			serviceItems[item.ItemID] = true
-		}
+	if err := checkoutdomain.ValidatePets(intent.Pets); err != nil {
+		return err
 	}
 
-	// Segunda pasada: validar servicios
-	for _, item := range intent.Items {
-		if item.ItemType == checkoutdomain.ItemTypeService {
-			service, err := uc.ServiceRepo.GetServiceByID(ctx, item.ItemID)
-			if err != nil {
-				return fmt.Errorf("%w: %w", ErrUnknownService, err)
-			}
-
-			// Validar elegibilidad
-			if !service.IsEligibleFor(intent.PetProfile) {
-				return ErrServiceNotEligible
-			}
-
-			// Validar dependencias addon/parent
-			if service.IsAddon && len(service.RequiresParentIDs) > 0 {
-				hasParent := false
-				for _, parentID := range service.RequiresParentIDs {
-					if serviceItems[parentID] {
-						hasParent = true
-						break
-					}
-				}
-				if !hasParent {
-					return ErrMissingParentService
-				}
-			}
-		}
-		// Products: no validar nada adicional en este prompt
+	validator := ValidateItems{ServiceRepo: uc.ServiceRepo, PriceRuleRepo: uc.PriceRuleRepo}
+
+	output, err := validator.Execute(ctx, ValidateItemsInput{
+		PetProfile: intent.PetProfile,
+		Pets:       intent.Pets,
+		Items:      intent.Items,
+	})
+	if err != nil {
+		return err
+	}
+	if len(output.Violations) > 0 {
+		return output.Violations[0].Reason
 	}
 
 	return nil
@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// ErrInvalidAppointmentOutcome indica un resultado de cita desconocido.
+var ErrInvalidAppointmentOutcome = errors.New("invalid appointment outcome")
+
+// AppointmentOutcome es el resultado de la cita de una orden pagada.
+type AppointmentOutcome string
+
+const (
+	AppointmentOutcomeFulfilled AppointmentOutcome = "fulfilled"
+	AppointmentOutcomeNoShow    AppointmentOutcome = "no_show"
+)
+
+// RecordAppointmentOutcomeInput contiene la orden y el resultado de su cita.
+type RecordAppointmentOutcomeInput struct {
+	OrderID string
+	Outcome AppointmentOutcome
+}
+
+// RecordAppointmentOutcomeOutput contiene la orden actualizada.
+type RecordAppointmentOutcomeOutput struct {
+	Order checkoutdomain.Order
+}
+
+// RecordAppointmentOutcome marca una orden pagada como fulfilled o no_show después de la cita.
+type RecordAppointmentOutcome struct {
+	Repo checkoutdomain.OrderRepository
+	Now  func() time.Time
+}
+
+// Execute registra el resultado de forma idempotente.
+// Si otra operación modificó la orden en paralelo, recarga y reintenta.
+func (uc RecordAppointmentOutcome) Execute(ctx context.Context, input RecordAppointmentOutcomeInput) (RecordAppointmentOutcomeOutput, error) {
+	if input.Outcome != AppointmentOutcomeFulfilled && input.Outcome != AppointmentOutcomeNoShow {
+		return RecordAppointmentOutcomeOutput{}, ErrInvalidAppointmentOutcome
+	}
+
+	var output RecordAppointmentOutcomeOutput
+	err := retryOnVersionConflict(func() error {
+		var err error
+		output, err = uc.execute(ctx, input)
+		return err
+	})
+	if err != nil {
+		return RecordAppointmentOutcomeOutput{}, err
+	}
+	return output, nil
+}
+
+func (uc RecordAppointmentOutcome) execute(ctx context.Context, input RecordAppointmentOutcomeInput) (RecordAppointmentOutcomeOutput, error) {
+	// 1. Cargar la orden
+	order, err := uc.Repo.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return RecordAppointmentOutcomeOutput{}, err
+	}
+
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	// 2. Aplicar la transición (idempotente si ya tiene ese resultado)
+	previousStatus := order.Status
+	if input.Outcome == AppointmentOutcomeFulfilled {
+		err = order.MarkFulfilled(now)
+	} else {
+		err = order.MarkNoShow(now)
+	}
+	if err != nil {
+		return RecordAppointmentOutcomeOutput{}, err
+	}
+
+	if order.Status == previousStatus {
+		return RecordAppointmentOutcomeOutput{Order: order}, nil
+	}
+
+	// 3. Persistir (compare-and-swap por version)
+	updatedOrder, err := uc.Repo.Update(ctx, order)
+	if err != nil {
+		return RecordAppointmentOutcomeOutput{}, err
+	}
+
+	return RecordAppointmentOutcomeOutput{Order: updatedOrder}, nil
+}
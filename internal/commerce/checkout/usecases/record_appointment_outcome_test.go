@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+func createPaidTestOrder(t *testing.T, repo checkoutdomain.OrderRepository) checkoutdomain.Order {
+	t.Helper()
+	order := createTestOrder(t, repo)
+	if err := order.MarkPaid("tx_paid", time.Now()); err != nil {
+		t.Fatalf("failed to mark paid: %v", err)
+	}
+	updated, err := repo.Update(context.Background(), order)
+	if err != nil {
+		t.Fatalf("failed to persist paid order: %v", err)
+	}
+	return updated
+}
+
+func TestRecordAppointmentOutcome_Fulfilled(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createPaidTestOrder(t, repo)
+
+	fulfilledAt := time.Date(2026, 1, 20, 15, 0, 0, 0, time.UTC)
+	uc := RecordAppointmentOutcome{Repo: repo, Now: func() time.Time { return fulfilledAt }}
+
+	output, err := uc.Execute(context.Background(), RecordAppointmentOutcomeInput{
+		OrderID: order.ID,
+		Outcome: AppointmentOutcomeFulfilled,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusFulfilled {
+		t.Errorf("expected fulfilled, got: %v", output.Order.Status)
+	}
+	if output.Order.FulfilledAt == nil || !output.Order.FulfilledAt.Equal(fulfilledAt) {
+		t.Errorf("expected fulfilled_at %v, got: %v", fulfilledAt, output.Order.FulfilledAt)
+	}
+
+	// Idempotente: no vuelve a escribir
+	again, err := uc.Execute(context.Background(), RecordAppointmentOutcomeInput{
+		OrderID: order.ID,
+		Outcome: AppointmentOutcomeFulfilled,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if again.Order.Version != output.Order.Version {
+		t.Errorf("expected no new version, got: %d -> %d", output.Order.Version, again.Order.Version)
+	}
+}
+
+func TestRecordAppointmentOutcome_IllegalTransition(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	uc := RecordAppointmentOutcome{Repo: repo}
+
+	// pending_payment -> no_show no está permitido
+	pending := createTestOrder(t, repo)
+	_, err := uc.Execute(context.Background(), RecordAppointmentOutcomeInput{
+		OrderID: pending.ID,
+		Outcome: AppointmentOutcomeNoShow,
+	})
+	var transitionErr *checkoutdomain.TransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("expected TransitionError, got: %v", err)
+	}
+	if transitionErr.From != checkoutdomain.OrderStatusPendingPayment || transitionErr.To != checkoutdomain.OrderStatusNoShow {
+		t.Errorf("unexpected transition error: %+v", transitionErr)
+	}
+	if !errors.Is(err, checkoutdomain.ErrInvalidOrderState) {
+		t.Errorf("expected ErrInvalidOrderState, got: %v", err)
+	}
+
+	// no_show -> fulfilled tampoco
+	paid := createPaidTestOrder(t, repo)
+	if _, err := uc.Execute(context.Background(), RecordAppointmentOutcomeInput{OrderID: paid.ID, Outcome: AppointmentOutcomeNoShow}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = uc.Execute(context.Background(), RecordAppointmentOutcomeInput{OrderID: paid.ID, Outcome: AppointmentOutcomeFulfilled})
+	if !errors.Is(err, checkoutdomain.ErrInvalidOrderState) {
+		t.Errorf("expected ErrInvalidOrderState, got: %v", err)
+	}
+}
+
+func TestRecordAppointmentOutcome_UnknownOutcome(t *testing.T) {
+	uc := RecordAppointmentOutcome{Repo: checkoutmemory.NewOrderRepository()}
+
+	_, err := uc.Execute(context.Background(), RecordAppointmentOutcomeInput{OrderID: "order_1", Outcome: "rescheduled"})
+	if !errors.Is(err, ErrInvalidAppointmentOutcome) {
+		t.Errorf("expected ErrInvalidAppointmentOutcome, got: %v", err)
+	}
+}
+
+func TestOrder_CancelledOrder_CannotBePaid(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTestOrder(t, repo)
+
+	cancelledAt := time.Now()
+	if err := order.MarkCancelled(checkoutdomain.CancelReasonRequested, cancelledAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.CancelledAt == nil || !order.CancelledAt.Equal(cancelledAt) {
+		t.Errorf("expected cancelled_at to be recorded")
+	}
+
+	err := order.MarkPaid("tx_late", time.Now())
+	if !errors.Is(err, checkoutdomain.ErrOrderCancelled) || !errors.Is(err, checkoutdomain.ErrInvalidOrderState) {
+		t.Errorf("expected ErrOrderCancelled and ErrInvalidOrderState, got: %v", err)
+	}
+}
+
+func TestOrder_FailedPaymentCanBeRetried(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTestOrder(t, repo)
+	now := time.Now()
+
+	if err := order.MarkProcessing(now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := order.MarkFailed("", now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := order.MarkPaid("tx_retry", now); err != nil {
+		t.Fatalf("expected failed -> paid to be allowed, got: %v", err)
+	}
+	if err := order.MarkRefunded(now); err != nil {
+		t.Fatalf("expected paid -> refunded to be allowed, got: %v", err)
+	}
+	if order.ProcessingAt == nil || order.FailedAt == nil || order.PaidAt == nil || order.RefundedAt == nil {
+		t.Errorf("expected a timestamp per transition, got: %+v", order)
+	}
+
+	// refunded es terminal, pero se sigue considerando pagada (idempotencia de ConfirmPayment)
+	if err := order.MarkPaid("tx_retry", now); err != nil {
+		t.Errorf("expected idempotent MarkPaid on refunded order, got: %v", err)
+	}
+	if err := order.MarkFulfilled(now); !errors.Is(err, checkoutdomain.ErrInvalidOrderState) {
+		t.Errorf("expected refunded to be terminal, got: %v", err)
+	}
+}
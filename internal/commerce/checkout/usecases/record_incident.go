@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// RecordIncidentInput contiene la operación que falló.
+type RecordIncidentInput struct {
+	OrderID   string // "" si el hold no llegó a tener orden
+	HoldID    string
+	Operation checkoutdomain.IncidentOperation
+	Err       error
+}
+
+// RecordIncidentOutput contiene el incidente registrado.
+type RecordIncidentOutput struct {
+	Incident checkoutdomain.Incident
+}
+
+// RecordIncident agrega a la cola de revisión manual un side effect que no se pudo completar.
+type RecordIncident struct {
+	Repo checkoutdomain.IncidentRepository
+	Now  func() time.Time
+}
+
+// Execute registra un incidente abierto. Si ya hay uno abierto para la misma orden, hold y
+// operación (p. ej. el barrido de carritos y CancelOrder fallan sobre el mismo hold), lo retorna.
+func (uc RecordIncident) Execute(ctx context.Context, input RecordIncidentInput) (RecordIncidentOutput, error) {
+	open, err := uc.Repo.List(ctx, checkoutdomain.IncidentQuery{
+		Status:  checkoutdomain.IncidentStatusOpen,
+		OrderID: input.OrderID,
+	})
+	if err != nil {
+		return RecordIncidentOutput{}, err
+	}
+	for _, incident := range open {
+		if incident.OrderID == input.OrderID && incident.HoldID == input.HoldID && incident.Operation == input.Operation {
+			return RecordIncidentOutput{Incident: incident}, nil
+		}
+	}
+
+	incidentID, err := generateIncidentID()
+	if err != nil {
+		return RecordIncidentOutput{}, fmt.Errorf("failed to generate incident ID: %w", err)
+	}
+
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	incident := checkoutdomain.NewIncident(incidentID, input.OrderID, input.HoldID, input.Operation, input.Err, now)
+	created, err := uc.Repo.Create(ctx, incident)
+	if err != nil {
+		return RecordIncidentOutput{}, err
+	}
+	return RecordIncidentOutput{Incident: created}, nil
+}
+
+// reportIncident registra la falla si hay cola de revisión configurada.
+// Es best-effort: quien la llama ya decidió no fallar por este side effect.
+func reportIncident(ctx context.Context, uc *RecordIncident, input RecordIncidentInput) {
+	if uc == nil {
+		return
+	}
+	_, _ = uc.Execute(ctx, input)
+}
+
+// generateIncidentID genera un ID único para el incidente.
+func generateIncidentID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "inc_" + hex.EncodeToString(b), nil
+}
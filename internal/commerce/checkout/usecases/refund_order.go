@@ -0,0 +1,195 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+	"paku-commerce/internal/platform/lock"
+)
+
+var (
+	ErrMissingRefundKey  = errors.New("refund key is required")
+	ErrRefundFailed      = errors.New("payment provider refund failed")
+	ErrRefundInProgress  = errors.New("another refund of this order is in progress")
+	ErrRefundNotRecorded = errors.New("refund issued by the provider but not recorded on the order")
+)
+
+// RefundOrderLockKeyPrefix antecede al ID de la orden en el lock que serializa sus reembolsos.
+const RefundOrderLockKeyPrefix = "refund-order:"
+
+// RefundOrderInput contiene el reembolso a emitir.
+type RefundOrderInput struct {
+	OrderID string
+	Key     string // clave de idempotencia: reintentar con la misma clave no reembolsa dos veces
+	Lines   []int  // posiciones de los items a reembolsar (vacío = todo lo reembolsable)
+}
+
+// RefundOrderOutput contiene la orden actualizada y el reembolso emitido.
+type RefundOrderOutput struct {
+	Order  checkoutdomain.Order
+	Refund checkoutdomain.Refund
+}
+
+// RefundOrder emite un reembolso total o por líneas sobre el pago de una orden, por el
+// mismo proveedor que capturó el cobro (ver Order.PaidWith).
+type RefundOrder struct {
+	Repo       checkoutdomain.OrderRepository
+	Payments   payments.PaymentsClient // cobros con tarjeta
+	QRPayments payments.PaymentsClient // cobros QR (Yape/Plin); nil = sin proveedor QR
+	Locker     lock.Locker             // opcional: un solo reembolso por orden a la vez (entre réplicas)
+
+	RecordIncidentUC *RecordIncident // opcional: reembolsos emitidos que no se pudieron registrar
+	Now              func() time.Time
+}
+
+// Execute reembolsa de forma idempotente por Key. El proveedor se llama una sola vez por
+// ejecución: si otra operación modificó la orden en paralelo, solo se recarga y reintenta
+// el registro del reembolso. Con Locker, dos reembolsos de la misma orden no se planifican
+// a la vez (el segundo recibe ErrRefundInProgress), así dos claves no pagan las mismas
+// líneas. Si el registro igual falla tras reembolsar, queda un incidente record_refund y se
+// retorna ErrRefundNotRecorded; reintentar con la misma Key no reembolsa dos veces.
+func (uc RefundOrder) Execute(ctx context.Context, input RefundOrderInput) (RefundOrderOutput, error) {
+	if input.Key == "" {
+		return RefundOrderOutput{}, ErrMissingRefundKey
+	}
+	if uc.Locker != nil {
+		unlock, acquired, err := uc.Locker.TryLock(ctx, RefundOrderLockKeyPrefix+input.OrderID)
+		if err != nil {
+			return RefundOrderOutput{}, err
+		}
+		if !acquired {
+			return RefundOrderOutput{}, ErrRefundInProgress
+		}
+		defer unlock()
+	}
+	lines := append([]int(nil), input.Lines...)
+	sort.Ints(lines)
+
+	// 1. Planificar: idempotencia por Key y monto a reembolsar
+	order, existing, err := uc.plan(ctx, input.OrderID, input.Key, lines)
+	if err != nil {
+		return RefundOrderOutput{}, err
+	}
+	if existing != nil {
+		return RefundOrderOutput{Order: order, Refund: *existing}, nil
+	}
+	amount, err := order.PlanRefund(lines)
+	if err != nil {
+		return RefundOrderOutput{}, err
+	}
+
+	// 2. Emitir el reembolso en el proveedor (fuera del reintento por versión)
+	client, err := uc.client(order)
+	if err != nil {
+		return RefundOrderOutput{}, err
+	}
+	result, err := client.Refund(ctx, payments.RefundRequest{
+		PaymentRef:     *order.PaymentRef,
+		Amount:         amount,
+		IdempotencyKey: order.ID + ":" + input.Key,
+	})
+	if err != nil {
+		return RefundOrderOutput{}, fmt.Errorf("%w: %v", ErrRefundFailed, err)
+	}
+
+	// 3. Registrar en la orden (compare-and-swap; ante conflicto recarga y reintenta)
+	refund := checkoutdomain.Refund{
+		Key:         input.Key,
+		PaymentRef:  *order.PaymentRef,
+		Amount:      amount,
+		Lines:       lines,
+		ProviderRef: result.ProviderRef,
+	}
+	var output RefundOrderOutput
+	err = retryOnVersionConflict(func() error {
+		var err error
+		output, err = uc.record(ctx, input.OrderID, refund)
+		return err
+	})
+	if err != nil {
+		// El dinero ya salió: queda para revisión manual con lo emitido en el proveedor
+		cause := fmt.Errorf("%w: key %s, %d %s (%s): %v", ErrRefundNotRecorded,
+			input.Key, amount.Amount, amount.Currency, result.ProviderRef, err)
+		reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+			OrderID:   input.OrderID,
+			Operation: checkoutdomain.IncidentOperationRecordRefund,
+			Err:       cause,
+		})
+		return RefundOrderOutput{}, cause
+	}
+	return output, nil
+}
+
+// client retorna el cliente del proveedor que capturó el cobro de la orden.
+func (uc RefundOrder) client(order checkoutdomain.Order) (payments.PaymentsClient, error) {
+	if order.PaidWith() != checkoutdomain.PaymentProviderQR {
+		return uc.Payments, nil
+	}
+	if uc.QRPayments == nil {
+		return nil, fmt.Errorf("%w: QR payment provider is not configured", ErrRefundFailed)
+	}
+	return uc.QRPayments, nil
+}
+
+// plan carga la orden y retorna el reembolso ya emitido con la misma clave, si lo hay.
+func (uc RefundOrder) plan(ctx context.Context, orderID, key string, lines []int) (checkoutdomain.Order, *checkoutdomain.Refund, error) {
+	order, err := uc.Repo.GetByID(ctx, orderID)
+	if err != nil {
+		return checkoutdomain.Order{}, nil, err
+	}
+	refunds, err := uc.Repo.ListRefunds(ctx, orderID)
+	if err != nil {
+		return checkoutdomain.Order{}, nil, err
+	}
+
+	// Idempotencia: la misma clave retorna el reembolso ya emitido
+	for _, refund := range refunds {
+		if refund.Key != key {
+			continue
+		}
+		if !refund.SameLines(lines) {
+			return checkoutdomain.Order{}, nil, checkoutdomain.ErrRefundKeyConflict
+		}
+		return order, &refund, nil
+	}
+	return order, nil, nil
+}
+
+// record aplica el reembolso emitido sobre la orden recargada y la persiste.
+func (uc RefundOrder) record(ctx context.Context, orderID string, refund checkoutdomain.Refund) (RefundOrderOutput, error) {
+	order, existing, err := uc.plan(ctx, orderID, refund.Key, refund.Lines)
+	if err != nil {
+		return RefundOrderOutput{}, err
+	}
+	if existing != nil {
+		// Una ejecución concurrente con la misma clave ya lo registró
+		return RefundOrderOutput{Order: order, Refund: *existing}, nil
+	}
+
+	// Revalidar sobre la orden recargada (otro reembolso pudo cubrir las mismas líneas)
+	if _, err := order.PlanRefund(refund.Lines); err != nil {
+		return RefundOrderOutput{}, err
+	}
+
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+	if err := order.ApplyRefund(refund, now); err != nil {
+		return RefundOrderOutput{}, err
+	}
+
+	updatedOrder, err := uc.Repo.Update(ctx, order)
+	if err != nil {
+		return RefundOrderOutput{}, err
+	}
+
+	refund.OrderID = updatedOrder.ID
+	refund.CreatedAt = now
+	return RefundOrderOutput{Order: updatedOrder, Refund: refund}, nil
+}
@@ -0,0 +1,297 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+	"paku-commerce/internal/platform/lock"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// recordingPaymentsClient registra los reembolsos pedidos al proveedor.
+type recordingPaymentsClient struct {
+	refunds []payments.RefundRequest
+	err     error
+}
+
+func (c *recordingPaymentsClient) ValidatePayment(ctx context.Context, paymentRef string, expected pricingdomain.Money) error {
+	return nil
+}
+
+func (c *recordingPaymentsClient) Refund(ctx context.Context, req payments.RefundRequest) (payments.RefundResult, error) {
+	if c.err != nil {
+		return payments.RefundResult{}, c.err
+	}
+	c.refunds = append(c.refunds, req)
+	return payments.RefundResult{ProviderRef: "re_" + req.IdempotencyKey}, nil
+}
+
+// createTwoLineOrder crea una orden pagada de dos líneas (4500 + 4000) con 10% de descuento.
+func createTwoLineOrder(t *testing.T, repo checkoutdomain.OrderRepository) checkoutdomain.Order {
+	t.Helper()
+	pen := pricingdomain.CurrencyPEN
+	order, err := repo.Create(context.Background(), checkoutdomain.Order{
+		ID:        "order_refund",
+		Status:    checkoutdomain.OrderStatusPendingPayment,
+		CreatedAt: time.Now(),
+		Items: []checkoutdomain.OrderItem{
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1, UnitPrice: pricingdomain.NewMoney(4500, pen), LineTotal: pricingdomain.NewMoney(4500, pen)},
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1, UnitPrice: pricingdomain.NewMoney(4000, pen), LineTotal: pricingdomain.NewMoney(4000, pen)},
+		},
+		Subtotal:      pricingdomain.NewMoney(8500, pen),
+		TotalDiscount: pricingdomain.NewMoney(850, pen),
+		Total:         pricingdomain.NewMoney(7650, pen),
+	})
+	if err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+	if err := order.MarkPaid("tx_refund", time.Now()); err != nil {
+		t.Fatalf("failed to mark paid: %v", err)
+	}
+	order, err = repo.Update(context.Background(), order)
+	if err != nil {
+		t.Fatalf("failed to persist paid order: %v", err)
+	}
+	return order
+}
+
+func TestRefundOrder_FullRefund_IdempotentByKey(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createPaidTestOrder(t, repo)
+	provider := &recordingPaymentsClient{}
+	uc := RefundOrder{Repo: repo, Payments: provider}
+
+	output, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusRefunded {
+		t.Errorf("expected refunded, got: %v", output.Order.Status)
+	}
+	if output.Refund.Amount != order.Total || output.Refund.PaymentRef != *order.PaymentRef {
+		t.Errorf("expected refund of %v on %s, got: %+v", order.Total, *order.PaymentRef, output.Refund)
+	}
+	if output.Order.NetPaid().Amount != 0 {
+		t.Errorf("expected net paid 0, got: %v", output.Order.NetPaid())
+	}
+
+	// Misma clave: retorna el reembolso ya emitido sin volver a llamar al proveedor
+	again, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_1"})
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if again.Refund.ProviderRef != output.Refund.ProviderRef {
+		t.Errorf("expected same refund, got: %+v", again.Refund)
+	}
+	if len(provider.refunds) != 1 {
+		t.Errorf("expected a single provider refund, got: %d", len(provider.refunds))
+	}
+
+	// Clave nueva sobre una orden ya reembolsada
+	if _, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_2"}); !errors.Is(err, checkoutdomain.ErrNothingToRefund) {
+		t.Errorf("expected ErrNothingToRefund, got: %v", err)
+	}
+}
+
+func TestRefundOrder_PerLine_ProratesDiscount(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTwoLineOrder(t, repo)
+	uc := RefundOrder{Repo: repo, Payments: &recordingPaymentsClient{}}
+
+	// Línea 1: 4000 con 10% de descuento
+	output, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_addon", Lines: []int{1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Refund.Amount.Amount != 3600 {
+		t.Errorf("expected refund 3600, got: %d", output.Refund.Amount.Amount)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected order to stay paid, got: %v", output.Order.Status)
+	}
+	if output.Order.NetPaid().Amount != 4050 {
+		t.Errorf("expected net paid 4050, got: %d", output.Order.NetPaid().Amount)
+	}
+	if !output.Order.Items[1].Refunded || output.Order.Items[0].Refunded {
+		t.Errorf("expected only line 1 refunded, got: %+v", output.Order.Items)
+	}
+
+	if _, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_again", Lines: []int{1}}); !errors.Is(err, checkoutdomain.ErrLineAlreadyRefunded) {
+		t.Errorf("expected ErrLineAlreadyRefunded, got: %v", err)
+	}
+	if _, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_addon", Lines: []int{0}}); !errors.Is(err, checkoutdomain.ErrRefundKeyConflict) {
+		t.Errorf("expected ErrRefundKeyConflict, got: %v", err)
+	}
+
+	// La última línea reembolsa el resto y la orden queda refunded
+	last, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_bath", Lines: []int{0}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last.Refund.Amount.Amount != 4050 || last.Order.Status != checkoutdomain.OrderStatusRefunded {
+		t.Errorf("expected remaining 4050 and refunded, got: %d %v", last.Refund.Amount.Amount, last.Order.Status)
+	}
+
+	refunds, _ := repo.ListRefunds(context.Background(), order.ID)
+	if len(refunds) != 2 {
+		t.Errorf("expected 2 refund records, got: %d", len(refunds))
+	}
+}
+
+func TestRefundOrder_CancelledWithFee_KeepsFee(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	appointmentAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	order := createBookedTestOrder(t, repo, "user_1", appointmentAt)
+	uc := RefundOrder{Repo: repo, Payments: &recordingPaymentsClient{}}
+
+	// La cancelación emite el reembolso de lo pagado menos la penalidad
+	cancelled, err := RequestCancellation{
+		Repo:          repo,
+		Booking:       &recordingBookingClient{},
+		Policy:        checkoutdomain.DefaultCancellationPolicy(),
+		RefundOrderUC: &uc,
+		Now:           func() time.Time { return appointmentAt.Add(-time.Hour) },
+	}.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID})
+	if err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+	if cancelled.Order.Status != checkoutdomain.OrderStatusCancelled {
+		t.Errorf("expected order to stay cancelled, got: %v", cancelled.Order.Status)
+	}
+	if cancelled.Order.NetPaid() != cancelled.Fee {
+		t.Errorf("expected net paid to be the fee %v, got: %v", cancelled.Fee, cancelled.Order.NetPaid())
+	}
+
+	// La penalidad retenida no se puede reembolsar con otra clave
+	if _, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_cancel"}); !errors.Is(err, checkoutdomain.ErrNothingToRefund) {
+		t.Errorf("expected ErrNothingToRefund, got: %v", err)
+	}
+}
+
+func TestRefundOrder_Rejections(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+
+	unpaid := createTestOrder(t, repo)
+	uc := RefundOrder{Repo: repo, Payments: &recordingPaymentsClient{}}
+	if _, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: unpaid.ID, Key: "rf"}); !errors.Is(err, checkoutdomain.ErrRefundWithoutPayment) {
+		t.Errorf("expected ErrRefundWithoutPayment, got: %v", err)
+	}
+	if _, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: unpaid.ID}); !errors.Is(err, ErrMissingRefundKey) {
+		t.Errorf("expected ErrMissingRefundKey, got: %v", err)
+	}
+
+	paid := createPaidTestOrder(t, repo)
+	if _, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: paid.ID, Key: "rf", Lines: []int{5}}); !errors.Is(err, checkoutdomain.ErrInvalidRefundLine) {
+		t.Errorf("expected ErrInvalidRefundLine, got: %v", err)
+	}
+
+	// Si el proveedor falla, la orden no cambia
+	failing := RefundOrder{Repo: repo, Payments: &recordingPaymentsClient{err: errors.New("provider down")}}
+	if _, err := failing.Execute(context.Background(), RefundOrderInput{OrderID: paid.ID, Key: "rf"}); !errors.Is(err, ErrRefundFailed) {
+		t.Errorf("expected ErrRefundFailed, got: %v", err)
+	}
+	stored, _ := repo.GetByID(context.Background(), paid.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid || stored.RefundedAmount.Amount != 0 {
+		t.Errorf("expected order untouched, got: %v refunded=%v", stored.Status, stored.RefundedAmount)
+	}
+}
+
+// bumpingOrderRepo simula otra escritura sobre la orden justo antes del primer Update.
+type bumpingOrderRepo struct {
+	*checkoutmemory.OrderRepository
+	bumped bool
+}
+
+func (r *bumpingOrderRepo) Update(ctx context.Context, order checkoutdomain.Order) (checkoutdomain.Order, error) {
+	if !r.bumped {
+		r.bumped = true
+		concurrent, _ := r.OrderRepository.GetByID(ctx, order.ID)
+		if _, err := r.OrderRepository.Update(ctx, concurrent); err != nil {
+			return checkoutdomain.Order{}, err
+		}
+	}
+	return r.OrderRepository.Update(ctx, order)
+}
+
+func TestRefundOrder_VersionConflict_RefundsOnce(t *testing.T) {
+	repo := &bumpingOrderRepo{OrderRepository: checkoutmemory.NewOrderRepository()}
+	order := createPaidTestOrder(t, repo.OrderRepository)
+	provider := &recordingPaymentsClient{}
+	uc := RefundOrder{Repo: repo, Payments: provider}
+
+	output, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_race"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusRefunded {
+		t.Errorf("expected refunded after reload, got: %v", output.Order.Status)
+	}
+	if len(provider.refunds) != 1 {
+		t.Errorf("expected a single provider refund across the conflict retry, got: %d", len(provider.refunds))
+	}
+}
+
+// reentrantPaymentsClient ejecuta otro reembolso de la misma orden mientras el proveedor
+// procesa el primero (simula dos requests concurrentes con claves distintas).
+type reentrantPaymentsClient struct {
+	recordingPaymentsClient
+	during func()
+}
+
+func (c *reentrantPaymentsClient) Refund(ctx context.Context, req payments.RefundRequest) (payments.RefundResult, error) {
+	if during := c.during; during != nil {
+		c.during = nil
+		during()
+	}
+	return c.recordingPaymentsClient.Refund(ctx, req)
+}
+
+func TestRefundOrder_TwoKeysSameLines_RefundsOnce(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTwoLineOrder(t, repo)
+	provider := &reentrantPaymentsClient{}
+	uc := RefundOrder{Repo: repo, Payments: provider, Locker: lock.NewMemoryLocker()}
+
+	var concurrentErr error
+	provider.during = func() {
+		_, concurrentErr = uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_b", Lines: []int{0}})
+	}
+	output, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_a", Lines: []int{0}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(concurrentErr, ErrRefundInProgress) {
+		t.Errorf("expected ErrRefundInProgress for the concurrent key, got: %v", concurrentErr)
+	}
+	if len(provider.refunds) != 1 || output.Refund.Key != "rf_a" {
+		t.Errorf("expected a single provider refund for rf_a, got: %d %+v", len(provider.refunds), output.Refund)
+	}
+}
+
+func TestRefundOrder_IssuedButNotRecorded_OpensIncident(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	incidents := checkoutmemory.NewIncidentRepository()
+	order := createTwoLineOrder(t, repo)
+	provider := &reentrantPaymentsClient{}
+	// Sin Locker (p. ej. un reembolso iniciado desde el proveedor): otra clave registra la línea primero
+	uc := RefundOrder{Repo: repo, Payments: provider, RecordIncidentUC: &RecordIncident{Repo: incidents}}
+	provider.during = func() {
+		if _, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_b", Lines: []int{0}}); err != nil {
+			t.Fatalf("unexpected concurrent error: %v", err)
+		}
+	}
+
+	_, err := uc.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_a", Lines: []int{0}})
+	if !errors.Is(err, ErrRefundNotRecorded) {
+		t.Fatalf("expected ErrRefundNotRecorded, got: %v", err)
+	}
+	listed, _ := incidents.List(context.Background(), checkoutdomain.IncidentQuery{OrderID: order.ID})
+	if len(listed) != 1 || listed[0].Operation != checkoutdomain.IncidentOperationRecordRefund {
+		t.Errorf("expected a record_refund incident, got: %+v", listed)
+	}
+}
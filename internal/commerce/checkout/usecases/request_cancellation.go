@@ -0,0 +1,131 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformbooking "paku-commerce/internal/commerce/platform/booking"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+)
+
+// CancellationRefundKey es la clave de idempotencia del reembolso que emite la cancelación
+// de una orden pagada: repetir la cancelación no reembolsa dos veces.
+const CancellationRefundKey = "cancel"
+
+// RequestCancellationInput contiene la orden a cancelar y quién lo pide.
+type RequestCancellationInput struct {
+	OrderID string
+	UserID  string // "" = sin control de dueño (uso admin)
+}
+
+// RequestCancellationOutput contiene la orden cancelada con penalidad y reembolso.
+type RequestCancellationOutput struct {
+	Order  checkoutdomain.Order
+	Fee    pricingdomain.Money
+	Refund pricingdomain.Money // emitido, o pendiente si el proveedor falló (queda en la cola de revisión)
+}
+
+// RequestCancellation cancela una orden a pedido del usuario aplicando la política de cancelación.
+// A diferencia de CancelOrder (expiraciones), también cancela órdenes pagadas y les
+// reembolsa lo pagado menos la penalidad.
+type RequestCancellation struct {
+	Repo             checkoutdomain.OrderRepository
+	Booking          platformbooking.Client
+	Policy           checkoutdomain.CancellationPolicy
+	RefundOrderUC    *RefundOrder    // emite el reembolso de las órdenes pagadas
+	RecordIncidentUC *RecordIncident // opcional: registra en la cola de revisión los holds y reembolsos fallidos
+	// CancelPaymentIntentUC (opcional) anula en el proveedor el QR pendiente de la orden.
+	CancelPaymentIntentUC *CancelPendingPaymentIntent
+	Now                   func() time.Time
+}
+
+// Execute cancela la orden de forma idempotente, libera su hold y, si estaba pagada,
+// emite el reembolso (clave CancellationRefundKey). Si otra operación modificó la orden
+// en paralelo, recarga y reintenta.
+func (uc RequestCancellation) Execute(ctx context.Context, input RequestCancellationInput) (RequestCancellationOutput, error) {
+	var output RequestCancellationOutput
+	err := retryOnVersionConflict(func() error {
+		var err error
+		output, err = uc.execute(ctx, input)
+		return err
+	})
+	if err != nil {
+		return RequestCancellationOutput{}, err
+	}
+
+	// 5. Anular el QR pendiente (best-effort; repetir la cancelación reintenta la anulación)
+	if uc.CancelPaymentIntentUC != nil {
+		_, _ = uc.CancelPaymentIntentUC.Execute(ctx, CancelPendingPaymentIntentInput{OrderID: output.Order.ID})
+	}
+
+	// 6. Reembolsar (fuera del reintento por versión; RefundOrder es idempotente por clave,
+	// así que repetir la cancelación completa un reembolso que antes falló)
+	if !output.Order.IsRefundable() {
+		return output, nil
+	}
+	refund, err := uc.RefundOrderUC.Execute(ctx, RefundOrderInput{OrderID: output.Order.ID, Key: CancellationRefundKey})
+	switch {
+	case err == nil:
+		output.Order = refund.Order
+		output.Refund = refund.Refund.Amount
+	case errors.Is(err, checkoutdomain.ErrNothingToRefund):
+		// La penalidad retiene todo (o ya se devolvió por otra vía)
+	default:
+		// La orden queda cancelada; el reembolso pendiente se reintenta desde la cola de revisión
+		reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+			OrderID:   output.Order.ID,
+			Operation: checkoutdomain.IncidentOperationCancellationRefund,
+			Err:       err,
+		})
+	}
+	return output, nil
+}
+
+func (uc RequestCancellation) execute(ctx context.Context, input RequestCancellationInput) (RequestCancellationOutput, error) {
+	// 1. Cargar la orden (solo el dueño puede cancelarla)
+	order, err := uc.Repo.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return RequestCancellationOutput{}, err
+	}
+	if input.UserID != "" && order.UserID != input.UserID {
+		return RequestCancellationOutput{}, checkoutdomain.ErrOrderNotFound
+	}
+
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	// 2. Aplicar la política (idempotente si ya estaba cancelada)
+	wasAlreadyCancelled := order.Status == checkoutdomain.OrderStatusCancelled
+	quote, err := order.CancelWithPolicy(uc.Policy, checkoutdomain.CancelReasonRequested, now)
+	if err != nil {
+		return RequestCancellationOutput{}, err
+	}
+
+	if wasAlreadyCancelled {
+		return RequestCancellationOutput{Order: order, Fee: quote.Fee, Refund: quote.Refund}, nil
+	}
+
+	// 3. Persistir (compare-and-swap: si se pagó en paralelo, se recalcula con la orden pagada)
+	updatedOrder, err := uc.Repo.Update(ctx, order)
+	if err != nil {
+		return RequestCancellationOutput{}, err
+	}
+
+	// 4. Liberar el hold (best-effort, solo tras persistir; si falla queda en la cola de revisión)
+	if updatedOrder.BookingHoldID != nil && *updatedOrder.BookingHoldID != "" {
+		if err := uc.Booking.CancelHold(ctx, *updatedOrder.BookingHoldID); err != nil {
+			reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+				OrderID:   updatedOrder.ID,
+				HoldID:    *updatedOrder.BookingHoldID,
+				Operation: checkoutdomain.IncidentOperationCancelHold,
+				Err:       err,
+			})
+		}
+	}
+
+	return RequestCancellationOutput{Order: updatedOrder, Fee: quote.Fee, Refund: quote.Refund}, nil
+}
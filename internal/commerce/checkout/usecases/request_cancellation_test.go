@@ -0,0 +1,260 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// createBookedTestOrder crea una orden pagada de userID con hold y cita en appointmentAt.
+func createBookedTestOrder(t *testing.T, repo checkoutdomain.OrderRepository, userID string, appointmentAt time.Time) checkoutdomain.Order {
+	t.Helper()
+	order := createTestOrder(t, repo)
+	holdID := "hold_" + order.ID
+	order.UserID = userID
+	order.BookingHoldID = &holdID
+	order.SlotID = "slot_1"
+	order.AppointmentAt = &appointmentAt
+	if err := order.MarkPaid("tx_booked", appointmentAt.Add(-7*24*time.Hour)); err != nil {
+		t.Fatalf("failed to mark paid: %v", err)
+	}
+	updated, err := repo.Update(context.Background(), order)
+	if err != nil {
+		t.Fatalf("failed to persist booked order: %v", err)
+	}
+	return updated
+}
+
+func TestRequestCancellation_BeforeFreeWindow_FullRefund(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	appointmentAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	order := createBookedTestOrder(t, repo, "user_1", appointmentAt)
+	booking := &recordingBookingClient{}
+	provider := &recordingPaymentsClient{}
+
+	uc := RequestCancellation{
+		Repo:          repo,
+		Booking:       booking,
+		Policy:        checkoutdomain.DefaultCancellationPolicy(),
+		RefundOrderUC: &RefundOrder{Repo: repo, Payments: provider},
+		Now:           func() time.Time { return appointmentAt.Add(-48 * time.Hour) },
+	}
+
+	output, err := uc.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID, UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusCancelled {
+		t.Errorf("expected cancelled, got: %v", output.Order.Status)
+	}
+	if output.Order.CancelReason != checkoutdomain.CancelReasonRequested {
+		t.Errorf("expected reason requested, got: %v", output.Order.CancelReason)
+	}
+	if output.Fee.Amount != 0 || output.Refund != order.Total {
+		t.Errorf("expected full refund of %v, got fee=%v refund=%v", order.Total, output.Fee, output.Refund)
+	}
+	if len(booking.cancelled) != 1 || booking.cancelled[0] != *order.BookingHoldID {
+		t.Errorf("expected hold to be released, got: %v", booking.cancelled)
+	}
+
+	// El reembolso se emite en el proveedor y queda registrado en la orden
+	if len(provider.refunds) != 1 || provider.refunds[0].Amount != order.Total ||
+		provider.refunds[0].IdempotencyKey != order.ID+":"+CancellationRefundKey {
+		t.Errorf("expected a provider refund of %v, got: %+v", order.Total, provider.refunds)
+	}
+	if output.Order.NetPaid().Amount != 0 || output.Order.Status != checkoutdomain.OrderStatusCancelled {
+		t.Errorf("expected cancelled order with nothing paid, got: %v net=%v", output.Order.Status, output.Order.NetPaid())
+	}
+}
+
+func TestRequestCancellation_InsideWindow_ChargesLateFee(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	appointmentAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	order := createBookedTestOrder(t, repo, "user_1", appointmentAt)
+	provider := &recordingPaymentsClient{}
+
+	uc := RequestCancellation{
+		Repo:          repo,
+		Booking:       &recordingBookingClient{},
+		Policy:        checkoutdomain.CancellationPolicy{FreeWindow: 24 * time.Hour, LateFeePercent: 50},
+		RefundOrderUC: &RefundOrder{Repo: repo, Payments: provider},
+		Now:           func() time.Time { return appointmentAt.Add(-2 * time.Hour) },
+	}
+
+	output, err := uc.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID, UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedFee := order.Total.Amount * 50 / 100
+	if output.Fee.Amount != expectedFee {
+		t.Errorf("expected fee %d, got: %d", expectedFee, output.Fee.Amount)
+	}
+	if output.Refund.Amount != order.Total.Amount-expectedFee {
+		t.Errorf("expected refund %d, got: %d", order.Total.Amount-expectedFee, output.Refund.Amount)
+	}
+	if output.Order.CancellationFee != output.Fee {
+		t.Errorf("expected fee to be stored on the order, got: %v", output.Order.CancellationFee)
+	}
+
+	// Idempotente: reintentar reporta la misma penalidad sin nueva versión
+	again, err := uc.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID, UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if again.Fee != output.Fee || again.Refund != output.Refund {
+		t.Errorf("expected same quote on retry, got fee=%v refund=%v", again.Fee, again.Refund)
+	}
+	if again.Order.Version != output.Order.Version {
+		t.Errorf("expected no new version, got: %d -> %d", output.Order.Version, again.Order.Version)
+	}
+	if len(provider.refunds) != 1 || output.Order.NetPaid() != output.Fee {
+		t.Errorf("expected a single refund leaving the fee paid, got: %d refunds, net=%v", len(provider.refunds), output.Order.NetPaid())
+	}
+}
+
+func TestRequestCancellation_PartiallyRefunded_QuotesRefundable(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	appointmentAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	order := createTwoLineOrder(t, repo)
+	order.UserID = "user_1"
+	order.AppointmentAt = &appointmentAt
+	order, err := repo.Update(context.Background(), order)
+	if err != nil {
+		t.Fatalf("failed to book order: %v", err)
+	}
+	refundUC := &RefundOrder{Repo: repo, Payments: &recordingPaymentsClient{}}
+
+	// Un reembolso previo por línea reduce lo que la cancelación puede devolver
+	partial, err := refundUC.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_line", Lines: []int{0}})
+	if err != nil {
+		t.Fatalf("failed to refund line: %v", err)
+	}
+	refundable := partial.Order.RefundableAmount()
+
+	uc := RequestCancellation{
+		Repo:          repo,
+		Booking:       &recordingBookingClient{},
+		Policy:        checkoutdomain.CancellationPolicy{FreeWindow: 24 * time.Hour, LateFeePercent: 50},
+		RefundOrderUC: refundUC,
+		Now:           func() time.Time { return appointmentAt.Add(-2 * time.Hour) },
+	}
+	output, err := uc.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID, UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Fee.Amount != refundable.Amount*50/100 || output.Fee.Amount+output.Refund.Amount != refundable.Amount {
+		t.Errorf("expected quote over the refundable %v, got fee=%v refund=%v", refundable, output.Fee, output.Refund)
+	}
+	if output.Order.NetPaid() != output.Fee {
+		t.Errorf("expected only the fee to stay paid, got: %v", output.Order.NetPaid())
+	}
+}
+
+func TestRequestCancellation_RefundFails_RecordsIncident(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	incidents := checkoutmemory.NewIncidentRepository()
+	appointmentAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	order := createBookedTestOrder(t, repo, "user_1", appointmentAt)
+	provider := &recordingPaymentsClient{err: errors.New("provider unavailable")}
+
+	uc := RequestCancellation{
+		Repo:             repo,
+		Booking:          &recordingBookingClient{},
+		Policy:           checkoutdomain.DefaultCancellationPolicy(),
+		RefundOrderUC:    &RefundOrder{Repo: repo, Payments: provider},
+		RecordIncidentUC: &RecordIncident{Repo: incidents},
+		Now:              func() time.Time { return appointmentAt.Add(-48 * time.Hour) },
+	}
+	output, err := uc.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID, UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusCancelled || output.Order.NetPaid() != order.Total {
+		t.Errorf("expected cancelled order still pending its refund, got: %v net=%v", output.Order.Status, output.Order.NetPaid())
+	}
+	listed, _ := incidents.List(context.Background(), checkoutdomain.IncidentQuery{OrderID: order.ID})
+	if len(listed) != 1 || listed[0].Operation != checkoutdomain.IncidentOperationCancellationRefund {
+		t.Fatalf("expected a cancellation_refund incident, got: %+v", listed)
+	}
+
+	// Reintentar el incidente emite el reembolso con la misma clave
+	provider.err = nil
+	retry := RetryIncident{Repo: incidents, Orders: repo, RefundOrderUC: uc.RefundOrderUC}
+	if _, err := retry.Execute(context.Background(), RetryIncidentInput{IncidentID: listed[0].ID}); err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.NetPaid().Amount != 0 || len(provider.refunds) != 1 {
+		t.Errorf("expected refund issued on retry, got net=%v refunds=%d", stored.NetPaid(), len(provider.refunds))
+	}
+}
+
+func TestRequestCancellation_AfterStart_Rejected(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	appointmentAt := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	order := createBookedTestOrder(t, repo, "user_1", appointmentAt)
+	booking := &recordingBookingClient{}
+
+	uc := RequestCancellation{
+		Repo:    repo,
+		Booking: booking,
+		Policy:  checkoutdomain.DefaultCancellationPolicy(),
+		Now:     func() time.Time { return appointmentAt },
+	}
+
+	_, err := uc.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID, UserID: "user_1"})
+	if !errors.Is(err, checkoutdomain.ErrCancellationWindowClosed) {
+		t.Fatalf("expected ErrCancellationWindowClosed, got: %v", err)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected order to stay paid, got: %v", stored.Status)
+	}
+	if len(booking.cancelled) != 0 {
+		t.Errorf("expected hold to be kept, got: %v", booking.cancelled)
+	}
+}
+
+func TestRequestCancellation_UnpaidOrder_NoFeeNoRefund(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createTestOrder(t, repo)
+
+	uc := RequestCancellation{
+		Repo:    repo,
+		Booking: &recordingBookingClient{},
+		Policy:  checkoutdomain.DefaultCancellationPolicy(),
+	}
+
+	output, err := uc.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusCancelled {
+		t.Errorf("expected cancelled, got: %v", output.Order.Status)
+	}
+	if output.Fee.Amount != 0 || output.Refund.Amount != 0 {
+		t.Errorf("expected no fee and no refund, got fee=%v refund=%v", output.Fee, output.Refund)
+	}
+}
+
+func TestRequestCancellation_OtherUser_NotFound(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	order := createBookedTestOrder(t, repo, "user_1", time.Now().Add(72*time.Hour))
+
+	uc := RequestCancellation{
+		Repo:    repo,
+		Booking: &recordingBookingClient{},
+		Policy:  checkoutdomain.DefaultCancellationPolicy(),
+	}
+
+	_, err := uc.Execute(context.Background(), RequestCancellationInput{OrderID: order.ID, UserID: "user_2"})
+	if !errors.Is(err, checkoutdomain.ErrOrderNotFound) {
+		t.Fatalf("expected ErrOrderNotFound, got: %v", err)
+	}
+}
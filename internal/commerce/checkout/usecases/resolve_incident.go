@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/platform/audit"
+)
+
+// ResolveIncidentInput contiene el incidente y la nota del operador.
+type ResolveIncidentInput struct {
+	IncidentID string
+	Note       string
+}
+
+// ResolveIncidentOutput contiene el incidente resuelto.
+type ResolveIncidentOutput struct {
+	Incident checkoutdomain.Incident
+}
+
+// ResolveIncident cierra a mano un incidente de la cola de revisión (p. ej. el hold se liberó
+// desde booking o la orden se reembolsó con POST /refunds).
+type ResolveIncident struct {
+	Repo checkoutdomain.IncidentRepository
+	Now  func() time.Time
+}
+
+// Execute marca el incidente como resuelto registrando la nota y el actor del ctx.
+func (uc ResolveIncident) Execute(ctx context.Context, input ResolveIncidentInput) (ResolveIncidentOutput, error) {
+	incident, err := uc.Repo.GetByID(ctx, input.IncidentID)
+	if err != nil {
+		return ResolveIncidentOutput{}, err
+	}
+
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+	if err := incident.Resolve(input.Note, audit.Actor(ctx), now); err != nil {
+		return ResolveIncidentOutput{}, err
+	}
+
+	updated, err := uc.Repo.Update(ctx, incident)
+	if err != nil {
+		return ResolveIncidentOutput{}, err
+	}
+	return ResolveIncidentOutput{Incident: updated}, nil
+}
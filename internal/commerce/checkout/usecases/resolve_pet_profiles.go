@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+)
+
+// ErrPetProfileMismatch indica que el perfil enviado no coincide con el registrado para pet_id.
+var ErrPetProfileMismatch = errors.New("pet profile does not match the registered pet")
+
+// ResolvePetProfilesInput contiene la intención a completar.
+type ResolvePetProfilesInput struct {
+	Intent checkoutdomain.PurchaseIntent
+}
+
+// ResolvePetProfilesOutput contiene la intención con los perfiles resueltos.
+type ResolvePetProfilesOutput struct {
+	Intent checkoutdomain.PurchaseIntent
+}
+
+// ResolvePetProfiles completa los perfiles consultando el servicio de mascotas. El pet principal
+// con PetID siempre se resuelve en el servidor: un perfil inline que no coincide con el
+// registrado se rechaza (el precio depende del peso y el pelaje). En las mascotas adicionales
+// el ID también etiqueta mascotas inline: solo se consultan las que no traen perfil.
+type ResolvePetProfiles struct {
+	Pets platformpets.Client // opcional: sin cliente la intención se retorna tal cual
+}
+
+// Execute resuelve el pet principal (PetID) y las mascotas adicionales sin perfil.
+func (uc ResolvePetProfiles) Execute(ctx context.Context, input ResolvePetProfilesInput) (ResolvePetProfilesOutput, error) {
+	intent := input.Intent
+	if uc.Pets == nil {
+		return ResolvePetProfilesOutput{Intent: intent}, nil
+	}
+
+	if intent.PetID != "" {
+		profile, err := uc.Pets.GetPetProfile(ctx, intent.PetID)
+		if err != nil {
+			return ResolvePetProfilesOutput{}, err
+		}
+		if intent.PetProfile != (servicedomain.PetProfile{}) && intent.PetProfile != profile {
+			return ResolvePetProfilesOutput{}, ErrPetProfileMismatch
+		}
+		intent.PetProfile = profile
+	}
+
+	if len(intent.Pets) > 0 {
+		pets := make([]checkoutdomain.Pet, len(intent.Pets))
+		copy(pets, intent.Pets)
+		for i, pet := range pets {
+			if pet.ID == "" || pet.PetProfile != (servicedomain.PetProfile{}) {
+				continue
+			}
+			profile, err := uc.Pets.GetPetProfile(ctx, pet.ID)
+			if err != nil {
+				return ResolvePetProfilesOutput{}, err
+			}
+			pets[i].PetProfile = profile
+		}
+		intent.Pets = pets
+	}
+
+	return ResolvePetProfilesOutput{Intent: intent}, nil
+}
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+)
+
+func TestResolvePetProfiles_ResolvesOnlyPetsWithoutProfile(t *testing.T) {
+	maxProfile := servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 15, CoatType: servicedomain.CoatTypeDouble}
+	luna := servicedomain.PetProfile{Species: servicedomain.SpeciesCat, WeightKg: 4, CoatType: servicedomain.CoatTypeShort}
+	inline := servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 30, CoatType: servicedomain.CoatTypeShort}
+
+	uc := ResolvePetProfiles{Pets: &platformpets.StubClient{Profiles: map[string]servicedomain.PetProfile{
+		"max":  maxProfile,
+		"luna": luna,
+		"rex":  {Species: servicedomain.SpeciesDog, WeightKg: 8, CoatType: servicedomain.CoatTypeShort},
+	}}}
+
+	pets := []checkoutdomain.Pet{{ID: "luna"}, {ID: "rex", PetProfile: inline}}
+	output, err := uc.Execute(context.Background(), ResolvePetProfilesInput{Intent: checkoutdomain.PurchaseIntent{
+		PetID: "max",
+		Pets:  pets,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.Intent.PetID != "max" || output.Intent.PetProfile != maxProfile {
+		t.Errorf("expected main pet max resolved, got: %s %+v", output.Intent.PetID, output.Intent.PetProfile)
+	}
+	if output.Intent.Pets[0].PetProfile != luna {
+		t.Errorf("expected luna resolved, got: %+v", output.Intent.Pets[0].PetProfile)
+	}
+	if output.Intent.Pets[1].PetProfile != inline {
+		t.Errorf("expected inline profile to take priority, got: %+v", output.Intent.Pets[1].PetProfile)
+	}
+	if pets[0].PetProfile != (servicedomain.PetProfile{}) {
+		t.Errorf("expected input pets not to be mutated")
+	}
+}
+
+func TestResolvePetProfiles_MainPetInlineProfile_MustMatchRegistered(t *testing.T) {
+	registered := servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 25, CoatType: servicedomain.CoatTypeDouble}
+	uc := ResolvePetProfiles{Pets: &platformpets.StubClient{Profiles: map[string]servicedomain.PetProfile{"max": registered}}}
+
+	// Perfil falso más liviano: se rechaza
+	fake := servicedomain.PetProfile{Species: servicedomain.SpeciesDog, WeightKg: 5, CoatType: servicedomain.CoatTypeShort}
+	_, err := uc.Execute(context.Background(), ResolvePetProfilesInput{Intent: checkoutdomain.PurchaseIntent{PetID: "max", PetProfile: fake}})
+	if !errors.Is(err, ErrPetProfileMismatch) {
+		t.Errorf("expected ErrPetProfileMismatch, got: %v", err)
+	}
+
+	// El mismo perfil registrado (p. ej. el snapshot del carrito) se acepta
+	output, err := uc.Execute(context.Background(), ResolvePetProfilesInput{Intent: checkoutdomain.PurchaseIntent{PetID: "max", PetProfile: registered}})
+	if err != nil || output.Intent.PetProfile != registered {
+		t.Errorf("expected registered profile accepted, got: %+v %v", output.Intent.PetProfile, err)
+	}
+}
+
+func TestResolvePetProfiles_UnknownPet_ReturnsErrPetNotFound(t *testing.T) {
+	uc := ResolvePetProfiles{Pets: &platformpets.StubClient{}}
+
+	_, err := uc.Execute(context.Background(), ResolvePetProfilesInput{Intent: checkoutdomain.PurchaseIntent{PetID: "ghost"}})
+	if !errors.Is(err, platformpets.ErrPetNotFound) {
+		t.Errorf("expected ErrPetNotFound, got: %v", err)
+	}
+}
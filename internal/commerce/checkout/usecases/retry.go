@@ -0,0 +1,23 @@
+package usecases
+
+import (
+	"errors"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// maxOrderUpdateAttempts limita los reintentos ante ErrOrderVersionConflict.
+const maxOrderUpdateAttempts = 3
+
+// retryOnVersionConflict re-ejecuta attempt (que debe recargar la orden) mientras
+// el repositorio reporte un conflicto de versión. Agotados los intentos retorna el conflicto.
+func retryOnVersionConflict(attempt func() error) error {
+	var err error
+	for i := 0; i < maxOrderUpdateAttempts; i++ {
+		err = attempt()
+		if !errors.Is(err, checkoutdomain.ErrOrderVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformbooking "paku-commerce/internal/commerce/platform/booking"
+	"paku-commerce/internal/platform/lock"
+)
+
+// RetryHoldConfirmationsLockKey es la clave del lock que serializa los reintentos entre réplicas.
+const RetryHoldConfirmationsLockKey = "hold-retry"
+
+// BookingFailedRefundKey es la clave de idempotencia del reembolso automático de la saga.
+const BookingFailedRefundKey = "booking-failed"
+
+// RetryHoldConfirmationsInput contiene el timestamp de referencia.
+type RetryHoldConfirmationsInput struct {
+	Now time.Time
+}
+
+// RetryHoldConfirmationsOutput contiene estadísticas del barrido.
+type RetryHoldConfirmationsOutput struct {
+	Confirmed    int // hold confirmado: la orden volvió a paid
+	Rescheduled  int // falló de nuevo, con otro reintento programado
+	Refunded     int // reintentos agotados: reembolso automático
+	SentToReview int // reintentos agotados: queda en revisión manual
+	// Skipped indica que otra réplica tenía el lock y no se procesó nada.
+	Skipped bool
+}
+
+// RetryHoldConfirmations es el paso de compensación de la saga de pago: reintenta
+// ConfirmHold de las órdenes payment_received_booking_failed con backoff y, agotado
+// el presupuesto, reembolsa (AutoRefund) o deja la orden en la cola de revisión manual.
+type RetryHoldConfirmations struct {
+	Repo             checkoutdomain.OrderRepository
+	Booking          platformbooking.Client
+	RefundOrderUC    *RefundOrder
+	RecordIncidentUC *RecordIncident                // opcional: registra las órdenes que quedan en revisión
+	Policy           checkoutdomain.HoldRetryPolicy // cero = checkoutdomain.DefaultHoldRetryPolicy()
+	AutoRefund       bool
+	Locker           lock.Locker // opcional: evita que dos réplicas reintenten las mismas órdenes
+}
+
+// Execute reintenta las órdenes con reintento vencido. Los errores por orden no
+// cortan el barrido: la orden conserva su reintento y se procesa en el próximo.
+func (uc RetryHoldConfirmations) Execute(ctx context.Context, input RetryHoldConfirmationsInput) (RetryHoldConfirmationsOutput, error) {
+	if uc.Locker != nil {
+		unlock, acquired, err := uc.Locker.TryLock(ctx, RetryHoldConfirmationsLockKey)
+		if err != nil {
+			return RetryHoldConfirmationsOutput{}, err
+		}
+		if !acquired {
+			return RetryHoldConfirmationsOutput{Skipped: true}, nil
+		}
+		defer unlock()
+	}
+
+	due, err := uc.Repo.ListHoldRetriesDue(ctx, input.Now)
+	if err != nil {
+		return RetryHoldConfirmationsOutput{}, err
+	}
+
+	var output RetryHoldConfirmationsOutput
+	for _, order := range due {
+		var attempt holdRetryAttempt
+		err := retryOnVersionConflict(func() error {
+			var err error
+			attempt, err = uc.retry(ctx, order.ID, input.Now)
+			return err
+		})
+		if err != nil {
+			continue
+		}
+
+		switch attempt.result {
+		case holdRetryConfirmed:
+			output.Confirmed++
+		case holdRetryRescheduled:
+			output.Rescheduled++
+		case holdRetryExhausted:
+			incident := RecordIncidentInput{
+				OrderID:   order.ID,
+				HoldID:    attempt.holdID,
+				Operation: checkoutdomain.IncidentOperationConfirmHold,
+				Err:       attempt.cause,
+			}
+			reason := "hold confirmation retries exhausted"
+			if uc.AutoRefund {
+				refundErr := uc.refund(ctx, order.ID)
+				if refundErr == nil {
+					output.Refunded++
+					continue
+				}
+				reason = "auto refund failed: " + refundErr.Error()
+				incident.Operation, incident.Err = checkoutdomain.IncidentOperationRefund, refundErr
+			}
+			if err := uc.sendToReview(ctx, order.ID, reason, input.Now); err == nil {
+				reportIncident(ctx, uc.RecordIncidentUC, incident)
+				output.SentToReview++
+			}
+		}
+	}
+
+	return output, nil
+}
+
+type holdRetryResult int
+
+// holdRetryAttempt es el resultado de reintentar el hold de una orden.
+type holdRetryAttempt struct {
+	result holdRetryResult
+	holdID string
+	cause  error // error de booking si el reintento falló
+}
+
+const (
+	holdRetrySkipped holdRetryResult = iota
+	holdRetryConfirmed
+	holdRetryRescheduled
+	holdRetryExhausted
+)
+
+func (uc RetryHoldConfirmations) retry(ctx context.Context, orderID string, now time.Time) (holdRetryAttempt, error) {
+	// 1. Recargar: otra operación pudo resolver la orden desde el listado
+	order, err := uc.Repo.GetByID(ctx, orderID)
+	if err != nil {
+		return holdRetryAttempt{}, err
+	}
+	if order.Status != checkoutdomain.OrderStatusPaymentReceivedBookingFailed ||
+		order.NextHoldRetryAt == nil || order.NextHoldRetryAt.After(now) {
+		return holdRetryAttempt{result: holdRetrySkipped}, nil
+	}
+
+	// 2. Reintentar el hold
+	attempt := holdRetryAttempt{result: holdRetryConfirmed, holdID: *order.BookingHoldID}
+	if err := uc.Booking.ConfirmHold(ctx, attempt.holdID); err != nil {
+		order.RecordHoldRetryFailure(err, now, uc.policy().NextRetryAt(order.HoldConfirmAttempts+1, now))
+		attempt.result, attempt.cause = holdRetryRescheduled, err
+		if order.NextHoldRetryAt == nil {
+			attempt.result = holdRetryExhausted
+		}
+	} else if err := order.MarkHoldConfirmed(now); err != nil {
+		return holdRetryAttempt{}, err
+	}
+
+	// 3. Persistir (compare-and-swap)
+	if _, err := uc.Repo.Update(ctx, order); err != nil {
+		return holdRetryAttempt{}, err
+	}
+	return attempt, nil
+}
+
+// refund compensa el cobro y libera el hold.
+func (uc RetryHoldConfirmations) refund(ctx context.Context, orderID string) error {
+	output, err := uc.RefundOrderUC.Execute(ctx, RefundOrderInput{OrderID: orderID, Key: BookingFailedRefundKey})
+	if err != nil {
+		return err
+	}
+	if output.Order.BookingHoldID != nil && *output.Order.BookingHoldID != "" {
+		if err := uc.Booking.CancelHold(ctx, *output.Order.BookingHoldID); err != nil {
+			reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+				OrderID:   orderID,
+				HoldID:    *output.Order.BookingHoldID,
+				Operation: checkoutdomain.IncidentOperationCancelHold,
+				Err:       err,
+			})
+		}
+	}
+	return nil
+}
+
+// sendToReview deja constancia en el historial de que la orden requiere revisión manual
+// (sigue payment_received_booking_failed sin reintento programado). El incidente de la cola
+// de revisión lo registra quien la llama.
+func (uc RetryHoldConfirmations) sendToReview(ctx context.Context, orderID, reason string, now time.Time) error {
+	return retryOnVersionConflict(func() error {
+		order, err := uc.Repo.GetByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if !order.NeedsReview() {
+			return nil
+		}
+		order.RecordEvent(checkoutdomain.OrderEventManualReview, now, map[string]string{"reason": reason})
+		_, err = uc.Repo.Update(ctx, order)
+		return err
+	})
+}
+
+func (uc RetryHoldConfirmations) policy() checkoutdomain.HoldRetryPolicy {
+	if uc.Policy.MaxAttempts <= 0 {
+		return checkoutdomain.DefaultHoldRetryPolicy()
+	}
+	return uc.Policy
+}
@@ -0,0 +1,211 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+var errBookingDown = errors.New("booking unavailable")
+
+// flakyBookingClient falla ConfirmHold las primeras failures veces.
+type flakyBookingClient struct {
+	recordingBookingClient
+	failures  int
+	confirmed []string
+}
+
+func (c *flakyBookingClient) ConfirmHold(ctx context.Context, holdID string) error {
+	if c.failures > 0 {
+		c.failures--
+		return errBookingDown
+	}
+	c.confirmed = append(c.confirmed, holdID)
+	return nil
+}
+
+// createHeldTestOrder crea una orden pending_payment con hold.
+func createHeldTestOrder(t *testing.T, repo checkoutdomain.OrderRepository) checkoutdomain.Order {
+	t.Helper()
+	order := createTestOrder(t, repo)
+	holdID := "hold_" + order.ID
+	order.BookingHoldID = &holdID
+	updated, err := repo.Update(context.Background(), order)
+	if err != nil {
+		t.Fatalf("failed to attach hold: %v", err)
+	}
+	return updated
+}
+
+// confirmWithFailingHold paga la orden con booking caído: queda payment_received_booking_failed.
+func confirmWithFailingHold(t *testing.T, repo checkoutdomain.OrderRepository, booking *flakyBookingClient, now time.Time) checkoutdomain.Order {
+	t.Helper()
+	order := createHeldTestOrder(t, repo)
+	output, err := ConfirmPayment{
+		Repo:      repo,
+		Booking:   booking,
+		Payments:  &recordingPaymentsClient{},
+		HoldRetry: checkoutdomain.HoldRetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute},
+		Now:       func() time.Time { return now },
+	}.Execute(context.Background(), ConfirmPaymentInput{OrderID: order.ID, PaymentRef: "tx_saga", PaidAt: now})
+	if err != nil {
+		t.Fatalf("expected payment to be recorded, got: %v", err)
+	}
+	return output.Order
+}
+
+func TestConfirmPayment_HoldFails_RecordsPaymentAndSchedulesRetry(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	order := confirmWithFailingHold(t, repo, &flakyBookingClient{failures: 1}, now)
+
+	if order.Status != checkoutdomain.OrderStatusPaymentReceivedBookingFailed {
+		t.Fatalf("expected payment_received_booking_failed, got: %v", order.Status)
+	}
+	if order.PaymentRef == nil || *order.PaymentRef != "tx_saga" || order.PaidAt == nil {
+		t.Errorf("expected payment to be recorded, got ref=%v paid_at=%v", order.PaymentRef, order.PaidAt)
+	}
+	if order.HoldConfirmAttempts != 1 || order.NextHoldRetryAt == nil || !order.NextHoldRetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected first retry at %v, got attempts=%d next=%v", now.Add(time.Minute), order.HoldConfirmAttempts, order.NextHoldRetryAt)
+	}
+
+	events, _ := repo.ListEvents(context.Background(), order.ID)
+	last := events[len(events)-1]
+	if last.Type != checkoutdomain.OrderEventBookingFailed || last.Data["error"] != errBookingDown.Error() {
+		t.Errorf("expected booking_failed event with error, got: %+v", last)
+	}
+
+	// Reintentar el webhook de pago no repite side effects
+	again, err := ConfirmPayment{Repo: repo, Booking: &flakyBookingClient{}, Payments: &recordingPaymentsClient{}}.Execute(
+		context.Background(), ConfirmPaymentInput{OrderID: order.ID, PaymentRef: "tx_saga"})
+	if err != nil || again.Order.Version != order.Version {
+		t.Errorf("expected idempotent confirmation, got: %v (version %d -> %d)", err, order.Version, again.Order.Version)
+	}
+}
+
+func TestRetryHoldConfirmations_ConfirmsOnRetry(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	booking := &flakyBookingClient{failures: 1}
+	order := confirmWithFailingHold(t, repo, booking, now)
+
+	uc := RetryHoldConfirmations{Repo: repo, Booking: booking}
+
+	// Antes del backoff no se reintenta
+	early, err := uc.Execute(context.Background(), RetryHoldConfirmationsInput{Now: now.Add(30 * time.Second)})
+	if err != nil || early.Confirmed != 0 || len(booking.confirmed) != 0 {
+		t.Fatalf("expected no retry before backoff, got: %+v %v", early, err)
+	}
+
+	output, err := uc.Execute(context.Background(), RetryHoldConfirmationsInput{Now: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Confirmed != 1 {
+		t.Errorf("expected 1 confirmed, got: %+v", output)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected paid, got: %v", stored.Status)
+	}
+	if !stored.PaidAt.Equal(now) {
+		t.Errorf("expected paid_at to keep the charge time %v, got: %v", now, stored.PaidAt)
+	}
+	if stored.NextHoldRetryAt != nil {
+		t.Errorf("expected no pending retry, got: %v", stored.NextHoldRetryAt)
+	}
+}
+
+func TestRetryHoldConfirmations_Exhausted_AutoRefund(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	booking := &flakyBookingClient{failures: 10}
+	order := confirmWithFailingHold(t, repo, booking, now)
+	provider := &recordingPaymentsClient{}
+
+	uc := RetryHoldConfirmations{
+		Repo:          repo,
+		Booking:       booking,
+		RefundOrderUC: &RefundOrder{Repo: repo, Payments: provider},
+		Policy:        checkoutdomain.HoldRetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute},
+		AutoRefund:    true,
+	}
+
+	// Intento 2 (a +1m) reprograma a +2m; intento 3 agota el presupuesto
+	first, _ := uc.Execute(context.Background(), RetryHoldConfirmationsInput{Now: now.Add(time.Minute)})
+	if first.Rescheduled != 1 {
+		t.Fatalf("expected rescheduled retry, got: %+v", first)
+	}
+	second, _ := uc.Execute(context.Background(), RetryHoldConfirmationsInput{Now: now.Add(3 * time.Minute)})
+	if second.Refunded != 1 {
+		t.Fatalf("expected auto refund, got: %+v", second)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusRefunded || stored.NetPaid().Amount != 0 {
+		t.Errorf("expected refunded with net paid 0, got: %v %v", stored.Status, stored.NetPaid())
+	}
+	if len(provider.refunds) != 1 || provider.refunds[0].PaymentRef != "tx_saga" {
+		t.Errorf("expected one provider refund on tx_saga, got: %+v", provider.refunds)
+	}
+	if len(booking.cancelled) != 1 || booking.cancelled[0] != *order.BookingHoldID {
+		t.Errorf("expected hold to be released, got: %v", booking.cancelled)
+	}
+}
+
+func TestRetryHoldConfirmations_Exhausted_SendsToReview(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	booking := &flakyBookingClient{failures: 10}
+	order := confirmWithFailingHold(t, repo, booking, now)
+
+	uc := RetryHoldConfirmations{
+		Repo:    repo,
+		Booking: booking,
+		Policy:  checkoutdomain.HoldRetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute},
+	}
+
+	output, err := uc.Execute(context.Background(), RetryHoldConfirmationsInput{Now: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.SentToReview != 1 {
+		t.Fatalf("expected order sent to review, got: %+v", output)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if !stored.NeedsReview() || stored.HoldConfirmAttempts != 2 {
+		t.Errorf("expected order waiting for review after 2 attempts, got: %v attempts=%d", stored.Status, stored.HoldConfirmAttempts)
+	}
+	events, _ := repo.ListEvents(context.Background(), order.ID)
+	if last := events[len(events)-1]; last.Type != checkoutdomain.OrderEventManualReview {
+		t.Errorf("expected manual_review_required event, got: %+v", last)
+	}
+
+	// Sin reintento programado, el próximo barrido no la vuelve a tomar
+	later, _ := uc.Execute(context.Background(), RetryHoldConfirmationsInput{Now: now.Add(time.Hour)})
+	if later.SentToReview != 0 || later.Rescheduled != 0 {
+		t.Errorf("expected nothing to retry, got: %+v", later)
+	}
+}
+
+func TestHoldRetryPolicy_ExponentialBackoff(t *testing.T) {
+	policy := checkoutdomain.HoldRetryPolicy{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute}
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	expected := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute}
+	for i, delay := range expected {
+		next := policy.NextRetryAt(i+1, now)
+		if next == nil || !next.Equal(now.Add(delay)) {
+			t.Errorf("attempt %d: expected retry after %v, got: %v", i+1, delay, next)
+		}
+	}
+	if next := policy.NextRetryAt(5, now); next != nil {
+		t.Errorf("expected no retry after the budget, got: %v", next)
+	}
+}
@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformbooking "paku-commerce/internal/commerce/platform/booking"
+	"paku-commerce/internal/platform/audit"
+)
+
+var ErrIncidentRetryFailed = errors.New("incident retry failed")
+
+// IncidentRetryNote es la nota con la que se cierra un incidente cuyo reintento funcionó.
+const IncidentRetryNote = "retry succeeded"
+
+// RetryIncidentInput contiene el incidente a reintentar.
+type RetryIncidentInput struct {
+	IncidentID string
+}
+
+// RetryIncidentOutput contiene el incidente resuelto.
+type RetryIncidentOutput struct {
+	Incident checkoutdomain.Incident
+}
+
+// RetryIncident reintenta la operación fallida de un incidente de la cola de revisión.
+type RetryIncident struct {
+	Repo             checkoutdomain.IncidentRepository
+	Orders           checkoutdomain.OrderRepository
+	Booking          platformbooking.Client
+	RefundOrderUC    *RefundOrder    // requerido para incidentes refund y cancellation_refund
+	RecordIncidentUC *RecordIncident // opcional: registra fallas al liberar el hold tras reembolsar
+	// CancelPaymentIntentUC es requerido para incidentes cancel_payment_intent; sin
+	// RecordIncidentUC, para que un reintento fallido no abra otro incidente.
+	CancelPaymentIntentUC *CancelPendingPaymentIntent
+	Now                   func() time.Time
+}
+
+// Execute reintenta la operación. Si funciona, el incidente queda resuelto; si vuelve a fallar,
+// queda abierto con el nuevo error y se retorna ErrIncidentRetryFailed.
+func (uc RetryIncident) Execute(ctx context.Context, input RetryIncidentInput) (RetryIncidentOutput, error) {
+	// 1. Cargar el incidente
+	incident, err := uc.Repo.GetByID(ctx, input.IncidentID)
+	if err != nil {
+		return RetryIncidentOutput{}, err
+	}
+	if incident.Status == checkoutdomain.IncidentStatusResolved {
+		return RetryIncidentOutput{}, checkoutdomain.ErrIncidentResolved
+	}
+
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	// 2. Reintentar la operación
+	if err := uc.retry(ctx, incident, now); err != nil {
+		if errors.Is(err, checkoutdomain.ErrIncidentNotRetryable) {
+			return RetryIncidentOutput{}, err
+		}
+		incident.RecordRetryFailure(err, now)
+		if _, updateErr := uc.Repo.Update(ctx, incident); updateErr != nil {
+			return RetryIncidentOutput{}, updateErr
+		}
+		return RetryIncidentOutput{}, fmt.Errorf("%w: %v", ErrIncidentRetryFailed, err)
+	}
+
+	// 3. Cerrar el incidente
+	if err := incident.Resolve(IncidentRetryNote, audit.Actor(ctx), now); err != nil {
+		return RetryIncidentOutput{}, err
+	}
+	updated, err := uc.Repo.Update(ctx, incident)
+	if err != nil {
+		return RetryIncidentOutput{}, err
+	}
+	return RetryIncidentOutput{Incident: updated}, nil
+}
+
+func (uc RetryIncident) retry(ctx context.Context, incident checkoutdomain.Incident, now time.Time) error {
+	switch incident.Operation {
+	case checkoutdomain.IncidentOperationCancelHold:
+		if incident.HoldID == "" {
+			return checkoutdomain.ErrIncidentNotRetryable
+		}
+		return uc.Booking.CancelHold(ctx, incident.HoldID)
+
+	case checkoutdomain.IncidentOperationConfirmHold:
+		return uc.confirmHold(ctx, incident, now)
+
+	case checkoutdomain.IncidentOperationRefund:
+		if uc.RefundOrderUC == nil {
+			return checkoutdomain.ErrIncidentNotRetryable
+		}
+		return uc.refund(ctx, incident)
+
+	case checkoutdomain.IncidentOperationCancellationRefund:
+		if uc.RefundOrderUC == nil {
+			return checkoutdomain.ErrIncidentNotRetryable
+		}
+		// Misma clave que la cancelación: no reembolsa dos veces
+		_, err := uc.RefundOrderUC.Execute(ctx, RefundOrderInput{OrderID: incident.OrderID, Key: CancellationRefundKey})
+		return err
+
+	case checkoutdomain.IncidentOperationCancelPaymentIntent:
+		if uc.CancelPaymentIntentUC == nil {
+			return checkoutdomain.ErrIncidentNotRetryable
+		}
+		// Si el QR ya no está pending (anulado, vencido o pagado) no queda nada que anular
+		_, err := uc.CancelPaymentIntentUC.Execute(ctx, CancelPendingPaymentIntentInput{OrderID: incident.OrderID})
+		return err
+
+	default:
+		return checkoutdomain.ErrIncidentNotRetryable
+	}
+}
+
+// confirmHold reintenta ConfirmHold y, si booking confirma, devuelve la orden a paid.
+func (uc RetryIncident) confirmHold(ctx context.Context, incident checkoutdomain.Incident, now time.Time) error {
+	order, err := uc.Orders.GetByID(ctx, incident.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != checkoutdomain.OrderStatusPaymentReceivedBookingFailed || incident.HoldID == "" {
+		return fmt.Errorf("%w: order is %s", checkoutdomain.ErrIncidentNotRetryable, order.Status)
+	}
+
+	if err := uc.Booking.ConfirmHold(ctx, incident.HoldID); err != nil {
+		return err
+	}
+
+	return retryOnVersionConflict(func() error {
+		order, err := uc.Orders.GetByID(ctx, incident.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != checkoutdomain.OrderStatusPaymentReceivedBookingFailed {
+			return nil // otra operación ya la resolvió
+		}
+		if err := order.MarkHoldConfirmed(now); err != nil {
+			return err
+		}
+		_, err = uc.Orders.Update(ctx, order)
+		return err
+	})
+}
+
+// refund reintenta el reembolso automático de la saga (misma clave: no reembolsa dos veces)
+// y libera el hold.
+func (uc RetryIncident) refund(ctx context.Context, incident checkoutdomain.Incident) error {
+	if _, err := uc.RefundOrderUC.Execute(ctx, RefundOrderInput{OrderID: incident.OrderID, Key: BookingFailedRefundKey}); err != nil {
+		return err
+	}
+	if incident.HoldID != "" {
+		if err := uc.Booking.CancelHold(ctx, incident.HoldID); err != nil {
+			reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+				OrderID:   incident.OrderID,
+				HoldID:    incident.HoldID,
+				Operation: checkoutdomain.IncidentOperationCancelHold,
+				Err:       err,
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/platform/audit"
+)
+
+// unreliableBookingClient falla CancelHold mientras cancelErr no sea nil.
+type unreliableBookingClient struct {
+	flakyBookingClient
+	cancelErr error
+}
+
+func (c *unreliableBookingClient) CancelHold(ctx context.Context, holdID string) error {
+	if c.cancelErr != nil {
+		return c.cancelErr
+	}
+	return c.recordingBookingClient.CancelHold(ctx, holdID)
+}
+
+func TestCancelOrder_HoldReleaseFails_RecordsIncident(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	incidents := checkoutmemory.NewIncidentRepository()
+	order := createHeldTestOrder(t, repo)
+	recordIncidentUC := &RecordIncident{Repo: incidents}
+
+	_, err := CancelOrder{
+		Repo:             repo,
+		Booking:          &unreliableBookingClient{cancelErr: errBookingDown},
+		RecordIncidentUC: recordIncidentUC,
+	}.Execute(context.Background(), CancelOrderInput{OrderID: order.ID})
+	if err != nil {
+		t.Fatalf("expected cancellation to succeed, got: %v", err)
+	}
+
+	open, _ := ListIncidents{Repo: incidents}.Execute(context.Background(), ListIncidentsInput{Status: checkoutdomain.IncidentStatusOpen})
+	if len(open.Incidents) != 1 {
+		t.Fatalf("expected 1 open incident, got: %+v", open.Incidents)
+	}
+	incident := open.Incidents[0]
+	if incident.OrderID != order.ID || incident.HoldID != *order.BookingHoldID ||
+		incident.Operation != checkoutdomain.IncidentOperationCancelHold || incident.Error != errBookingDown.Error() {
+		t.Errorf("unexpected incident: %+v", incident)
+	}
+
+	// La misma falla no abre un segundo incidente
+	again, err := recordIncidentUC.Execute(context.Background(), RecordIncidentInput{
+		OrderID:   order.ID,
+		HoldID:    *order.BookingHoldID,
+		Operation: checkoutdomain.IncidentOperationCancelHold,
+		Err:       errBookingDown,
+	})
+	if err != nil || again.Incident.ID != incident.ID {
+		t.Errorf("expected the existing incident, got: %+v %v", again.Incident, err)
+	}
+}
+
+func TestRetryIncident_CancelHold(t *testing.T) {
+	incidents := checkoutmemory.NewIncidentRepository()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	recorded, _ := RecordIncident{Repo: incidents, Now: func() time.Time { return now }}.Execute(context.Background(), RecordIncidentInput{
+		OrderID:   "order_1",
+		HoldID:    "hold_1",
+		Operation: checkoutdomain.IncidentOperationCancelHold,
+		Err:       errBookingDown,
+	})
+
+	booking := &unreliableBookingClient{cancelErr: errors.New("still down")}
+	uc := RetryIncident{Repo: incidents, Booking: booking, Now: func() time.Time { return now.Add(time.Hour) }}
+	ctx := audit.WithActor(context.Background(), audit.ActorAdmin)
+
+	// Booking sigue caído: el incidente queda abierto con el nuevo error
+	if _, err := uc.Execute(ctx, RetryIncidentInput{IncidentID: recorded.Incident.ID}); !errors.Is(err, ErrIncidentRetryFailed) {
+		t.Fatalf("expected ErrIncidentRetryFailed, got: %v", err)
+	}
+	stored, _ := incidents.GetByID(context.Background(), recorded.Incident.ID)
+	if stored.Status != checkoutdomain.IncidentStatusOpen || stored.Attempts != 1 || stored.Error != "still down" {
+		t.Errorf("expected open incident with 1 failed attempt, got: %+v", stored)
+	}
+
+	booking.cancelErr = nil
+	output, err := uc.Execute(ctx, RetryIncidentInput{IncidentID: recorded.Incident.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Incident.Status != checkoutdomain.IncidentStatusResolved ||
+		output.Incident.Resolution != IncidentRetryNote || output.Incident.ResolvedBy != audit.ActorAdmin {
+		t.Errorf("expected incident resolved by retry, got: %+v", output.Incident)
+	}
+	if len(booking.cancelled) != 1 || booking.cancelled[0] != "hold_1" {
+		t.Errorf("expected hold_1 released, got: %v", booking.cancelled)
+	}
+
+	if _, err := uc.Execute(ctx, RetryIncidentInput{IncidentID: recorded.Incident.ID}); !errors.Is(err, checkoutdomain.ErrIncidentResolved) {
+		t.Errorf("expected ErrIncidentResolved, got: %v", err)
+	}
+}
+
+func TestRetryIncident_ConfirmHold_RestoresPaidOrder(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	incidents := checkoutmemory.NewIncidentRepository()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	booking := &flakyBookingClient{failures: 2}
+	order := confirmWithFailingHold(t, repo, booking, now)
+
+	// Reintentos agotados: la saga deja la orden en la cola de revisión
+	saga, _ := RetryHoldConfirmations{
+		Repo:             repo,
+		Booking:          booking,
+		RecordIncidentUC: &RecordIncident{Repo: incidents},
+		Policy:           checkoutdomain.HoldRetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute},
+	}.Execute(context.Background(), RetryHoldConfirmationsInput{Now: now.Add(time.Minute)})
+	if saga.SentToReview != 1 {
+		t.Fatalf("expected order sent to review, got: %+v", saga)
+	}
+
+	open, _ := incidents.List(context.Background(), checkoutdomain.IncidentQuery{OrderID: order.ID})
+	if len(open) != 1 || open[0].Operation != checkoutdomain.IncidentOperationConfirmHold || open[0].Error != errBookingDown.Error() {
+		t.Fatalf("expected confirm_hold incident, got: %+v", open)
+	}
+
+	// Booking se recuperó: el reintento confirma el hold y la orden vuelve a paid
+	output, err := RetryIncident{Repo: incidents, Orders: repo, Booking: booking}.Execute(
+		context.Background(), RetryIncidentInput{IncidentID: open[0].ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Incident.Status != checkoutdomain.IncidentStatusResolved {
+		t.Errorf("expected resolved incident, got: %v", output.Incident.Status)
+	}
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected paid order, got: %v", stored.Status)
+	}
+}
+
+func TestRetryIncident_AutoRefundFailed_RetriesRefund(t *testing.T) {
+	repo := checkoutmemory.NewOrderRepository()
+	incidents := checkoutmemory.NewIncidentRepository()
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	booking := &flakyBookingClient{failures: 10}
+	order := confirmWithFailingHold(t, repo, booking, now)
+
+	provider := &recordingPaymentsClient{err: errors.New("provider down")}
+	refundOrderUC := &RefundOrder{Repo: repo, Payments: provider}
+	RetryHoldConfirmations{
+		Repo:             repo,
+		Booking:          booking,
+		RefundOrderUC:    refundOrderUC,
+		RecordIncidentUC: &RecordIncident{Repo: incidents},
+		Policy:           checkoutdomain.HoldRetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute},
+		AutoRefund:       true,
+	}.Execute(context.Background(), RetryHoldConfirmationsInput{Now: now.Add(time.Minute)})
+
+	open, _ := incidents.List(context.Background(), checkoutdomain.IncidentQuery{Status: checkoutdomain.IncidentStatusOpen})
+	if len(open) != 1 || open[0].Operation != checkoutdomain.IncidentOperationRefund {
+		t.Fatalf("expected refund incident, got: %+v", open)
+	}
+
+	provider.err = nil
+	if _, err := (RetryIncident{Repo: incidents, Orders: repo, Booking: booking, RefundOrderUC: refundOrderUC}).Execute(
+		context.Background(), RetryIncidentInput{IncidentID: open[0].ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, _ := repo.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusRefunded {
+		t.Errorf("expected refunded order, got: %v", stored.Status)
+	}
+	if len(booking.cancelled) != 1 {
+		t.Errorf("expected hold released after refund, got: %v", booking.cancelled)
+	}
+}
+
+func TestResolveIncident(t *testing.T) {
+	incidents := checkoutmemory.NewIncidentRepository()
+	recorded, _ := RecordIncident{Repo: incidents}.Execute(context.Background(), RecordIncidentInput{
+		HoldID:    "hold_orphan",
+		Operation: checkoutdomain.IncidentOperationCancelHold,
+		Err:       errBookingDown,
+	})
+	uc := ResolveIncident{Repo: incidents}
+	ctx := audit.WithActor(context.Background(), audit.ActorAdmin)
+
+	if _, err := uc.Execute(ctx, ResolveIncidentInput{IncidentID: recorded.Incident.ID, Note: "  "}); !errors.Is(err, checkoutdomain.ErrMissingResolutionNote) {
+		t.Errorf("expected ErrMissingResolutionNote, got: %v", err)
+	}
+	output, err := uc.Execute(ctx, ResolveIncidentInput{IncidentID: recorded.Incident.ID, Note: "released from the booking console"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Incident.Status != checkoutdomain.IncidentStatusResolved || output.Incident.ResolvedAt == nil ||
+		output.Incident.Resolution != "released from the booking console" {
+		t.Errorf("unexpected incident: %+v", output.Incident)
+	}
+	if _, err := uc.Execute(ctx, ResolveIncidentInput{IncidentID: "inc_missing", Note: "x"}); !errors.Is(err, checkoutdomain.ErrIncidentNotFound) {
+		t.Errorf("expected ErrIncidentNotFound, got: %v", err)
+	}
+
+	list := ListIncidents{Repo: incidents}
+	open, _ := list.Execute(context.Background(), ListIncidentsInput{Status: checkoutdomain.IncidentStatusOpen})
+	resolved, _ := list.Execute(context.Background(), ListIncidentsInput{Status: checkoutdomain.IncidentStatusResolved})
+	if len(open.Incidents) != 0 || len(resolved.Incidents) != 1 {
+		t.Errorf("expected 0 open and 1 resolved, got: %d %d", len(open.Incidents), len(resolved.Incidents))
+	}
+	if _, err := list.Execute(context.Background(), ListIncidentsInput{Status: "closed"}); !errors.Is(err, ErrInvalidIncidentFilter) {
+		t.Errorf("expected ErrInvalidIncidentFilter, got: %v", err)
+	}
+}
@@ -2,10 +2,12 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	cartdomain "paku-commerce/internal/commerce/cart/domain"
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
 	platformbooking "paku-commerce/internal/commerce/platform/booking"
+	"paku-commerce/internal/platform/transaction"
 )
 
 // StartCheckoutInput contiene user_id y slot_id.
@@ -25,10 +27,16 @@ type StartCheckoutOutput struct {
 type StartCheckout struct {
 	CartRepo      cartdomain.CartRepository
 	Booking       platformbooking.Client
+	Slots         platformbooking.SlotReader // opcional: guarda la hora de la cita en la orden (best-effort)
 	CreateOrderUC *CreateOrder
+	Tx            transaction.Manager
+
+	RecordIncidentUC *RecordIncident // opcional: registra en la cola de revisión los holds que no se liberaron
 }
 
 // Execute ejecuta el flujo de start checkout.
+// La orden y la actualización del cart se confirman o revierten juntas;
+// el hold (externo) se compensa con CancelHold solo si la transacción se revierte.
 func (uc StartCheckout) Execute(ctx context.Context, input StartCheckoutInput) (StartCheckoutOutput, error) {
 	// 1. Validaciones
 	if input.UserID == "" {
@@ -48,44 +56,91 @@ func (uc StartCheckout) Execute(ctx context.Context, input StartCheckoutInput) (
 		return StartCheckoutOutput{}, cartdomain.ErrEmptyItems
 	}
 
-	// 3. Reemplazar hold previo (cancelar si existe)
-	if cart.BookingHoldID != nil && *cart.BookingHoldID != "" {
-		_ = uc.Booking.CancelHold(ctx, *cart.BookingHoldID) // best-effort
+	// 3. Hora de la cita (la usa la política de cancelación). No bloquea el checkout: si
+	// booking no la entrega, la orden queda sin fecha y la política aplica su default
+	// (reembolso completo); un slot inexistente igual falla al crear el hold.
+	var appointmentAt *time.Time
+	if uc.Slots != nil {
+		if slot, err := uc.Slots.GetSlot(ctx, input.SlotID); err == nil {
+			appointmentAt = &slot.StartsAt
+		}
 	}
 
-	// 4. Crear nuevo hold
+	// 4. Crear nuevo hold (side effect externo, fuera de la transacción)
 	holdID, err := uc.Booking.CreateHold(ctx, input.SlotID)
 	if err != nil {
 		return StartCheckoutOutput{}, err
 	}
 
-	// 5. Crear orden usando CreateOrder UC
-	intent := checkoutdomain.PurchaseIntent{
-		PetProfile:    cart.PetProfile,
-		Items:         cart.Items,
-		CouponCode:    nil, // TODO: tomar del cart si se agregó cupón
-		BookingHoldID: &holdID,
+	var previousHoldID, previousOrderID string
+	if cart.BookingHoldID != nil {
+		previousHoldID = *cart.BookingHoldID
 	}
-
-	orderOutput, err := uc.CreateOrderUC.Execute(ctx, CreateOrderInput{Intent: intent})
-	if err != nil {
-		// Best-effort: cancelar hold si falló crear orden
-		_ = uc.Booking.CancelHold(ctx, holdID)
-		return StartCheckoutOutput{}, err
+	if cart.OrderID != nil {
+		previousOrderID = *cart.OrderID
 	}
 
-	// 6. Actualizar cart con hold y order refs
-	cart.BookingHoldID = &holdID
-	cart.OrderID = &orderOutput.Order.ID
+	// 5. Crear orden + actualizar cart en una sola unidad de trabajo
+	var (
+		order       checkoutdomain.Order
+		updatedCart cartdomain.Cart
+	)
+	err = uc.withinTx(ctx, func(ctx context.Context) error {
+		intent := cart.PurchaseIntent()
+		intent.BookingHoldID = &holdID
+
+		orderOutput, err := uc.CreateOrderUC.Execute(ctx, CreateOrderInput{
+			UserID:        input.UserID,
+			SlotID:        input.SlotID,
+			AppointmentAt: appointmentAt,
+			Intent:        intent,
+		})
+		if err != nil {
+			return err
+		}
+		order = orderOutput.Order
+
+		cart.BookingHoldID = &holdID
+		cart.OrderID = &order.ID
 
-	updatedCart, err := uc.CartRepo.Upsert(ctx, cart)
+		updatedCart, err = uc.CartRepo.Upsert(ctx, cart)
+		return err
+	})
 	if err != nil {
+		// Compensación: liberar el hold nuevo (best-effort, sin orden: la transacción se revirtió)
+		if cancelErr := uc.Booking.CancelHold(ctx, holdID); cancelErr != nil {
+			reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+				HoldID:    holdID,
+				Operation: checkoutdomain.IncidentOperationCancelHold,
+				Err:       cancelErr,
+			})
+		}
 		return StartCheckoutOutput{}, err
 	}
 
+	// 6. Reemplazar hold previo: liberarlo solo tras el commit (best-effort)
+	if previousHoldID != "" && previousHoldID != holdID {
+		if err := uc.Booking.CancelHold(ctx, previousHoldID); err != nil {
+			reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+				OrderID:   previousOrderID,
+				HoldID:    previousHoldID,
+				Operation: checkoutdomain.IncidentOperationCancelHold,
+				Err:       err,
+			})
+		}
+	}
+
 	return StartCheckoutOutput{
 		Cart:          updatedCart,
-		Order:         orderOutput.Order,
+		Order:         order,
 		BookingHoldID: holdID,
 	}, nil
 }
+
+// withinTx usa el Manager configurado o ejecuta fn directamente si no hay uno.
+func (uc StartCheckout) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if uc.Tx == nil {
+		return fn(ctx)
+	}
+	return uc.Tx.WithinTx(ctx, fn)
+}
@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cartmemory "paku-commerce/internal/commerce/cart/adapters/memory"
+	cartdomain "paku-commerce/internal/commerce/cart/domain"
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	platformbooking "paku-commerce/internal/commerce/platform/booking"
+	servicememory "paku-commerce/internal/commerce/service/adapters/memory"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	"paku-commerce/internal/platform/transaction"
+	pricingmemory "paku-commerce/internal/pricing/adapters/memory"
+	pricingusecases "paku-commerce/internal/pricing/usecases"
+	promotionsmemory "paku-commerce/internal/promotions/adapters/memory"
+	promotionsusecases "paku-commerce/internal/promotions/usecases"
+)
+
+var errUpsertFailed = errors.New("upsert failed")
+
+// failingCartRepo falla en Upsert para simular un error a mitad de la transacción.
+type failingCartRepo struct {
+	*cartmemory.CartRepository
+}
+
+func (r failingCartRepo) Upsert(ctx context.Context, cart cartdomain.Cart) (cartdomain.Cart, error) {
+	return cartdomain.Cart{}, errUpsertFailed
+}
+
+// recordingBookingClient registra holds creados y cancelados.
+type recordingBookingClient struct {
+	created   []string
+	cancelled []string
+}
+
+func (c *recordingBookingClient) CreateHold(ctx context.Context, slotID string) (string, error) {
+	holdID := "hold_" + slotID
+	c.created = append(c.created, holdID)
+	return holdID, nil
+}
+
+func (c *recordingBookingClient) ValidateHold(ctx context.Context, holdID string) error {
+	return nil
+}
+
+func (c *recordingBookingClient) ConfirmHold(ctx context.Context, holdID string) error {
+	return nil
+}
+
+func (c *recordingBookingClient) CancelHold(ctx context.Context, holdID string) error {
+	c.cancelled = append(c.cancelled, holdID)
+	return nil
+}
+
+// captureOrderRepo guarda el ID de la última orden creada.
+type captureOrderRepo struct {
+	*checkoutmemory.OrderRepository
+	lastID string
+}
+
+func (r *captureOrderRepo) Create(ctx context.Context, order checkoutdomain.Order) (checkoutdomain.Order, error) {
+	r.lastID = order.ID
+	return r.OrderRepository.Create(ctx, order)
+}
+
+func newTestCreateOrder(orderRepo checkoutdomain.OrderRepository) *CreateOrder {
+	return &CreateOrder{
+		QuoteCheckoutUC: &QuoteCheckout{
+			ServiceRepo:  servicememory.NewServiceRepository(),
+			PriceQuoteUC: &pricingusecases.QuoteItems{RuleRepo: pricingmemory.NewPriceRuleRepository()},
+			PromotionsUC: &promotionsusecases.ApplyDiscounts{Repo: promotionsmemory.NewPromotionsRepository()},
+		},
+		OrderRepo: orderRepo,
+		Now:       time.Now,
+	}
+}
+
+func seedCart(t *testing.T, repo cartdomain.CartRepository, userID string, holdID *string) {
+	t.Helper()
+	cart := cartdomain.NewCart(userID, servicedomain.PetProfile{
+		Species:  servicedomain.SpeciesDog,
+		WeightKg: 10,
+		CoatType: servicedomain.CoatTypeShort,
+	}, []checkoutdomain.PurchaseItem{
+		{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+	}, time.Now())
+	cart.BookingHoldID = holdID
+	if _, err := repo.Upsert(context.Background(), cart); err != nil {
+		t.Fatalf("failed to seed cart: %v", err)
+	}
+}
+
+func TestStartCheckout_CartUpsertFails_RollsBackOrderAndCancelsNewHold(t *testing.T) {
+	cartRepo := cartmemory.NewCartRepository()
+	previousHold := "hold_previous"
+	seedCart(t, cartRepo, "user_tx", &previousHold)
+
+	orderRepo := &captureOrderRepo{OrderRepository: checkoutmemory.NewOrderRepository()}
+	booking := &recordingBookingClient{}
+
+	uc := &StartCheckout{
+		CartRepo:      failingCartRepo{cartRepo},
+		Booking:       booking,
+		CreateOrderUC: newTestCreateOrder(orderRepo),
+		Tx:            transaction.NewMemoryManager(),
+	}
+
+	_, err := uc.Execute(context.Background(), StartCheckoutInput{UserID: "user_tx", SlotID: "slot_1"})
+	if !errors.Is(err, errUpsertFailed) {
+		t.Fatalf("expected errUpsertFailed, got: %v", err)
+	}
+
+	// La orden creada dentro de la transacción no debe quedar persistida
+	if orderRepo.lastID == "" {
+		t.Fatalf("expected an order to have been attempted")
+	}
+	if _, err := orderRepo.GetByID(context.Background(), orderRepo.lastID); !errors.Is(err, checkoutdomain.ErrOrderNotFound) {
+		t.Errorf("expected orphan order to be rolled back, got: %v", err)
+	}
+
+	// Solo el hold nuevo se compensa; el previo sigue vivo
+	if len(booking.cancelled) != 1 || booking.cancelled[0] != "hold_slot_1" {
+		t.Errorf("expected only new hold to be cancelled, got: %v", booking.cancelled)
+	}
+}
+
+func TestStartCheckout_Success_ReleasesPreviousHoldAfterCommit(t *testing.T) {
+	cartRepo := cartmemory.NewCartRepository()
+	previousHold := "hold_previous"
+	seedCart(t, cartRepo, "user_tx_ok", &previousHold)
+
+	orderRepo := checkoutmemory.NewOrderRepository()
+	booking := &recordingBookingClient{}
+
+	uc := &StartCheckout{
+		CartRepo:      cartRepo,
+		Booking:       booking,
+		CreateOrderUC: newTestCreateOrder(orderRepo),
+		Tx:            transaction.NewMemoryManager(),
+	}
+
+	output, err := uc.Execute(context.Background(), StartCheckoutInput{UserID: "user_tx_ok", SlotID: "slot_2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := orderRepo.GetByID(context.Background(), output.Order.ID); err != nil {
+		t.Errorf("expected order to be persisted, got: %v", err)
+	}
+	if output.Cart.OrderID == nil || *output.Cart.OrderID != output.Order.ID {
+		t.Errorf("expected cart.order_id to match order")
+	}
+	if len(booking.cancelled) != 1 || booking.cancelled[0] != previousHold {
+		t.Errorf("expected previous hold to be cancelled, got: %v", booking.cancelled)
+	}
+}
+
+func TestStartCheckout_UsesCartCoupon(t *testing.T) {
+	cartRepo := cartmemory.NewCartRepository()
+	cart := cartdomain.NewCart("user_coupon", servicedomain.PetProfile{
+		Species:  servicedomain.SpeciesDog,
+		WeightKg: 10,
+		CoatType: servicedomain.CoatTypeShort,
+	}, []checkoutdomain.PurchaseItem{
+		{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+	}, time.Now())
+	cart.ApplyCoupon("BANO10", time.Now())
+	if _, err := cartRepo.Upsert(context.Background(), cart); err != nil {
+		t.Fatalf("failed to seed cart: %v", err)
+	}
+
+	uc := &StartCheckout{
+		CartRepo:      cartRepo,
+		Booking:       &recordingBookingClient{},
+		CreateOrderUC: newTestCreateOrder(checkoutmemory.NewOrderRepository()),
+		Tx:            transaction.NewMemoryManager(),
+	}
+
+	output, err := uc.Execute(context.Background(), StartCheckoutInput{UserID: "user_coupon", SlotID: "slot_3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.Order.CouponCode == nil || *output.Order.CouponCode != "BANO10" {
+		t.Errorf("expected order coupon BANO10, got: %v", output.Order.CouponCode)
+	}
+	if output.Order.TotalDiscount.Amount == 0 {
+		t.Errorf("expected coupon discount on order")
+	}
+}
+
+// unavailableSlotReader simula que booking no entrega la hora del slot.
+type unavailableSlotReader struct{}
+
+func (unavailableSlotReader) GetSlot(ctx context.Context, slotID string) (platformbooking.Slot, error) {
+	return platformbooking.Slot{}, errors.New("booking unavailable")
+}
+
+func TestStartCheckout_SlotLookupFails_CreatesOrderWithoutAppointment(t *testing.T) {
+	cartRepo := cartmemory.NewCartRepository()
+	seedCart(t, cartRepo, "user_no_slot", nil)
+
+	orderRepo := checkoutmemory.NewOrderRepository()
+	uc := &StartCheckout{
+		CartRepo:      cartRepo,
+		Booking:       &recordingBookingClient{},
+		Slots:         unavailableSlotReader{},
+		CreateOrderUC: newTestCreateOrder(orderRepo),
+		Tx:            transaction.NewMemoryManager(),
+	}
+
+	output, err := uc.Execute(context.Background(), StartCheckoutInput{UserID: "user_no_slot", SlotID: "slot_3"})
+	if err != nil {
+		t.Fatalf("expected checkout to succeed without the slot time, got: %v", err)
+	}
+	if output.Order.AppointmentAt != nil {
+		t.Errorf("expected order without appointment time, got: %v", output.Order.AppointmentAt)
+	}
+}
+
+func TestMemoryTx_Rollback_KeepsWritesOfOtherRequests(t *testing.T) {
+	ctx := context.Background()
+	orderRepo := checkoutmemory.NewOrderRepository()
+	cartRepo := cartmemory.NewCartRepository()
+	own := createTestOrder(t, orderRepo)
+	shared := createTestOrder(t, orderRepo)
+	seedCart(t, cartRepo, "user_tx_own", nil)
+	seedCart(t, cartRepo, "user_tx_shared", nil)
+	ownEvents, _ := orderRepo.ListEvents(ctx, own.ID)
+
+	err := transaction.NewMemoryManager().WithinTx(ctx, func(txCtx context.Context) error {
+		for _, order := range []checkoutdomain.Order{own, shared} {
+			if err := order.MarkProcessing(time.Now()); err != nil {
+				t.Fatalf("failed to mark processing: %v", err)
+			}
+			if _, err := orderRepo.Update(txCtx, order); err != nil {
+				t.Fatalf("failed to update order in tx: %v", err)
+			}
+		}
+		for _, userID := range []string{"user_tx_own", "user_tx_shared"} {
+			cart, _ := cartRepo.GetByUserID(txCtx, userID)
+			if _, err := cartRepo.Upsert(txCtx, cart); err != nil {
+				t.Fatalf("failed to update cart in tx: %v", err)
+			}
+		}
+
+		// Otro request (sin transacción) escribe sobre la orden y el carrito compartidos
+		concurrent, _ := orderRepo.GetByID(ctx, shared.ID)
+		if err := concurrent.MarkFailed("card_declined", time.Now()); err != nil {
+			t.Fatalf("failed to mark failed: %v", err)
+		}
+		if _, err := orderRepo.Update(ctx, concurrent); err != nil {
+			t.Fatalf("failed concurrent order update: %v", err)
+		}
+		if err := cartRepo.DeleteByUserID(ctx, "user_tx_shared"); err != nil {
+			t.Fatalf("failed concurrent cart delete: %v", err)
+		}
+		return errUpsertFailed
+	})
+	if !errors.Is(err, errUpsertFailed) {
+		t.Fatalf("expected the tx error, got: %v", err)
+	}
+
+	// Lo que solo escribió la transacción se revierte
+	if stored, _ := orderRepo.GetByID(ctx, own.ID); stored.Version != own.Version || stored.Status != own.Status {
+		t.Errorf("expected own order restored, got: v%d %s", stored.Version, stored.Status)
+	}
+	if events, _ := orderRepo.ListEvents(ctx, own.ID); len(events) != len(ownEvents) {
+		t.Errorf("expected own order events restored, got %d (want %d)", len(events), len(ownEvents))
+	}
+	if cart, _ := cartRepo.GetByUserID(ctx, "user_tx_own"); cart.Revision != 1 {
+		t.Errorf("expected own cart restored, got revision %d", cart.Revision)
+	}
+
+	// Lo que otro request escribió encima se conserva
+	stored, _ := orderRepo.GetByID(ctx, shared.ID)
+	if stored.Status != checkoutdomain.OrderStatusFailed || stored.Version != shared.Version+2 {
+		t.Errorf("expected the concurrent write kept, got: v%d %s", stored.Version, stored.Status)
+	}
+	if _, err := cartRepo.GetByUserID(ctx, "user_tx_shared"); !errors.Is(err, cartdomain.ErrCartNotFound) {
+		t.Errorf("expected the concurrent cart delete kept, got: %v", err)
+	}
+}
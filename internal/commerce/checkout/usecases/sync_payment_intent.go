@@ -0,0 +1,188 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+)
+
+// PaymentIntentClaimTimeout es cuánto se respeta el claim de una conciliación en curso;
+// pasado ese tiempo se asume que murió y otra puede retomar el intento.
+const PaymentIntentClaimTimeout = 5 * time.Minute
+
+// SyncPaymentIntentInput identifica el intento a conciliar: por ID o, desde una
+// notificación del proveedor, por el ID del cobro.
+type SyncPaymentIntentInput struct {
+	IntentID    string
+	ProviderRef string // se usa si IntentID está vacío
+}
+
+// SyncPaymentIntentOutput contiene el intento tras la conciliación.
+type SyncPaymentIntentOutput struct {
+	Intent checkoutdomain.PaymentIntent
+}
+
+// SyncPaymentIntent consulta al proveedor el estado de un cobro QR pendiente y lo
+// aplica a la orden:
+//   - succeeded confirma el pago (ConfirmPayment, cuyo Payments debe ser el cliente QR)
+//   - failed marca la orden failed (se puede generar otro QR)
+//   - expired cierra el intento; la orden vence con su propio plazo (ExpirePendingOrders)
+//   - cancelled (QR anulado, ver CancelPendingPaymentIntent) cierra el intento
+//
+// Si la transferencia llega a una orden que ya no la acepta (cancelada, vencida, monto
+// distinto), el intento queda succeeded con el motivo y se abre un incidente
+// confirm_payment para reembolsar a mano.
+type SyncPaymentIntent struct {
+	Intents          checkoutdomain.PaymentIntentRepository
+	Orders           checkoutdomain.OrderRepository
+	QR               payments.QRPaymentsClient
+	ConfirmPaymentUC *ConfirmPayment
+	RecordIncidentUC *RecordIncident // opcional: registra los cobros que la orden no pudo aceptar
+	Now              func() time.Time
+}
+
+// Execute concilia el intento. Un intento ya resuelto se retorna sin consultar al proveedor.
+// Antes de aplicar un resultado toma el intento (Claim): si otra conciliación lo tiene, lo
+// retorna sin aplicar nada. Si la confirmación falla por un error transitorio, el intento
+// se libera y sigue pending para reintentar.
+func (uc SyncPaymentIntent) Execute(ctx context.Context, input SyncPaymentIntentInput) (SyncPaymentIntentOutput, error) {
+	// 1. Cargar el intento
+	var (
+		intent checkoutdomain.PaymentIntent
+		err    error
+	)
+	if input.IntentID != "" {
+		intent, err = uc.Intents.GetByID(ctx, input.IntentID)
+	} else {
+		intent, err = uc.Intents.GetByProviderRef(ctx, input.ProviderRef)
+	}
+	if err != nil {
+		return SyncPaymentIntentOutput{}, err
+	}
+	if !intent.IsPending() {
+		return SyncPaymentIntentOutput{Intent: intent}, nil
+	}
+
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+
+	// 2. Consultar el cobro en el proveedor
+	qr, err := uc.QR.GetQRPayment(ctx, intent.ProviderRef)
+	if errors.Is(err, payments.ErrPaymentNotFound) {
+		// El proveedor no conoce el cobro: el QR no se puede pagar
+		qr = payments.QRPayment{Status: payments.ChargeStatusFailed, FailureReason: err.Error()}
+	} else if err != nil {
+		return SyncPaymentIntentOutput{Intent: intent}, err
+	}
+
+	// 3. Tomar el intento: solo una conciliación aplica el resultado
+	if qr.Status != payments.ChargeStatusSucceeded && qr.Status != payments.ChargeStatusFailed &&
+		qr.Status != payments.ChargeStatusExpired && qr.Status != payments.ChargeStatusCancelled {
+		// Sigue pendiente en el proveedor
+		return SyncPaymentIntentOutput{Intent: intent}, nil
+	}
+	intent, claimed, err := uc.Intents.Claim(ctx, intent.ID, now, now.Add(-PaymentIntentClaimTimeout))
+	if err != nil {
+		return SyncPaymentIntentOutput{}, err
+	}
+	if !claimed {
+		return SyncPaymentIntentOutput{Intent: intent}, nil
+	}
+
+	// 4. Aplicar el resultado a la orden
+	switch qr.Status {
+	case payments.ChargeStatusSucceeded:
+		paidAt := now
+		if qr.PaidAt != nil {
+			paidAt = *qr.PaidAt
+		}
+		_, err := uc.ConfirmPaymentUC.Execute(ctx, ConfirmPaymentInput{
+			OrderID:    intent.OrderID,
+			PaymentRef: intent.ProviderRef,
+			PaidAt:     paidAt,
+		})
+		reason := ""
+		if err != nil {
+			if !isPermanentPaymentEventError(err) {
+				return uc.release(ctx, intent, err)
+			}
+			reason = err.Error()
+			reportIncident(ctx, uc.RecordIncidentUC, RecordIncidentInput{
+				OrderID:   intent.OrderID,
+				Operation: checkoutdomain.IncidentOperationConfirmPayment,
+				Err:       err,
+			})
+		}
+		err = intent.MarkSucceeded(reason, now)
+		if err != nil {
+			return SyncPaymentIntentOutput{}, err
+		}
+
+	case payments.ChargeStatusFailed:
+		err := retryOnVersionConflict(func() error {
+			return uc.markOrderFailed(ctx, intent.OrderID, qr.FailureReason, now)
+		})
+		if err != nil {
+			return uc.release(ctx, intent, err)
+		}
+		if err := intent.MarkFailed(qr.FailureReason, now); err != nil {
+			return SyncPaymentIntentOutput{}, err
+		}
+
+	case payments.ChargeStatusExpired:
+		if err := intent.MarkExpired(now); err != nil {
+			return SyncPaymentIntentOutput{}, err
+		}
+
+	case payments.ChargeStatusCancelled:
+		if err := intent.MarkCancelled(now); err != nil {
+			return SyncPaymentIntentOutput{}, err
+		}
+	}
+
+	// 5. Persistir el intento resuelto (si un claim vencido lo retomó y resolvió antes, gana ese)
+	updated, err := uc.Intents.Update(ctx, intent)
+	if errors.Is(err, checkoutdomain.ErrPaymentIntentSettled) {
+		current, err := uc.Intents.GetByID(ctx, intent.ID)
+		if err != nil {
+			return SyncPaymentIntentOutput{}, err
+		}
+		return SyncPaymentIntentOutput{Intent: current}, nil
+	}
+	if err != nil {
+		return SyncPaymentIntentOutput{}, err
+	}
+	return SyncPaymentIntentOutput{Intent: updated}, nil
+}
+
+// release suelta el claim tras un error transitorio para que la próxima conciliación
+// reintente sin esperar PaymentIntentClaimTimeout (best-effort: si falla, el claim vence).
+func (uc SyncPaymentIntent) release(ctx context.Context, intent checkoutdomain.PaymentIntent, cause error) (SyncPaymentIntentOutput, error) {
+	intent.ClaimedAt = nil
+	if released, err := uc.Intents.Update(ctx, intent); err == nil {
+		intent = released
+	}
+	return SyncPaymentIntentOutput{Intent: intent}, cause
+}
+
+// markOrderFailed registra el rechazo en una orden pending_payment. Si la orden ya salió
+// de ese estado (pagada por otra vía, cancelada, ya failed) no se toca.
+func (uc SyncPaymentIntent) markOrderFailed(ctx context.Context, orderID, reason string, at time.Time) error {
+	order, err := uc.Orders.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != checkoutdomain.OrderStatusPendingPayment {
+		return nil
+	}
+	if err := order.MarkFailed(reason, at); err != nil {
+		return err
+	}
+	_, err = uc.Orders.Update(ctx, order)
+	return err
+}
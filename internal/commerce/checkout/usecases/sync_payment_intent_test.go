@@ -0,0 +1,216 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+func createQRIntent(t *testing.T, f *qrPaymentFixture, order checkoutdomain.Order) checkoutdomain.PaymentIntent {
+	t.Helper()
+	output, err := f.create.Execute(context.Background(), CreatePaymentIntentInput{
+		OrderID: order.ID,
+		Method:  checkoutdomain.PaymentMethodYape,
+	})
+	if err != nil {
+		t.Fatalf("failed to create payment intent: %v", err)
+	}
+	return output.Intent
+}
+
+func TestSyncPaymentIntent_TransferLands_MarksOrderPaid(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	intent := createQRIntent(t, f, order)
+
+	// Mientras no llegue la transferencia, el intento y la orden siguen esperando
+	pending, err := f.sync.Execute(context.Background(), SyncPaymentIntentInput{IntentID: intent.ID})
+	if err != nil || !pending.Intent.IsPending() {
+		t.Fatalf("expected pending intent, got: %+v %v", pending.Intent, err)
+	}
+
+	f.now = f.now.Add(time.Minute)
+	if err := f.qr.Complete(intent.ProviderRef); err != nil {
+		t.Fatalf("failed to complete QR payment: %v", err)
+	}
+	output, err := f.sync.Execute(context.Background(), SyncPaymentIntentInput{IntentID: intent.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Intent.Status != checkoutdomain.PaymentIntentStatusSucceeded || output.Intent.FailureReason != "" {
+		t.Errorf("expected succeeded intent, got: %+v", output.Intent)
+	}
+	stored, _ := f.orders.GetByID(context.Background(), order.ID)
+	if stored.Status != checkoutdomain.OrderStatusPaid || *stored.PaymentRef != intent.ProviderRef || !stored.PaidAt.Equal(f.now) {
+		t.Errorf("expected order paid with the QR charge, got: %+v", stored)
+	}
+
+	// El intento resuelto no se vuelve a aplicar
+	again, err := f.sync.Execute(context.Background(), SyncPaymentIntentInput{IntentID: intent.ID})
+	if err != nil || again.Intent.Status != checkoutdomain.PaymentIntentStatusSucceeded {
+		t.Errorf("expected settled intent, got: %+v %v", again.Intent, err)
+	}
+	if after, _ := f.orders.GetByID(context.Background(), order.ID); after.Version != stored.Version {
+		t.Errorf("expected order untouched, version %d -> %d", stored.Version, after.Version)
+	}
+}
+
+func TestSyncPaymentIntent_PaidByQR_RefundsThroughQRProvider(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	intent := createQRIntent(t, f, order)
+	if err := f.qr.Complete(intent.ProviderRef); err != nil {
+		t.Fatalf("failed to complete QR payment: %v", err)
+	}
+	if _, err := f.sync.Execute(context.Background(), SyncPaymentIntentInput{IntentID: intent.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, _ := f.orders.GetByID(context.Background(), order.ID)
+	if stored.PaidWith() != checkoutdomain.PaymentProviderQR {
+		t.Fatalf("expected order paid with the QR provider, got: %q", stored.PaymentProvider)
+	}
+
+	// Sin proveedor QR configurado el reembolso no cae en el de tarjetas
+	card := &recordingPaymentsClient{}
+	if _, err := (RefundOrder{Repo: f.orders, Payments: card}).Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_qr"}); !errors.Is(err, ErrRefundFailed) {
+		t.Errorf("expected ErrRefundFailed without a QR provider, got: %v", err)
+	}
+
+	output, err := RefundOrder{Repo: f.orders, Payments: card, QRPayments: f.qr}.Execute(context.Background(), RefundOrderInput{OrderID: order.ID, Key: "rf_qr"})
+	if err != nil {
+		t.Fatalf("unexpected refund error: %v", err)
+	}
+	if output.Order.Status != checkoutdomain.OrderStatusRefunded || !strings.HasPrefix(output.Refund.ProviderRef, "qr_sim_refund_") {
+		t.Errorf("expected refund issued by the QR provider, got: %+v", output.Refund)
+	}
+	if len(card.refunds) != 0 {
+		t.Errorf("expected no card refunds for a QR payment, got: %d", len(card.refunds))
+	}
+}
+
+func TestSyncPaymentIntent_FailedAndExpired(t *testing.T) {
+	f := newQRPaymentFixture()
+
+	rejected := createTestOrder(t, f.orders)
+	rejectedIntent := createQRIntent(t, f, rejected)
+	if err := f.qr.Fail(rejectedIntent.ProviderRef, "insufficient_balance"); err != nil {
+		t.Fatalf("failed to fail QR payment: %v", err)
+	}
+	output, err := f.sync.Execute(context.Background(), SyncPaymentIntentInput{ProviderRef: rejectedIntent.ProviderRef})
+	if err != nil || output.Intent.Status != checkoutdomain.PaymentIntentStatusFailed ||
+		output.Intent.FailureReason != "insufficient_balance" {
+		t.Fatalf("expected failed intent, got: %+v %v", output.Intent, err)
+	}
+	stored, _ := f.orders.GetByID(context.Background(), rejected.ID)
+	if stored.Status != checkoutdomain.OrderStatusFailed {
+		t.Errorf("expected order failed, got: %s", stored.Status)
+	}
+
+	// Un QR que vence sin pago se cierra; la orden vence con su propio plazo
+	abandoned := createTestOrder(t, f.orders)
+	abandonedIntent := createQRIntent(t, f, abandoned)
+	f.now = abandonedIntent.ExpiresAt.Add(time.Second)
+	sweep := SyncPendingPaymentIntents{Intents: f.intents, SyncUC: f.sync}
+	swept, err := sweep.Execute(context.Background(), SyncPendingPaymentIntentsInput{Now: f.now})
+	if err != nil || swept.Expired != 1 || swept.Succeeded+swept.Failed+swept.Pending != 0 {
+		t.Fatalf("expected one expired intent, got: %+v %v", swept, err)
+	}
+	stored, _ = f.orders.GetByID(context.Background(), abandoned.ID)
+	if stored.Status != checkoutdomain.OrderStatusPendingPayment {
+		t.Errorf("expected order still pending_payment, got: %s", stored.Status)
+	}
+}
+
+func TestSyncPaymentIntent_LatePaymentOnCancelledOrder_OpensIncident(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	intent := createQRIntent(t, f, order)
+
+	// La orden se cancela mientras el comprador paga el QR
+	if err := order.MarkCancelled(checkoutdomain.CancelReasonExpired, f.now); err != nil {
+		t.Fatalf("failed to cancel order: %v", err)
+	}
+	if _, err := f.orders.Update(context.Background(), order); err != nil {
+		t.Fatalf("failed to persist cancelled order: %v", err)
+	}
+	if err := f.qr.Complete(intent.ProviderRef); err != nil {
+		t.Fatalf("failed to complete QR payment: %v", err)
+	}
+
+	output, err := f.sync.Execute(context.Background(), SyncPaymentIntentInput{IntentID: intent.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Intent.Status != checkoutdomain.PaymentIntentStatusSucceeded || output.Intent.FailureReason == "" {
+		t.Errorf("expected succeeded intent with the reason the order rejected it, got: %+v", output.Intent)
+	}
+	incidents, _ := f.incidents.List(context.Background(), checkoutdomain.IncidentQuery{OrderID: order.ID})
+	if len(incidents) != 1 || incidents[0].Operation != checkoutdomain.IncidentOperationConfirmPayment {
+		t.Errorf("expected a confirm_payment incident, got: %+v", incidents)
+	}
+}
+
+func TestHandlePaymentWebhook_QRNotification_SyncsPaymentIntent(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	intent := createQRIntent(t, f, order)
+	if err := f.qr.Complete(intent.ProviderRef); err != nil {
+		t.Fatalf("failed to complete QR payment: %v", err)
+	}
+
+	uc := newPaymentWebhookUC(f.orders, checkoutmemory.NewPaymentEventInbox())
+	uc.SyncPaymentIntentUC = f.sync
+	output, err := uc.Execute(context.Background(), HandlePaymentWebhookInput{Event: checkoutdomain.PaymentEvent{
+		Provider:   "qr",
+		ID:         "evt_qr_1",
+		Type:       checkoutdomain.PaymentEventSucceeded,
+		OrderID:    order.ID,
+		PaymentRef: intent.ProviderRef,
+		OccurredAt: f.now,
+	}})
+	if err != nil || output.Record.Status != checkoutdomain.PaymentEventStatusProcessed {
+		t.Fatalf("expected processed event, got: %+v %v", output.Record, err)
+	}
+
+	synced, _ := f.intents.GetByID(context.Background(), intent.ID)
+	stored, _ := f.orders.GetByID(context.Background(), order.ID)
+	if synced.Status != checkoutdomain.PaymentIntentStatusSucceeded || stored.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected intent succeeded and order paid, got: %s / %s", synced.Status, stored.Status)
+	}
+}
+
+func TestSyncPaymentIntent_ClaimedByAnotherSync_SkipsConfirmation(t *testing.T) {
+	f := newQRPaymentFixture()
+	order := createTestOrder(t, f.orders)
+	intent := createQRIntent(t, f, order)
+	if err := f.qr.Complete(intent.ProviderRef); err != nil {
+		t.Fatalf("failed to complete QR payment: %v", err)
+	}
+
+	// Otra conciliación (worker, GET o webhook) ya tomó el intento
+	if _, claimed, err := f.intents.Claim(context.Background(), intent.ID, f.now, f.now.Add(-PaymentIntentClaimTimeout)); err != nil || !claimed {
+		t.Fatalf("failed to claim intent: %v %v", claimed, err)
+	}
+	output, err := f.sync.Execute(context.Background(), SyncPaymentIntentInput{IntentID: intent.ID})
+	if err != nil || !output.Intent.IsPending() {
+		t.Fatalf("expected intent left to the other sync, got: %+v %v", output.Intent, err)
+	}
+	if stored, _ := f.orders.GetByID(context.Background(), order.ID); stored.Status != checkoutdomain.OrderStatusPendingPayment {
+		t.Errorf("expected order untouched while claimed, got: %v", stored.Status)
+	}
+
+	// Si esa conciliación muere, el claim vence y se retoma
+	f.now = f.now.Add(PaymentIntentClaimTimeout + time.Second)
+	output, err = f.sync.Execute(context.Background(), SyncPaymentIntentInput{IntentID: intent.ID})
+	if err != nil || output.Intent.Status != checkoutdomain.PaymentIntentStatusSucceeded {
+		t.Fatalf("expected stale claim retaken, got: %+v %v", output.Intent, err)
+	}
+	if stored, _ := f.orders.GetByID(context.Background(), order.ID); stored.Status != checkoutdomain.OrderStatusPaid {
+		t.Errorf("expected order paid, got: %v", stored.Status)
+	}
+}
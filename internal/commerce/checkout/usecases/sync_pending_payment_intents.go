@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	"paku-commerce/internal/platform/lock"
+)
+
+// SyncPendingPaymentIntentsLockKey es la clave del lock que serializa el polling entre réplicas.
+const SyncPendingPaymentIntentsLockKey = "payment-intent-poll"
+
+// SyncPendingPaymentIntentsInput contiene el timestamp de referencia.
+type SyncPendingPaymentIntentsInput struct {
+	Now time.Time
+}
+
+// SyncPendingPaymentIntentsOutput contiene estadísticas del barrido.
+type SyncPendingPaymentIntentsOutput struct {
+	Succeeded int // la transferencia llegó
+	Failed    int // el proveedor rechazó la transferencia
+	Expired   int // el QR venció sin pago
+	Cancelled int // el QR se anuló en el proveedor
+	Pending   int // sigue esperando (o falló la consulta; se reintenta en el próximo)
+	// Skipped indica que otra réplica tenía el lock y no se procesó nada.
+	Skipped bool
+}
+
+// SyncPendingPaymentIntents consulta al proveedor todos los intentos de pago QR pending
+// (polling), por si su webhook no llegó o no está configurado.
+type SyncPendingPaymentIntents struct {
+	Intents checkoutdomain.PaymentIntentRepository
+	SyncUC  *SyncPaymentIntent
+	Locker  lock.Locker // opcional: evita que dos réplicas consulten los mismos intentos
+}
+
+// Execute concilia los intentos pendientes. Los errores por intento no cortan el barrido.
+func (uc SyncPendingPaymentIntents) Execute(ctx context.Context, input SyncPendingPaymentIntentsInput) (SyncPendingPaymentIntentsOutput, error) {
+	if uc.Locker != nil {
+		unlock, acquired, err := uc.Locker.TryLock(ctx, SyncPendingPaymentIntentsLockKey)
+		if err != nil {
+			return SyncPendingPaymentIntentsOutput{}, err
+		}
+		if !acquired {
+			return SyncPendingPaymentIntentsOutput{Skipped: true}, nil
+		}
+		defer unlock()
+	}
+
+	pending, err := uc.Intents.ListPending(ctx)
+	if err != nil {
+		return SyncPendingPaymentIntentsOutput{}, err
+	}
+
+	syncUC := *uc.SyncUC
+	syncUC.Now = func() time.Time { return input.Now }
+
+	var output SyncPendingPaymentIntentsOutput
+	for _, intent := range pending {
+		synced, err := syncUC.Execute(ctx, SyncPaymentIntentInput{IntentID: intent.ID})
+		if err != nil {
+			output.Pending++
+			continue
+		}
+
+		switch synced.Intent.Status {
+		case checkoutdomain.PaymentIntentStatusSucceeded:
+			output.Succeeded++
+		case checkoutdomain.PaymentIntentStatusFailed:
+			output.Failed++
+		case checkoutdomain.PaymentIntentStatusExpired:
+			output.Expired++
+		case checkoutdomain.PaymentIntentStatusCancelled:
+			output.Cancelled++
+		default:
+			output.Pending++
+		}
+	}
+
+	return output, nil
+}
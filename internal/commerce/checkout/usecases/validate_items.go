@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+	pricingusecases "paku-commerce/internal/pricing/usecases"
+)
+
+var (
+	ErrUnknownProduct  = errors.New("product not found")
+	ErrUnknownItemType = errors.New("item type must be service or product")
+	ErrUnknownPet      = errors.New("item references unknown pet")
+)
+
+// ValidateItemsInput contiene los items a validar y las mascotas para las que se compran.
+// Los items sin PetID son de PetProfile; el resto, de la mascota en Pets con ese ID.
+type ValidateItemsInput struct {
+	PetProfile servicedomain.PetProfile
+	Pets       []checkoutdomain.Pet
+	Items      []checkoutdomain.PurchaseItem
+}
+
+// ValidateItemsOutput contiene todas las violaciones encontradas (vacío = válido).
+type ValidateItemsOutput struct {
+	Violations []checkoutdomain.ItemViolation
+}
+
+// ValidateItems aplica las reglas de catálogo a una lista de items.
+// Lo usan QuoteCheckout y las mutaciones del carrito.
+type ValidateItems struct {
+	ServiceRepo servicedomain.ServiceRepository
+	// PriceRuleRepo es opcional: verifica que cada servicio tenga precio para el pet y,
+	// mientras no exista catálogo de productos, que el producto tenga regla de precio.
+	// nil = no se validan precios ni productos.
+	PriceRuleRepo pricingdomain.PriceRuleRepository
+}
+
+// Execute retorna una violación por item inválido. El error solo se usa para fallas de infraestructura.
+func (uc ValidateItems) Execute(ctx context.Context, input ValidateItemsInput) (ValidateItemsOutput, error) {
+	violations := make([]checkoutdomain.ItemViolation, 0)
+	reject := func(i int, reason error) {
+		violations = append(violations, checkoutdomain.ItemViolation{Index: i, Item: input.Items[i], Reason: reason})
+	}
+
+	intent := checkoutdomain.PurchaseIntent{PetProfile: input.PetProfile, Pets: input.Pets}
+
+	// Servicios presentes por mascota (un addon requiere su base en la misma mascota)
+	serviceItems := make(map[string]map[string]bool)
+	for _, item := range input.Items {
+		if item.ItemType == checkoutdomain.ItemTypeService {
+			if serviceItems[item.PetID] == nil {
+				serviceItems[item.PetID] = make(map[string]bool)
+			}
+			serviceItems[item.PetID][item.ItemID] = true
+		}
+	}
+
+	for i, item := range input.Items {
+		if item.Qty <= 0 {
+			reject(i, ErrInvalidQuantity)
+			continue
+		}
+
+		pet, ok := intent.PetProfileFor(item.PetID)
+		if !ok {
+			reject(i, ErrUnknownPet)
+			continue
+		}
+
+		switch item.ItemType {
+		case checkoutdomain.ItemTypeService:
+			service, err := uc.ServiceRepo.GetServiceByID(ctx, item.ItemID)
+			if errors.Is(err, servicedomain.ErrServiceNotFound) {
+				reject(i, ErrUnknownService)
+				continue
+			}
+			if err != nil {
+				return ValidateItemsOutput{}, fmt.Errorf("failed to get service %s: %w", item.ItemID, err)
+			}
+
+			// Validar elegibilidad
+			if !service.IsEligibleFor(pet) {
+				reject(i, ErrServiceNotEligible)
+				continue
+			}
+
+			// Validar dependencias addon/parent
+			if service.IsAddon && service.RequiresParent() && !hasAnyParent(service, serviceItems[item.PetID]) {
+				reject(i, ErrMissingParentService)
+				continue
+			}
+
+			// Validar que exista precio para este pet
+			if uc.PriceRuleRepo != nil {
+				priced, err := uc.hasServicePrice(ctx, item.ItemID, pet)
+				if err != nil {
+					return ValidateItemsOutput{}, err
+				}
+				if !priced {
+					reject(i, pricingusecases.ErrNoPriceRule)
+				}
+			}
+
+		case checkoutdomain.ItemTypeProduct:
+			if uc.PriceRuleRepo == nil {
+				continue
+			}
+			rules, err := uc.PriceRuleRepo.ListRulesForItem(ctx, pricingdomain.ItemTypeProduct, item.ItemID)
+			if err != nil {
+				return ValidateItemsOutput{}, fmt.Errorf("failed to get product %s: %w", item.ItemID, err)
+			}
+			if len(rules) == 0 {
+				reject(i, ErrUnknownProduct)
+			}
+
+		default:
+			reject(i, ErrUnknownItemType)
+		}
+	}
+
+	return ValidateItemsOutput{Violations: violations}, nil
+}
+
+func (uc ValidateItems) hasServicePrice(ctx context.Context, serviceID string, pet servicedomain.PetProfile) (bool, error) {
+	rules, err := uc.PriceRuleRepo.ListRulesForItem(ctx, pricingdomain.ItemTypeService, serviceID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get price rules for %s: %w", serviceID, err)
+	}
+	for _, rule := range rules {
+		if rule.MatchesService(serviceID, pet) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func hasAnyParent(service servicedomain.Service, serviceItems map[string]bool) bool {
+	for _, parentID := range service.RequiresParentIDs {
+		if serviceItems[parentID] {
+			return true
+		}
+	}
+	return false
+}
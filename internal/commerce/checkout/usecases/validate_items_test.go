@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	servicememory "paku-commerce/internal/commerce/service/adapters/memory"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	pricingmemory "paku-commerce/internal/pricing/adapters/memory"
+	pricingusecases "paku-commerce/internal/pricing/usecases"
+)
+
+func TestValidateItems_ReportsEveryInvalidItem(t *testing.T) {
+	uc := &ValidateItems{
+		ServiceRepo:   servicememory.NewServiceRepository(),
+		PriceRuleRepo: pricingmemory.NewPriceRuleRepository(),
+	}
+
+	input := ValidateItemsInput{
+		PetProfile: servicedomain.PetProfile{
+			Species:  servicedomain.SpeciesDog,
+			WeightKg: 15,
+			CoatType: servicedomain.CoatTypeDouble,
+		},
+		Items: []checkoutdomain.PurchaseItem{
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "foo", Qty: 1},
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "deshedding", Qty: 1}, // sin bath
+			{ItemType: checkoutdomain.ItemTypeProduct, ItemID: "shampoo_basic", Qty: 1},
+			{ItemType: checkoutdomain.ItemTypeProduct, ItemID: "unknown_product", Qty: 1},
+			{ItemType: checkoutdomain.ItemTypeProduct, ItemID: "shampoo_basic", Qty: 0},
+			{ItemType: "gift_card", ItemID: "gc_50", Qty: 1},
+		},
+	}
+
+	output, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[int]error{
+		0: ErrUnknownService,
+		1: ErrMissingParentService,
+		3: ErrUnknownProduct,
+		4: ErrInvalidQuantity,
+		5: ErrUnknownItemType,
+	}
+	if len(output.Violations) != len(expected) {
+		t.Fatalf("expected %d violations, got: %+v", len(expected), output.Violations)
+	}
+	for _, v := range output.Violations {
+		if expected[v.Index] != v.Reason {
+			t.Errorf("item %d: expected %v, got: %v", v.Index, expected[v.Index], v.Reason)
+		}
+	}
+}
+
+func TestValidateItems_ServiceWithoutPriceForPet(t *testing.T) {
+	uc := &ValidateItems{
+		ServiceRepo:   servicememory.NewServiceRepository(),
+		PriceRuleRepo: pricingmemory.NewPriceRuleRepository(),
+	}
+
+	// bath tiene precio solo hasta 40kg
+	input := ValidateItemsInput{
+		PetProfile: servicedomain.PetProfile{
+			Species:  servicedomain.SpeciesDog,
+			WeightKg: 55,
+			CoatType: servicedomain.CoatTypeShort,
+		},
+		Items: []checkoutdomain.PurchaseItem{
+			{ItemType: checkoutdomain.ItemTypeService, ItemID: "bath", Qty: 1},
+		},
+	}
+
+	output, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Violations) != 1 || output.Violations[0].Reason != pricingusecases.ErrNoPriceRule {
+		t.Fatalf("expected ErrNoPriceRule, got: %+v", output.Violations)
+	}
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	"paku-commerce/internal/platform/audit"
+)
+
+// DefaultHoldRetryInterval es la frecuencia por defecto del barrido de reintentos de ConfirmHold.
+const DefaultHoldRetryInterval = time.Minute
+
+// HoldRetryWorker ejecuta RetryHoldConfirmations periódicamente dentro del proceso.
+// El lock del usecase evita que varias réplicas reintenten las mismas órdenes.
+type HoldRetryWorker struct {
+	RetryHoldConfirmationsUC *checkoutusecases.RetryHoldConfirmations
+	Interval                 time.Duration // 0 = DefaultHoldRetryInterval
+	Now                      func() time.Time
+	Logger                   *log.Logger // opcional: log.Default()
+}
+
+// Run reintenta holds vencidos en cada tick hasta que ctx se cancele.
+func (w *HoldRetryWorker) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultHoldRetryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce ejecuta un barrido y registra cómo se resolvió cada orden.
+func (w *HoldRetryWorker) RunOnce(ctx context.Context) (checkoutusecases.RetryHoldConfirmationsOutput, error) {
+	now := time.Now()
+	if w.Now != nil {
+		now = w.Now()
+	}
+
+	// Reintentos, reembolsos y envíos a revisión quedan en el historial con el worker como actor
+	ctx = audit.WithActor(ctx, audit.ActorHoldRetry)
+	output, err := w.RetryHoldConfirmationsUC.Execute(ctx, checkoutusecases.RetryHoldConfirmationsInput{Now: now})
+	switch {
+	case err != nil:
+		w.logger().Printf("hold retry: %v", err)
+	case output.Confirmed+output.Rescheduled+output.Refunded+output.SentToReview > 0:
+		w.logger().Printf("hold retry: confirmed=%d rescheduled=%d refunded=%d sent_to_review=%d",
+			output.Confirmed, output.Rescheduled, output.Refunded, output.SentToReview)
+	}
+	return output, err
+}
+
+func (w *HoldRetryWorker) logger() *log.Logger {
+	if w.Logger != nil {
+		return w.Logger
+	}
+	return log.Default()
+}
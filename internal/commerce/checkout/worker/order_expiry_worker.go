@@ -0,0 +1,69 @@
+// Package worker contiene las tareas de fondo del módulo checkout.
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	"paku-commerce/internal/platform/audit"
+)
+
+// DefaultOrderExpiryInterval es la frecuencia por defecto del barrido de órdenes vencidas.
+const DefaultOrderExpiryInterval = time.Minute
+
+// OrderExpiryWorker ejecuta ExpirePendingOrders periódicamente dentro del proceso.
+// El lock del usecase evita que varias réplicas procesen las mismas órdenes.
+type OrderExpiryWorker struct {
+	ExpirePendingOrdersUC *checkoutusecases.ExpirePendingOrders
+	Interval              time.Duration // 0 = DefaultOrderExpiryInterval
+	Now                   func() time.Time
+	Logger                *log.Logger // opcional: log.Default()
+}
+
+// Run barre órdenes vencidas en cada tick hasta que ctx se cancele.
+func (w *OrderExpiryWorker) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultOrderExpiryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce ejecuta un barrido y registra cuántas órdenes expiró.
+func (w *OrderExpiryWorker) RunOnce(ctx context.Context) (checkoutusecases.ExpirePendingOrdersOutput, error) {
+	now := time.Now()
+	if w.Now != nil {
+		now = w.Now()
+	}
+
+	// Las cancelaciones quedan en el historial con el worker como actor
+	ctx = audit.WithActor(ctx, audit.ActorOrderExpiry)
+	output, err := w.ExpirePendingOrdersUC.Execute(ctx, checkoutusecases.ExpirePendingOrdersInput{Now: now})
+	switch {
+	case err != nil:
+		w.logger().Printf("order expiry: %v", err)
+	case output.ExpiredCount > 0:
+		w.logger().Printf("order expiry: expired=%d", output.ExpiredCount)
+	}
+	return output, err
+}
+
+func (w *OrderExpiryWorker) logger() *log.Logger {
+	if w.Logger != nil {
+		return w.Logger
+	}
+	return log.Default()
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	checkoutusecases "paku-commerce/internal/commerce/checkout/usecases"
+	"paku-commerce/internal/platform/audit"
+)
+
+// DefaultPaymentIntentPollInterval es la frecuencia por defecto del polling de pagos QR.
+const DefaultPaymentIntentPollInterval = 15 * time.Second
+
+// PaymentIntentWorker ejecuta SyncPendingPaymentIntents periódicamente dentro del proceso.
+// El lock del usecase evita que varias réplicas consulten los mismos cobros.
+type PaymentIntentWorker struct {
+	SyncPendingPaymentIntentsUC *checkoutusecases.SyncPendingPaymentIntents
+	Interval                    time.Duration // 0 = DefaultPaymentIntentPollInterval
+	Now                         func() time.Time
+	Logger                      *log.Logger // opcional: log.Default()
+}
+
+// Run consulta los pagos QR pendientes en cada tick hasta que ctx se cancele.
+func (w *PaymentIntentWorker) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultPaymentIntentPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce ejecuta un barrido y registra cómo se resolvió cada intento.
+func (w *PaymentIntentWorker) RunOnce(ctx context.Context) (checkoutusecases.SyncPendingPaymentIntentsOutput, error) {
+	now := time.Now()
+	if w.Now != nil {
+		now = w.Now()
+	}
+
+	// Pagos y rechazos quedan en el historial de la orden con el worker como actor
+	ctx = audit.WithActor(ctx, audit.ActorPaymentIntentPoll)
+	output, err := w.SyncPendingPaymentIntentsUC.Execute(ctx, checkoutusecases.SyncPendingPaymentIntentsInput{Now: now})
+	switch {
+	case err != nil:
+		w.logger().Printf("payment intent poll: %v", err)
+	case output.Succeeded+output.Failed+output.Expired+output.Cancelled > 0:
+		w.logger().Printf("payment intent poll: succeeded=%d failed=%d expired=%d cancelled=%d pending=%d",
+			output.Succeeded, output.Failed, output.Expired, output.Cancelled, output.Pending)
+	}
+	return output, err
+}
+
+func (w *PaymentIntentWorker) logger() *log.Logger {
+	if w.Logger != nil {
+		return w.Logger
+	}
+	return log.Default()
+}
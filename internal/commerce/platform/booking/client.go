@@ -1,6 +1,9 @@
 package booking
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Client define la integración con el servicio de booking.
 // Este interface unifica las operaciones de booking usadas por cart y checkout.
@@ -17,3 +20,14 @@ type Client interface {
 	// CancelHold cancela un hold de booking.
 	CancelHold(ctx context.Context, holdID string) error
 }
+
+// Slot es un turno de booking (la cita de la orden).
+type Slot struct {
+	ID       string
+	StartsAt time.Time
+}
+
+// SlotReader consulta slots de booking (hora de inicio para la política de cancelación).
+type SlotReader interface {
+	GetSlot(ctx context.Context, slotID string) (Slot, error)
+}
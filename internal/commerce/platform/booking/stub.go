@@ -4,8 +4,12 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"time"
 )
 
+// StubSlotLeadTime es cuánto falta para la cita de cualquier slot del stub.
+const StubSlotLeadTime = 72 * time.Hour
+
 // StubClient es un stub no-op de Client para desarrollo.
 type StubClient struct{}
 
@@ -30,3 +34,8 @@ func (s *StubClient) ConfirmHold(ctx context.Context, holdID string) error {
 func (s *StubClient) CancelHold(ctx context.Context, holdID string) error {
 	return nil
 }
+
+// GetSlot retorna un slot que empieza StubSlotLeadTime después de ahora (stub).
+func (s *StubClient) GetSlot(ctx context.Context, slotID string) (Slot, error) {
+	return Slot{ID: slotID, StartsAt: time.Now().Add(StubSlotLeadTime)}, nil
+}
@@ -0,0 +1,23 @@
+package pets
+
+import (
+	"context"
+	"errors"
+
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+)
+
+var (
+	// ErrPetNotFound indica que la mascota no existe en el servicio de mascotas.
+	ErrPetNotFound = errors.New("pet not found")
+
+	// ErrPetsUnavailable indica que el servicio de mascotas está caído o no respondió a tiempo.
+	ErrPetsUnavailable = errors.New("pets service unavailable")
+)
+
+// Client define la integración con el servicio de mascotas.
+// Este interface unifica la resolución de perfiles usada por cart y checkout.
+type Client interface {
+	// GetPetProfile obtiene el perfil de una mascota por ID.
+	GetPetProfile(ctx context.Context, petID string) (servicedomain.PetProfile, error)
+}
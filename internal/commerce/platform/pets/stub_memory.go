@@ -0,0 +1,31 @@
+package pets
+
+import (
+	"context"
+
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+)
+
+// StubClient es un Client en memoria para desarrollo y tests.
+type StubClient struct {
+	Profiles map[string]servicedomain.PetProfile
+}
+
+// NewStubClient crea un stub con mascotas de ejemplo.
+func NewStubClient() *StubClient {
+	return &StubClient{
+		Profiles: map[string]servicedomain.PetProfile{
+			"pet_demo_dog": {Species: servicedomain.SpeciesDog, WeightKg: 15, CoatType: servicedomain.CoatTypeDouble},
+			"pet_demo_cat": {Species: servicedomain.SpeciesCat, WeightKg: 4, CoatType: servicedomain.CoatTypeShort},
+		},
+	}
+}
+
+// GetPetProfile retorna el perfil registrado o ErrPetNotFound.
+func (s *StubClient) GetPetProfile(ctx context.Context, petID string) (servicedomain.PetProfile, error) {
+	profile, ok := s.Profiles[petID]
+	if !ok {
+		return servicedomain.PetProfile{}, ErrPetNotFound
+	}
+	return profile, nil
+}
@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/adapters/culqihttp"
+	"paku-commerce/internal/commerce/checkout/adapters/paymentwebhook"
+	"paku-commerce/internal/commerce/checkout/adapters/qrsimulator"
+	"paku-commerce/internal/commerce/checkout/ports/payments"
+)
+
+// PaymentsClientSingleton verifica cobros y emite reembolsos (stub que acepta todo por defecto).
+var PaymentsClientSingleton payments.PaymentsClient = &payments.StubPaymentsClient{}
+
+// QRPaymentsSingleton genera y consulta los cobros QR de Yape/Plin. Es nil mientras no se
+// configure un proveedor QR (ver InitPayments y QR_PROVIDER): sin él no hay pagos QR.
+var QRPaymentsSingleton payments.QRPaymentsClient
+
+// QRProviderSimulator es el simulador QR en memoria; solo para desarrollo y pruebas.
+const QRProviderSimulator = "simulator"
+
+// PaymentsConfig contiene la configuración de los proveedores de pago (Culqi y QR).
+type PaymentsConfig struct {
+	CulqiBaseURL   string
+	CulqiSecretKey string
+	Timeout        time.Duration
+	// QRProvider elige el proveedor de pagos QR ("" = sin pagos QR).
+	QRProvider string
+	// QRSimulatorAutoComplete paga solos los QR del simulador tras este tiempo (0 = nunca).
+	QRSimulatorAutoComplete time.Duration
+}
+
+// PaymentsConfigFromEnv lee CULQI_SECRET_KEY, CULQI_BASE_URL (default la API pública de Culqi)
+// y CULQI_TIMEOUT (duración Go, ej. "10s"). Sin CULQI_SECRET_KEY se mantiene el stub.
+// QR_PROVIDER habilita los pagos QR: por ahora solo "simulator" (opt-in explícito) y
+// QR_SIMULATOR_AUTO_COMPLETE (default "0" = nunca) paga solos sus QR tras ese tiempo.
+func PaymentsConfigFromEnv() PaymentsConfig {
+	timeout, _ := time.ParseDuration(os.Getenv("CULQI_TIMEOUT"))
+	return PaymentsConfig{
+		CulqiBaseURL:   os.Getenv("CULQI_BASE_URL"),
+		CulqiSecretKey: os.Getenv("CULQI_SECRET_KEY"),
+		Timeout:        timeout,
+
+		QRProvider:              strings.TrimSpace(os.Getenv("QR_PROVIDER")),
+		QRSimulatorAutoComplete: intervalFromEnv("QR_SIMULATOR_AUTO_COMPLETE", 0),
+	}
+}
+
+// InitPayments configura PaymentsClientSingleton con Culqi si hay llave secreta y
+// QRPaymentsSingleton según QR_PROVIDER. Debe llamarse antes de construir el router y los workers.
+func InitPayments(cfg PaymentsConfig) error {
+	switch cfg.QRProvider {
+	case "":
+		QRPaymentsSingleton = nil
+	case QRProviderSimulator:
+		simulator := qrsimulator.NewSimulator()
+		simulator.AutoCompleteAfter = cfg.QRSimulatorAutoComplete
+		QRPaymentsSingleton = simulator
+	default:
+		return fmt.Errorf("unknown QR_PROVIDER %q", cfg.QRProvider)
+	}
+
+	if cfg.CulqiSecretKey == "" {
+		return nil
+	}
+
+	client, err := culqihttp.NewClient(culqihttp.Config{
+		BaseURL:   cfg.CulqiBaseURL,
+		SecretKey: cfg.CulqiSecretKey,
+		Timeout:   cfg.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+	PaymentsClientSingleton = client
+	return nil
+}
+
+// PaymentWebhookVerifiersFromEnv lee PAYMENT_WEBHOOK_SECRETS ("proveedor=secreto,...") y
+// PAYMENT_WEBHOOK_TOLERANCE (duración Go, antigüedad máxima de la firma). Los proveedores
+// sin secreto no tienen webhook: sus entregas responden 404.
+func PaymentWebhookVerifiersFromEnv() map[string]payments.WebhookVerifier {
+	tolerance := intervalFromEnv("PAYMENT_WEBHOOK_TOLERANCE", paymentwebhook.DefaultTolerance)
+
+	verifiers := make(map[string]payments.WebhookVerifier)
+	for _, entry := range strings.Split(os.Getenv("PAYMENT_WEBHOOK_SECRETS"), ",") {
+		provider, secret, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || provider == "" || secret == "" {
+			continue
+		}
+		verifiers[provider] = paymentwebhook.Verifier{
+			Provider:  provider,
+			Secret:    secret,
+			Tolerance: tolerance,
+		}
+	}
+	return verifiers
+}
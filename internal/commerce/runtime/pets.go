@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"os"
+	"time"
+
+	"paku-commerce/internal/commerce/checkout/adapters/petshttp"
+	platformpets "paku-commerce/internal/commerce/platform/pets"
+)
+
+// PetsClientSingleton resuelve perfiles de mascota por ID (stub en memoria por defecto).
+var PetsClientSingleton platformpets.Client = platformpets.NewStubClient()
+
+// PetsConfig contiene la configuración del servicio de mascotas.
+type PetsConfig struct {
+	BaseURL string
+	APIKey  string
+	Timeout time.Duration
+}
+
+// PetsConfigFromEnv lee PETS_BASE_URL, PETS_API_KEY y PETS_TIMEOUT (duración Go, ej. "3s").
+// Sin PETS_BASE_URL se mantiene el stub para desarrollo local.
+func PetsConfigFromEnv() PetsConfig {
+	timeout, _ := time.ParseDuration(os.Getenv("PETS_TIMEOUT"))
+	return PetsConfig{
+		BaseURL: os.Getenv("PETS_BASE_URL"),
+		APIKey:  os.Getenv("PETS_API_KEY"),
+		Timeout: timeout,
+	}
+}
+
+// InitPets configura PetsClientSingleton con el cliente HTTP si hay BaseURL.
+// Debe llamarse antes de construir el router.
+func InitPets(cfg PetsConfig) error {
+	if cfg.BaseURL == "" {
+		return nil
+	}
+
+	client, err := petshttp.NewClient(petshttp.Config{
+		BaseURL: cfg.BaseURL,
+		APIKey:  cfg.APIKey,
+		Timeout: cfg.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+	PetsClientSingleton = client
+	return nil
+}
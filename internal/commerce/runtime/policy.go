@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"os"
+	"strconv"
+
+	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+)
+
+// CancellationPolicyFromEnv lee CANCEL_FREE_WINDOW (duración Go, ej. "24h") y
+// CANCEL_LATE_FEE_PERCENT (0-100). Valores ausentes o inválidos usan la política por defecto.
+func CancellationPolicyFromEnv() checkoutdomain.CancellationPolicy {
+	policy := checkoutdomain.DefaultCancellationPolicy()
+	policy.FreeWindow = intervalFromEnv("CANCEL_FREE_WINDOW", policy.FreeWindow)
+
+	if raw := os.Getenv("CANCEL_LATE_FEE_PERCENT"); raw != "" {
+		if percent, err := strconv.ParseInt(raw, 10, 64); err == nil && percent >= 0 && percent <= 100 {
+			policy.LateFeePercent = percent
+		}
+	}
+
+	return policy
+}
+
+// HoldRetryPolicyFromEnv lee HOLD_RETRY_MAX_ATTEMPTS, HOLD_RETRY_BASE_DELAY y HOLD_RETRY_MAX_DELAY
+// (duración Go). Valores ausentes o inválidos usan la política por defecto.
+func HoldRetryPolicyFromEnv() checkoutdomain.HoldRetryPolicy {
+	policy := checkoutdomain.DefaultHoldRetryPolicy()
+	policy.BaseDelay = intervalFromEnv("HOLD_RETRY_BASE_DELAY", policy.BaseDelay)
+	policy.MaxDelay = intervalFromEnv("HOLD_RETRY_MAX_DELAY", policy.MaxDelay)
+
+	if raw := os.Getenv("HOLD_RETRY_MAX_ATTEMPTS"); raw != "" {
+		if attempts, err := strconv.Atoi(raw); err == nil && attempts > 0 {
+			policy.MaxAttempts = attempts
+		}
+	}
+
+	return policy
+}
+
+// HoldFailureAutoRefundFromEnv lee HOLD_FAILURE_AUTO_REFUND: "true" reembolsa las órdenes
+// que agotan los reintentos de ConfirmHold; por defecto quedan en revisión manual.
+func HoldFailureAutoRefundFromEnv() bool {
+	autoRefund, _ := strconv.ParseBool(os.Getenv("HOLD_FAILURE_AUTO_REFUND"))
+	return autoRefund
+}
@@ -5,10 +5,37 @@ import (
 	cartdomain "paku-commerce/internal/commerce/cart/domain"
 	checkoutmemory "paku-commerce/internal/commerce/checkout/adapters/memory"
 	checkoutdomain "paku-commerce/internal/commerce/checkout/domain"
+	servicememory "paku-commerce/internal/commerce/service/adapters/memory"
+	servicedomain "paku-commerce/internal/commerce/service/domain"
+	"paku-commerce/internal/platform/lock"
+	"paku-commerce/internal/platform/transaction"
+	pricingmemory "paku-commerce/internal/pricing/adapters/memory"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+	promotionsmemory "paku-commerce/internal/promotions/adapters/memory"
+	promotionsdomain "paku-commerce/internal/promotions/domain"
 )
 
 // Singletons de repositorios para compartir estado entre módulos.
 var (
 	CartRepoSingleton  cartdomain.CartRepository      = cartmemory.NewCartRepository()
 	OrderRepoSingleton checkoutdomain.OrderRepository = checkoutmemory.NewOrderRepository()
+	// IncidentRepoSingleton es la cola de revisión manual de checkout.
+	IncidentRepoSingleton checkoutdomain.IncidentRepository = checkoutmemory.NewIncidentRepository()
+	// PaymentEventInboxSingleton deduplica los webhooks del proveedor de pagos.
+	PaymentEventInboxSingleton checkoutdomain.PaymentEventInbox = checkoutmemory.NewPaymentEventInbox()
+	// PaymentIntentRepoSingleton guarda los intentos de pago QR (Yape/Plin).
+	PaymentIntentRepoSingleton checkoutdomain.PaymentIntentRepository = checkoutmemory.NewPaymentIntentRepository()
+)
+
+// TxManagerSingleton coordina transacciones sobre los repos activos (memory o postgres).
+var TxManagerSingleton transaction.Manager = transaction.NewMemoryManager()
+
+// LockerSingleton coordina tareas de fondo entre réplicas (memory o advisory locks de postgres).
+var LockerSingleton lock.Locker = lock.NewMemoryLocker()
+
+// Singletons del catálogo (servicios, precios, promociones).
+var (
+	ServiceRepoSingleton    servicedomain.ServiceRepository       = servicememory.NewServiceRepository()
+	PriceRuleRepoSingleton  pricingdomain.PriceRuleRepository     = pricingmemory.NewPriceRuleRepository()
+	PromotionsRepoSingleton promotionsdomain.PromotionsRepository = promotionsmemory.NewPromotionsRepository()
 )
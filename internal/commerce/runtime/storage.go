@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	cartpostgres "paku-commerce/internal/commerce/cart/adapters/postgres"
+	checkoutpostgres "paku-commerce/internal/commerce/checkout/adapters/postgres"
+	servicepostgres "paku-commerce/internal/commerce/service/adapters/postgres"
+	dbpostgres "paku-commerce/internal/db/postgres"
+	pricingpostgres "paku-commerce/internal/pricing/adapters/postgres"
+	promotionspostgres "paku-commerce/internal/promotions/adapters/postgres"
+)
+
+// StorageDriver selecciona la implementación de los repositorios compartidos.
+type StorageDriver string
+
+const (
+	StorageMemory   StorageDriver = "memory"
+	StoragePostgres StorageDriver = "postgres"
+)
+
+// StorageConfig contiene la configuración de persistencia.
+type StorageConfig struct {
+	Driver      StorageDriver
+	DatabaseURL string
+}
+
+// StorageConfigFromEnv lee STORAGE_DRIVER (memory|postgres) y DATABASE_URL.
+// Por defecto usa memory para mantener el desarrollo local sin dependencias.
+func StorageConfigFromEnv() StorageConfig {
+	driver := StorageDriver(os.Getenv("STORAGE_DRIVER"))
+	if driver == "" {
+		driver = StorageMemory
+	}
+	return StorageConfig{
+		Driver:      driver,
+		DatabaseURL: os.Getenv("DATABASE_URL"),
+	}
+}
+
+// DBPool es el pool de PostgreSQL activo (nil con driver memory).
+var DBPool *pgxpool.Pool
+
+// InitStorage configura los singletons según el driver.
+// Debe llamarse antes de construir el router. Retorna una función para liberar recursos.
+func InitStorage(ctx context.Context, cfg StorageConfig) (func(), error) {
+	switch cfg.Driver {
+	case StorageMemory:
+		return func() {}, nil
+
+	case StoragePostgres:
+		pool, err := dbpostgres.NewPool(ctx, dbpostgres.Config{DatabaseURL: cfg.DatabaseURL})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := dbpostgres.Migrate(ctx, pool); err != nil {
+			pool.Close()
+			return nil, err
+		}
+
+		DBPool = pool
+		CartRepoSingleton = cartpostgres.NewCartRepository(pool)
+		OrderRepoSingleton = checkoutpostgres.NewOrderRepository(pool)
+		IncidentRepoSingleton = checkoutpostgres.NewIncidentRepository(pool)
+		PaymentEventInboxSingleton = checkoutpostgres.NewPaymentEventInbox(pool)
+		PaymentIntentRepoSingleton = checkoutpostgres.NewPaymentIntentRepository(pool)
+		ServiceRepoSingleton = servicepostgres.NewServiceRepository(pool)
+		PriceRuleRepoSingleton = pricingpostgres.NewPriceRuleRepository(pool)
+		PromotionsRepoSingleton = promotionspostgres.NewPromotionsRepository(pool)
+		TxManagerSingleton = dbpostgres.NewTxManager(pool)
+		LockerSingleton = dbpostgres.NewAdvisoryLocker(pool)
+
+		return pool.Close, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
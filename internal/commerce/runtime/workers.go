@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"os"
+	"time"
+)
+
+// WorkersConfig contiene la configuración de las tareas de fondo y del endpoint manual.
+type WorkersConfig struct {
+	// CartExpiryInterval es la frecuencia del barrido de carritos vencidos (0 = deshabilitado).
+	CartExpiryInterval time.Duration
+	// OrderExpiryInterval es la frecuencia del barrido de órdenes pending_payment vencidas (0 = deshabilitado).
+	OrderExpiryInterval time.Duration
+	// HoldRetryInterval es la frecuencia de los reintentos de ConfirmHold tras un cobro (0 = deshabilitado).
+	HoldRetryInterval time.Duration
+	// PaymentIntentPollInterval es la frecuencia del polling de pagos QR pendientes (0 = deshabilitado).
+	PaymentIntentPollInterval time.Duration
+	// AdminToken habilita POST /cart/expire con X-Admin-Token ("" = endpoint deshabilitado).
+	AdminToken string
+}
+
+// WorkersConfigFromEnv lee CART_EXPIRY_INTERVAL, ORDER_EXPIRY_INTERVAL, HOLD_RETRY_INTERVAL y
+// PAYMENT_INTENT_POLL_INTERVAL (duración Go, ej. "30s"; "0" deshabilita) y ADMIN_TOKEN.
+// Por defecto los barridos corren cada minuto y el polling de pagos QR cada 15 segundos.
+func WorkersConfigFromEnv() WorkersConfig {
+	return WorkersConfig{
+		CartExpiryInterval:        intervalFromEnv("CART_EXPIRY_INTERVAL", time.Minute),
+		OrderExpiryInterval:       intervalFromEnv("ORDER_EXPIRY_INTERVAL", time.Minute),
+		HoldRetryInterval:         intervalFromEnv("HOLD_RETRY_INTERVAL", time.Minute),
+		PaymentIntentPollInterval: intervalFromEnv("PAYMENT_INTENT_POLL_INTERVAL", 15*time.Second),
+		AdminToken:                os.Getenv("ADMIN_TOKEN"),
+	}
+}
+
+// intervalFromEnv parsea una duración; si falta o es inválida usa fallback.
+func intervalFromEnv(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil || parsed < 0 {
+		return fallback
+	}
+	return parsed
+}
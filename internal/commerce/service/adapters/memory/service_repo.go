@@ -2,12 +2,12 @@ package memory
 
 import (
 	"context"
-	"errors"
 
 	"paku-commerce/internal/commerce/service/domain"
 )
 
-var ErrServiceNotFound = errors.New("service not found")
+// ErrServiceNotFound se mantiene como alias del error de dominio.
+var ErrServiceNotFound = domain.ErrServiceNotFound
 
 // ServiceRepository implementa domain.ServiceRepository en memoria.
 type ServiceRepository struct {
@@ -1 +1,142 @@
 package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"paku-commerce/internal/commerce/service/domain"
+	dbpostgres "paku-commerce/internal/db/postgres"
+)
+
+// ServiceRepository implementa domain.ServiceRepository sobre PostgreSQL.
+type ServiceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewServiceRepository crea un repositorio de servicios en PostgreSQL.
+func NewServiceRepository(pool *pgxpool.Pool) *ServiceRepository {
+	return &ServiceRepository{pool: pool}
+}
+
+// ListServices retorna todos los servicios con reglas y parents.
+func (r *ServiceRepository) ListServices(ctx context.Context) ([]domain.Service, error) {
+	return r.load(ctx, nil)
+}
+
+// GetServiceByID busca un servicio por ID.
+func (r *ServiceRepository) GetServiceByID(ctx context.Context, id string) (domain.Service, error) {
+	services, err := r.load(ctx, &id)
+	if err != nil {
+		return domain.Service{}, err
+	}
+	if len(services) == 0 {
+		return domain.Service{}, domain.ErrServiceNotFound
+	}
+	return services[0], nil
+}
+
+// load carga servicios (todos o uno por ID) y completa reglas y parents.
+func (r *ServiceRepository) load(ctx context.Context, serviceID *string) ([]domain.Service, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT id, name, is_addon
+		FROM services
+		WHERE $1::text IS NULL OR id = $1
+		ORDER BY id`, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	defer rows.Close()
+
+	var services []domain.Service
+	index := make(map[string]int)
+	for rows.Next() {
+		var svc domain.Service
+		if err := rows.Scan(&svc.ID, &svc.Name, &svc.IsAddon); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		index[svc.ID] = len(services)
+		services = append(services, svc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	if err := r.loadRules(ctx, serviceID, services, index); err != nil {
+		return nil, err
+	}
+	if err := r.loadParents(ctx, serviceID, services, index); err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+func (r *ServiceRepository) loadRules(ctx context.Context, serviceID *string, services []domain.Service, index map[string]int) error {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT service_id,
+		       allowed_species, excluded_species,
+		       min_weight_kg, max_weight_kg,
+		       allowed_coat_types, excluded_coat_types
+		FROM service_eligibility_rules
+		WHERE $1::text IS NULL OR service_id = $1
+		ORDER BY service_id, id`, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to list eligibility rules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id   string
+			rule domain.EligibilityRule
+		)
+		if err := rows.Scan(
+			&id,
+			&rule.AllowedSpecies, &rule.ExcludedSpecies,
+			&rule.MinWeightKg, &rule.MaxWeightKg,
+			&rule.AllowedCoatTypes, &rule.ExcludedCoatTypes,
+		); err != nil {
+			return fmt.Errorf("failed to scan eligibility rule: %w", err)
+		}
+		if i, ok := index[id]; ok {
+			services[i].EligibilityRules = append(services[i].EligibilityRules, rule)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list eligibility rules: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ServiceRepository) loadParents(ctx context.Context, serviceID *string, services []domain.Service, index map[string]int) error {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT service_id, parent_service_id
+		FROM service_addon_parents
+		WHERE $1::text IS NULL OR service_id = $1
+		ORDER BY service_id, position, parent_service_id`, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to list addon parents: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, parentID string
+		if err := rows.Scan(&id, &parentID); err != nil {
+			return fmt.Errorf("failed to scan addon parent: %w", err)
+		}
+		if i, ok := index[id]; ok {
+			services[i].RequiresParentIDs = append(services[i].RequiresParentIDs, parentID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list addon parents: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	servicememory "paku-commerce/internal/commerce/service/adapters/memory"
+	"paku-commerce/internal/commerce/service/domain"
+	"paku-commerce/internal/db/postgres/pgtest"
+)
+
+func TestServiceRepository_SeedMatchesMemoryCatalog(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewServiceRepository(pool)
+	ctx := context.Background()
+
+	memServices, _ := servicememory.NewServiceRepository().ListServices(ctx)
+
+	pets := []domain.PetProfile{
+		{Species: domain.SpeciesDog, WeightKg: 10, CoatType: domain.CoatTypeShort},
+		{Species: domain.SpeciesDog, WeightKg: 15, CoatType: domain.CoatTypeDouble},
+		{Species: domain.SpeciesDog, WeightKg: 5, CoatType: domain.CoatTypeHairless},
+		{Species: domain.SpeciesCat, WeightKg: 4, CoatType: domain.CoatTypeLong},
+	}
+
+	for _, want := range memServices {
+		got, err := repo.GetServiceByID(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", want.ID, err)
+		}
+
+		if got.Name != want.Name || got.IsAddon != want.IsAddon {
+			t.Errorf("%s: expected name=%s addon=%v, got name=%s addon=%v", want.ID, want.Name, want.IsAddon, got.Name, got.IsAddon)
+		}
+		if !reflect.DeepEqual(got.RequiresParentIDs, want.RequiresParentIDs) {
+			t.Errorf("%s: expected parents %v, got %v", want.ID, want.RequiresParentIDs, got.RequiresParentIDs)
+		}
+		for _, pet := range pets {
+			if got.IsEligibleFor(pet) != want.IsEligibleFor(pet) {
+				t.Errorf("%s: eligibility mismatch for %+v", want.ID, pet)
+			}
+		}
+	}
+
+	if _, err := repo.GetServiceByID(ctx, "missing"); !errors.Is(err, domain.ErrServiceNotFound) {
+		t.Errorf("expected ErrServiceNotFound, got: %v", err)
+	}
+}
@@ -1,6 +1,11 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var ErrServiceNotFound = errors.New("service not found")
 
 // ServiceRepository define el acceso a servicios.
 type ServiceRepository interface {
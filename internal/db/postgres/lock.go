@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdvisoryLocker implementa lock.Locker con advisory locks de sesión de PostgreSQL,
+// compartidos por todas las réplicas que usan la misma base.
+type AdvisoryLocker struct {
+	pool *pgxpool.Pool
+}
+
+// NewAdvisoryLocker crea un Locker sobre el pool.
+func NewAdvisoryLocker(pool *pgxpool.Pool) *AdvisoryLocker {
+	return &AdvisoryLocker{pool: pool}
+}
+
+// TryLock toma pg_try_advisory_lock sobre el hash de key.
+// El lock vive en la conexión: se retiene hasta unlock (o hasta que la conexión se cierre).
+func (l *AdvisoryLocker) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtextextended($1, 0))`, key).Scan(&acquired); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("failed to try advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtextextended($1, 0))`, key)
+		conn.Release()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,34 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	dbpostgres "paku-commerce/internal/db/postgres"
+	"paku-commerce/internal/db/postgres/pgtest"
+)
+
+func TestAdvisoryLocker_ExclusiveUntilUnlock(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	ctx := context.Background()
+
+	first := dbpostgres.NewAdvisoryLocker(pool)
+	second := dbpostgres.NewAdvisoryLocker(pool) // otra "réplica" sobre la misma base
+
+	unlock, acquired, err := first.TryLock(ctx, "test-lock")
+	if err != nil || !acquired {
+		t.Fatalf("expected first lock to be acquired, got: %v %v", acquired, err)
+	}
+
+	if _, acquired, err := second.TryLock(ctx, "test-lock"); err != nil || acquired {
+		t.Fatalf("expected second lock to be denied, got: %v %v", acquired, err)
+	}
+
+	unlock()
+
+	unlock, acquired, err = second.TryLock(ctx, "test-lock")
+	if err != nil || !acquired {
+		t.Fatalf("expected lock after unlock, got: %v %v", acquired, err)
+	}
+	unlock()
+}
@@ -0,0 +1,57 @@
+-- Carritos y órdenes (checkout).
+-- Money se guarda como minor units (BIGINT) + currency, igual que pricingdomain.Money.
+
+CREATE TABLE IF NOT EXISTS orders (
+    id                      TEXT PRIMARY KEY,
+    status                  TEXT NOT NULL,
+    created_at              TIMESTAMPTZ NOT NULL,
+    pet_species             TEXT NOT NULL,
+    pet_weight_kg           INTEGER NOT NULL,
+    pet_coat_type           TEXT NOT NULL,
+    subtotal_amount         BIGINT NOT NULL,
+    subtotal_currency       TEXT NOT NULL,
+    total_discount_amount   BIGINT NOT NULL,
+    total_discount_currency TEXT NOT NULL,
+    total_amount            BIGINT NOT NULL,
+    total_currency          TEXT NOT NULL,
+    coupon_code             TEXT,
+    booking_hold_id         TEXT,
+    payment_ref             TEXT,
+    paid_at                 TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id            TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    position            INTEGER NOT NULL,
+    item_type           TEXT NOT NULL,
+    item_id             TEXT NOT NULL,
+    qty                 INTEGER NOT NULL,
+    unit_price_amount   BIGINT NOT NULL,
+    unit_price_currency TEXT NOT NULL,
+    line_total_amount   BIGINT NOT NULL,
+    line_total_currency TEXT NOT NULL,
+    PRIMARY KEY (order_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS carts (
+    id              TEXT PRIMARY KEY,
+    user_id         TEXT NOT NULL UNIQUE,
+    pet_species     TEXT NOT NULL,
+    pet_weight_kg   INTEGER NOT NULL,
+    pet_coat_type   TEXT NOT NULL,
+    booking_hold_id TEXT,
+    order_id        TEXT,
+    updated_at      TIMESTAMPTZ NOT NULL,
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS carts_expires_at_idx ON carts (expires_at);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    cart_id   TEXT NOT NULL REFERENCES carts (id) ON DELETE CASCADE,
+    position  INTEGER NOT NULL,
+    item_type TEXT NOT NULL,
+    item_id   TEXT NOT NULL,
+    qty       INTEGER NOT NULL,
+    PRIMARY KEY (cart_id, position)
+);
@@ -0,0 +1,96 @@
+-- Catálogo: servicios, reglas de elegibilidad, dependencias addon -> parent,
+-- reglas de precio por rango de peso, cupones y promociones.
+
+CREATE TABLE IF NOT EXISTS services (
+    id       TEXT PRIMARY KEY,
+    name     TEXT NOT NULL,
+    is_addon BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS service_eligibility_rules (
+    id                  BIGSERIAL PRIMARY KEY,
+    service_id          TEXT NOT NULL REFERENCES services (id) ON DELETE CASCADE,
+    allowed_species     TEXT[] NOT NULL DEFAULT '{}',
+    excluded_species    TEXT[] NOT NULL DEFAULT '{}',
+    min_weight_kg       INTEGER,
+    max_weight_kg       INTEGER,
+    allowed_coat_types  TEXT[] NOT NULL DEFAULT '{}',
+    excluded_coat_types TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS service_eligibility_rules_service_idx ON service_eligibility_rules (service_id);
+
+CREATE TABLE IF NOT EXISTS service_addon_parents (
+    service_id        TEXT NOT NULL REFERENCES services (id) ON DELETE CASCADE,
+    parent_service_id TEXT NOT NULL REFERENCES services (id) ON DELETE CASCADE,
+    position          INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (service_id, parent_service_id)
+);
+
+CREATE TABLE IF NOT EXISTS price_rules (
+    id                  BIGSERIAL PRIMARY KEY,
+    item_type           TEXT NOT NULL,
+    item_id             TEXT NOT NULL,
+    min_weight_kg       INTEGER,
+    max_weight_kg       INTEGER,
+    unit_price_amount   BIGINT NOT NULL,
+    unit_price_currency TEXT NOT NULL,
+    CHECK (min_weight_kg IS NULL OR max_weight_kg IS NULL OR min_weight_kg <= max_weight_kg)
+);
+
+CREATE INDEX IF NOT EXISTS price_rules_item_idx ON price_rules (item_type, item_id);
+
+CREATE TABLE IF NOT EXISTS coupons (
+    code                  TEXT PRIMARY KEY,
+    active                BOOLEAN NOT NULL DEFAULT TRUE,
+    percent_off           INTEGER NOT NULL CHECK (percent_off BETWEEN 0 AND 100),
+    applies_to_item_types TEXT[] NOT NULL DEFAULT '{}',
+    min_subtotal_amount   BIGINT NOT NULL DEFAULT 0,
+    currency              TEXT NOT NULL,
+    CHECK (code = upper(btrim(code)))
+);
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id                    BIGSERIAL PRIMARY KEY,
+    name                  TEXT NOT NULL UNIQUE,
+    active                BOOLEAN NOT NULL DEFAULT TRUE,
+    percent_off           INTEGER NOT NULL CHECK (percent_off BETWEEN 0 AND 100),
+    applies_to_item_types TEXT[] NOT NULL DEFAULT '{}',
+    currency              TEXT NOT NULL
+);
+
+-- Seed: mismo catálogo que los memory repos (se puede editar luego sin redeploy).
+
+INSERT INTO services (id, name, is_addon) VALUES
+    ('bath', 'Baño', FALSE),
+    ('deshedding', 'Deslanado', TRUE),
+    ('dematting', 'Desmotado', TRUE)
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO service_eligibility_rules (service_id, allowed_species) VALUES
+    ('bath', '{dog}');
+INSERT INTO service_eligibility_rules (service_id, allowed_coat_types) VALUES
+    ('deshedding', '{double,long}');
+INSERT INTO service_eligibility_rules (service_id, excluded_coat_types) VALUES
+    ('dematting', '{hairless}');
+
+INSERT INTO service_addon_parents (service_id, parent_service_id) VALUES
+    ('deshedding', 'bath'),
+    ('dematting', 'bath')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO price_rules (item_type, item_id, min_weight_kg, max_weight_kg, unit_price_amount, unit_price_currency) VALUES
+    ('service', 'bath', 0, 10, 3500, 'PEN'),
+    ('service', 'bath', 11, 20, 4500, 'PEN'),
+    ('service', 'bath', 21, 40, 6000, 'PEN'),
+    ('service', 'deshedding', 0, 20, 2000, 'PEN'),
+    ('service', 'deshedding', 21, 40, 3000, 'PEN'),
+    ('product', 'shampoo_basic', NULL, NULL, 2500, 'PEN');
+
+INSERT INTO coupons (code, active, percent_off, applies_to_item_types, min_subtotal_amount, currency) VALUES
+    ('BANO10', TRUE, 10, '{service}', 0, 'PEN')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO promotions (name, active, percent_off, applies_to_item_types, currency) VALUES
+    ('Tuesday Grooming', TRUE, 5, '{service}', 'PEN')
+ON CONFLICT (name) DO NOTHING;
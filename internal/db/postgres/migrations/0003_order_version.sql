@@ -0,0 +1,2 @@
+-- Control de concurrencia optimista en órdenes.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Revisión de carrito para ETag / If-Match.
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 1;
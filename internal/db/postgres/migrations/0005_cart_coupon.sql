@@ -0,0 +1,2 @@
+-- Cupón aplicado al carrito (código normalizado).
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS coupon_code TEXT;
@@ -0,0 +1,30 @@
+-- Carritos y órdenes con varias mascotas.
+-- pet_id = '' identifica al pet principal (columnas pet_* de carts/orders).
+
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS pet_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS pet_id TEXT NOT NULL DEFAULT '';
+
+-- Mascotas adicionales del carrito.
+CREATE TABLE IF NOT EXISTS cart_pets (
+    cart_id       TEXT NOT NULL REFERENCES carts (id) ON DELETE CASCADE,
+    position      INTEGER NOT NULL,
+    pet_id        TEXT NOT NULL,
+    pet_species   TEXT NOT NULL,
+    pet_weight_kg INTEGER NOT NULL,
+    pet_coat_type TEXT NOT NULL,
+    PRIMARY KEY (cart_id, position),
+    UNIQUE (cart_id, pet_id)
+);
+
+-- Resumen por mascota de la orden (incluye al pet principal si tiene items).
+CREATE TABLE IF NOT EXISTS order_pets (
+    order_id          TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    position          INTEGER NOT NULL,
+    pet_id            TEXT NOT NULL,
+    pet_species       TEXT NOT NULL,
+    pet_weight_kg     INTEGER NOT NULL,
+    pet_coat_type     TEXT NOT NULL,
+    subtotal_amount   BIGINT NOT NULL,
+    subtotal_currency TEXT NOT NULL,
+    PRIMARY KEY (order_id, position)
+);
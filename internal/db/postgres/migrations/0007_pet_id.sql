@@ -0,0 +1,4 @@
+-- ID del pet principal en el servicio de mascotas ('' = perfil enviado inline).
+
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS pet_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pet_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,9 @@
+-- Plazo de pago de órdenes pending_payment y motivo de cancelación.
+-- expires_at NULL = orden sin vencimiento (creada antes de esta migración).
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancel_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS orders_pending_expires_at_idx
+    ON orders (expires_at)
+    WHERE status = 'pending_payment';
@@ -0,0 +1,14 @@
+-- Ciclo de vida extendido de órdenes: un timestamp por transición (NULL = no ocurrió).
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS processing_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fulfilled_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS no_show_at TIMESTAMPTZ;
+
+-- Las órdenes con pago rechazado (failed) también vencen.
+DROP INDEX IF EXISTS orders_pending_expires_at_idx;
+CREATE INDEX IF NOT EXISTS orders_awaiting_payment_expires_at_idx
+    ON orders (expires_at)
+    WHERE status IN ('pending_payment', 'failed');
@@ -0,0 +1,16 @@
+-- Historial append-only de las órdenes: una fila por hecho (creación, pago, hold, cancelación, ...).
+-- Solo se insertan filas; nunca se actualizan ni borran (salvo en cascada con la orden).
+
+CREATE TABLE IF NOT EXISTS order_events (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    event_type  TEXT NOT NULL,
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status   TEXT NOT NULL,
+    actor       TEXT NOT NULL,
+    request_id  TEXT NOT NULL DEFAULT '',
+    data        JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS order_events_order_id_idx ON order_events (order_id, id);
@@ -0,0 +1,7 @@
+-- Dueño de la orden (X-User-ID) para el historial de órdenes por usuario.
+-- '' = orden anónima o creada antes de esta migración.
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS orders_user_created_at_idx
+    ON orders (user_id, created_at DESC, id DESC);
@@ -0,0 +1,6 @@
+-- Cita de la orden (slot de booking) y penalidad de cancelación según la política.
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS slot_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS appointment_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancellation_fee_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancellation_fee_currency TEXT NOT NULL DEFAULT '';
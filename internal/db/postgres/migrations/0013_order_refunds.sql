@@ -0,0 +1,20 @@
+-- Reembolsos (totales o por línea) sobre el pago de una orden.
+-- refunded_amount acumula lo reembolsado: neto pagado = total_amount - refunded_amount.
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS refunded_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS refunded_currency TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS refunded BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- refund_key es la clave de idempotencia: un mismo reembolso no se emite dos veces.
+CREATE TABLE IF NOT EXISTS order_refunds (
+    order_id     TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    refund_key   TEXT NOT NULL,
+    payment_ref  TEXT NOT NULL,
+    amount       BIGINT NOT NULL,
+    currency     TEXT NOT NULL,
+    lines        INTEGER[] NOT NULL DEFAULT '{}',
+    provider_ref TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (order_id, refund_key)
+);
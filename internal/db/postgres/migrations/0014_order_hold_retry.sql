@@ -0,0 +1,10 @@
+-- Saga de compensación: órdenes cobradas cuyo hold no se pudo confirmar
+-- (payment_received_booking_failed) con reintentos de ConfirmHold programados.
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS booking_failed_at TIMESTAMPTZ;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS hold_confirm_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS next_hold_retry_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS orders_hold_retry_idx
+    ON orders (next_hold_retry_at)
+    WHERE status = 'payment_received_booking_failed';
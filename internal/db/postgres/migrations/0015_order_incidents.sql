@@ -0,0 +1,20 @@
+-- Cola de revisión manual: side effects de booking/pagos que checkout no pudo completar
+-- (liberar o confirmar un hold, reembolso automático) y quedan para un operador.
+
+CREATE TABLE IF NOT EXISTS order_incidents (
+    id          TEXT PRIMARY KEY,
+    order_id    TEXT NOT NULL DEFAULT '',
+    hold_id     TEXT NOT NULL DEFAULT '',
+    operation   TEXT NOT NULL,
+    error       TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    attempts    INTEGER NOT NULL DEFAULT 0,
+    resolution  TEXT NOT NULL DEFAULT '',
+    resolved_by TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS order_incidents_status_idx ON order_incidents (status, created_at);
+CREATE INDEX IF NOT EXISTS order_incidents_order_idx ON order_incidents (order_id);
@@ -0,0 +1,23 @@
+-- Inbox de webhooks del proveedor de pagos: cada evento se procesa una sola vez
+-- por (provider, event_id) aunque el proveedor lo entregue varias veces.
+
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    provider     TEXT NOT NULL,
+    event_id     TEXT NOT NULL,
+    type         TEXT NOT NULL,
+    order_id     TEXT NOT NULL DEFAULT '',
+    payment_ref  TEXT NOT NULL DEFAULT '',
+    refund_ref   TEXT NOT NULL DEFAULT '',
+    amount       BIGINT NOT NULL DEFAULT 0,
+    currency     TEXT NOT NULL DEFAULT '',
+    reason       TEXT NOT NULL DEFAULT '',
+    occurred_at  TIMESTAMPTZ NOT NULL,
+    status       TEXT NOT NULL,
+    error        TEXT NOT NULL DEFAULT '',
+    attempts     INTEGER NOT NULL DEFAULT 0,
+    received_at  TIMESTAMPTZ NOT NULL,
+    processed_at TIMESTAMPTZ,
+    PRIMARY KEY (provider, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS payment_webhook_events_order_idx ON payment_webhook_events (order_id);
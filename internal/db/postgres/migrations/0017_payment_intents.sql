@@ -0,0 +1,22 @@
+-- Intentos de pago por QR (Yape/Plin): el cobro es asíncrono y se concilia por polling
+-- al proveedor o por su webhook hasta que la transferencia llega, falla o vence.
+
+CREATE TABLE IF NOT EXISTS payment_intents (
+    id             TEXT PRIMARY KEY,
+    order_id       TEXT NOT NULL,
+    method         TEXT NOT NULL,
+    amount         BIGINT NOT NULL,
+    currency       TEXT NOT NULL,
+    provider_ref   TEXT NOT NULL,
+    qr_data        TEXT NOT NULL DEFAULT '',
+    status         TEXT NOT NULL,
+    failure_reason TEXT NOT NULL DEFAULT '',
+    expires_at     TIMESTAMPTZ NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL,
+    updated_at     TIMESTAMPTZ NOT NULL,
+    completed_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS payment_intents_order_idx ON payment_intents (order_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS payment_intents_provider_ref_idx ON payment_intents (provider_ref);
+CREATE INDEX IF NOT EXISTS payment_intents_pending_idx ON payment_intents (created_at) WHERE status = 'pending';
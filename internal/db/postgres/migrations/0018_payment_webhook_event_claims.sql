@@ -0,0 +1,5 @@
+-- Claim atómico de eventos de webhook: una entrega toma el evento (status = 'processing')
+-- antes de aplicarlo, así dos entregas concurrentes no lo aplican dos veces.
+-- claimed_at permite retomar eventos cuya entrega murió a mitad del proceso.
+
+ALTER TABLE payment_webhook_events ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ NOT NULL DEFAULT now();
@@ -0,0 +1,4 @@
+-- Proveedor que capturó el cobro de la orden (card o qr): los reembolsos salen por él.
+-- Las órdenes anteriores quedan en '' y se tratan como cobradas con tarjeta.
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_provider TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- Claim de intentos de pago QR: la conciliación (worker, GET o webhook) toma el intento
+-- antes de confirmar el pago, así dos conciliaciones concurrentes no lo aplican dos veces.
+-- claimed_at permite retomar intentos cuya conciliación murió a mitad del proceso.
+
+ALTER TABLE payment_intents ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
@@ -0,0 +1,16 @@
+-- Un solo intento de pago QR pending por orden: dos creaciones concurrentes no generan dos
+-- QR vigentes. Antes de crear el índice se cierran los pending duplicados (queda el más
+-- reciente de cada orden).
+
+UPDATE payment_intents p
+SET status = 'expired', updated_at = now(), completed_at = now()
+WHERE p.status = 'pending'
+  AND EXISTS (
+      SELECT 1 FROM payment_intents q
+      WHERE q.order_id = p.order_id
+        AND q.status = 'pending'
+        AND (q.created_at, q.id) > (p.created_at, p.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS payment_intents_pending_order_idx
+    ON payment_intents (order_id) WHERE status = 'pending';
@@ -0,0 +1,35 @@
+// Package pgtest contiene helpers para tests de integración contra PostgreSQL.
+package pgtest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	dbpostgres "paku-commerce/internal/db/postgres"
+)
+
+// NewPool conecta a TEST_DATABASE_URL y aplica migraciones; sin la variable el test se omite.
+func NewPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := dbpostgres.NewPool(ctx, dbpostgres.Config{DatabaseURL: dsn})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if err := dbpostgres.Migrate(ctx, pool); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	return pool
+}
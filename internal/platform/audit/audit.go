@@ -0,0 +1,54 @@
+// Package audit propaga en el context quién ejecuta una operación y desde qué request,
+// para que los repos lo registren en el historial sin que los usecases lo reciban por parámetro.
+package audit
+
+import "context"
+
+// Actores conocidos. Los usuarios se registran como "user:<id>" (ver UserActor) y los
+// webhooks de pagos como "payments:<proveedor>" (ver PaymentProviderActor).
+const (
+	ActorSystem            = "system"
+	ActorAdmin             = "admin"
+	ActorCartExpiry        = "system:cart-expiry"
+	ActorOrderExpiry       = "system:order-expiry"
+	ActorHoldRetry         = "system:hold-retry"
+	ActorPaymentIntentPoll = "system:payment-intent-poll"
+)
+
+type actorKey struct{}
+
+type requestIDKey struct{}
+
+// UserActor construye el actor de un usuario final.
+func UserActor(userID string) string {
+	return "user:" + userID
+}
+
+// PaymentProviderActor construye el actor de un webhook del proveedor de pagos.
+func PaymentProviderActor(provider string) string {
+	return "payments:" + provider
+}
+
+// WithActor retorna un ctx que identifica al actor de las operaciones.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor retorna el actor del ctx (ActorSystem si no hay ninguno).
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorSystem
+}
+
+// WithRequestID retorna un ctx con el ID del request HTTP en curso.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID retorna el ID del request del ctx ("" fuera de un request HTTP).
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
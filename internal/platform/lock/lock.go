@@ -0,0 +1,47 @@
+// Package lock define locks exclusivos por clave para coordinar tareas
+// de fondo entre réplicas (ej. que un solo proceso expire carritos a la vez).
+package lock
+
+import (
+	"context"
+	"sync"
+)
+
+// Locker obtiene locks exclusivos por clave.
+type Locker interface {
+	// TryLock intenta tomar el lock sin bloquear. Si otro proceso lo tiene retorna acquired=false.
+	// Con acquired=true, el llamador debe invocar unlock al terminar.
+	TryLock(ctx context.Context, key string) (unlock func(), acquired bool, err error)
+}
+
+// MemoryLocker implementa Locker dentro de un solo proceso (dev/tests o una réplica).
+type MemoryLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+// NewMemoryLocker crea un Locker en memoria.
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{held: make(map[string]bool)}
+}
+
+// TryLock toma el lock de key si está libre.
+func (l *MemoryLocker) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+
+	var once sync.Once
+	unlock := func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			delete(l.held, key)
+		})
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,77 @@
+// Package transaction define la unidad de trabajo que usan los usecases
+// para que varias escrituras (ej. orden + cart) confirmen o reviertan juntas.
+package transaction
+
+import (
+	"context"
+	"sync"
+)
+
+// Manager ejecuta fn dentro de una transacción.
+// Si fn retorna error (o hace panic) todas las escrituras hechas con el ctx recibido se revierten.
+// Llamadas anidadas se unen a la transacción en curso.
+type Manager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type journalKey struct{}
+
+// journal acumula acciones de undo de los repos en memoria.
+type journal struct {
+	mu    sync.Mutex
+	undos []func()
+}
+
+// RecordUndo registra una acción para revertir una escritura en memoria.
+// El undo debe restaurar solo la clave que escribió y solo si nadie la modificó
+// después (compare-and-restore), porque otros requests no esperan al commit.
+// Fuera de una transacción de MemoryManager no hace nada.
+func RecordUndo(ctx context.Context, undo func()) {
+	j, ok := ctx.Value(journalKey{}).(*journal)
+	if !ok {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.undos = append(j.undos, undo)
+}
+
+// MemoryManager implementa Manager para los repos en memoria.
+// No provee aislamiento: otras lecturas ven escrituras aún no confirmadas. Un rollback
+// revierte solo las claves que la transacción escribió y que siguen como las dejó; si
+// otro request ya escribió encima, esa clave conserva su escritura.
+type MemoryManager struct{}
+
+// NewMemoryManager crea un Manager en memoria.
+func NewMemoryManager() *MemoryManager {
+	return &MemoryManager{}
+}
+
+// WithinTx ejecuta fn y aplica los undo registrados en orden inverso si falla.
+func (m *MemoryManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(journalKey{}).(*journal); ok {
+		return fn(ctx)
+	}
+
+	j := &journal{}
+	defer func() {
+		if p := recover(); p != nil {
+			j.rollback()
+			panic(p)
+		}
+		if err != nil {
+			j.rollback()
+		}
+	}()
+
+	return fn(context.WithValue(ctx, journalKey{}, j))
+}
+
+func (j *journal) rollback() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for i := len(j.undos) - 1; i >= 0; i-- {
+		j.undos[i]()
+	}
+	j.undos = nil
+}
@@ -1 +1,65 @@
 package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	dbpostgres "paku-commerce/internal/db/postgres"
+	"paku-commerce/internal/pricing/domain"
+)
+
+// PriceRuleRepository implementa domain.PriceRuleRepository sobre PostgreSQL.
+type PriceRuleRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPriceRuleRepository crea un repositorio de reglas de precio en PostgreSQL.
+func NewPriceRuleRepository(pool *pgxpool.Pool) *PriceRuleRepository {
+	return &PriceRuleRepository{pool: pool}
+}
+
+const selectRuleSQL = `
+	SELECT item_type, item_id, min_weight_kg, max_weight_kg,
+	       unit_price_amount, unit_price_currency
+	FROM price_rules`
+
+// ListRules retorna todas las reglas.
+func (r *PriceRuleRepository) ListRules(ctx context.Context) ([]domain.PriceRule, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, selectRuleSQL+` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price rules: %w", err)
+	}
+	return collectRules(rows)
+}
+
+// ListRulesForItem filtra reglas por tipo e ID de item.
+func (r *PriceRuleRepository) ListRulesForItem(ctx context.Context, itemType domain.ItemType, itemID string) ([]domain.PriceRule, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, selectRuleSQL+` WHERE item_type = $1 AND item_id = $2 ORDER BY id`, string(itemType), itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price rules: %w", err)
+	}
+	return collectRules(rows)
+}
+
+func collectRules(rows pgx.Rows) ([]domain.PriceRule, error) {
+	rules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.PriceRule, error) {
+		var (
+			rule               domain.PriceRule
+			itemType, currency string
+		)
+		err := row.Scan(
+			&itemType, &rule.ItemID, &rule.MinWeightKg, &rule.MaxWeightKg,
+			&rule.UnitPrice.Amount, &currency,
+		)
+		rule.ItemType = domain.ItemType(itemType)
+		rule.UnitPrice.Currency = domain.Currency(currency)
+		return rule, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan price rules: %w", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"paku-commerce/internal/db/postgres/pgtest"
+	"paku-commerce/internal/pricing/domain"
+)
+
+func TestPriceRuleRepository_ListRulesForItem(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewPriceRuleRepository(pool)
+	ctx := context.Background()
+
+	rules, err := repo.ListRulesForItem(ctx, domain.ItemTypeService, "bath")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 bath rules, got: %d", len(rules))
+	}
+
+	first := rules[0]
+	if first.MinWeightKg == nil || *first.MinWeightKg != 0 || first.MaxWeightKg == nil || *first.MaxWeightKg != 10 {
+		t.Errorf("expected first bath rule 0-10kg, got: %+v", first)
+	}
+	if first.UnitPrice != domain.NewMoney(3500, domain.CurrencyPEN) {
+		t.Errorf("expected S/ 35.00, got: %+v", first.UnitPrice)
+	}
+
+	products, err := repo.ListRulesForItem(ctx, domain.ItemTypeProduct, "shampoo_basic")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(products) != 1 || products[0].MinWeightKg != nil || products[0].MaxWeightKg != nil {
+		t.Errorf("expected one product rule without weight range, got: %+v", products)
+	}
+}
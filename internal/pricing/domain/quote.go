@@ -7,6 +7,7 @@ type QuoteItem struct {
 	Qty       int
 	UnitPrice Money
 	LineTotal Money
+	PetID     string // mascota para la que se cotizó ("" = pet principal)
 }
 
 // Quote agrupa items cotizados con subtotal.
@@ -9,18 +9,24 @@ import (
 	pricingdomain "paku-commerce/internal/pricing/domain"
 )
 
-var ErrNoPriceRule = errors.New("no price rule found for item")
+var (
+	ErrNoPriceRule = errors.New("no price rule found for item")
+	ErrUnknownPet  = errors.New("item references unknown pet")
+)
 
 // QuoteRequestItem representa un item a cotizar.
 type QuoteRequestItem struct {
 	ItemType pricingdomain.ItemType
 	ItemID   string
 	Qty      int
+	PetID    string // "" = PetProfile del input
 }
 
 // QuoteItemsInput contiene el pet profile y los items a cotizar.
+// Los items con PetID se cotizan con el perfil de esa mascota en Pets.
 type QuoteItemsInput struct {
 	PetProfile domain.PetProfile
+	Pets       map[string]domain.PetProfile
 	Items      []QuoteRequestItem
 }
 
@@ -46,10 +52,18 @@ func (uc QuoteItems) Execute(ctx context.Context, input QuoteItemsInput) (QuoteI
 			return QuoteItemsOutput{}, err
 		}
 
+		pet := input.PetProfile
+		if reqItem.PetID != "" {
+			var ok bool
+			if pet, ok = input.Pets[reqItem.PetID]; !ok {
+				return QuoteItemsOutput{}, ErrUnknownPet
+			}
+		}
+
 		// Seleccionar regla aplicable
 		var selectedRule *pricingdomain.PriceRule
 		if reqItem.ItemType == pricingdomain.ItemTypeService {
-			selectedRule = selectServiceRule(rules, reqItem.ItemID, input.PetProfile)
+			selectedRule = selectServiceRule(rules, reqItem.ItemID, pet)
 		} else if reqItem.ItemType == pricingdomain.ItemTypeProduct {
 			selectedRule = selectProductRule(rules)
 		}
@@ -67,6 +81,7 @@ func (uc QuoteItems) Execute(ctx context.Context, input QuoteItemsInput) (QuoteI
 			Qty:       reqItem.Qty,
 			UnitPrice: selectedRule.UnitPrice,
 			LineTotal: lineTotal,
+			PetID:     reqItem.PetID,
 		})
 
 		// Sumar al subtotal
@@ -1 +1,77 @@
 package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	dbpostgres "paku-commerce/internal/db/postgres"
+	pricingdomain "paku-commerce/internal/pricing/domain"
+	"paku-commerce/internal/promotions/domain"
+)
+
+// PromotionsRepository implementa domain.PromotionsRepository sobre PostgreSQL.
+type PromotionsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPromotionsRepository crea un repositorio de cupones/promos en PostgreSQL.
+func NewPromotionsRepository(pool *pgxpool.Pool) *PromotionsRepository {
+	return &PromotionsRepository{pool: pool}
+}
+
+// GetCouponByCode busca un cupón por código normalizado.
+func (r *PromotionsRepository) GetCouponByCode(ctx context.Context, code string) (domain.Coupon, error) {
+	var (
+		coupon   domain.Coupon
+		currency string
+	)
+
+	err := dbpostgres.Conn(ctx, r.pool).QueryRow(ctx, `
+		SELECT code, active, percent_off, applies_to_item_types, min_subtotal_amount, currency
+		FROM coupons
+		WHERE code = $1`, domain.NormalizeCode(code),
+	).Scan(
+		&coupon.Code, &coupon.Active, &coupon.PercentOff,
+		&coupon.AppliesToItemTypes, &coupon.MinSubtotalAmount, &currency,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Coupon{}, domain.ErrCouponNotFound
+	}
+	if err != nil {
+		return domain.Coupon{}, fmt.Errorf("failed to get coupon: %w", err)
+	}
+
+	coupon.Currency = pricingdomain.Currency(currency)
+	return coupon, nil
+}
+
+// ListActivePromotions retorna todas las promociones activas.
+func (r *PromotionsRepository) ListActivePromotions(ctx context.Context) ([]domain.Promotion, error) {
+	rows, err := dbpostgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT name, active, percent_off, applies_to_item_types, currency
+		FROM promotions
+		WHERE active
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promotions: %w", err)
+	}
+
+	promos, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Promotion, error) {
+		var (
+			promo    domain.Promotion
+			currency string
+		)
+		err := row.Scan(&promo.Name, &promo.Active, &promo.PercentOff, &promo.AppliesToItemTypes, &currency)
+		promo.Currency = pricingdomain.Currency(currency)
+		return promo, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan promotions: %w", err)
+	}
+
+	return promos, nil
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"paku-commerce/internal/db/postgres/pgtest"
+	"paku-commerce/internal/promotions/domain"
+)
+
+func TestPromotionsRepository_CouponAndPromotions(t *testing.T) {
+	pool := pgtest.NewPool(t)
+	repo := NewPromotionsRepository(pool)
+	ctx := context.Background()
+
+	coupon, err := repo.GetCouponByCode(ctx, "  bano10 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if coupon.Code != "BANO10" || coupon.PercentOff != 10 || !coupon.Active {
+		t.Errorf("unexpected coupon: %+v", coupon)
+	}
+
+	if _, err := repo.GetCouponByCode(ctx, "NOPE"); !errors.Is(err, domain.ErrCouponNotFound) {
+		t.Errorf("expected ErrCouponNotFound, got: %v", err)
+	}
+
+	promos, err := repo.ListActivePromotions(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for _, p := range promos {
+		if p.Name == "Tuesday Grooming" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected Tuesday Grooming in active promotions")
+	}
+}
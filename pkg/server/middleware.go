@@ -1,24 +1,30 @@
 package server
 
 import (
-	"context"
 	"net/http"
 
+	"paku-commerce/internal/platform/audit"
 	"paku-commerce/internal/platform/id"
 )
 
-type contextKey string
-
-const requestIDKey contextKey = "request_id"
-
 func RequestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		reqID := r.Header.Get("X-Request-ID")
 		if reqID == "" {
 			reqID = id.NewRequestID()
 		}
-		ctx := context.WithValue(r.Context(), requestIDKey, reqID)
+		ctx := audit.WithRequestID(r.Context(), reqID)
 		w.Header().Set("X-Request-ID", reqID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// ActorMiddleware identifica al usuario (X-User-ID) como actor de las operaciones del request.
+func ActorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userID := r.Header.Get("X-User-ID"); userID != "" {
+			r = r.WithContext(audit.WithActor(r.Context(), audit.UserActor(userID)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -14,6 +14,7 @@ func NewRouter() http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(RequestIDMiddleware)
+	r.Use(ActorMiddleware)
 
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)